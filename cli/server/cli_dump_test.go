@@ -156,3 +156,156 @@ func TestDBDumpRestoreIncremental(t *testing.T) {
 	// Restore second 15 blocks from incremental dump.
 	e.Run(t, append(restoreBaseArgs, "--in", incDump, "-n", "--count", "15")...)
 }
+
+func TestDBDumpRestoreIndexed(t *testing.T) {
+	tmpDir := t.TempDir()
+	chainPath := filepath.Join(tmpDir, "neogotestchain")
+	fullDump := filepath.Join(tmpDir, "full.idx")
+	incDump := filepath.Join(tmpDir, "inc.idx")
+	legacyDump := filepath.Join(tmpDir, "legacy.acc")
+
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = chainPath
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	cfgPath := filepath.Join(tmpDir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	e := testcli.NewExecutor(t, false)
+	restoreBaseArgs := []string{"neo-go", "db", "restore", "--unittest", "--config-path", tmpDir}
+	dumpBaseArgs := []string{"neo-go", "db", "dump", "--unittest", "--config-path", tmpDir}
+
+	// Create DB from legacy dump.
+	e.Run(t, append(restoreBaseArgs, "--in", inDump)...)
+
+	t.Run("unknown format", func(t *testing.T) {
+		e.RunWithError(t, append(dumpBaseArgs, "--out", fullDump, "--format", "unknown")...)
+	})
+	e.Run(t, append(dumpBaseArgs, "--out", fullDump, "--format", "indexed", "--chunk-size", "7")...)
+	e.Run(t, append(dumpBaseArgs, "--out", incDump, "--format", "indexed", "--start", "15", "--count", "15")...)
+
+	// Restore from the full dump in several steps (resuming from the current height).
+	require.NoError(t, os.RemoveAll(chainPath))
+	e.Run(t, append(restoreBaseArgs, "--in", fullDump, "--count", "10")...)
+	e.RunWithError(t, append(restoreBaseArgs, "--in", fullDump, "--count", "1000")...)
+	e.Run(t, append(restoreBaseArgs, "--in", fullDump, "--count", "10")...)
+	e.Run(t, append(restoreBaseArgs, "--in", fullDump)...)
+
+	// Compare with the original chain.
+	e.Run(t, append(dumpBaseArgs, "--out", legacyDump)...)
+	d1, err := os.ReadFile(inDump)
+	require.NoError(t, err)
+	d2, err := os.ReadFile(legacyDump)
+	require.NoError(t, err)
+	require.Equal(t, d1, d2, "dumps differ")
+
+	// Incremental dump doesn't need -n and can't be restored to the empty DB.
+	require.NoError(t, os.RemoveAll(chainPath))
+	e.RunWithError(t, append(restoreBaseArgs, "--in", incDump)...)
+	e.Run(t, append(restoreBaseArgs, "--in", fullDump, "--count", "15")...)
+	e.Run(t, append(restoreBaseArgs, "--in", incDump)...)
+
+	// Corrupted dump.
+	require.NoError(t, os.RemoveAll(chainPath))
+	data, err := os.ReadFile(fullDump)
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(fullDump, data, os.ModePerm))
+	e.RunWithError(t, append(restoreBaseArgs, "--in", fullDump)...)
+}
+
+func TestDBSnapshot(t *testing.T) {
+	tmpDir := t.TempDir()
+	chainPath := filepath.Join(tmpDir, "neogotestchain")
+	snapshot := filepath.Join(tmpDir, "state.snp")
+
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = chainPath
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	cfgPath := filepath.Join(tmpDir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	e := testcli.NewExecutor(t, false)
+	exportArgs := []string{"neo-go", "db", "snapshot", "export", "--unittest", "--config-path", tmpDir}
+	importArgs := []string{"neo-go", "db", "snapshot", "import", "--unittest", "--config-path", tmpDir}
+
+	e.Run(t, "neo-go", "db", "restore", "--unittest", "--config-path", tmpDir, "--in", inDump)
+
+	t.Run("excessive parameters", func(t *testing.T) {
+		e.RunWithError(t, append(exportArgs, "--out", snapshot, "something")...)
+	})
+	t.Run("state sync disabled", func(t *testing.T) {
+		e.RunWithError(t, append(exportArgs, "--out", snapshot)...)
+		e.RunWithError(t, append(exportArgs, "--out", snapshot, "--height", "10")...)
+	})
+	t.Run("import to non-light node", func(t *testing.T) {
+		e.RunWithError(t, append(importArgs, "--in", inDump)...)
+	})
+
+	require.NoError(t, os.RemoveAll(chainPath))
+	cfg.ProtocolConfiguration.StateRootInHeader = true
+	cfg.ProtocolConfiguration.P2PStateExchangeExtensions = true
+	cfg.ApplicationConfiguration.Ledger.KeepOnlyLatestState = true
+	cfg.ApplicationConfiguration.Ledger.RemoveUntraceableBlocks = true
+	out, err = yaml.Marshal(cfg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	t.Run("missing input", func(t *testing.T) {
+		e.RunWithError(t, append(importArgs, "--in", snapshot)...)
+	})
+	t.Run("not a snapshot", func(t *testing.T) {
+		e.RunWithError(t, append(importArgs, "--in", inDump)...)
+	})
+}
+
+func TestDBExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	chainPath := filepath.Join(tmpDir, "neogotestchain")
+	outDir := filepath.Join(tmpDir, "export")
+
+	cfg, err := config.LoadFile(filepath.Join("..", "..", "config", "protocol.unit_testnet.yml"))
+	require.NoError(t, err, "could not load config")
+	cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.LevelDB
+	cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = chainPath
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+
+	cfgPath := filepath.Join(tmpDir, "protocol.unit_testnet.yml")
+	require.NoError(t, os.WriteFile(cfgPath, out, os.ModePerm))
+
+	e := testcli.NewExecutor(t, false)
+	e.Run(t, "neo-go", "db", "restore", "--unittest", "--config-path", tmpDir, "--in", inDump)
+
+	exportArgs := []string{"neo-go", "db", "export", "--unittest", "--config-path", tmpDir, "--out", outDir}
+	t.Run("excessive parameters", func(t *testing.T) {
+		e.RunWithError(t, append(exportArgs, "something")...)
+	})
+	t.Run("bad format", func(t *testing.T) {
+		e.RunWithError(t, append(exportArgs, "--format", "xml")...)
+	})
+
+	e.Run(t, append(exportArgs, "--count", "5", "--partition-size", "2", "--dataset", "blocks", "--dataset", "transactions")...)
+	files, err := filepath.Glob(filepath.Join(outDir, "blocks", "*.ndjson"))
+	require.NoError(t, err)
+	require.Equal(t, 3, len(files))
+
+	// Resume.
+	e.Run(t, append(exportArgs, "--partition-size", "2", "--dataset", "blocks", "--dataset", "transactions")...)
+	files, err = filepath.Glob(filepath.Join(outDir, "blocks", "*.ndjson"))
+	require.NoError(t, err)
+	require.Greater(t, len(files), 3)
+	_, err = os.Stat(filepath.Join(outDir, "notifications"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	t.Run("changed parameters", func(t *testing.T) {
+		e.RunWithError(t, append(exportArgs, "--partition-size", "3", "--dataset", "blocks", "--dataset", "transactions")...)
+	})
+}
@@ -98,6 +98,14 @@ func TestServerStart(t *testing.T) {
 			e.RunWithError(t, baseCmd...)
 		})
 	})
+	t.Run("invalid dev mode params", func(t *testing.T) {
+		saveCfg(t, func(cfg *config.Config) {})
+		badWallet := filepath.Join(t.TempDir(), "bad_dev_wallet.json")
+		require.NoError(t, os.WriteFile(badWallet, []byte("not a wallet"), os.ModePerm))
+		e.RunWithError(t, append(baseCmd, "--dev", "--light")...)
+		e.RunWithError(t, append(baseCmd, "--dev", "--dev-wallet", badWallet)...)
+		e.RunWithError(t, append(baseCmd, "--dev", "--dev-wallet", filepath.Join(t.TempDir(), "w.json"), "--dev-accounts", "1000")...)
+	})
 	// We can't properly shutdown server on windows and release the resources.
 	// Also, windows doesn't support SIGHUP and SIGINT.
 	if runtime.GOOS != "windows" {
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/services/devnode"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+)
+
+// maxDevAccounts is the maximum number of prefunded accounts in a generated
+// developer chain wallet.
+const maxDevAccounts = 100
+
+// configureDevNode adjusts the given configuration for a single-node developer
+// chain with the given validator. The chain is kept in memory, it doesn't
+// connect to other nodes and runs only RPC server among the other services.
+func configureDevNode(cfg *config.Config, validator *keys.PublicKey) {
+	cfg.ProtocolConfiguration.StandbyCommittee = []string{hex.EncodeToString(validator.Bytes())}
+	cfg.ProtocolConfiguration.ValidatorsCount = 1
+	cfg.ProtocolConfiguration.ValidatorsHistory = nil
+	cfg.ProtocolConfiguration.CommitteeHistory = nil
+	cfg.ProtocolConfiguration.SeedList = nil
+	cfg.ProtocolConfiguration.P2PStateExchangeExtensions = false
+
+	app := &cfg.ApplicationConfiguration
+	app.DBConfiguration = dbconfig.DBConfiguration{Type: dbconfig.InMemoryDB}
+	app.Ledger.KeepOnlyLatestState = false
+	app.Ledger.RemoveUntraceableBlocks = false
+	app.MemPoolPersistence.Enabled = false
+	app.MinPeers = 0 //nolint:staticcheck // SA1019: app.MinPeers is deprecated
+	app.P2P.MinPeers = 0
+	app.Consensus.Enabled = false
+	app.Oracle.Enabled = false
+	app.P2PNotary.Enabled = false
+	app.StateRoot.Enabled = false
+	app.RPC.Enabled = true
+}
+
+// getDevWallet opens the developer chain wallet at the given path or creates
+// a new one with n prefunded accounts if there is no such file.
+func getDevWallet(path string, n uint) (*wallet.Wallet, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return devnode.OpenWallet(path)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if n > maxDevAccounts {
+		return nil, fmt.Errorf("too many accounts requested: %d > %d", n, maxDevAccounts)
+	}
+	return devnode.NewWallet(path, int(n))
+}
+
+// startDevNode runs a single-node developer chain until the grace context is
+// done.
+func startDevNode(ctx *cli.Context, grace context.Context, cfg config.Config, log *zap.Logger) error {
+	w, err := getDevWallet(ctx.String("dev-wallet"), ctx.Uint("dev-accounts"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get developer wallet: %w", err), 1)
+	}
+	defer w.Close()
+	validator, err := devnode.Validator(w)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	configureDevNode(&cfg, validator)
+
+	serverConfig, err := network.NewServerConfig(cfg)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	serv, err := network.NewServer(serverConfig, chain, chain.GetStateSyncModule(), log)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to create network server: %w", err), 1)
+	}
+	dev, err := devnode.New(chain, w, log)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to create block producer: %w", err), 1)
+	}
+	serv.AddService(dev)
+
+	errChan := make(chan error)
+	rpcServer := rpcsrv.New(chain, cfg.ApplicationConfiguration.RPC, serv, nil, log, errChan)
+	rpcServer.SetDevHandler(dev)
+	serv.AddService(&rpcServer)
+
+	go serv.Start()
+	go rpcServer.Start()
+
+	fmt.Fprintln(ctx.App.Writer, Logo())
+	fmt.Fprintln(ctx.App.Writer, serv.UserAgent)
+	fmt.Fprintf(ctx.App.Writer, "\nDeveloper chain wallet: %s (password %q)\n", w.Path(), devnode.WalletPassword)
+	for _, acc := range w.Accounts {
+		fmt.Fprintf(ctx.App.Writer, "  %s %s\n", acc.Address, acc.Label)
+	}
+	fmt.Fprintln(ctx.App.Writer)
+
+	var shutdownErr error
+	select {
+	case err := <-errChan:
+		shutdownErr = fmt.Errorf("server error: %w", err)
+	case <-grace.Done():
+	}
+	serv.Shutdown()
+	if shutdownErr != nil {
+		return cli.NewExitError(shutdownErr, 1)
+	}
+	return nil
+}
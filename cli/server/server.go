@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -21,17 +22,25 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/lightclient"
 	"github.com/nspcc-dev/neo-go/pkg/network"
 	"github.com/nspcc-dev/neo-go/pkg/services/metrics"
 	"github.com/nspcc-dev/neo-go/pkg/services/notary"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
 	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/services/tracing"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Supported chain dump formats.
+const (
+	dumpFormatLegacy  = "legacy"
+	dumpFormatIndexed = "indexed"
+)
+
 // NewCommands returns 'node' command.
 func NewCommands() []cli.Command {
 	cfgFlags := []cli.Flag{options.Config, options.ConfigFile}
@@ -57,6 +66,15 @@ func NewCommands() []cli.Command {
 			Name:  "out, o",
 			Usage: "Output file (stdout if not given)",
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Dump format: 'legacy' (default) or 'indexed' (compressed, with block index and checksums)",
+		},
+		cli.UintFlag{
+			Name:  "chunk-size",
+			Usage: "Number of blocks per compressed chunk for indexed dump format",
+			Value: chaindump.DefaultChunkSize,
+		},
 	)
 	var cfgCountInFlags = make([]cli.Flag, len(cfgWithCountFlags))
 	copy(cfgCountInFlags, cfgWithCountFlags)
@@ -81,13 +99,91 @@ func NewCommands() []cli.Command {
 		Usage:    "Height of the state to reset DB to",
 		Required: true,
 	}
+	var cfgSnapshotOutFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgSnapshotOutFlags, cfgFlags)
+	cfgSnapshotOutFlags = append(cfgSnapshotOutFlags,
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "Output file (stdout if not given)",
+		},
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "State sync point to export state for (default: the latest one with validated state root)",
+		},
+		cli.BoolFlag{
+			Name:  "skip-blocks",
+			Usage: "Don't include blocks required for the state jump, they're fetched from the network then",
+		},
+	)
+	var cfgSnapshotInFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgSnapshotInFlags, cfgFlags)
+	cfgSnapshotInFlags = append(cfgSnapshotInFlags,
+		cli.StringFlag{
+			Name:  "in, i",
+			Usage: "Input file (stdin if not given)",
+		},
+	)
+	var cfgExportFlags = make([]cli.Flag, len(cfgWithCountFlags))
+	copy(cfgExportFlags, cfgWithCountFlags)
+	cfgExportFlags = append(cfgExportFlags,
+		cli.UintFlag{
+			Name:  "start, s",
+			Usage: "block number to start from for a new export (default: 0)",
+		},
+		cli.StringFlag{
+			Name:     "out, o",
+			Usage:    "Output directory",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: chaindump.ExportFormatNDJSON,
+			Usage: "Export format: 'ndjson' or 'csv'",
+		},
+		cli.StringSliceFlag{
+			Name:  "dataset",
+			Usage: "Dataset to export (can be specified multiple times, default: all): " + strings.Join(chaindump.ExportDatasets, ", "),
+		},
+		cli.UintFlag{
+			Name:  "partition-size",
+			Value: chaindump.DefaultExportPartitionSize,
+			Usage: "Number of blocks per file",
+		},
+		cli.UintFlag{
+			Name:  "workers",
+			Value: 4,
+			Usage: "Number of partitions exported in parallel",
+		},
+	)
+	var nodeFlags = make([]cli.Flag, len(cfgFlags))
+	copy(nodeFlags, cfgFlags)
+	nodeFlags = append(nodeFlags,
+		cli.BoolFlag{
+			Name:  "light",
+			Usage: "Run in light client mode (sync and verify headers only, see LightClient configuration section)",
+		},
+		cli.BoolFlag{
+			Name:  "dev",
+			Usage: "Run single-node in-memory developer chain with on-demand block production",
+		},
+		cli.StringFlag{
+			Name:  "dev-wallet",
+			Value: "dev-wallet.json",
+			Usage: "Developer chain wallet (created with prefunded accounts if missing)",
+		},
+		cli.UintFlag{
+			Name:  "dev-accounts",
+			Value: 10,
+			Usage: "Number of prefunded accounts in the created developer chain wallet",
+		},
+	)
 	return []cli.Command{
 		{
 			Name:      "node",
 			Usage:     "start a NeoGo node",
-			UsageText: "neo-go node [--config-path path] [-d] [-p/-m/-t] [--config-file file]",
+			UsageText: "neo-go node [--config-path path] [-d] [-p/-m/-t] [--config-file file] [--light | --dev [--dev-wallet file] [--dev-accounts N]]",
 			Action:    startServer,
-			Flags:     cfgFlags,
+			Flags:     nodeFlags,
 		},
 		{
 			Name:  "db",
@@ -96,7 +192,7 @@ func NewCommands() []cli.Command {
 				{
 					Name:      "dump",
 					Usage:     "dump blocks (starting with block #1) to the file",
-					UsageText: "neo-go db dump -o file [-s start] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
+					UsageText: "neo-go db dump -o file [-s start] [-c count] [--format format] [--chunk-size size] [--config-path path] [-p/-m/-t] [--config-file file]",
 					Action:    dumpDB,
 					Flags:     cfgCountOutFlags,
 				},
@@ -104,8 +200,32 @@ func NewCommands() []cli.Command {
 					Name:      "restore",
 					Usage:     "restore blocks from the file",
 					UsageText: "neo-go db restore -i file [--dump] [-n] [-c count] [--config-path path] [-p/-m/-t] [--config-file file]",
-					Action:    restoreDB,
-					Flags:     cfgCountInFlags,
+					Description: `Restore blocks from the dump file. Both legacy and indexed dump formats
+   are supported (format is detected automatically). If the DB already contains
+   some blocks, the restore continues from the current height, so interrupted
+   restores can be resumed. Indexed dumps have their start height in the header
+   (-n is not needed for them), allow to skip directly to the required block and
+   are checked for integrity.
+`,
+					Action: restoreDB,
+					Flags:  cfgCountInFlags,
+				},
+				{
+					Name:      "export",
+					Usage:     "export chain data to NDJSON or CSV files for analytics",
+					UsageText: "neo-go db export -o dir [-s start] [-c count] [--format format] [--dataset name ...] [--partition-size size] [--workers N] [--config-path path] [-p/-m/-t] [--config-file file]",
+					Description: `Export blocks, transactions, signers, application logs, notifications and
+   NEP-17/NEP-11 transfers from the local DB to the output directory. Every
+   dataset is stored in its own subdirectory, files are partitioned by block
+   height ranges aligned to the partition size. Complete files only appear
+   when the whole partition is exported. The resume point is stored in the
+   export.json file of the output directory, so an interrupted (or finished)
+   export can be continued with the same command (it starts from the resume
+   point then, start and count only define the last block to export). Format,
+   datasets and partition size can't be changed for the same directory.
+`,
+					Action: exportDB,
+					Flags:  cfgExportFlags,
 				},
 				{
 					Name:      "reset",
@@ -114,6 +234,41 @@ func NewCommands() []cli.Command {
 					Action:    resetDB,
 					Flags:     cfgHeightFlags,
 				},
+				{
+					Name:  "snapshot",
+					Usage: "state snapshot export and import",
+					Subcommands: []cli.Command{
+						{
+							Name:      "export",
+							Usage:     "export state at the state sync point to the file",
+							UsageText: "neo-go db snapshot export [-o file] [--height height] [--skip-blocks] [--config-path path] [-p/-m/-t] [--config-file file]",
+							Description: `Export contract storage and MPT at the state sync point with validated
+   state root together with headers and blocks required for the state jump.
+   The latest state sync point with validated state root available is used by
+   default. State sync must be enabled by the protocol configuration
+   (StateRootInHeader and P2PStateExchangeExtensions), but the node itself
+   may be an archival one.
+`,
+							Action: exportSnapshot,
+							Flags:  cfgSnapshotOutFlags,
+						},
+						{
+							Name:      "import",
+							Usage:     "bootstrap an empty database from the snapshot file",
+							UsageText: "neo-go db snapshot import [-i file] [--config-path path] [-p/-m/-t] [--config-file file]",
+							Description: `Import headers, contract storage and MPT from the snapshot file into an
+   empty database and jump to the snapshot state. Headers are verified, MPT is
+   checked against the state root from the headers and this state root is
+   checked to be signed by the state validators designated in it. The node
+   must have P2PStateExchangeExtensions and RemoveUntraceableBlocks enabled.
+   If the snapshot doesn't contain blocks, the state jump is performed after
+   the node fetches them from the network.
+`,
+							Action: importSnapshot,
+							Flags:  cfgSnapshotInFlags,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -170,6 +325,10 @@ func dumpDB(ctx *cli.Context) error {
 	}
 	count := uint32(ctx.Uint("count"))
 	start := uint32(ctx.Uint("start"))
+	format := ctx.String("format")
+	if format != "" && format != dumpFormatLegacy && format != dumpFormatIndexed {
+		return cli.NewExitError(fmt.Errorf("unknown dump format: %s", format), 1)
+	}
 
 	var outStream = os.Stdout
 	if out := ctx.String("out"); out != "" {
@@ -198,17 +357,66 @@ func dumpDB(ctx *cli.Context) error {
 	if count == 0 {
 		count = chainCount - start
 	}
-	if start != 0 {
-		writer.WriteU32LE(start)
+	if format == dumpFormatIndexed {
+		err = chaindump.DumpIndexed(chain, outStream, start, count, uint32(ctx.Uint("chunk-size")))
+	} else {
+		if start != 0 {
+			writer.WriteU32LE(start)
+		}
+		writer.WriteU32LE(count)
+		err = chaindump.Dump(chain, writer, start, count)
 	}
-	writer.WriteU32LE(count)
-	err = chaindump.Dump(chain, writer, start, count)
 	if err != nil {
 		return cli.NewExitError(err.Error(), 1)
 	}
 	return nil
 }
 
+func exportDB(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	opts := chaindump.ExportOptions{
+		Dir:           ctx.String("out"),
+		Format:        ctx.String("format"),
+		Datasets:      ctx.StringSlice("dataset"),
+		Start:         uint32(ctx.Uint("start")),
+		Count:         uint32(ctx.Uint("count")),
+		PartitionSize: uint32(ctx.Uint("partition-size")),
+		Workers:       int(ctx.Uint("workers")),
+		Progress: func(from, to uint32) {
+			log.Info("exported blocks", zap.Uint32("from", from), zap.Uint32("to", to))
+		},
+	}
+	err = chaindump.Export(newGraceContext(), chain, opts)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to export chain data: %w", err), 1)
+	}
+	return nil
+}
+
 func restoreDB(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -234,7 +442,22 @@ func restoreDB(ctx *cli.Context) error {
 		}
 	}
 	defer inStream.Close()
-	reader := io.NewBinReaderFromIO(inStream)
+	in, indexed, err := chaindump.Detect(inStream)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to read dump: %w", err), 1)
+	}
+	var (
+		reader   *io.BinReader
+		idReader *chaindump.IndexedReader
+	)
+	if indexed {
+		idReader, err = chaindump.NewIndexedReader(in)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to read dump: %w", err), 1)
+		}
+	} else {
+		reader = io.NewBinReaderFromIO(in)
+	}
 
 	dumpDir := ctx.String("dump")
 	if dumpDir != "" {
@@ -252,12 +475,15 @@ func restoreDB(ctx *cli.Context) error {
 	}()
 
 	var start uint32
-	if ctx.Bool("incremental") {
+	if indexed {
+		// Indexed dump header always contains the start.
+		start = idReader.Header().Start
+	} else if ctx.Bool("incremental") {
 		start = reader.ReadU32LE()
-		if chain.BlockHeight()+1 < start {
-			return cli.NewExitError(fmt.Errorf("expected height: %d, dump starts at %d",
-				chain.BlockHeight()+1, start), 1)
-		}
+	}
+	if chain.BlockHeight()+1 < start {
+		return cli.NewExitError(fmt.Errorf("expected height: %d, dump starts at %d",
+			chain.BlockHeight()+1, start), 1)
 	}
 
 	var skip uint32
@@ -265,9 +491,14 @@ func restoreDB(ctx *cli.Context) error {
 		skip = chain.BlockHeight() + 1 - start
 	}
 
-	var allBlocks = reader.ReadU32LE()
-	if reader.Err != nil {
-		return cli.NewExitError(err, 1)
+	var allBlocks uint32
+	if indexed {
+		allBlocks = idReader.Header().Count
+	} else {
+		allBlocks = reader.ReadU32LE()
+		if reader.Err != nil {
+			return cli.NewExitError(err, 1)
+		}
 	}
 	if skip+count > allBlocks {
 		return cli.NewExitError(fmt.Errorf("input file has only %d blocks, can't read %d starting from %d", allBlocks, count, skip), 1)
@@ -279,7 +510,8 @@ func restoreDB(ctx *cli.Context) error {
 		zap.Uint32("start", start),
 		zap.Uint32("height", chain.BlockHeight()),
 		zap.Uint32("skip", skip),
-		zap.Uint32("count", count))
+		zap.Uint32("count", count),
+		zap.Bool("indexed", indexed))
 
 	gctx := newGraceContext()
 	var lastIndex uint32
@@ -319,7 +551,11 @@ func restoreDB(ctx *cli.Context) error {
 		}
 	}
 
-	err = chaindump.Restore(chain, reader, skip, count, f)
+	if indexed {
+		err = idReader.Restore(chain, start+skip, count, f)
+	} else {
+		err = chaindump.Restore(chain, reader, skip, count, f)
+	}
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -359,6 +595,109 @@ func resetDB(ctx *cli.Context) error {
 	return nil
 }
 
+func exportSnapshot(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	p := uint32(ctx.Uint("height"))
+	if p == 0 {
+		interval := uint32(chain.GetConfig().StateSyncInterval)
+		if interval == 0 {
+			return cli.NewExitError(errors.New("state synchronisation is disabled by the protocol configuration"), 1)
+		}
+		h := chain.GetStateModule().CurrentValidatedHeight()
+		if h >= chain.BlockHeight() && chain.BlockHeight() > 0 {
+			h = chain.BlockHeight() - 1
+		}
+		p = h / interval * interval
+	}
+
+	var outStream = os.Stdout
+	if out := ctx.String("out"); out != "" {
+		outStream, err = os.Create(out)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	defer outStream.Close()
+
+	log.Info("exporting state snapshot", zap.Uint32("state sync point", p))
+	err = chaindump.DumpSnapshot(chain, outStream, p, !ctx.Bool("skip-blocks"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to export snapshot: %w", err), 1)
+	}
+	return nil
+}
+
+func importSnapshot(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, _, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	var inStream = os.Stdin
+	if in := ctx.String("in"); in != "" {
+		inStream, err = os.Open(in)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	defer inStream.Close()
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	p, err := chaindump.RestoreSnapshot(chain, inStream)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to import snapshot: %w", err), 1)
+	}
+	if chain.BlockHeight() != p {
+		log.Info("state snapshot imported, blocks are to be fetched from the network", zap.Uint32("state sync point", p))
+	} else {
+		log.Info("state snapshot imported", zap.Uint32("state sync point", p))
+	}
+	return nil
+}
+
 // oracleService is an interface representing Oracle service with network.Service
 // capabilities and ability to submit oracle responses.
 type oracleService interface {
@@ -457,11 +796,28 @@ func startServer(ctx *cli.Context) error {
 	grace, cancel := context.WithCancel(newGraceContext())
 	defer cancel()
 
+	if ctx.Bool("light") && ctx.Bool("dev") {
+		return cli.NewExitError(errors.New("--light and --dev can't be used together"), 1)
+	}
+	if ctx.Bool("light") {
+		return startLightClient(grace, cfg, log)
+	}
+	if ctx.Bool("dev") {
+		return startDevNode(ctx, grace, cfg, log)
+	}
+
 	serverConfig, err := network.NewServerConfig(cfg)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
+	tracer, err := tracing.New(cfg.ApplicationConfiguration.Tracing, log)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't initialize Tracing service: %w", err), 1)
+	}
+	tracer.Start()
+	defer func() { tracer.ShutDown() }()
+
 	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
 	if err != nil {
 		return cli.NewExitError(err, 1)
@@ -576,6 +932,14 @@ Main:
 					shutdownErr = fmt.Errorf("failed to start Prometheus service: %w", err)
 					cancel() // Fatal error, like for RPC server.
 				}
+				tracer.ShutDown()
+				if t, err := tracing.New(cfgnew.ApplicationConfiguration.Tracing, log); err != nil {
+					shutdownErr = fmt.Errorf("can't initialize Tracing service: %w", err)
+					cancel() // Fatal error, like for RPC server.
+				} else {
+					tracer = t
+					tracer.Start()
+				}
 			case sigusr1:
 				if oracleSrv != nil {
 					serv.DelService(oracleSrv)
@@ -688,6 +1052,30 @@ func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, stora
 	return chain, store, nil
 }
 
+// startLightClient runs the node in the light client mode until the grace
+// context is done.
+func startLightClient(grace context.Context, cfg config.Config, log *zap.Logger) error {
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
+	}
+	defer func() { _ = store.Close() }()
+
+	lcCfg := cfg.ApplicationConfiguration.LightClient
+	clients, err := lightclient.Dial(grace, lcCfg)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	lc, err := lightclient.New(cfg.ProtocolConfiguration, lcCfg, clients, store, log)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to create light client: %w", err), 1)
+	}
+	lc.Start()
+	<-grace.Done()
+	lc.Shutdown()
+	return nil
+}
+
 // Logo returns NeoGo logo.
 func Logo() string {
 	return `
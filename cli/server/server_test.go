@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"flag"
 	"os"
 	"path/filepath"
@@ -414,3 +415,30 @@ func TestResetDB(t *testing.T) {
 	err = resetDB(ctx)
 	require.NoError(t, err)
 }
+
+func TestConfigureDevNode(t *testing.T) {
+	cfg, err := config.Load(filepath.Join("..", "..", "config"), netmode.PrivNet)
+	require.NoError(t, err)
+	w, err := getDevWallet(filepath.Join(t.TempDir(), "dev.json"), 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(w.Accounts))
+	pub := w.Accounts[0].PublicKey()
+
+	configureDevNode(&cfg, pub)
+	require.NoError(t, cfg.ProtocolConfiguration.Validate())
+	require.Equal(t, []string{hex.EncodeToString(pub.Bytes())}, cfg.ProtocolConfiguration.StandbyCommittee)
+	require.Equal(t, uint32(1), cfg.ProtocolConfiguration.ValidatorsCount)
+	require.Nil(t, cfg.ProtocolConfiguration.SeedList)
+	require.Equal(t, dbconfig.InMemoryDB, cfg.ApplicationConfiguration.DBConfiguration.Type)
+	require.Equal(t, 0, cfg.ApplicationConfiguration.P2P.MinPeers)
+	require.False(t, cfg.ApplicationConfiguration.Consensus.Enabled)
+	require.True(t, cfg.ApplicationConfiguration.RPC.Enabled)
+
+	w2, err := getDevWallet(w.Path(), 5) // Existing wallet is reused.
+	require.NoError(t, err)
+	require.Equal(t, 2, len(w2.Accounts))
+	require.True(t, pub.Equal(w2.Accounts[0].PublicKey()))
+
+	_, err = getDevWallet(filepath.Join(t.TempDir(), "big.json"), maxDevAccounts+1)
+	require.Error(t, err)
+}
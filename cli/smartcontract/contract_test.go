@@ -290,6 +290,12 @@ func TestContractInitAndCompile(t *testing.T) {
 		e.CheckNextLine(t, "^[0-9a-hA-H]+$")
 	})
 
+	t.Run("optimized", func(t *testing.T) {
+		e.Run(t, append(cmd, "-O")...)
+		e.CheckEOF(t)
+		require.FileExists(t, nefPath)
+	})
+
 	t.Run("autocomplete outputs", func(t *testing.T) {
 		cfg, err := os.ReadFile(cfgPath)
 		require.NoError(t, err)
@@ -16,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/cli/txctx"
 	cliwallet "github.com/nspcc-dev/neo-go/cli/wallet"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -30,6 +31,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"github.com/urfave/cli"
 	"gopkg.in/yaml.v3"
 )
@@ -126,14 +128,16 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "compile",
 				Usage:     "compile a smart contract to a .nef file",
-				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--no-standards] [--no-events] [--no-permissions] [--guess-eventtypes]",
+				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--no-standards] [--no-events] [--no-permissions] [--guess-eventtypes] [-O]",
 				Description: `Compiles given smart contract to a .nef file and emits other associated
    information (manifest, bindings configuration, debug information files) if
    asked to. If none of --out, --manifest, --config, --bindings flags are specified,
    then the output filenames for these flags will be guessed using the contract
    name or path provided via --in option by trimming/adding corresponding suffixes
    to the common part of the path. In the latter case the configuration filepath
-   will be guessed from the --in option using the same rule."`,
+   will be guessed from the --in option using the same rule. Bytecode
+   optimizations can be enabled with --optimize (-O) flag, they make contract
+   smaller and cheaper to execute, debug information is adjusted accordingly."`,
 				Action: contractCompile,
 				Flags: []cli.Flag{
 					cli.StringFlag{
@@ -176,6 +180,10 @@ func NewCommands() []cli.Command {
 						Name:  "guess-eventtypes",
 						Usage: "guess event types for smart-contract bindings configuration from the code usages",
 					},
+					cli.BoolFlag{
+						Name:  "optimize, O",
+						Usage: "optimize the bytecode (constant folding, dead code and unused variables elimination)",
+					},
 					cli.StringFlag{
 						Name:  "bindings",
 						Usage: "output file for smart-contract bindings configuration",
@@ -456,6 +464,7 @@ func contractCompile(ctx *cli.Context) error {
 		NoPermissionsCheck: ctx.Bool("no-permissions"),
 
 		GuessEventTypes: ctx.Bool("guess-eventtypes"),
+		Optimize:        ctx.Bool("optimize"),
 	}
 
 	if len(confFile) != 0 {
@@ -768,17 +777,20 @@ func getAccFromContext(ctx *cli.Context) (*wallet.Account, *wallet.Wallet, error
 	if len(wPath) == 0 && len(walletConfigPath) == 0 {
 		return nil, nil, errNoWallet
 	}
-	var pass *string
+	var (
+		pass *string
+		cfg  = &config.Wallet{Path: wPath}
+	)
 	if len(walletConfigPath) != 0 {
-		cfg, err := cliwallet.ReadWalletConfig(walletConfigPath)
+		var err error
+		cfg, err = cliwallet.ReadWalletConfig(walletConfigPath)
 		if err != nil {
 			return nil, nil, err
 		}
-		wPath = cfg.Path
 		pass = &cfg.Password
 	}
 
-	wall, err := wallet.NewWalletFromFile(wPath)
+	wall, err := extsigner.OpenWallet(*cfg)
 	if err != nil {
 		return nil, nil, err
 	}
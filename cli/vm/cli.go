@@ -32,6 +32,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
@@ -43,6 +44,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
@@ -54,11 +56,21 @@ const (
 	chainCfgKey         = "chainCfg"
 	icKey               = "ic"
 	contractStateKey    = "contractState"
+	debugInfoKey        = "debugInfo"
 	exitFuncKey         = "exitFunc"
 	readlineInstanceKey = "readlineKey"
 	printLogoKey        = "printLogoKey"
+	historyPosKey       = "historyPos"
+	replayKey           = "replay"
+	profilingKey        = "profiling"
+	tracingKey          = "tracing"
 )
 
+// defaultTraceLimit is the maximum number of instructions recorded in the
+// execution history, it keeps the memory used by tracing reasonable for long
+// scripts.
+const defaultTraceLimit = 100_000
+
 // Various flag names.
 const (
 	verboseFlagFullName   = "verbose"
@@ -67,6 +79,8 @@ const (
 	backwardsFlagFullName = "backwards"
 	diffFlagFullName      = "diff"
 	hashFlagFullName      = "hash"
+	topFlagFullName       = "top"
+	pprofFlagFullName     = "pprof"
 )
 
 var (
@@ -320,6 +334,59 @@ Example:
 > stepover`,
 		Action: handleStepOver,
 	},
+	{
+		Name:      "stepback",
+		Usage:     "Step (n) instructions back in the execution history",
+		UsageText: `stepback [<n>]`,
+		Description: `<n> is optional parameter to specify number of instructions to step back.
+
+If tracing is enabled (see 'trace' command), every instruction executed is
+recorded, so it's possible to get back in the execution history and inspect the state of the evaluation stack and slots
+('ip', 'estack', 'sslot', 'lslot' and 'aslot' commands show the historic state
+then). Stepping commands move forward in the history until the current state is
+reached, 'cont' and 'run' return to the current state immediately. The VM itself
+is not affected by this command, invocation stack, storage and events always
+show the current state.
+
+Example:
+> stepback 10`,
+		Action: handleStepBack,
+	},
+	{
+		Name:      "trace",
+		Usage:     "Enable, disable or dump the execution trace of the current loaded program",
+		UsageText: `trace [on|off|<file>]`,
+		Description: `Tracing is disabled by default, 'on' enables it for the current and all
+subsequently loaded programs, 'off' disables it. At most 100000 instructions
+are recorded for every program, the trace is marked as truncated if more are
+executed. Without arguments the command dumps the execution trace of the
+current loaded program in JSON format to the standard output, the trace is
+written to the file if its name is specified. The trace contains every executed
+instruction with the changes it made to the evaluation stack and slots. It can
+be replayed later with the 'replay' command.
+
+Example:
+> trace on
+> trace failed.json`,
+		Action: handleTrace,
+	},
+	{
+		Name:      "replay",
+		Usage:     "Replay the execution trace from the file for the current loaded program",
+		UsageText: `replay <file>`,
+		Description: `Make the subsequent execution of the current loaded program follow the trace
+from the file (dumped with the 'trace' command). Every instruction executed is
+checked to match the recorded one and SYSCALL/CALLT instructions are not
+executed, their recorded results are used instead, so the execution doesn't
+depend on the chain state. The program should be loaded and run the same way it
+was when the trace was recorded. Loading another program disables replay.
+
+Example:
+> loadtx 0x36ed0e0b3e5e1ea5b2a50f4f0f38e5d5d1bad4fb1f9e8b03f5b4e9ba6bd1a221
+> replay failed.json
+> run`,
+		Action: handleReplay,
+	},
 	{
 		Name:        "ops",
 		Usage:       "Dump opcodes of the current loaded program",
@@ -390,6 +457,34 @@ Example:
 > changes 0x0000000009070e030d0f0e020d0c06050e030c02 030e`,
 		Action: handleChanges,
 	},
+	{
+		Name:      "profile",
+		Usage:     "Enable, disable or show gas profile of the current loaded program execution",
+		UsageText: `profile [on|off] [--top <n>] [--pprof <file>]`,
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  topFlagFullName + ",n",
+				Usage: "Number of entries to show for every table (all by default)",
+			},
+			cli.StringFlag{
+				Name:  pprofFlagFullName,
+				Usage: "Write gzipped pprof profile to the specified file",
+			},
+		},
+		Description: `Profiling is disabled by default, 'on' enables it for the current and all
+subsequently loaded programs, 'off' disables it. Without arguments the command
+shows GAS spent and the number of instructions executed per function, syscall and
+opcode by the current loaded program so far (since profiling is enabled). Functions are resolved via debug info
+for contracts loaded with 'loadgo' command and via manifests for other contracts,
+unresolved ones are shown as script hashes. Every entry contains its own (self) cost.
+The profile can also be written to the file in pprof format to be analyzed with
+'go tool pprof'.
+
+Example:
+> profile on
+> profile --top 10 --pprof gas.pb.gz`,
+		Action: handleProfile,
+	},
 }
 
 var completer *readline.PrefixCompleter
@@ -413,6 +508,9 @@ func init() {
 var (
 	ErrMissingParameter = errors.New("missing argument")
 	ErrInvalidParameter = errors.New("can't parse argument")
+
+	errHistoryMode     = errors.New("not available in the execution history, use 'cont' to return to the current state")
+	errTracingDisabled = errors.New("tracing is disabled, use 'trace on' to enable it")
 )
 
 // CLI object for interacting with the VM.
@@ -424,6 +522,13 @@ type CLI struct {
 // NewWithConfig returns new CLI instance using provided config and (optionally)
 // provided node config for state-backed VM.
 func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg config.Config) (*CLI, error) {
+	return NewWithConfigAndStore(printLogotype, onExit, c, cfg, nil)
+}
+
+// NewWithConfigAndStore is similar to NewWithConfig, but allows to override
+// the store specified in the node config (it's used if the store given is nil),
+// like with fork.Store to use the state of some remote chain.
+func NewWithConfigAndStore(printLogotype bool, onExit func(int), c *readline.Config, cfg config.Config, store storage.Store) (*CLI, error) {
 	if c.AutoComplete == nil {
 		// Autocomplete commands/flags on TAB.
 		c.AutoComplete = completer
@@ -450,11 +555,13 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 
 	ctl.Commands = commands
 
-	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
-	if err != nil {
-		writeErr(ctl.ErrWriter, fmt.Errorf("failed to open DB, clean in-memory storage will be used: %w", err))
-		cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.InMemoryDB
-		store = storage.NewMemoryStore()
+	if store == nil {
+		store, err = storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+		if err != nil {
+			writeErr(ctl.ErrWriter, fmt.Errorf("failed to open DB, clean in-memory storage will be used: %w", err))
+			cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.InMemoryDB
+			store = storage.NewMemoryStore()
+		}
 	}
 
 	log, _, logCloser, err := options.HandleLoggingParams(false, cfg.ApplicationConfiguration)
@@ -497,9 +604,14 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 		chainCfgKey:         cfg,
 		icKey:               ic,
 		contractStateKey:    new(state.ContractBase),
+		debugInfoKey:        (*compiler.DebugInfo)(nil),
 		exitFuncKey:         exitF,
 		readlineInstanceKey: l,
 		printLogoKey:        printLogotype,
+		historyPosKey:       -1,
+		replayKey:           (*vm.Trace)(nil),
+		profilingKey:        false,
+		tracingKey:          false,
 	}
 	changePrompt(vmcli.shell)
 	return &vmcli, nil
@@ -525,6 +637,14 @@ func getChainConfigFromContext(app *cli.App) config.Config {
 	return app.Metadata[chainCfgKey].(config.Config)
 }
 
+func isProfilingEnabled(app *cli.App) bool {
+	return app.Metadata[profilingKey].(bool)
+}
+
+func isTracingEnabled(app *cli.App) bool {
+	return app.Metadata[tracingKey].(bool)
+}
+
 func getInteropContextFromContext(app *cli.App) *interop.Context {
 	return app.Metadata[icKey].(*interop.Context)
 }
@@ -533,10 +653,32 @@ func getContractStateFromContext(app *cli.App) *state.ContractBase {
 	return app.Metadata[contractStateKey].(*state.ContractBase)
 }
 
+func getDebugInfoFromContext(app *cli.App) *compiler.DebugInfo {
+	return app.Metadata[debugInfoKey].(*compiler.DebugInfo)
+}
+
 func getPrintLogoFromContext(app *cli.App) bool {
 	return app.Metadata[printLogoKey].(bool)
 }
 
+// getHistoryPosFromContext returns the position in the execution history the
+// user has stepped back to, it's negative if the current state is shown.
+func getHistoryPosFromContext(app *cli.App) int {
+	return app.Metadata[historyPosKey].(int)
+}
+
+func getReplayFromContext(app *cli.App) *vm.Trace {
+	return app.Metadata[replayKey].(*vm.Trace)
+}
+
+func setHistoryPosInContext(app *cli.App, pos int) {
+	app.Metadata[historyPosKey] = pos
+}
+
+func setReplayInContext(app *cli.App, tr *vm.Trace) {
+	app.Metadata[replayKey] = tr
+}
+
 func setInteropContextInContext(app *cli.App, ic *interop.Context) {
 	app.Metadata[icKey] = ic
 }
@@ -545,6 +687,10 @@ func setContractStateInContext(app *cli.App, cs *state.ContractBase) {
 	app.Metadata[contractStateKey] = cs
 }
 
+func setDebugInfoInContext(app *cli.App, di *compiler.DebugInfo) {
+	app.Metadata[debugInfoKey] = di
+}
+
 func checkVMIsReady(app *cli.App) bool {
 	v := getVMFromContext(app)
 	if v == nil || !v.Ready() {
@@ -565,6 +711,11 @@ func handleExit(c *cli.Context) error {
 }
 
 func handleIP(c *cli.Context) error {
+	if step, _, ok := getHistoryState(c.App); ok {
+		fmt.Fprintf(c.App.Writer, "instruction pointer at %d (%s), history step %d of %d\n",
+			step.Offset, step.Opcode, getHistoryPosFromContext(c.App)+1, getInteropContextFromContext(c.App).Tracer.Len())
+		return nil
+	}
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
@@ -603,6 +754,9 @@ func handleJump(c *cli.Context) error {
 		return err
 	}
 
+	if getHistoryPosFromContext(c.App) >= 0 {
+		return errHistoryMode
+	}
 	v := getVMFromContext(c.App)
 	v.Context().Jump(n)
 	fmt.Fprintf(c.App.Writer, "jumped to instruction %d\n", n)
@@ -623,11 +777,19 @@ func getInstructionParameter(c *cli.Context) (int, error) {
 
 func handleXStack(c *cli.Context) error {
 	v := getVMFromContext(c.App)
+	_, st, historic := getHistoryState(c.App)
 	var stackDump string
 	switch c.Command.Name {
 	case "estack":
-		stackDump = v.DumpEStack()
+		if historic {
+			stackDump = dumpTraceItems(st.EStack)
+		} else {
+			stackDump = v.DumpEStack()
+		}
 	case "istack":
+		if historic {
+			return errHistoryMode
+		}
 		stackDump = v.DumpIStack()
 	default:
 		return errors.New("unknown stack")
@@ -639,17 +801,30 @@ func handleXStack(c *cli.Context) error {
 func handleSlots(c *cli.Context) error {
 	v := getVMFromContext(c.App)
 	vmCtx := v.Context()
-	if vmCtx == nil {
+	_, st, historic := getHistoryState(c.App)
+	if vmCtx == nil && !historic {
 		return errors.New("no program loaded")
 	}
 	var rawSlot string
 	switch c.Command.Name {
 	case "sslot":
-		rawSlot = vmCtx.DumpStaticSlot()
+		if historic {
+			rawSlot = dumpTraceItems(st.Static)
+		} else {
+			rawSlot = vmCtx.DumpStaticSlot()
+		}
 	case "lslot":
-		rawSlot = vmCtx.DumpLocalSlot()
+		if historic {
+			rawSlot = dumpTraceItems(st.Local)
+		} else {
+			rawSlot = vmCtx.DumpLocalSlot()
+		}
 	case "aslot":
-		rawSlot = vmCtx.DumpArgumentsSlot()
+		if historic {
+			rawSlot = dumpTraceItems(st.Arguments)
+		} else {
+			rawSlot = vmCtx.DumpArgumentsSlot()
+		}
 	default:
 		return errors.New("unknown slot")
 	}
@@ -877,6 +1052,7 @@ func handleLoadGo(c *cli.Context) error {
 		Manifest: *m,
 	}
 	setContractStateInContext(c.App, cs)
+	setDebugInfoInContext(c.App, di)
 
 	v := getVMFromContext(c.App)
 	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions\n", v.Context().LenInstr())
@@ -1022,6 +1198,12 @@ func resetInteropContext(app *cli.App, tx *transaction.Transaction, height ...ui
 			return fmt.Errorf("failed to create VM: %w", err)
 		}
 	}
+	if isProfilingEnabled(app) {
+		enableProfiler(newIc)
+	}
+	if isTracingEnabled(app) {
+		enableTracer(newIc)
+	}
 	if tx != nil {
 		newIc.VM.LoadWithFlags(tx.Script, callflag.All)
 	}
@@ -1030,9 +1212,39 @@ func resetInteropContext(app *cli.App, tx *transaction.Transaction, height ...ui
 	return nil
 }
 
+// enableProfiler enables gas profiling for the VM bound to the interop context
+// (and the ones reusing it).
+func enableProfiler(ic *interop.Context) {
+	ic.Profiler = vm.NewProfiler()
+	ic.VM.SetProfiler(ic.Profiler)
+}
+
+// disableProfiler disables gas profiling for the VM bound to the interop
+// context.
+func disableProfiler(ic *interop.Context) {
+	ic.Profiler = nil
+	ic.VM.SetProfiler(nil)
+}
+
+// enableTracer enables execution tracing for the VM bound to the interop
+// context (and the ones reusing it).
+func enableTracer(ic *interop.Context) {
+	ic.Tracer = vm.NewTracer()
+	ic.Tracer.SetLimit(defaultTraceLimit)
+	ic.VM.SetTracer(ic.Tracer)
+}
+
+// disableTracer disables execution tracing for the VM bound to the interop
+// context.
+func disableTracer(ic *interop.Context) {
+	ic.Tracer = nil
+	ic.VM.SetTracer(nil)
+}
+
 // resetContractState removes loaded contract state from app context.
 func resetContractState(app *cli.App) {
 	setContractStateInContext(app, nil)
+	setDebugInfoInContext(app, nil)
 }
 
 // resetState resets state of the app (clear interop context and manifest) so that it's ready
@@ -1043,6 +1255,8 @@ func resetState(app *cli.App, tx *transaction.Transaction, height ...uint32) err
 		return err
 	}
 	resetContractState(app)
+	setHistoryPosInContext(app, -1)
+	setReplayInContext(app, nil)
 	return nil
 }
 
@@ -1060,6 +1274,7 @@ func getManifestFromFile(name string) (*manifest.Manifest, error) {
 }
 
 func handleRun(c *cli.Context) error {
+	setHistoryPosInContext(c.App, -1)
 	v := getVMFromContext(c.App)
 	cs := getContractStateFromContext(c.App)
 	args := c.Args()
@@ -1104,6 +1319,10 @@ func handleRun(c *cli.Context) error {
 			gasLimit := v.GasLimit
 			breaks := v.Context().BreakPoints() // We ensure that there's a context loaded.
 			ic.ReuseVM(v)
+			if ic.Tracer != nil {
+				ic.Tracer.Reset()
+			}
+			v.SetReplay(getReplayFromContext(c.App))
 			v.GasLimit = gasLimit
 			v.LoadNEFMethod(&cs.NEF, util.Uint160{}, cs.Hash, callflag.All, hasRet, offset, initOff, nil)
 			for _, bp := range breaks {
@@ -1163,6 +1382,13 @@ func runVMWithHandling(c *cli.Context) {
 }
 
 func handleCont(c *cli.Context) error {
+	if getHistoryPosFromContext(c.App) >= 0 {
+		setHistoryPosInContext(c.App, -1)
+		if !getVMFromContext(c.App).Ready() {
+			changePrompt(c.App)
+			return nil
+		}
+	}
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
@@ -1177,10 +1403,6 @@ func handleStep(c *cli.Context) error {
 		err error
 	)
 
-	if !checkVMIsReady(c.App) {
-		return nil
-	}
-	v := getVMFromContext(c.App)
 	args := c.Args()
 	if len(args) > 0 {
 		n, err = strconv.Atoi(args[0])
@@ -1188,6 +1410,14 @@ func handleStep(c *cli.Context) error {
 			return fmt.Errorf("%w: %s", ErrInvalidParameter, err) //nolint:errorlint // errorlint: non-wrapping format verb for fmt.Errorf. Use `%w` to format errors
 		}
 	}
+	if stepHistory(c.App, "", n) {
+		showHistoryStep(c)
+		return nil
+	}
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	v := getVMFromContext(c.App)
 	v.AddBreakPointRel(n)
 	runVMWithHandling(c)
 	changePrompt(c.App)
@@ -1207,6 +1437,10 @@ func handleStepOver(c *cli.Context) error {
 }
 
 func handleStepType(c *cli.Context, stepType string) error {
+	if stepHistory(c.App, stepType, 1) {
+		showHistoryStep(c)
+		return nil
+	}
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
@@ -1228,6 +1462,179 @@ func handleStepType(c *cli.Context, stepType string) error {
 	return nil
 }
 
+func handleStepBack(c *cli.Context) error {
+	var (
+		n   = 1
+		err error
+	)
+
+	args := c.Args()
+	if len(args) > 0 {
+		n, err = strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%w: positive number of instructions expected", ErrInvalidParameter)
+		}
+	}
+	tr := getInteropContextFromContext(c.App).Tracer
+	if tr == nil {
+		return errTracingDisabled
+	}
+	pos := getHistoryPosFromContext(c.App)
+	if pos < 0 {
+		pos = tr.Len()
+	}
+	if pos == 0 {
+		return errors.New("no executed instructions to step back to")
+	}
+	pos -= n
+	if pos < 0 {
+		pos = 0
+	}
+	setHistoryPosInContext(c.App, pos)
+	_ = handleIP(c)
+	changePrompt(c.App)
+	return nil
+}
+
+// stepHistory moves the execution history position forward according to the
+// step type ("into", "over", "out" or "" for plain instruction steps). It
+// returns false if the current state is shown, the history is left when the
+// current state is reached.
+func stepHistory(app *cli.App, stepType string, n int) bool {
+	pos := getHistoryPosFromContext(app)
+	if pos < 0 {
+		return false
+	}
+	steps := getInteropContextFromContext(app).Tracer.Trace().Steps
+	cur := steps[pos]
+	switch stepType {
+	case "over", "out":
+		pos++
+		for ; pos < len(steps); pos++ {
+			st := steps[pos]
+			if st.Level > cur.Level {
+				continue
+			}
+			if st.Level < cur.Level || st.Depth < cur.Depth ||
+				stepType == "over" && st.Depth == cur.Depth {
+				break
+			}
+		}
+	default:
+		pos += n
+	}
+	if pos >= len(steps) {
+		pos = -1
+	}
+	setHistoryPosInContext(app, pos)
+	return true
+}
+
+// showHistoryStep prints the instruction pointer after the move in the
+// execution history.
+func showHistoryStep(c *cli.Context) {
+	if getHistoryPosFromContext(c.App) < 0 {
+		fmt.Fprintln(c.App.Writer, "current state is reached")
+		if !getVMFromContext(c.App).Ready() {
+			changePrompt(c.App)
+			return
+		}
+	}
+	_ = handleIP(c)
+	changePrompt(c.App)
+}
+
+// getHistoryState returns the step and the state before it for the current
+// execution history position, ok is false if the current state is shown.
+func getHistoryState(app *cli.App) (*vm.TraceStep, *vm.TraceState, bool) {
+	pos := getHistoryPosFromContext(app)
+	if pos < 0 {
+		return nil, nil, false
+	}
+	tr := getInteropContextFromContext(app).Tracer.Trace()
+	st, err := tr.StateAt(pos)
+	if err != nil {
+		return nil, nil, false
+	}
+	return &tr.Steps[pos], st, true
+}
+
+// dumpTraceItems returns JSON representation of the recorded stack or slot
+// items.
+func dumpTraceItems(items []json.RawMessage) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	b, _ := json.MarshalIndent(items, "", "    ")
+	return string(b)
+}
+
+func handleTrace(c *cli.Context) error {
+	ic := getInteropContextFromContext(c.App)
+	args := c.Args()
+	switch {
+	case len(args) > 1:
+		return fmt.Errorf("%w: too many arguments", ErrInvalidParameter)
+	case len(args) == 1 && args[0] == "on":
+		if ic.Tracer == nil {
+			enableTracer(ic)
+		}
+		c.App.Metadata[tracingKey] = true
+		return nil
+	case len(args) == 1 && args[0] == "off":
+		disableTracer(ic)
+		c.App.Metadata[tracingKey] = false
+		if getHistoryPosFromContext(c.App) >= 0 {
+			setHistoryPosInContext(c.App, -1)
+			changePrompt(c.App)
+		}
+		return nil
+	}
+	if ic.Tracer == nil {
+		return errTracingDisabled
+	}
+	tr := ic.Tracer.Trace()
+	if len(args) == 0 {
+		b, err := json.MarshalIndent(tr, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal trace: %w", err)
+		}
+		fmt.Fprintln(c.App.Writer, string(b))
+		return nil
+	}
+	b, err := json.Marshal(tr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace: %w", err)
+	}
+	if err := os.WriteFile(args[0], b, 0644); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	fmt.Fprintf(c.App.Writer, "%d steps written to %s\n", len(tr.Steps), args[0])
+	return nil
+}
+
+func handleReplay(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: <file>", ErrMissingParameter)
+	}
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read trace file: %w", err)
+	}
+	tr := new(vm.Trace)
+	if err := json.Unmarshal(b, tr); err != nil {
+		return fmt.Errorf("failed to parse trace file: %w", err)
+	}
+	getVMFromContext(c.App).SetReplay(tr)
+	setReplayInContext(c.App, tr)
+	fmt.Fprintf(c.App.Writer, "replaying %d steps\n", len(tr.Steps))
+	return nil
+}
+
 func handleOps(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
@@ -1242,7 +1649,9 @@ func handleOps(c *cli.Context) error {
 func changePrompt(app *cli.App) {
 	v := getVMFromContext(app)
 	l := getReadlineInstanceFromContext(app)
-	if v.Ready() && v.Context().NextIP() >= 0 && v.Context().NextIP() < v.Context().LenInstr() {
+	if step, _, ok := getHistoryState(app); ok {
+		l.SetPrompt(fmt.Sprintf("\033[33mNEO-GO-VM %d (history) >\033[0m ", step.Offset))
+	} else if v.Ready() && v.Context().NextIP() >= 0 && v.Context().NextIP() < v.Context().LenInstr() {
 		l.SetPrompt(fmt.Sprintf("\033[32mNEO-GO-VM %d >\033[0m ", v.Context().NextIP()))
 	} else {
 		l.SetPrompt("\033[32mNEO-GO-VM >\033[0m ")
@@ -1342,6 +1751,71 @@ func handleChanges(c *cli.Context) error {
 	return nil
 }
 
+func handleProfile(c *cli.Context) error {
+	ic := getInteropContextFromContext(c.App)
+	switch args := c.Args(); {
+	case len(args) > 1:
+		return fmt.Errorf("%w: too many arguments", ErrInvalidParameter)
+	case len(args) == 1 && args[0] == "on":
+		if ic.Profiler == nil {
+			enableProfiler(ic)
+		}
+		c.App.Metadata[profilingKey] = true
+		return nil
+	case len(args) == 1 && args[0] == "off":
+		disableProfiler(ic)
+		c.App.Metadata[profilingKey] = false
+		return nil
+	case len(args) == 1:
+		return fmt.Errorf("%w: 'on' or 'off' expected", ErrInvalidParameter)
+	}
+	if ic.Profiler == nil {
+		return errors.New("profiling is disabled, use 'profile on' to enable it")
+	}
+	var (
+		samples = ic.Profiler.Samples()
+		res     profile.Resolvers
+		seen    = make(map[util.Uint160]bool)
+	)
+	if cs := getContractStateFromContext(c.App); cs != nil {
+		// Loaded script can be executed either as is or as a contract.
+		di := getDebugInfoFromContext(c.App)
+		for _, h := range []util.Uint160{cs.Hash, hash.Hash160(cs.NEF.Script)} {
+			if di != nil {
+				res = append(res, profile.NewDebugInfoResolver(h, di))
+			}
+			res = append(res, profile.NewManifestResolver(h, &cs.Manifest))
+			seen[h] = true
+		}
+	}
+	for _, s := range samples {
+		for _, f := range s.Stack {
+			if seen[f.ScriptHash] {
+				continue
+			}
+			seen[f.ScriptHash] = true
+			if cs, err := ic.GetContract(f.ScriptHash); err == nil {
+				res = append(res, profile.NewManifestResolver(f.ScriptHash, &cs.Manifest))
+			}
+		}
+	}
+
+	if name := c.String(pprofFlagFullName); name != "" {
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to create pprof file: %w", err)
+		}
+		err = profile.WritePprof(f, samples, res)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write pprof file: %w", err)
+		}
+	}
+	return profile.NewReport(samples, res).Print(c.App.Writer, c.Int(topFlagFullName))
+}
+
 // getDumpArgs is a helper function that retrieves contract ID and search prefix (if given).
 func getDumpArgs(c *cli.Context) (int32, []byte, error) {
 	id, err := getContractID(c)
@@ -2,6 +2,7 @@ package vm
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -952,6 +953,113 @@ func TestStepIntoOverOut(t *testing.T) {
 	e.checkStack(t, 5)
 }
 
+func TestStepBack(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH2), byte(opcode.CALL), 4, byte(opcode.NOP), byte(opcode.RET),
+		byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET),
+	})
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"run",
+		"stepback",
+		"trace",
+		"trace on",
+		"loadhex "+script,
+		"stepback",
+		"run",
+		"stepback 2", "estack",
+		"stepback 3", "estack", "lslot",
+		"stepover", "estack",
+		"stepout", "istack",
+		"step 5",
+		"stepback 100", "ip",
+		"cont", "estack")
+
+	e.checkNextLine(t, "READY: loaded 8 instructions")
+	e.checkStack(t, 5)
+	e.checkError(t, errTracingDisabled)
+	e.checkError(t, errTracingDisabled)
+	e.checkNextLine(t, "READY: loaded 8 instructions")
+	e.checkError(t, errors.New("no executed instructions to step back to"))
+	e.checkStack(t, 5)
+	e.checkNextLine(t, "instruction pointer at 3 \\(NOP\\), history step 6 of 7")
+	e.checkStack(t, 5)
+	e.checkNextLine(t, "instruction pointer at 5 \\(PUSH3\\), history step 3 of 7")
+	e.checkStack(t, 2)
+	e.checkSlot(t)
+	e.checkNextLine(t, "instruction pointer at 6 \\(ADD\\), history step 4 of 7")
+	e.checkStack(t, 2, 3)
+	e.checkNextLine(t, "instruction pointer at 3 \\(NOP\\), history step 6 of 7")
+	e.checkError(t, errHistoryMode)
+	e.checkNextLine(t, "current state is reached")
+	e.checkNextLine(t, "instruction pointer at 0 \\(PUSH2\\), history step 1 of 7")
+	e.checkNextLine(t, "instruction pointer at 0 \\(PUSH2\\), history step 1 of 7")
+	e.checkStack(t, 5)
+}
+
+func TestTraceReplay(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetTrigger)
+	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.ADD)
+	script := hex.EncodeToString(w.Bytes())
+	other := hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD)})
+
+	tmpDir := t.TempDir()
+	traceFile := filepath.Join(tmpDir, "trace.json")
+	changedFile := filepath.Join(tmpDir, "changed.json")
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"trace on",
+		"loadhex "+script,
+		"run",
+		"trace '"+traceFile+"'",
+	)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, 0x41)
+	e.checkNextLine(t, "4 steps written to")
+
+	var tr vm.Trace
+	data, err := os.ReadFile(traceFile)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &tr))
+	require.Equal(t, 4, len(tr.Steps))
+	require.Equal(t, opcode.SYSCALL, tr.Steps[0].Opcode)
+	tr.Steps[0].Push[0], err = stackitem.ToJSONWithTypes(stackitem.Make(10))
+	require.NoError(t, err)
+	data, err = json.Marshal(tr)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(changedFile, data, os.ModePerm))
+
+	e = newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"replay '"+traceFile+"'",
+		"run",
+		"loadhex "+script,
+		"replay '"+changedFile+"'",
+		"run",
+		"loadhex "+other,
+		"replay '"+traceFile+"'",
+		"run",
+		"loadhex "+script,
+		"run",
+	)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "replaying 4 steps")
+	e.checkStack(t, 0x41)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "replaying 4 steps")
+	e.checkStack(t, 11)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "replaying 4 steps")
+	e.checkNextLine(t, "Error:.*replay: step 0 mismatch")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, 0x41)
+}
+
 // `Parse` output is written via `tabwriter` so if any problems
 // are encountered in this test, try to replace ' ' with '\\s+'.
 func TestParse(t *testing.T) {
@@ -1274,6 +1382,51 @@ func TestDumpChanges(t *testing.T) {
 	e.checkChange(t, expected[2])
 }
 
+func TestProfile(t *testing.T) {
+	src := `package kek
+	func Loop(n int) int {
+		var s int
+		for i := 0; i < n; i++ {
+			s += i
+		}
+		return s
+	}`
+	tmpDir := t.TempDir()
+	filename := filepath.Join(tmpDir, "profile_vmtestcontract.go")
+	require.NoError(t, os.WriteFile(filename, []byte(src), os.ModePerm))
+	pprofFile := filepath.Join(tmpDir, "gas.pb.gz")
+
+	e := newTestVMCLI(t)
+	e.runProgWithTimeout(t, 10*time.Second,
+		"loadgo '"+filename+"'",
+		"profile",
+		"profile on",
+		"run loop 10",
+		"profile --top 1 --pprof '"+pprofFile+"'",
+		"profile off",
+		"profile",
+		"profile maybe",
+	)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "Error: profiling is disabled")
+	e.checkStack(t, 45)
+	e.checkNextLine(t, "^Total: \\d+ instructions, [0-9.]+ GAS")
+	e.checkNextLineExact(t, "\n")
+	e.checkNextLine(t, "^FUNCTION +INSTRUCTIONS +GAS +%")
+	e.checkNextLine(t, "^kek.Loop +\\d+ +[0-9.]+ +100.00")
+	e.checkNextLineExact(t, "\n")
+	e.checkNextLine(t, "^OPCODE +INSTRUCTIONS +GAS +%")
+	e.checkNextLine(t, "^[A-Z0-9]+ +\\d+ +[0-9.]+ +[0-9.]+")
+	e.checkNextLine(t, "Error: profiling is disabled")
+	e.checkError(t, ErrInvalidParameter)
+
+	f, err := os.Open(pprofFile)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = gzip.NewReader(f)
+	require.NoError(t, err)
+}
+
 func TestLoadtx(t *testing.T) {
 	e := newTestVMClIWithState(t)
 
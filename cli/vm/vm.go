@@ -1,13 +1,18 @@
 package vm
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/chzyer/readline"
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 	"github.com/urfave/cli"
 )
 
@@ -15,6 +20,16 @@ import (
 func NewCommands() []cli.Command {
 	cfgFlags := []cli.Flag{options.Config, options.ConfigFile}
 	cfgFlags = append(cfgFlags, options.Network...)
+	cfgFlags = append(cfgFlags,
+		cli.StringFlag{
+			Name:  "fork",
+			Usage: "RPC node address to fork the chain from, its state is fetched on demand instead of using the local DB",
+		},
+		cli.UintFlag{
+			Name:  "fork-height",
+			Usage: "height of the remote chain to fork at (the latest block by default)",
+		},
+	)
 	return []cli.Command{{
 		Name:   "vm",
 		Usage:  "start the virtual machine",
@@ -38,11 +53,51 @@ func startVMPrompt(ctx *cli.Context) error {
 	if cfg.ApplicationConfiguration.DBConfiguration.Type != dbconfig.InMemoryDB {
 		cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.ReadOnly = true
 		cfg.ApplicationConfiguration.DBConfiguration.BoltDBOptions.ReadOnly = true
+		cfg.ApplicationConfiguration.DBConfiguration.PebbleDBOptions.ReadOnly = true
 	}
 
-	p, err := NewWithConfig(true, os.Exit, &readline.Config{}, cfg)
+	var store storage.Store
+	if endpoint := ctx.String("fork"); endpoint != "" {
+		store, err = newForkStore(endpoint, uint32(ctx.Uint("fork-height")), cfg)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to fork the chain: %w", err), 1)
+		}
+	}
+
+	p, err := NewWithConfigAndStore(true, os.Exit, &readline.Config{}, cfg, store)
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("failed to create VM CLI: %w", err), 1)
 	}
 	return p.Run()
 }
+
+// newForkStore creates a store forked from the chain of the given RPC node at
+// the given height (the latest one if it's 0).
+func newForkStore(endpoint string, height uint32, cfg config.Config) (*fork.Store, error) {
+	c, err := rpcclient.New(context.Background(), endpoint, rpcclient.Options{RequestTimeout: options.DefaultTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = c.Init()
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	if v.Protocol.Network != cfg.ProtocolConfiguration.Magic {
+		return nil, fmt.Errorf("remote network magic %d doesn't match the configured one %d", v.Protocol.Network, cfg.ProtocolConfiguration.Magic)
+	}
+	if cfg.ProtocolConfiguration.ValidatorsCount != 0 && uint32(v.Protocol.ValidatorsCount) != cfg.ProtocolConfiguration.ValidatorsCount {
+		return nil, fmt.Errorf("remote validators count %d doesn't match the configured one %d", v.Protocol.ValidatorsCount, cfg.ProtocolConfiguration.ValidatorsCount)
+	}
+	if height == 0 {
+		count, err := c.GetBlockCount()
+		if err != nil {
+			return nil, err
+		}
+		height = count - 1
+	}
+	return fork.New(c, cfg.Blockchain(), height)
+}
@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/input"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+const (
+	// EnterMnemonicPrompt is a prompt used to ask the user for a BIP-39 mnemonic.
+	EnterMnemonicPrompt = "Enter mnemonic > "
+	// EnterMnemonicPassphrasePrompt is a prompt used to ask the user for an
+	// optional BIP-39 mnemonic passphrase.
+	EnterMnemonicPassphrasePrompt = "Enter BIP-39 passphrase (optional) > "
+)
+
+var errNotHDWallet = errors.New("wallet wasn't created from a mnemonic")
+
+func deriveAccounts(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	count := ctx.Int("count")
+	if count <= 0 {
+		return cli.NewExitError("number of accounts should be positive", 1)
+	}
+	wall, pass, err := openWallet(ctx, true)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	if wall.Extra.HD == nil {
+		return cli.NewExitError(errNotHDWallet, 1)
+	}
+	seed, err := readSeed(ctx, true, 0)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := deriveAndSave(wall, seed, count, pass); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	for _, acc := range wall.Accounts[len(wall.Accounts)-count:] {
+		fmt.Fprintln(ctx.App.Writer, acc.Address)
+	}
+	return nil
+}
+
+// readSeed reads an existing mnemonic (if restore is set) or generates a new
+// one with the given number of words and prints it, then reads an optional
+// passphrase and returns the seed.
+func readSeed(ctx *cli.Context, restore bool, words int) ([]byte, error) {
+	var (
+		mnemonic string
+		err      error
+	)
+	if restore {
+		mnemonic, err = input.ReadPassword(EnterMnemonicPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mnemonic: %w", err)
+		}
+		if !wallet.IsMnemonicValid(mnemonic) {
+			return nil, errors.New("invalid mnemonic")
+		}
+	} else {
+		mnemonic, err = wallet.NewMnemonic(words)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintln(ctx.App.Writer, "Write down the mnemonic and keep it in a safe place, it's the only way to recover your keys:")
+		fmt.Fprintln(ctx.App.Writer, mnemonic)
+	}
+	passphrase, err := input.ReadPassword(EnterMnemonicPassphrasePrompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return wallet.NewSeedFromMnemonic(mnemonic, passphrase)
+}
+
+// deriveAndSave derives count new accounts from the seed for the wallet,
+// encrypts them with the given password (or the one read from the input
+// along with the account name) and saves the wallet.
+func deriveAndSave(wall *wallet.Wallet, seed []byte, count int, pass *string) error {
+	var (
+		name, phrase string
+		err          error
+	)
+	if pass == nil {
+		name, phrase, err = readAccountInfo()
+		if err != nil {
+			return err
+		}
+	} else {
+		phrase = *pass
+	}
+	if _, err := wall.DeriveAccounts(seed, 0, count, name, phrase); err != nil {
+		return err
+	}
+	return wall.Save()
+}
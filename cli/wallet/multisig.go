@@ -9,6 +9,7 @@ import (
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/cli/paramcontext"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/urfave/cli"
 )
 
@@ -59,7 +60,10 @@ func signStoredTransaction(ctx *cli.Context) error {
 	}
 
 	if acc.CanSign() {
-		sign := acc.SignHashable(pc.Network, pc.Verifiable)
+		sign, err := acc.SignHash(hash.NetSha256(uint32(pc.Network), pc.Verifiable))
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't sign: %w", err), 1)
+		}
 		if err := pc.AddSignature(ch, acc.Contract, acc.PublicKey(), sign); err != nil {
 			return cli.NewExitError(fmt.Errorf("can't add signature: %w", err), 1)
 		}
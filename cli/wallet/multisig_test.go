@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/testcli"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -18,7 +20,10 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // Test signing of multisig transactions.
@@ -191,6 +196,38 @@ func TestSignMultisigTx(t *testing.T) {
 		require.NotEqual(t, pcOld.Items[multisigHash].Signatures, pcNew.Items[multisigHash].Signatures)
 	})
 
+	t.Run("external signer", func(t *testing.T) {
+		srv := httptest.NewServer(extsigner.NewHandler("secret", wallet.NewAccountFromPrivateKey(privs[1])))
+		t.Cleanup(srv.Close)
+		cfg := config.Wallet{
+			Path: wallet2Path,
+			ExternalSigner: &config.ExternalSigner{Remote: &config.RemoteSigner{
+				URL:       srv.URL,
+				PublicKey: hex.EncodeToString(privs[1].PublicKey().Bytes()),
+				Token:     "secret",
+			}},
+		}
+		cfgData, err := yaml.Marshal(cfg)
+		require.NoError(t, err)
+		cfgPath := filepath.Join(t.TempDir(), "wallet-config.yaml")
+		require.NoError(t, os.WriteFile(cfgPath, cfgData, 0644))
+
+		// No password is needed.
+		e.Run(t, "neo-go", "wallet", "sign",
+			"--wallet-config", cfgPath, "--address", multisigAddr,
+			"--in", txPath)
+		pc := new(context.ParameterContext)
+		require.NoError(t, json.Unmarshal(e.Out.Bytes(), pc))
+		require.Equal(t, 2, len(pc.Items[multisigHash].Signatures))
+		_, err = pc.GetCompleteTransaction()
+		require.NoError(t, err)
+
+		srv.Close()
+		e.RunWithError(t, "neo-go", "wallet", "sign",
+			"--wallet-config", cfgPath, "--address", multisigAddr,
+			"--in", txPath)
+	})
+
 	t.Run("sign, save and send", func(t *testing.T) {
 		e.In.WriteString("pass\r")
 		e.Run(t, "neo-go", "wallet", "sign",
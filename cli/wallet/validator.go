@@ -163,8 +163,9 @@ func getDecryptedAccount(wall *wallet.Wallet, addr util.Uint160, password *strin
 		return nil, fmt.Errorf("can't find account for the address: %s", address.Uint160ToString(addr))
 	}
 
-	// No private key available, nothing to decrypt, but it's still a useful account for many purposes.
-	if acc.EncryptedWIF == "" {
+	// No private key available (or it's kept by an external signer), nothing to
+	// decrypt, but it's still a useful account for many purposes.
+	if acc.EncryptedWIF == "" || acc.CanSign() {
 		return acc, nil
 	}
 
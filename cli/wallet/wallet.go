@@ -25,6 +25,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"github.com/urfave/cli"
 	"gopkg.in/yaml.v3"
 )
@@ -119,8 +120,15 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "init",
 				Usage:     "create a new wallet",
-				UsageText: "neo-go wallet init -w wallet [--wallet-config path] [-a]",
-				Action:    createWallet,
+				UsageText: "neo-go wallet init -w wallet [--wallet-config path] [-a] [--mnemonic [--words n] [--restore]]",
+				Description: `Creates a new wallet. If --mnemonic is given, an HD wallet is created from
+   a BIP-39 mnemonic: a new random mnemonic of --words words is generated
+   and printed (write it down, it's the only way to recover the keys) or an
+   existing one is requested if --restore is given. An optional BIP-39
+   passphrase can also be entered. The first account (m/44'/888'/0'/0/0) is
+   added to the wallet then, subsequent ones can be added with 'wallet derive'.
+`,
+				Action: createWallet,
 				Flags: []cli.Flag{
 					walletPathFlag,
 					walletConfigFlag,
@@ -128,6 +136,38 @@ func NewCommands() []cli.Command {
 						Name:  "account, a",
 						Usage: "Create a new account",
 					},
+					cli.BoolFlag{
+						Name:  "mnemonic, m",
+						Usage: "Create an HD wallet from a BIP-39 mnemonic",
+					},
+					cli.IntFlag{
+						Name:  "words",
+						Value: 24,
+						Usage: "Number of words in the generated mnemonic (12, 15, 18, 21 or 24)",
+					},
+					cli.BoolFlag{
+						Name:  "restore",
+						Usage: "Enter an existing mnemonic instead of generating a new one",
+					},
+				},
+			},
+			{
+				Name:      "derive",
+				Usage:     "derive new accounts for an HD wallet",
+				UsageText: "neo-go wallet derive -w wallet [--wallet-config path] [--count n]",
+				Description: `Derives the next accounts (m/44'/888'/0'/0/i) of an HD wallet created with
+   'wallet init --mnemonic' and adds them to the wallet. The same mnemonic
+   and passphrase that were used to create the wallet must be entered.
+`,
+				Action: deriveAccounts,
+				Flags: []cli.Flag{
+					walletPathFlag,
+					walletConfigFlag,
+					cli.IntFlag{
+						Name:  "count, c",
+						Value: 1,
+						Usage: "Number of accounts to derive",
+					},
 				},
 			},
 			{
@@ -829,6 +869,14 @@ func createWallet(ctx *cli.Context) error {
 		path = cfg.Path
 		pass = &cfg.Password
 	}
+	var seed []byte
+	if ctx.Bool("mnemonic") {
+		var err error
+		seed, err = readSeed(ctx, ctx.Bool("restore"), ctx.Int("words"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
 	wall, err := wallet.NewWallet(path)
 	if err != nil {
 		return cli.NewExitError(err, 1)
@@ -837,7 +885,12 @@ func createWallet(ctx *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	if ctx.Bool("account") {
+	if seed != nil {
+		if err := deriveAndSave(wall, seed, 1, pass); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer wall.Close()
+	} else if ctx.Bool("account") {
 		if err := createAccount(wall, pass); err != nil {
 			return cli.NewExitError(err, 1)
 		}
@@ -898,14 +951,14 @@ func createAccount(wall *wallet.Wallet, pass *string) error {
 }
 
 func openWallet(ctx *cli.Context, canUseWalletConfig bool) (*wallet.Wallet, *string, error) {
-	path, pass, err := getWalletPathAndPass(ctx, canUseWalletConfig)
+	cfg, pass, err := getWalletConfig(ctx, canUseWalletConfig)
 	if err != nil {
 		return nil, nil, err
 	}
-	if path == "-" {
+	if cfg.Path == "-" {
 		return nil, nil, errNoStdin
 	}
-	w, err := wallet.NewWalletFromFile(path)
+	w, err := extsigner.OpenWallet(*cfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -913,47 +966,47 @@ func openWallet(ctx *cli.Context, canUseWalletConfig bool) (*wallet.Wallet, *str
 }
 
 func readWallet(ctx *cli.Context) (*wallet.Wallet, *string, error) {
-	path, pass, err := getWalletPathAndPass(ctx, true)
+	cfg, pass, err := getWalletConfig(ctx, true)
 	if err != nil {
 		return nil, nil, err
 	}
-	if path == "-" {
+	if cfg.Path == "-" {
 		w := &wallet.Wallet{}
 		if err := json.NewDecoder(os.Stdin).Decode(w); err != nil {
 			return nil, nil, fmt.Errorf("js %w", err)
 		}
 		return w, nil, nil
 	}
-	w, err := wallet.NewWalletFromFile(path)
+	w, err := extsigner.OpenWallet(*cfg)
 	if err != nil {
 		return nil, nil, err
 	}
 	return w, pass, nil
 }
 
-// getWalletPathAndPass retrieves wallet path from context or from wallet configuration file.
-// If wallet configuration file is specified, then account password is returned.
-func getWalletPathAndPass(ctx *cli.Context, canUseWalletConfig bool) (string, *string, error) {
+// getWalletConfig retrieves wallet path from context or the whole wallet
+// configuration (including external signer settings) from wallet configuration
+// file. If wallet configuration file is specified, then account password is
+// returned.
+func getWalletConfig(ctx *cli.Context, canUseWalletConfig bool) (*config.Wallet, *string, error) {
 	path, configPath := ctx.String("wallet"), ctx.String("wallet-config")
 	if !canUseWalletConfig && len(configPath) != 0 {
-		return "", nil, errors.New("can't use wallet configuration file for this command")
+		return nil, nil, errors.New("can't use wallet configuration file for this command")
 	}
 	if len(path) != 0 && len(configPath) != 0 {
-		return "", nil, errConflictingWalletFlags
+		return nil, nil, errConflictingWalletFlags
 	}
 	if len(path) == 0 && len(configPath) == 0 {
-		return "", nil, errNoPath
+		return nil, nil, errNoPath
 	}
-	var pass *string
-	if len(configPath) != 0 {
-		cfg, err := ReadWalletConfig(configPath)
-		if err != nil {
-			return "", nil, err
-		}
-		path = cfg.Path
-		pass = &cfg.Password
+	if len(configPath) == 0 {
+		return &config.Wallet{Path: path}, nil, nil
+	}
+	cfg, err := ReadWalletConfig(configPath)
+	if err != nil {
+		return nil, nil, err
 	}
-	return path, pass, nil
+	return cfg, &cfg.Password, nil
 }
 
 func ReadWalletConfig(configPath string) (*config.Wallet, error) {
@@ -142,6 +142,106 @@ func TestWalletChangePassword(t *testing.T) {
 	})
 }
 
+func TestWalletInitMnemonic(t *testing.T) {
+	const mnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+	e := testcli.NewExecutor(t, false)
+	tmp := t.TempDir()
+	seed, err := wallet.NewSeedFromMnemonic(mnemonic, "secret")
+	require.NoError(t, err)
+	expected := make([]string, 4)
+	for i := range expected {
+		acc, err := wallet.NewAccountFromSeed(seed, wallet.DerivationPath(0, uint32(i)))
+		require.NoError(t, err)
+		expected[i] = acc.Address
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		walletPath := filepath.Join(tmp, "generated.json")
+		t.Run("invalid words", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "wallet", "init", "--wallet", walletPath, "--mnemonic", "--words", "13")
+		})
+		e.In.WriteString("\r")
+		e.In.WriteString("acc\r")
+		e.In.WriteString("pass\r")
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "init", "--wallet", walletPath, "--mnemonic", "--words", "12")
+		e.CheckNextLine(t, "Write down the mnemonic")
+		words := strings.Fields(e.GetNextLine(t))
+		require.Equal(t, 12, len(words))
+
+		w, err := wallet.NewWalletFromFile(walletPath)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(w.Accounts))
+		require.Equal(t, "acc 0", w.Accounts[0].Label)
+		require.NoError(t, w.Accounts[0].Decrypt("pass", w.Scrypt))
+		require.Equal(t, &wallet.HDInfo{Next: 1, Address: w.Accounts[0].Address}, w.Extra.HD)
+
+		s, err := wallet.NewSeedFromMnemonic(strings.Join(words, " "), "")
+		require.NoError(t, err)
+		acc, err := wallet.NewAccountFromSeed(s, wallet.DerivationPath(0, 0))
+		require.NoError(t, err)
+		require.Equal(t, acc.Address, w.Accounts[0].Address)
+	})
+
+	walletPath := filepath.Join(tmp, "restored.json")
+	t.Run("restore", func(t *testing.T) {
+		t.Run("invalid mnemonic", func(t *testing.T) {
+			e.In.WriteString("legal winner thank year wave sausage worth useful legal winner thank thank\r")
+			e.RunWithError(t, "neo-go", "wallet", "init", "--wallet", walletPath, "--mnemonic", "--restore")
+		})
+		e.In.WriteString(strings.ToUpper(mnemonic) + "\r")
+		e.In.WriteString("secret\r")
+		e.In.WriteString("acc\r")
+		e.In.WriteString("pass\r")
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "init", "--wallet", walletPath, "--mnemonic", "--restore")
+
+		w, err := wallet.NewWalletFromFile(walletPath)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(w.Accounts))
+		require.Equal(t, expected[0], w.Accounts[0].Address)
+	})
+
+	t.Run("derive", func(t *testing.T) {
+		t.Run("not HD wallet", func(t *testing.T) {
+			p := filepath.Join(tmp, "plain.json")
+			e.Run(t, "neo-go", "wallet", "init", "--wallet", p)
+			e.RunWithError(t, "neo-go", "wallet", "derive", "--wallet", p)
+		})
+		t.Run("invalid count", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "wallet", "derive", "--wallet", walletPath, "--count", "0")
+		})
+		t.Run("wrong passphrase", func(t *testing.T) {
+			e.In.WriteString(mnemonic + "\r")
+			e.In.WriteString("\r")
+			e.In.WriteString("acc\r")
+			e.In.WriteString("pass\r")
+			e.In.WriteString("pass\r")
+			e.RunWithError(t, "neo-go", "wallet", "derive", "--wallet", walletPath)
+		})
+		e.In.WriteString(mnemonic + "\r")
+		e.In.WriteString("secret\r")
+		e.In.WriteString("next\r")
+		e.In.WriteString("pass\r")
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "derive", "--wallet", walletPath, "--count", "3")
+		for _, addr := range expected[1:] {
+			e.CheckNextLine(t, addr)
+		}
+		e.CheckEOF(t)
+
+		w, err := wallet.NewWalletFromFile(walletPath)
+		require.NoError(t, err)
+		require.Equal(t, 4, len(w.Accounts))
+		for i, acc := range w.Accounts {
+			require.Equal(t, expected[i], acc.Address)
+		}
+		require.Equal(t, "next 3", w.Accounts[3].Label)
+		require.Equal(t, uint32(4), w.Extra.HD.Next)
+	})
+}
+
 func TestWalletInit(t *testing.T) {
 	e := testcli.NewExecutor(t, false)
 
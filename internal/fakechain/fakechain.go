@@ -361,6 +361,11 @@ func (chain *FakeChain) SubscribeForNotifications(ch chan *state.ContainedNotifi
 	panic("TODO")
 }
 
+// SubscribeForStorageChanges implements the Blockchainer interface.
+func (chain *FakeChain) SubscribeForStorageChanges(ch chan *state.StorageChangeEvent) {
+	panic("TODO")
+}
+
 // SubscribeForTransactions implements the Blockchainer interface.
 func (chain *FakeChain) SubscribeForTransactions(ch chan *transaction.Transaction) {
 	panic("TODO")
@@ -401,6 +406,11 @@ func (chain *FakeChain) UnsubscribeFromNotifications(ch chan *state.ContainedNot
 	panic("TODO")
 }
 
+// UnsubscribeFromStorageChanges implements the Blockchainer interface.
+func (chain *FakeChain) UnsubscribeFromStorageChanges(ch chan *state.StorageChangeEvent) {
+	panic("TODO")
+}
+
 // UnsubscribeFromTransactions implements the Blockchainer interface.
 func (chain *FakeChain) UnsubscribeFromTransactions(ch chan *transaction.Transaction) {
 	panic("TODO")
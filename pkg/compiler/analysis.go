@@ -19,8 +19,13 @@ var (
 	ErrMissingExportedParamName = errors.New("exported method is not allowed to have unnamed parameter")
 	// ErrInvalidExportedRetCount is returned when exported contract method has invalid return values count.
 	ErrInvalidExportedRetCount = errors.New("exported method is not allowed to have more than one return value")
-	// ErrGenericsUnsuppored is returned when generics-related tokens are encountered.
-	ErrGenericsUnsuppored = errors.New("generics are currently unsupported, please, see the https://github.com/nspcc-dev/neo-go/issues/2376")
+	// ErrGenericsUnsupported is returned when generic code can't be compiled,
+	// e.g. when an exported contract method has type parameters.
+	ErrGenericsUnsupported = errors.New("unsupported generics usage")
+	// ErrGenericsUnsuppored is the same as ErrGenericsUnsupported.
+	//
+	// Deprecated: please, use ErrGenericsUnsupported, this alias will be removed in future versions.
+	ErrGenericsUnsuppored = ErrGenericsUnsupported
 )
 
 var (
@@ -351,7 +356,7 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 				// functions invoked in variable declarations in imported packages
 				// are marked as used.
 				var name string
-				switch t := n.Fun.(type) {
+				switch t := unwrapFuncInstance(n.Fun).(type) {
 				case *ast.Ident:
 					name = c.getIdentName(pkgPath, t.Name)
 				case *ast.SelectorExpr:
@@ -363,19 +368,17 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 			case *ast.FuncDecl:
 				name := c.getFuncNameFromDecl(pkgPath, n)
 
-				// filter out generic functions
-				err := c.checkGenericsFuncDecl(n, name)
-				if err != nil {
-					c.prog.Err = err
-					return false // Program is invalid.
-				}
-
 				// exported functions and methods are always assumed to be used
 				if isMain && n.Name.IsExported() || isInitFunc(n) || isDeployFunc(n) {
 					diff[name] = true
 				}
 				// exported functions are not allowed to have unnamed parameters  or multiple return values
 				if isMain && n.Name.IsExported() && n.Recv == nil {
+					// Contract methods can't be instantiated by the caller.
+					if n.Type.TypeParams != nil {
+						c.prog.Err = fmt.Errorf("%w: exported method %s has type parameters", ErrGenericsUnsupported, n.Name)
+						return false // Program is invalid.
+					}
 					if n.Type.Params.List != nil {
 						for i, param := range n.Type.Params.List {
 							if param.Names == nil {
@@ -397,13 +400,6 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 				nodeCache[name] = declPair{n, c.importMap, pkgPath}
 				return false // will be processed in the next stage
 			case *ast.GenDecl:
-				// Filter out generics usage.
-				err := c.checkGenericsGenDecl(n, pkgPath)
-				if err != nil {
-					c.prog.Err = err
-					return false // Program is invalid.
-				}
-
 				// After skipping all funcDecls, we are sure that each value spec
 				// is a globally declared variable or constant. We need to gather global
 				// vars from both main and imported packages.
@@ -484,10 +480,20 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 			ast.Inspect(fd.decl, func(node ast.Node) bool {
 				switch n := node.(type) {
 				case *ast.CallExpr:
-					switch t := n.Fun.(type) {
+					switch t := unwrapFuncInstance(n.Fun).(type) {
 					case *ast.Ident:
 						nextDiff[c.getIdentName(fd.path, t.Name)] = true
 					case *ast.SelectorExpr:
+						if _, ok := c.typeOf(t.X).(*types.TypeParam); ok {
+							// Method of a type parameter, the actual receiver type
+							// is known for instances only, so mark all candidates.
+							for name, decl := range nodeCache {
+								if decl.decl.Recv != nil && decl.decl.Name.Name == t.Sel.Name {
+									nextDiff[name] = true
+								}
+							}
+							break
+						}
 						name, _ := c.getFuncNameFromSelector(t)
 						nextDiff[name] = true
 					}
@@ -551,54 +557,6 @@ func (c *codegen) analyzeFuncAndGlobalVarUsage() funcUsage {
 	return usage
 }
 
-// checkGenericFuncDecl checks whether provided ast.FuncDecl has generic code.
-func (c *codegen) checkGenericsFuncDecl(n *ast.FuncDecl, funcName string) error {
-	var errGenerics error
-
-	// Generic function receiver.
-	if n.Recv != nil {
-		switch t := n.Recv.List[0].Type.(type) {
-		case *ast.StarExpr:
-			switch t.X.(type) {
-			case *ast.IndexExpr:
-				// func (x *Pointer[T]) Load() *T
-				errGenerics = errors.New("generic pointer function receiver")
-			}
-		case *ast.IndexExpr:
-			// func (x Structure[T]) Load() *T
-			errGenerics = errors.New("generic function receiver")
-		}
-	}
-
-	// Generic function parameters type: func SumInts[V int64 | int32](vals []V) V
-	if n.Type.TypeParams != nil {
-		errGenerics = errors.New("function type parameters")
-	}
-
-	if errGenerics != nil {
-		return fmt.Errorf("%w: %s has %s", ErrGenericsUnsuppored, funcName, errGenerics.Error())
-	}
-
-	return nil
-}
-
-// checkGenericsGenDecl checks whether provided ast.GenDecl has generic code.
-func (c *codegen) checkGenericsGenDecl(n *ast.GenDecl, pkgPath string) error {
-	// Generic type declaration:
-	// 	type List[T any] struct
-	// 	type List[T any] interface
-	if n.Tok == token.TYPE {
-		for _, s := range n.Specs {
-			typeSpec := s.(*ast.TypeSpec)
-			if typeSpec.TypeParams != nil {
-				return fmt.Errorf("%w: type %s is generic", ErrGenericsUnsuppored, c.getIdentName(pkgPath, typeSpec.Name.Name))
-			}
-		}
-	}
-
-	return nil
-}
-
 // nodeContext contains ast node with the corresponding import map, type info and package information
 // required to retrieve fully qualified node name (if so).
 type nodeContext struct {
@@ -637,7 +595,7 @@ func (c *codegen) pickVarsFromNodes(nodes []nodeContext, markAsUsed func(name st
 					nextExprToCheck = append(nextExprToCheck, val.derive(n.Value))
 					return false
 				case *ast.CallExpr:
-					switch t := n.Fun.(type) {
+					switch t := unwrapFuncInstance(n.Fun).(type) {
 					case *ast.Ident:
 						// Do nothing, used functions are handled in a separate cycle.
 					case *ast.SelectorExpr:
@@ -50,6 +50,12 @@ type codegen struct {
 	// Current funcScope being converted.
 	scope *funcScope
 
+	// subst is the type parameters substitution of the generic function
+	// instance being converted.
+	subst typeSubst
+	// instQueue contains generic function instances pending conversion.
+	instQueue []*funcScope
+
 	globals map[string]int
 	// staticVariables contains global (static in NDX-DN11) variable names and types.
 	staticVariables []string
@@ -473,6 +479,13 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 		}
 	}
 
+	c.convertFuncScope(file, f, pkg, isInit, isDeploy, isLambda)
+	return f
+}
+
+// convertFuncScope emits the code for the function body described by f.
+func (c *codegen) convertFuncScope(file ast.Node, f *funcScope, pkg *types.Package, isInit, isDeploy, isLambda bool) {
+	decl := f.decl
 	f.rng.Start = uint16(c.prog.Len())
 	c.scope = f
 	ast.Inspect(decl, c.scope.analyzeVoidCalls) // @OPTIMIZE
@@ -536,6 +549,7 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 	}
 
 	f.rng.End = uint16(c.prog.Len() - 1)
+	f.emitted = true
 
 	if !isLambda {
 		for _, f := range c.lambda {
@@ -553,7 +567,6 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 			count: f.vars.localsCnt,
 		}
 	}
-	return f
 }
 
 func (c *codegen) Visit(node ast.Node) ast.Visitor {
@@ -566,13 +579,6 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 	//     x = 2
 	// )
 	case *ast.GenDecl:
-		// Filter out generics usage.
-		err := c.checkGenericsGenDecl(n, c.currPkg.PkgPath)
-		if err != nil {
-			c.prog.Err = err
-			return nil // Program is invalid.
-		}
-
 		if n.Tok == token.VAR || n.Tok == token.CONST {
 			c.saveSequencePoint(n)
 		}
@@ -964,9 +970,12 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			isLiteral bool
 		)
 
-		switch fun := n.Fun.(type) {
+		switch fun := unwrapFuncInstance(n.Fun).(type) {
 		case *ast.Ident:
 			f, ok = c.getFuncFromIdent(fun)
+			if ok {
+				f = c.getFuncInstance(f, n.Fun)
+			}
 			isBuiltin = isGoBuiltin(fun.Name)
 			if !ok && !isBuiltin {
 				name = fun.Name
@@ -984,6 +993,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 
 			f, ok = c.funcs[name]
 			if ok {
+				f = c.getFuncInstance(f, n.Fun)
 				f.selector = fun.X
 				isBuiltin = isPotentialCustomBuiltin(f, n)
 				if canInline(f.pkg.Path(), f.decl.Name.Name, isBuiltin) {
@@ -2162,7 +2172,12 @@ func convertToken(tok token.Token, typ types.Type) (opcode.Opcode, error) {
 
 func (c *codegen) newFunc(decl *ast.FuncDecl) *funcScope {
 	f := c.newFuncScope(decl, c.newLabel())
-	c.funcs[c.getFuncNameFromDecl("", decl)] = f
+	f.key = c.getFuncNameFromDecl("", decl)
+	f.typeParams = c.funcTypeParams(decl)
+	if len(f.typeParams) != 0 {
+		f.instances = make(map[string]*funcScope)
+	}
+	c.funcs[f.key] = f
 	return f
 }
 
@@ -2180,7 +2195,11 @@ func (c *codegen) getFuncFromIdent(fun *ast.Ident) (*funcScope, bool) {
 // Second return value is true iff this was a method call, not foreign package call.
 func (c *codegen) getFuncNameFromSelector(e *ast.SelectorExpr) (string, bool) {
 	if c.typeInfo.Selections[e] != nil {
-		typ := c.typeInfo.Types[e.X].Type.String()
+		typ := c.subst.typ(c.typeInfo.Types[e.X].Type).String()
+		// Methods of generic types are declared for the origin type.
+		if i := strings.IndexByte(typ, '['); i >= 0 {
+			typ = typ[:i]
+		}
 		name := c.getIdentName(typ, e.Sel.Name)
 		if name[0] == '*' {
 			name = name[1:]
@@ -2210,6 +2229,12 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 	if c.prog.Err != nil {
 		return c.prog.Err
 	}
+	c.ForEachFile(func(f *ast.File, _ *types.Package) {
+		c.checkGenericFuncValues(f)
+	})
+	if c.prog.Err != nil {
+		return c.prog.Err
+	}
 
 	// Bring all imported functions into scope.
 	c.ForEachFile(c.resolveFuncDecls)
@@ -2247,13 +2272,15 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 					pkgPath = pkg.Path()
 				}
 				name := c.getFuncNameFromDecl(pkgPath, n)
-				if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) &&
+				// Generic functions are converted per-instance.
+				if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) && !isGenericFuncDecl(n) &&
 					(!isInteropPath(pkg.Path()) && !canInline(pkg.Path(), n.Name.Name, false)) {
 					c.convertFuncDecl(f, n, pkg)
 				}
 			}
 		}
 	})
+	c.convertFuncInstances()
 
 	return c.prog.Err
 }
@@ -2294,7 +2321,14 @@ func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
 		return nil, nil, err
 	}
 
-	buf, err := c.writeJumps(c.prog.Bytes())
+	var (
+		buf  = c.prog.Bytes()
+		nops []int
+	)
+	if info.options != nil && info.options.Optimize {
+		nops = c.optimize(buf)
+	}
+	buf, err := c.writeJumps(buf, nops)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2325,9 +2359,10 @@ func (c *codegen) resolveFuncDecls(f *ast.File, pkg *types.Package) {
 	}
 }
 
-func (c *codegen) writeJumps(b []byte) ([]byte, error) {
+// writeJumps resolves labels, shortens jumps where possible and removes NOPs
+// at nopOffsets along with the ones produced here.
+func (c *codegen) writeJumps(b []byte, nopOffsets []int) ([]byte, error) {
 	ctx := vm.NewContext(b)
-	var nopOffsets []int
 	for op, param, err := ctx.Next(); err == nil && ctx.IP() < len(b); op, param, err = ctx.Next() {
 		switch op {
 		case opcode.JMP, opcode.JMPIFNOT, opcode.JMPIF, opcode.CALL,
@@ -2375,6 +2410,7 @@ func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 		}
 	}
 
+	sort.Ints(nopOffsets)
 	if c.deployEndOffset >= 0 {
 		_, end := correctRange(uint16(c.initEndOffset+1), uint16(c.deployEndOffset), nopOffsets)
 		c.deployEndOffset = int(end)
@@ -2387,7 +2423,16 @@ func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 	// Correct function ip range.
 	// Note: indices are sorted in increasing order.
 	for _, f := range c.funcs {
-		f.rng.Start, f.rng.End = correctRange(f.rng.Start, f.rng.End, nopOffsets)
+		if f.emitted {
+			f.rng.Start, f.rng.End = correctRange(f.rng.Start, f.rng.End, nopOffsets)
+		}
+	}
+	// Correct sequence points, every point is shifted by the number of
+	// NOPs removed before it.
+	for _, points := range c.sequencePoints {
+		for i := range points {
+			points[i].Opcode -= sort.SearchInts(nopOffsets, points[i].Opcode)
+		}
 	}
 	return removeNOPs(b, nopOffsets), nil
 }
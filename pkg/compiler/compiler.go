@@ -61,6 +61,11 @@ type Options struct {
 	// occurrence of event call.
 	GuessEventTypes bool
 
+	// Optimize enables bytecode optimizations: constant folding, dead code
+	// elimination, unused variable slots removal and peephole rewrites of
+	// common instruction sequences.
+	Optimize bool
+
 	// Name is a contract's name to be written to manifest.
 	Name string
 
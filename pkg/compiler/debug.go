@@ -206,10 +206,17 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 	start := len(d.Methods)
 	d.NamedTypes = make(map[string]binding.ExtendedType)
 	for name, scope := range c.funcs {
+		if len(scope.typeParams) != 0 {
+			continue // Generic function template, instances are processed separately.
+		}
 		m := c.methodInfoFromScope(name, scope, d.NamedTypes)
 		if m.Range.Start == m.Range.End {
 			continue
 		}
+		// Optimized ranges of non-emitted functions can be non-empty.
+		if c.buildInfo.options != nil && c.buildInfo.options.Optimize && !scope.emitted {
+			continue
+		}
 		d.Methods = append(d.Methods, *m)
 	}
 	sort.Slice(d.Methods[start:], func(i, j int) bool {
@@ -230,6 +237,11 @@ func (c *codegen) registerDebugVariable(name string, expr ast.Expr) {
 }
 
 func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[string]binding.ExtendedType) *MethodDebugInfo {
+	// Parameter types of generic function instances depend on type arguments.
+	oldSubst := c.subst
+	c.subst = scope.subst
+	defer func() { c.subst = oldSubst }()
+
 	ps := scope.decl.Type.Params
 	params := make([]DebugParam, 0, ps.NumFields())
 	for i := range ps.List {
@@ -244,8 +256,13 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[st
 			})
 		}
 	}
-	ss := strings.Split(name, ".")
-	name = ss[len(ss)-1]
+	if scope.subst != nil {
+		// Instance names contain type arguments that can have dots inside.
+		name = scope.name
+	} else {
+		ss := strings.Split(name, ".")
+		name = ss[len(ss)-1]
+	}
 	r, n := utf8.DecodeRuneInString(name)
 	st, vt, rt, et := c.scAndVMReturnTypeFromScope(scope, exts)
 
@@ -255,7 +272,7 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope, exts map[st
 			Name:      string(unicode.ToLower(r)) + name[n:],
 			Namespace: scope.pkg.Name(),
 		},
-		IsExported:         scope.decl.Name.IsExported(),
+		IsExported:         scope.decl.Name.IsExported() && scope.subst == nil,
 		IsFunction:         scope.decl.Recv == nil,
 		Range:              scope.rng,
 		Parameters:         params,
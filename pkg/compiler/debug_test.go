@@ -315,7 +315,7 @@ func TestSequencePoints(t *testing.T) {
 		return false
 	}`
 
-	_, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	ne, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
 	require.NoError(t, err)
 	require.NotNil(t, d)
 
@@ -327,6 +327,11 @@ func TestSequencePoints(t *testing.T) {
 	require.Equal(t, 2, len(ps))
 	require.Equal(t, 4, ps[0].StartLine)
 	require.Equal(t, 6, ps[1].StartLine)
+
+	// Offsets are corrected after jump optimization and point to RET.
+	for _, p := range ps {
+		require.Equal(t, opcode.RET, opcode.Opcode(ne.Script[p.Opcode]))
+	}
 }
 
 func TestDebugInfo_MarshalJSON(t *testing.T) {
@@ -12,6 +12,9 @@ type funcScope struct {
 	// Identifier of the function.
 	name string
 
+	// key is the name the function is registered with in codegen.funcs.
+	key string
+
 	// Selector of the function if there is any. Only functions imported
 	// from other packages should have a selector.
 	selector ast.Expr
@@ -29,6 +32,9 @@ type funcScope struct {
 
 	// Range of opcodes corresponding to the function.
 	rng DebugRange
+	// emitted is true if the code of the function is emitted. The range can't
+	// be used for this check, since it's empty for one-byte functions.
+	emitted bool
 	// Variables together with it's type in neo-vm.
 	variables []string
 
@@ -48,6 +54,17 @@ type funcScope struct {
 
 	// Local variable counter.
 	i int
+
+	// typeParams is a list of type parameters of a generic function (or
+	// receiver type parameters of a generic type method). It's nil for
+	// regular functions.
+	typeParams []*types.TypeParam
+	// instances contains instances of a generic function by their type
+	// arguments.
+	instances map[string]*funcScope
+	// subst maps type parameters to type arguments for generic function
+	// instances, it's nil for all other functions.
+	subst typeSubst
 }
 
 type deferInfo struct {
@@ -92,6 +109,9 @@ func (c *codegen) getFuncNameFromDecl(pkgPath string, decl *ast.FuncDecl) string
 			case *ast.IndexExpr:
 				// Generic func declaration receiver: func (x *Pointer[T]) Load() *T
 				name = t.X.(*ast.IndexExpr).X.(*ast.Ident).Name + "." + name
+			case *ast.IndexListExpr:
+				// Generic func declaration receiver: func (x *Pair[K, V]) Load() *V
+				name = t.X.(*ast.IndexListExpr).X.(*ast.Ident).Name + "." + name
 			default:
 				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
 			}
@@ -103,6 +123,14 @@ func (c *codegen) getFuncNameFromDecl(pkgPath string, decl *ast.FuncDecl) string
 			default:
 				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
 			}
+		case *ast.IndexListExpr:
+			switch t.X.(type) {
+			case *ast.Ident:
+				// Generic func declaration receiver: func (x Pair[K, V]) Load() V
+				name = t.X.(*ast.Ident).Name + "." + name
+			default:
+				panic(fmt.Errorf("unexpected function `%s` receiver type: %T", name, t.X))
+			}
 		}
 	}
 	return c.getIdentName(pkgPath, name)
@@ -0,0 +1,317 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// typeSubst maps type parameters of a generic function (including receiver
+// type parameters of methods declared on generic types) to the type arguments
+// of a particular instance of this function.
+type typeSubst map[*types.TypeParam]types.Type
+
+// typ returns t with all type parameters replaced by the corresponding type
+// arguments. Types that don't depend on type parameters are returned as is.
+func (s typeSubst) typ(t types.Type) types.Type {
+	if len(s) == 0 || t == nil {
+		return t
+	}
+	switch t := t.(type) {
+	case *types.TypeParam:
+		if r, ok := s[t]; ok {
+			return r
+		}
+	case *types.Pointer:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewPointer(elem)
+		}
+	case *types.Slice:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewSlice(elem)
+		}
+	case *types.Array:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewArray(elem, t.Len())
+		}
+	case *types.Map:
+		key, elem := s.typ(t.Key()), s.typ(t.Elem())
+		if key != t.Key() || elem != t.Elem() {
+			return types.NewMap(key, elem)
+		}
+	case *types.Chan:
+		if elem := s.typ(t.Elem()); elem != t.Elem() {
+			return types.NewChan(t.Dir(), elem)
+		}
+	case *types.Tuple:
+		if vars, ok := s.vars(t); ok {
+			return types.NewTuple(vars...)
+		}
+	case *types.Signature:
+		params, pok := s.vars(t.Params())
+		results, rok := s.vars(t.Results())
+		if pok || rok {
+			return types.NewSignatureType(nil, nil, nil,
+				types.NewTuple(params...), types.NewTuple(results...), t.Variadic())
+		}
+	case *types.Struct:
+		var (
+			changed bool
+			fields  = make([]*types.Var, t.NumFields())
+			tags    = make([]string, t.NumFields())
+		)
+		for i := range fields {
+			f := t.Field(i)
+			typ := s.typ(f.Type())
+			changed = changed || typ != f.Type()
+			fields[i] = types.NewField(f.Pos(), f.Pkg(), f.Name(), typ, f.Embedded())
+			tags[i] = t.Tag(i)
+		}
+		if changed {
+			return types.NewStruct(fields, tags)
+		}
+	case *types.Named:
+		targs := t.TypeArgs()
+		if targs.Len() == 0 {
+			return t
+		}
+		var (
+			changed bool
+			args    = make([]types.Type, targs.Len())
+		)
+		for i := range args {
+			args[i] = s.typ(targs.At(i))
+			changed = changed || args[i] != targs.At(i)
+		}
+		if changed {
+			inst, err := types.Instantiate(nil, t.Origin(), args, false)
+			if err == nil {
+				return inst
+			}
+		}
+	}
+	return t
+}
+
+// vars substitutes types of the tuple variables. The second return value is
+// true iff at least one of the types was changed.
+func (s typeSubst) vars(t *types.Tuple) ([]*types.Var, bool) {
+	var (
+		changed bool
+		vars    = make([]*types.Var, t.Len())
+	)
+	for i := range vars {
+		v := t.At(i)
+		typ := s.typ(v.Type())
+		changed = changed || typ != v.Type()
+		vars[i] = types.NewParam(v.Pos(), v.Pkg(), v.Name(), typ)
+	}
+	return vars, changed
+}
+
+// isGenericFuncDecl checks whether provided ast.FuncDecl is a generic function
+// or a method of a generic type.
+func isGenericFuncDecl(decl *ast.FuncDecl) bool {
+	if decl.Type.TypeParams != nil {
+		return true
+	}
+	if decl.Recv != nil {
+		t := decl.Recv.List[0].Type
+		if star, ok := t.(*ast.StarExpr); ok {
+			t = star.X
+		}
+		switch t.(type) {
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapFuncInstance strips explicit type arguments from the function
+// expression, i.e. returns `Sum` for `Sum[int]`.
+func unwrapFuncInstance(fun ast.Expr) ast.Expr {
+	switch t := fun.(type) {
+	case *ast.IndexExpr:
+		return t.X
+	case *ast.IndexListExpr:
+		return t.X
+	}
+	return fun
+}
+
+// checkGenericFuncValues ensures that generic functions declared or used in f
+// are only called directly. Instances of generic functions can't be used as
+// values (like `f := sum[int]`), since they're only compiled for the types
+// they're called with.
+func (c *codegen) checkGenericFuncValues(f *ast.File) {
+	if c.prog.Err != nil {
+		return
+	}
+	callees := make(map[*ast.Ident]bool)
+	ast.Inspect(f, func(node ast.Node) bool {
+		if n, ok := node.(*ast.CallExpr); ok {
+			switch t := unwrapFuncInstance(n.Fun).(type) {
+			case *ast.Ident:
+				callees[t] = true
+			case *ast.SelectorExpr:
+				callees[t.Sel] = true
+			}
+		}
+		return true
+	})
+	ast.Inspect(f, func(node ast.Node) bool {
+		id, ok := node.(*ast.Ident)
+		if !ok || c.prog.Err != nil || callees[id] {
+			return c.prog.Err == nil
+		}
+		if _, ok := c.typeInfo.Instances[id]; !ok {
+			return true
+		}
+		if _, ok := c.typeInfo.Uses[id].(*types.Func); ok {
+			c.prog.Err = fmt.Errorf("%w: generic function %s can only be called, not used as a value",
+				ErrGenericsUnsupported, id.Name)
+		}
+		return true
+	})
+}
+
+// funcTypeParams returns the list of type parameters of the function declared
+// by decl. Receiver type parameters are returned for methods of generic types.
+func (c *codegen) funcTypeParams(decl *ast.FuncDecl) []*types.TypeParam {
+	if !isGenericFuncDecl(decl) {
+		return nil
+	}
+	obj, ok := c.typeInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := obj.Type().(*types.Signature)
+	tps := sig.TypeParams()
+	if decl.Recv != nil {
+		tps = sig.RecvTypeParams()
+	}
+	res := make([]*types.TypeParam, tps.Len())
+	for i := range res {
+		res[i] = tps.At(i)
+	}
+	return res
+}
+
+// instanceOf returns instantiation info for the generic function identifier.
+func (c *codegen) instanceOf(id *ast.Ident) (types.Instance, bool) {
+	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
+		if inst, ok := c.pkgInfoInline[i].TypesInfo.Instances[id]; ok {
+			return inst, true
+		}
+	}
+	for _, p := range c.packageCache {
+		if inst, ok := p.TypesInfo.Instances[id]; ok {
+			return inst, true
+		}
+	}
+	return types.Instance{}, false
+}
+
+// typeArgsOf returns type arguments of the generic function or method
+// referenced by fun with type parameters of the current instance (if any)
+// substituted.
+func (c *codegen) typeArgsOf(fun ast.Expr) ([]types.Type, error) {
+	var (
+		targs *types.TypeList
+		id    *ast.Ident
+	)
+	switch t := unwrapFuncInstance(fun).(type) {
+	case *ast.Ident:
+		id = t
+	case *ast.SelectorExpr:
+		if c.typeInfo.Selections[t] == nil {
+			id = t.Sel
+			break
+		}
+		// Method of a generic type, type arguments are taken from the receiver.
+		recv := c.typeOf(t.X)
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			targs = named.TypeArgs()
+		}
+	}
+	if id != nil {
+		if inst, ok := c.instanceOf(id); ok {
+			targs = inst.TypeArgs
+		}
+	}
+	if targs == nil {
+		return nil, fmt.Errorf("%w: can't infer type arguments for %s", ErrGenericsUnsupported, fun)
+	}
+	res := make([]types.Type, targs.Len())
+	for i := range res {
+		res[i] = c.subst.typ(targs.At(i))
+	}
+	return res, nil
+}
+
+// getFuncInstance returns the instance of generic function f referenced by fun.
+// New instances are registered and queued for conversion. Non-generic functions
+// are returned as is.
+func (c *codegen) getFuncInstance(f *funcScope, fun ast.Expr) *funcScope {
+	if len(f.typeParams) == 0 {
+		return f
+	}
+	targs, err := c.typeArgsOf(fun)
+	if err == nil && len(targs) != len(f.typeParams) {
+		err = fmt.Errorf("%w: %s expects %d type arguments, got %d", ErrGenericsUnsupported,
+			f.name, len(f.typeParams), len(targs))
+	}
+	if err != nil {
+		c.prog.Err = err
+		return f
+	}
+
+	var (
+		fullArgs  = make([]string, len(targs))
+		shortArgs = make([]string, len(targs))
+		subst     = make(typeSubst, len(targs))
+	)
+	for i := range targs {
+		fullArgs[i] = types.TypeString(targs[i], nil)
+		shortArgs[i] = types.TypeString(targs[i], func(p *types.Package) string { return p.Name() })
+		subst[f.typeParams[i]] = targs[i]
+	}
+	key := "[" + strings.Join(fullArgs, ",") + "]"
+	inst, ok := f.instances[key]
+	if ok {
+		return inst
+	}
+
+	inst = c.newFuncScope(f.decl, c.newLabel())
+	inst.name = f.name + "[" + strings.Join(shortArgs, ",") + "]"
+	inst.pkg = f.pkg
+	inst.file = f.file
+	inst.subst = subst
+	f.instances[key] = inst
+	c.funcs[f.key+key] = inst
+	c.instQueue = append(c.instQueue, inst)
+	return inst
+}
+
+// convertFuncInstances converts all queued generic function instances. New
+// instances can be queued during conversion, they're processed as well.
+func (c *codegen) convertFuncInstances() {
+	for len(c.instQueue) != 0 && c.prog.Err == nil {
+		inst := c.instQueue[0]
+		c.instQueue = c.instQueue[1:]
+
+		pkg := c.packageCache[inst.pkg.Path()]
+		c.typeInfo = pkg.TypesInfo
+		c.currPkg = pkg
+		c.fillImportMap(inst.file, pkg)
+		c.subst = inst.subst
+		c.setLabel(inst.label)
+		c.convertFuncScope(inst.file, inst, inst.pkg, false, false, false)
+		c.subst = nil
+	}
+}
@@ -1,6 +1,7 @@
 package compiler_test
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -18,74 +19,228 @@ func TestGenericMethodReceiver(t *testing.T) {
 		func (x *Pointer[T]) Load() *T {
 			return &x.value
 		}
+		func (x *Pointer[T]) Store(v T) {
+			x.value = v
+		}
+		func Main() int {
+			p := &Pointer[int]{}
+			p.Store(42)
+			return p.value
+		}
 `
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
+		eval(t, src, big.NewInt(42))
 	})
 	t.Run("ident expression", func(t *testing.T) {
 		src := `
 		package receiver
-		type Pointer[T any] struct {
-			value T
+		type Pair[K comparable, V any] struct {
+			key K
+			val V
 		}
-		func (x Pointer[T]) Load() *T {
-			return &x.value
+		func (p Pair[K, V]) Key() K {
+			return p.key
+		}
+		func (p Pair[K, V]) Val() V {
+			return p.val
+		}
+		func Main() string {
+			p := Pair[int, string]{key: 1, val: "one"}
+			q := Pair[string, int]{key: "two", val: 2}
+			return p.Val() + q.Key()
 		}
 `
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
+		eval(t, src, []byte("onetwo"))
 	})
 }
 
 func TestGenericFuncArgument(t *testing.T) {
 	src := `
 		package sum
-		func SumInts[V int64 | int32 | int16](vals []V) V { // doesn't make sense with NeoVM, but still it's a valid go code.
+		func sumInts[V int64 | int32 | int16](vals []V) V { // doesn't make sense with NeoVM, but still it's a valid go code.
 			var s V
 			for i := range vals {
 				s += vals[i]
 			}
 			return s
 		}
+		func Main() int64 {
+			return sumInts([]int64{1, 2, 3}) + sumInts[int64]([]int64{4, 5})
+		}
 `
-	_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-	require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
+	eval(t, src, big.NewInt(15))
 }
 
-func TestGenericTypeDecl(t *testing.T) {
-	t.Run("global scope", func(t *testing.T) {
+func TestGenericFuncMonomorphization(t *testing.T) {
+	t.Run("type-dependent code", func(t *testing.T) {
+		src := `
+		package foo
+		func add[T int | string](a, b T) T {
+			return a + b
+		}
+		func Main() string {
+			if add(1, 2) != 3 {
+				panic("bad int")
+			}
+			return add("a", "b")
+		}
+`
+		eval(t, src, []byte("ab"))
+	})
+	t.Run("zero values", func(t *testing.T) {
+		src := `
+		package foo
+		func zero[T any]() T {
+			var z T
+			return z
+		}
+		func Main() bool {
+			return zero[int]() == 0 && zero[string]() == "" && !zero[bool]()
+		}
+`
+		eval(t, src, true)
+	})
+	t.Run("nested instantiation", func(t *testing.T) {
 		src := `
+		package foo
+		func mapSlice[T, U any](vals []T, f func(T) U) []U {
+			res := []U{}
+			for _, v := range vals {
+				res = append(res, f(v))
+			}
+			return res
+		}
+		func length[T any](vals []T) int {
+			return len(mapSlice(vals, func(v T) bool { return true }))
+		}
+		func Main() int {
+			return length([]string{"a", "b"}) + length([]int{1, 2, 3})
+		}
+`
+		eval(t, src, big.NewInt(5))
+	})
+	t.Run("type parameter method", func(t *testing.T) {
+		src := `
+		package foo
+		type Stringer interface {
+			String() string
+		}
+		type Name string
+		func (n Name) String() string {
+			return "name:" + string(n)
+		}
+		func join[T Stringer](vals []T) string {
+			var res string
+			for i := range vals {
+				res += vals[i].String()
+			}
+			return res
+		}
+		func Main() string {
+			return join([]Name{"a", "b"})
+		}
+`
+		eval(t, src, []byte("name:aname:b"))
+	})
+}
+
+func TestGenericTypeDecl(t *testing.T) {
+	src := `
 		package sum
 		type List[T any] struct {
 			next *List[T]
 			val  T
 		}
 
+		func (l *List[T]) Push(v T) *List[T] {
+			return &List[T]{next: l, val: v}
+		}
+
+		func (l *List[T]) Len() int {
+			var n int
+			for ; l != nil; l = l.next {
+				n++
+			}
+			return n
+		}
+
 		func Main() any {
-			l := List[int]{}
-			return l
+			var l *List[int]
+			l = l.Push(1).Push(2).Push(3)
+			return l.Len()
 		}
 `
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
-	t.Run("local scope", func(t *testing.T) {
-		src := `
-		package sum
-		func Main() any {
-			type (
-				SomeGoodType int
-			
-				List[T any] struct {
-					next *List[T]
-					val  T
-				}
-			)
-			l := List[int]{}
-			return l
+	eval(t, src, big.NewInt(3))
+}
+
+func TestGenericExportedMethod(t *testing.T) {
+	src := `
+		package foo
+		func Main[T any](v T) T {
+			return v
 		}
 `
-		_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
-		require.ErrorIs(t, err, compiler.ErrGenericsUnsuppored)
-	})
+	_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.ErrorIs(t, err, compiler.ErrGenericsUnsupported)
+}
+
+func TestGenericFuncValue(t *testing.T) {
+	for name, body := range map[string]string{
+		"explicit instance": `f := sum[int]
+			return f(1, 2)`,
+		"argument": `return apply(sum[int], 1, 2)`,
+		"assignment": `var f func(int, int) int
+			f = sum[int]
+			return f(1, 2)`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := `
+		package foo
+		func sum[T int | string](a, b T) T {
+			return a + b
+		}
+		func apply(f func(int, int) int, a, b int) int {
+			return f(a, b)
+		}
+		func Main() int {
+			` + body + `
+		}
+`
+			_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+			require.ErrorIs(t, err, compiler.ErrGenericsUnsupported)
+		})
+	}
+}
+
+func TestGenericDebugInfo(t *testing.T) {
+	src := `
+		package foo
+		func id[T any](v T) T {
+			return v
+		}
+		func Main() int {
+			if id("str") == "str" {
+				return id(1)
+			}
+			return 0
+		}
+`
+	_, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	methods := make(map[string]compiler.MethodDebugInfo)
+	for _, m := range di.Methods {
+		methods[m.ID] = m
+	}
+	require.NotContains(t, methods, "id")
+	for id, typ := range map[string]string{"id[int]": "Integer", "id[string]": "ByteString"} {
+		m, ok := methods[id]
+		require.True(t, ok, id)
+		require.False(t, m.IsExported)
+		require.Equal(t, typ, m.ReturnType)
+		require.Equal(t, 1, len(m.Parameters))
+		require.Equal(t, typ, m.Parameters[0].Type)
+		require.Equal(t, 1, len(m.SeqPoints))
+		require.Equal(t, 4, m.SeqPoints[0].StartLine)
+	}
+	require.NotEqual(t, methods["id[int]"].Range, methods["id[string]"].Range)
 }
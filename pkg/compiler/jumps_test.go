@@ -96,9 +96,9 @@ func TestWriteJumps(t *testing.T) {
 		byte(opcode.PUSH2), byte(opcode.RET),
 	}
 	c.funcs = map[string]*funcScope{
-		"init":   {rng: DebugRange{Start: 0, End: 3}},
-		"main":   {rng: DebugRange{Start: 4, End: 9}},
-		"method": {rng: DebugRange{Start: 10, End: 11}},
+		"init":   {rng: DebugRange{Start: 0, End: 3}, emitted: true},
+		"main":   {rng: DebugRange{Start: 4, End: 9}, emitted: true},
+		"method": {rng: DebugRange{Start: 10, End: 11}, emitted: true},
 	}
 
 	expProg := []byte{
@@ -107,12 +107,12 @@ func TestWriteJumps(t *testing.T) {
 		byte(opcode.PUSH2), byte(opcode.RET),
 	}
 	expFuncs := map[string]*funcScope{
-		"init":   {rng: DebugRange{Start: 0, End: 3}},
-		"main":   {rng: DebugRange{Start: 4, End: 6}},
-		"method": {rng: DebugRange{Start: 7, End: 8}},
+		"init":   {rng: DebugRange{Start: 0, End: 3}, emitted: true},
+		"main":   {rng: DebugRange{Start: 4, End: 6}, emitted: true},
+		"method": {rng: DebugRange{Start: 7, End: 8}, emitted: true},
 	}
 
-	buf, err := c.writeJumps(before)
+	buf, err := c.writeJumps(before, nil)
 	require.NoError(t, err)
 	require.Equal(t, expProg, buf)
 	require.Equal(t, expFuncs, c.funcs)
@@ -123,7 +123,7 @@ func TestWriteJumpsLastJump(t *testing.T) {
 	c.l = []int{2}
 	prog := []byte{byte(opcode.JMP), 3, byte(opcode.RET), byte(opcode.JMPL), 0, 0, 0, 0}
 	expected := []byte{byte(opcode.JMP), 3, byte(opcode.RET), byte(opcode.JMP), 0xFF}
-	actual, err := c.writeJumps(prog)
+	actual, err := c.writeJumps(prog, nil)
 	require.NoError(t, err)
 	require.Equal(t, expected, actual)
 }
@@ -0,0 +1,719 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// maxOptimizationRounds limits the number of optimization rounds, every round
+// can open new possibilities for the next one, but programs converge quickly.
+const maxOptimizationRounds = 16
+
+type (
+	// optimizer performs bytecode optimizations before the jump labels are
+	// resolved by writeJumps. Instructions are never moved, they're rewritten
+	// in place and the excessive bytes are replaced with NOPs that are removed
+	// along with the ones produced by writeJumps. This way jump offsets, method
+	// ranges and sequence points are corrected by the same code that is used
+	// for the non-optimized program.
+	optimizer struct {
+		c *codegen
+		b []byte
+		// removed marks bytes replaced with NOPs.
+		removed []bool
+		// ins is a list of instructions that are not removed.
+		ins []instruction
+		// barriers contains offsets of instructions that can be reached not
+		// only from the previous instruction (jump targets and method
+		// starts). Sequences of instructions can't be rewritten across
+		// barriers.
+		barriers map[int]bool
+		changed  bool
+	}
+
+	// instruction is a decoded program instruction.
+	instruction struct {
+		off   int
+		op    opcode.Opcode
+		param []byte
+		size  int
+	}
+)
+
+// optimize rewrites the program b and returns the list of offsets replaced
+// with NOPs in the increasing order.
+func (c *codegen) optimize(b []byte) []int {
+	o := &optimizer{
+		c:       c,
+		b:       b,
+		removed: make([]bool, len(b)),
+	}
+	for i := 0; i < maxOptimizationRounds; i++ {
+		o.changed = false
+		o.decode()
+		o.peephole()
+		o.decode()
+		o.removeUnreachable()
+		o.decode()
+		o.removeDeadStores()
+		if !o.changed {
+			break
+		}
+	}
+	o.decode()
+	o.compactLocals()
+	o.compactStatics()
+	o.dropSequencePoints()
+
+	var nops []int
+	for i := range o.removed {
+		if o.removed[i] {
+			nops = append(nops, i)
+		}
+	}
+	return nops
+}
+
+// decode refreshes the list of instructions and barriers.
+func (o *optimizer) decode() {
+	o.ins = o.ins[:0]
+	ctx := vm.NewContext(o.b)
+	for op, param, err := ctx.Next(); err == nil && ctx.IP() < len(o.b); op, param, err = ctx.Next() {
+		if o.removed[ctx.IP()] {
+			continue
+		}
+		o.ins = append(o.ins, instruction{
+			off:   ctx.IP(),
+			op:    op,
+			param: param,
+			size:  ctx.NextIP() - ctx.IP(),
+		})
+	}
+
+	o.barriers = make(map[int]bool)
+	for i := range o.ins {
+		if i == 0 || o.ins[i].op == opcode.INITSLOT {
+			o.barriers[o.ins[i].off] = true
+		}
+		for _, t := range o.targets(o.ins[i]) {
+			if j := o.at(t); j >= 0 {
+				o.barriers[o.ins[j].off] = true
+			}
+		}
+	}
+}
+
+// at returns the index of the instruction executed when control is
+// transferred to the offset off or -1 if there is no such instruction.
+func (o *optimizer) at(off int) int {
+	i := sort.Search(len(o.ins), func(i int) bool { return o.ins[i].off >= off })
+	if i == len(o.ins) {
+		return -1
+	}
+	return i
+}
+
+// label returns the label index used by the long jump instruction.
+func (o *optimizer) label(in instruction) uint16 {
+	return binary.LittleEndian.Uint16(in.param)
+}
+
+// targets returns offsets of all possible control transfers from the
+// instruction except the next instruction.
+func (o *optimizer) targets(in instruction) []int {
+	switch in.op {
+	case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT, opcode.CALL,
+		opcode.JMPEQ, opcode.JMPNE,
+		opcode.JMPGT, opcode.JMPGE, opcode.JMPLE, opcode.JMPLT, opcode.ENDTRY:
+		return []int{in.off + int(int8(in.param[0]))}
+	case opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.CALLL,
+		opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
+		opcode.PUSHA, opcode.ENDTRYL:
+		return o.labelTargets(in.param[0:])
+	case opcode.TRYL:
+		return append(o.labelTargets(in.param[0:]), o.labelTargets(in.param[4:])...)
+	case opcode.TRY:
+		var res []int
+		for _, p := range in.param {
+			if p != 0 {
+				res = append(res, in.off+int(int8(p)))
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+func (o *optimizer) labelTargets(param []byte) []int {
+	index := int(binary.LittleEndian.Uint16(param))
+	if index >= len(o.c.l) || o.c.l[index] < 0 {
+		return nil // Reported by writeJumps.
+	}
+	return []int{o.c.l[index]}
+}
+
+// next returns the index of the instruction following i if it can be
+// rewritten together with i.
+func (o *optimizer) next(i int) (int, bool) {
+	if i+1 >= len(o.ins) || o.barriers[o.ins[i+1].off] {
+		return 0, false
+	}
+	return i + 1, true
+}
+
+// rewrite replaces instructions from i to j inclusive with the code, it must
+// not be longer than the original instructions.
+func (o *optimizer) rewrite(i, j int, code []byte) {
+	start, end := o.ins[i].off, o.ins[j].off+o.ins[j].size
+	if len(code) > end-start {
+		panic("rewritten code is too long")
+	}
+	copy(o.b[start:], code)
+	for k := start; k < end; k++ {
+		o.removed[k] = k >= start+len(code)
+		if o.removed[k] {
+			o.b[k] = byte(opcode.NOP)
+		}
+	}
+	o.changed = true
+}
+
+// remove replaces instructions from i to j inclusive with NOPs.
+func (o *optimizer) remove(i, j int) {
+	o.rewrite(i, j, nil)
+}
+
+// peephole performs constant folding and rewrites common instruction
+// sequences with the cheaper ones.
+func (o *optimizer) peephole() {
+	for i := 0; i < len(o.ins); i++ {
+		if n := o.peepholeAt(i); n > 0 {
+			i += n - 1
+		}
+	}
+}
+
+// peepholeAt tries to rewrite the sequence starting at the instruction i and
+// returns the number of instructions processed.
+func (o *optimizer) peepholeAt(i int) int {
+	in := o.ins[i]
+	j, ok := o.next(i)
+	if !ok {
+		if isLongJump(in.op) {
+			return o.threadJump(i)
+		}
+		return 0
+	}
+	next := o.ins[j]
+
+	if val, isBool, ok := constValue(in); ok {
+		switch next.op {
+		case opcode.DROP:
+			o.remove(i, j)
+			return 2
+		case opcode.JMPIF, opcode.JMPIFL, opcode.JMPIFNOT, opcode.JMPIFNOTL:
+			// Dead branch elimination: the condition is known.
+			taken := (val.Sign() != 0) == (next.op == opcode.JMPIF || next.op == opcode.JMPIFL)
+			if !taken {
+				o.remove(i, j)
+				return 2
+			}
+			jmp := opcode.JMP
+			if isLongJump(next.op) {
+				jmp = opcode.JMPL
+			}
+			o.b[next.off] = byte(jmp)
+			o.remove(i, i)
+			return 2
+		case opcode.ADD, opcode.SUB:
+			if !isBool && val.Cmp(big.NewInt(1)) == 0 {
+				// x+1 and x-1 are cheaper with INC and DEC.
+				op := opcode.INC
+				if next.op == opcode.SUB {
+					op = opcode.DEC
+				}
+				o.rewrite(i, j, []byte{byte(op)})
+				return 2
+			}
+		}
+		if code, ok := foldUnary(val, next.op); ok && o.fits(i, j, code) {
+			o.rewrite(i, j, code)
+			return 2
+		}
+		if k, ok := o.next(j); ok {
+			if val2, isBool2, ok := constValue(next); ok {
+				code, ok := foldBinary(val, isBool, val2, isBool2, o.ins[k].op)
+				if ok && o.fits(i, k, code) {
+					o.rewrite(i, k, code)
+					return 3
+				}
+			}
+		}
+		return 0
+	}
+
+	switch {
+	case isPureLoad(in.op) && next.op == opcode.DROP:
+		o.remove(i, j)
+		return 2
+	case in.op == opcode.NOT && (next.op == opcode.JMPIF || next.op == opcode.JMPIFL ||
+		next.op == opcode.JMPIFNOT || next.op == opcode.JMPIFNOTL):
+		o.b[next.off] = byte(negateCondJmp(next.op))
+		o.remove(i, i)
+		return 2
+	case in.op == opcode.SWAP && next.op == opcode.SWAP:
+		o.remove(i, j)
+		return 2
+	case isLongJump(in.op):
+		return o.threadJump(i)
+	}
+	return 0
+}
+
+// threadJump simplifies the long jump instruction i: jumps to the next
+// instruction are removed, jumps to unconditional jumps are redirected to
+// their targets and unconditional jumps to RET are replaced with RET.
+func (o *optimizer) threadJump(i int) int {
+	in := o.ins[i]
+	if in.op != opcode.JMPL && in.op != opcode.JMPIFL && in.op != opcode.JMPIFNOTL {
+		return 0
+	}
+	ts := o.targets(in)
+	if len(ts) == 0 {
+		return 0
+	}
+	t := o.at(ts[0])
+	switch {
+	case t == i+1:
+		if in.op == opcode.JMPL {
+			o.remove(i, i)
+		} else {
+			o.rewrite(i, i, []byte{byte(opcode.DROP)})
+		}
+		return 1
+	case t < 0 || t == i:
+	case o.ins[t].op == opcode.JMPL:
+		if l := o.label(o.ins[t]); l != o.label(in) {
+			binary.LittleEndian.PutUint16(o.b[in.off+1:], l)
+			o.changed = true
+		}
+	case o.ins[t].op == opcode.RET && in.op == opcode.JMPL:
+		o.rewrite(i, i, []byte{byte(opcode.RET)})
+	}
+	return 1
+}
+
+// fits checks whether the code is shorter than the instructions from i to j
+// inclusive.
+func (o *optimizer) fits(i, j int, code []byte) bool {
+	return len(code) <= o.ins[j].off+o.ins[j].size-o.ins[i].off
+}
+
+// removeUnreachable removes instructions that can't be reached from any
+// method start.
+func (o *optimizer) removeUnreachable() {
+	var (
+		reached = make([]bool, len(o.ins))
+		queue   []int
+	)
+	push := func(i int) {
+		if i >= 0 && i < len(o.ins) && !reached[i] {
+			reached[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for i := range o.ins {
+		if i == 0 || o.ins[i].op == opcode.INITSLOT {
+			push(i)
+		}
+	}
+	for len(queue) != 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, t := range o.targets(o.ins[i]) {
+			push(o.at(t))
+		}
+		if !isTerminator(o.ins[i].op) {
+			push(i + 1)
+		}
+	}
+	for i := range o.ins {
+		if !reached[i] {
+			o.remove(i, i)
+		}
+	}
+}
+
+// forEachRegion calls f for every method-level region of instructions
+// starting with INITSLOT (local variables are method-specific).
+func (o *optimizer) forEachRegion(f func(start, end int)) {
+	start := -1
+	for i := range o.ins {
+		if o.ins[i].op == opcode.INITSLOT {
+			if start >= 0 {
+				f(start, i)
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		f(start, len(o.ins))
+	}
+}
+
+// removeDeadStores replaces stores to variables that are never loaded with
+// DROP.
+func (o *optimizer) removeDeadStores() {
+	o.forEachRegion(func(start, end int) {
+		o.removeDeadStoresIn(start, end, opcode.LDLOC0, opcode.STLOC0)
+	})
+	o.removeDeadStoresIn(0, len(o.ins), opcode.LDSFLD0, opcode.STSFLD0)
+}
+
+func (o *optimizer) removeDeadStoresIn(start, end int, ld, st opcode.Opcode) {
+	loaded := make(map[int]bool)
+	for i := start; i < end; i++ {
+		if idx, ok := slotIndex(o.ins[i], ld); ok {
+			loaded[idx] = true
+		}
+	}
+	for i := start; i < end; i++ {
+		if idx, ok := slotIndex(o.ins[i], st); ok && !loaded[idx] {
+			o.rewrite(i, i, []byte{byte(opcode.DROP)})
+		}
+	}
+}
+
+// compactLocals renumbers local variables of every method, so that unused
+// slots are not allocated.
+func (o *optimizer) compactLocals() {
+	vars := map[int]*[]string{}
+	for _, f := range o.c.funcs {
+		if info, ok := o.c.reverseOffsetMap[int(f.rng.Start)]; ok && f.emitted && info.name == f.name {
+			vars[int(f.rng.Start)] = &f.variables
+		}
+	}
+	for off, info := range o.c.reverseOffsetMap {
+		switch info.name {
+		case "init":
+			vars[off] = &o.c.initVariables
+		case "_deploy":
+			vars[off] = &o.c.deployVariables
+		}
+	}
+	o.forEachRegion(func(start, end int) {
+		off := o.ins[start].off
+		info, ok := o.c.reverseOffsetMap[off]
+		if !ok {
+			return
+		}
+		count, ok := o.compactSlots(start, end, info.count, opcode.LDLOC0, opcode.STLOC0, vars[off])
+		if ok {
+			info.count = count
+			o.c.reverseOffsetMap[off] = info
+		}
+	})
+}
+
+// compactStatics renumbers static variables, so that unused slots are not
+// allocated.
+func (o *optimizer) compactStatics() {
+	for i := range o.ins {
+		if o.ins[i].op != opcode.INITSSLOT {
+			continue
+		}
+		count, ok := o.compactSlots(0, len(o.ins), int(o.ins[i].param[0]), opcode.LDSFLD0, opcode.STSFLD0, &o.c.staticVariables)
+		switch {
+		case !ok:
+		case count == 0:
+			o.remove(i, i)
+		default:
+			o.b[o.ins[i].off+1] = byte(count)
+		}
+		return
+	}
+}
+
+// compactSlots renumbers slots used by instructions from start to end
+// exclusive and returns the new number of slots. Debug info variables are
+// updated if they match the slots. False is returned if there was nothing to
+// change.
+func (o *optimizer) compactSlots(start, end, count int, ld, st opcode.Opcode, vars *[]string) (int, bool) {
+	used := make([]bool, count)
+	for i := start; i < end; i++ {
+		for _, base := range []opcode.Opcode{ld, st} {
+			if idx, ok := slotIndex(o.ins[i], base); ok && idx < count {
+				used[idx] = true
+			}
+		}
+	}
+	var (
+		newIndex = make([]int, count)
+		n        int
+	)
+	for idx := range used {
+		newIndex[idx] = n
+		if used[idx] {
+			n++
+		}
+	}
+	if n == count {
+		return count, false
+	}
+	for i := start; i < end; i++ {
+		for _, base := range []opcode.Opcode{ld, st} {
+			if idx, ok := slotIndex(o.ins[i], base); ok && idx < count && newIndex[idx] != idx {
+				o.rewrite(i, i, slotInstruction(base, newIndex[idx]))
+			}
+		}
+	}
+	if vars != nil && len(*vars) == count {
+		var res []string
+		for idx, v := range *vars {
+			if used[idx] {
+				res = append(res, v)
+			}
+		}
+		*vars = res
+	}
+	return n, true
+}
+
+// dropSequencePoints removes sequence points of statements which code was
+// completely removed, otherwise they'd be shifted to the following
+// statements.
+func (o *optimizer) dropSequencePoints() {
+	// Method starts, INITSLOT instructions are never moved or removed here.
+	var starts []int
+	for i := range o.ins {
+		if o.ins[i].op == opcode.INITSLOT {
+			starts = append(starts, o.ins[i].off)
+		}
+	}
+	for name, points := range o.c.sequencePoints {
+		var res = points[:0]
+		for i, p := range points {
+			end := len(o.b)
+			if k := sort.SearchInts(starts, p.Opcode+1); k < len(starts) {
+				end = starts[k]
+			}
+			if i+1 < len(points) && points[i+1].Opcode > p.Opcode && points[i+1].Opcode < end {
+				end = points[i+1].Opcode
+			}
+			if !o.isRemoved(p.Opcode, end) {
+				res = append(res, p)
+			}
+		}
+		o.c.sequencePoints[name] = res
+	}
+}
+
+// isRemoved checks whether all bytes from start to end exclusive are removed,
+// empty ranges are never removed.
+func (o *optimizer) isRemoved(start, end int) bool {
+	if start >= end || start >= len(o.b) {
+		return false
+	}
+	for i := start; i < end; i++ {
+		if !o.removed[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// constValue returns the integer value pushed by the instruction and whether
+// it's boolean.
+func constValue(in instruction) (*big.Int, bool, bool) {
+	switch {
+	case in.op == opcode.PUSHT:
+		return big.NewInt(1), true, true
+	case in.op == opcode.PUSHF:
+		return big.NewInt(0), true, true
+	case in.op == opcode.PUSHM1 || opcode.PUSH0 <= in.op && in.op <= opcode.PUSH16:
+		return big.NewInt(int64(in.op) - int64(opcode.PUSH0)), false, true
+	case opcode.PUSHINT8 <= in.op && in.op <= opcode.PUSHINT256:
+		return bigint.FromBytes(in.param), false, true
+	}
+	return nil, false, false
+}
+
+// foldUnary returns the code pushing the result of the unary operation.
+func foldUnary(a *big.Int, op opcode.Opcode) ([]byte, bool) {
+	r := new(big.Int)
+	switch op {
+	case opcode.NOT:
+		return boolCode(a.Sign() == 0), true
+	case opcode.NZ:
+		return boolCode(a.Sign() != 0), true
+	case opcode.NEGATE:
+		r.Neg(a)
+	case opcode.INC:
+		r.Add(a, big.NewInt(1))
+	case opcode.DEC:
+		r.Sub(a, big.NewInt(1))
+	case opcode.ABS:
+		r.Abs(a)
+	case opcode.SIGN:
+		r.SetInt64(int64(a.Sign()))
+	default:
+		return nil, false
+	}
+	return intCode(r)
+}
+
+// foldBinary returns the code pushing the result of the binary operation.
+func foldBinary(a *big.Int, isBoolA bool, b *big.Int, isBoolB bool, op opcode.Opcode) ([]byte, bool) {
+	r := new(big.Int)
+	switch op {
+	case opcode.BOOLAND:
+		return boolCode(a.Sign() != 0 && b.Sign() != 0), true
+	case opcode.BOOLOR:
+		return boolCode(a.Sign() != 0 || b.Sign() != 0), true
+	}
+	if isBoolA || isBoolB {
+		return nil, false // Keep conversions as is.
+	}
+	switch op {
+	case opcode.NUMEQUAL:
+		return boolCode(a.Cmp(b) == 0), true
+	case opcode.NUMNOTEQUAL:
+		return boolCode(a.Cmp(b) != 0), true
+	case opcode.LT:
+		return boolCode(a.Cmp(b) < 0), true
+	case opcode.LE:
+		return boolCode(a.Cmp(b) <= 0), true
+	case opcode.GT:
+		return boolCode(a.Cmp(b) > 0), true
+	case opcode.GE:
+		return boolCode(a.Cmp(b) >= 0), true
+	case opcode.ADD:
+		r.Add(a, b)
+	case opcode.SUB:
+		r.Sub(a, b)
+	case opcode.MUL:
+		r.Mul(a, b)
+	case opcode.DIV, opcode.MOD:
+		if b.Sign() == 0 {
+			return nil, false // Fault at runtime.
+		}
+		if op == opcode.DIV {
+			r.Quo(a, b)
+		} else {
+			r.Rem(a, b)
+		}
+	case opcode.AND:
+		r.And(a, b)
+	case opcode.OR:
+		r.Or(a, b)
+	case opcode.XOR:
+		r.Xor(a, b)
+	case opcode.MIN:
+		r.Set(a)
+		if a.Cmp(b) > 0 {
+			r.Set(b)
+		}
+	case opcode.MAX:
+		r.Set(a)
+		if a.Cmp(b) < 0 {
+			r.Set(b)
+		}
+	default:
+		return nil, false
+	}
+	return intCode(r)
+}
+
+func intCode(n *big.Int) ([]byte, bool) {
+	w := io.NewBufBinWriter()
+	emit.BigInt(w.BinWriter, n)
+	if w.Err != nil {
+		return nil, false // Too big, fault at runtime.
+	}
+	return w.Bytes(), true
+}
+
+func boolCode(ok bool) []byte {
+	if ok {
+		return []byte{byte(opcode.PUSHT)}
+	}
+	return []byte{byte(opcode.PUSHF)}
+}
+
+// slotIndex returns the slot index used by the instruction if it belongs to
+// the LDLOC/STLOC/LDSFLD/STSFLD group starting with base.
+func slotIndex(in instruction, base opcode.Opcode) (int, bool) {
+	switch {
+	case base <= in.op && in.op < base+7:
+		return int(in.op - base), true
+	case in.op == base+7:
+		return int(in.param[0]), true
+	}
+	return 0, false
+}
+
+// slotInstruction returns the shortest instruction for the slot access.
+func slotInstruction(base opcode.Opcode, index int) []byte {
+	if index < 7 {
+		return []byte{byte(base + opcode.Opcode(index))}
+	}
+	return []byte{byte(base + 7), byte(index)}
+}
+
+// isPureLoad checks whether the instruction just pushes something on the
+// stack without any side effects.
+func isPureLoad(op opcode.Opcode) bool {
+	switch {
+	case op == opcode.PUSHNULL, op == opcode.DUP,
+		op == opcode.PUSHDATA1, op == opcode.PUSHDATA2, op == opcode.PUSHDATA4,
+		opcode.LDSFLD0 <= op && op <= opcode.LDSFLD,
+		opcode.LDLOC0 <= op && op <= opcode.LDLOC,
+		opcode.LDARG0 <= op && op <= opcode.LDARG:
+		return true
+	}
+	return false
+}
+
+// isLongJump checks whether the instruction is a jump with a label argument.
+func isLongJump(op opcode.Opcode) bool {
+	switch op {
+	case opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL,
+		opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL:
+		return true
+	}
+	return false
+}
+
+// isTerminator checks whether the instruction never passes control to the
+// next one.
+func isTerminator(op opcode.Opcode) bool {
+	switch op {
+	case opcode.JMP, opcode.JMPL, opcode.RET, opcode.THROW,
+		opcode.ABORT, opcode.ABORTMSG, opcode.ENDFINALLY,
+		opcode.ENDTRY, opcode.ENDTRYL:
+		return true
+	}
+	return false
+}
+
+// negateCondJmp returns the boolean conditional jump with the opposite
+// condition.
+func negateCondJmp(op opcode.Opcode) opcode.Opcode {
+	switch op {
+	case opcode.JMPIF:
+		return opcode.JMPIFNOT
+	case opcode.JMPIFNOT:
+		return opcode.JMPIF
+	default:
+		return negateJmp(op)
+	}
+}
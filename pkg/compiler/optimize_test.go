@@ -0,0 +1,194 @@
+package compiler_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func compileOptimized(t *testing.T, src string, optimize bool) ([]byte, *compiler.DebugInfo) {
+	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), &compiler.Options{Optimize: optimize})
+	require.NoError(t, err)
+	return b.Script, di
+}
+
+// evalOptimized checks that both optimized and non-optimized programs return
+// the same result and returns the optimized script.
+func evalOptimized(t *testing.T, src string, result any) []byte {
+	var scripts [2][]byte
+	for i, optimize := range []bool{false, true} {
+		script, di := compileOptimized(t, src, optimize)
+		v := vm.New()
+		invokeMethod(t, testMainIdent, script, v, di)
+		runAndCheck(t, v, result)
+		scripts[i] = script
+	}
+	require.True(t, len(scripts[1]) <= len(scripts[0]), "optimized script is longer")
+	return scripts[1]
+}
+
+func TestOptimizeSemantics(t *testing.T) {
+	testCases := []testCase{
+		{"loop with function call", `package foo
+		func Main() int {
+			s := 0
+			for i := 0; i < 10; i++ {
+				s += inc(i)
+			}
+			return s
+		}
+		func inc(x int) int { return x + 1 }`, big.NewInt(55)},
+		{"switch with break", `package foo
+		func Main() int {
+			a := 0
+			for i := 0; i < 5; i++ {
+				switch {
+				case i == 1:
+					continue
+				case i == 3:
+					break
+				default:
+					a += i
+				}
+			}
+			return a
+		}`, big.NewInt(6)},
+		{"globals", `package foo
+		var unused, used = 1, 2
+		var another int
+		func init() { another = 5 }
+		func Main() int { return used + another }`, big.NewInt(7)},
+		{"defer and recover", `package foo
+		var r int
+		func Main() int {
+			f()
+			return r
+		}
+		func f() {
+			defer func() {
+				if x := recover(); x != nil {
+					r = 42
+				}
+			}()
+			panic("boom")
+		}`, big.NewInt(42)},
+		{"lambda", `package foo
+		func Main() int {
+			unused := 3
+			_ = unused
+			f := func(a int) int { b := 1; _ = b; return a * 2 }
+			return f(21)
+		}`, big.NewInt(42)},
+		{"many locals", `package foo
+		func Main() int {
+			a, b, c, d, e, f, g, h, i := 1, 2, 3, 4, 5, 6, 7, 8, 9
+			_, _, _, _, _, _, _ = a, b, c, d, e, f, g
+			return h + i
+		}`, big.NewInt(17)},
+		{"constant conditions", `package foo
+		func Main() bool {
+			a := 1
+			if true {
+				a++
+			} else {
+				a--
+			}
+			for false {
+				a = 0
+			}
+			return a == 2 && !false
+		}`, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evalOptimized(t, tc.src, tc.result)
+		})
+	}
+}
+
+func TestOptimizeConstantFolding(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/compiler/testdata/inline"
+	func Main() int {
+		return inline.Sum(1, 2) * inline.Sum(3, 4)
+	}`
+	script := evalOptimized(t, src, big.NewInt(21))
+	require.Equal(t, []byte{byte(opcode.PUSHINT8), 21, byte(opcode.RET)}, script)
+}
+
+func TestOptimizeDeadBranch(t *testing.T) {
+	src := `package foo
+	func Main() int {
+		if false {
+			return 1
+		}
+		return 2
+	}`
+	script := evalOptimized(t, src, big.NewInt(2))
+	require.Equal(t, []byte{byte(opcode.PUSH2), byte(opcode.RET)}, script)
+}
+
+func TestOptimizeUnusedSlots(t *testing.T) {
+	src := `package foo
+	var a, b int
+	func init() {
+		a = 1
+		b = 2
+	}
+	func Main() int {
+		x := 1
+		y := 2
+		_ = x
+		return y + b
+	}`
+	script := evalOptimized(t, src, big.NewInt(4))
+	_, di := compileOptimized(t, src, true)
+
+	require.Equal(t, []byte{byte(opcode.INITSSLOT), 1}, script[:2])
+	require.Equal(t, []string{"b,Integer"}, di.StaticVariables)
+	for _, m := range di.Methods {
+		if m.ID == testMainIdent {
+			require.Equal(t, []byte{byte(opcode.INITSLOT), 1, 0}, script[m.Range.Start:m.Range.Start+3])
+			require.Equal(t, []string{"y,Integer"}, m.Variables)
+		}
+	}
+}
+
+func TestOptimizeSequencePoints(t *testing.T) {
+	src := `package foo
+	func Main() int {
+		a := 1
+		b := a
+		_ = b
+		if false {
+			a = 2
+		}
+		for i := 0; i < 3; i++ {
+			a += i
+		}
+		return a
+	}`
+	evalOptimized(t, src, big.NewInt(4))
+	script, di := compileOptimized(t, src, true)
+
+	starts := make(map[int]bool)
+	ctx := vm.NewContext(script)
+	for _, _, err := ctx.Next(); err == nil && ctx.IP() < len(script); _, _, err = ctx.Next() {
+		starts[ctx.IP()] = true
+	}
+	var lines []int
+	for _, m := range di.Methods {
+		for _, p := range m.SeqPoints {
+			require.True(t, starts[p.Opcode], "sequence point is not at the instruction start")
+			require.True(t, int(m.Range.Start) <= p.Opcode && p.Opcode <= int(m.Range.End))
+			lines = append(lines, p.StartLine)
+		}
+	}
+	// Points of removed statements (lines 4, 5 and 7) are dropped.
+	require.Equal(t, []int{3, 9, 10, 12}, lines)
+}
@@ -10,33 +10,37 @@ import (
 func (c *codegen) typeAndValueOf(e ast.Expr) types.TypeAndValue {
 	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
 		if tv, ok := c.pkgInfoInline[i].TypesInfo.Types[e]; ok {
+			tv.Type = c.subst.typ(tv.Type)
 			return tv
 		}
 	}
 
 	if tv, ok := c.typeInfo.Types[e]; ok {
+		tv.Type = c.subst.typ(tv.Type)
 		return tv
 	}
 
 	se, ok := e.(*ast.SelectorExpr)
 	if ok {
 		if tv, ok := c.typeInfo.Selections[se]; ok {
-			return types.TypeAndValue{Type: tv.Type()}
+			return types.TypeAndValue{Type: c.subst.typ(tv.Type())}
 		}
 	}
 	return types.TypeAndValue{}
 }
 
+// typeOf returns the type of e with type parameters substituted for generic
+// function instances.
 func (c *codegen) typeOf(e ast.Expr) types.Type {
 	for i := len(c.pkgInfoInline) - 1; i >= 0; i-- {
 		if typ := c.pkgInfoInline[i].TypesInfo.TypeOf(e); typ != nil {
-			return typ
+			return c.subst.typ(typ)
 		}
 	}
 	for _, p := range c.packageCache {
 		typ := p.TypesInfo.TypeOf(e)
 		if typ != nil {
-			return typ
+			return c.subst.typ(typ)
 		}
 	}
 	return nil
@@ -26,11 +26,13 @@ type ApplicationConfiguration struct {
 	BroadcastFactor int                      `yaml:"BroadcastFactor"`
 	DBConfiguration dbconfig.DBConfiguration `yaml:"DBConfiguration"`
 	// Deprecated: this option is moved to the P2P section.
-	DialTimeout int64  `yaml:"DialTimeout"`
-	LogLevel    string `yaml:"LogLevel"`
-	LogPath     string `yaml:"LogPath"`
+	DialTimeout int64       `yaml:"DialTimeout"`
+	LightClient LightClient `yaml:"LightClient"`
+	LogLevel    string      `yaml:"LogLevel"`
+	LogPath     string      `yaml:"LogPath"`
 	// Deprecated: this option is moved to the P2P section.
-	MaxPeers int `yaml:"MaxPeers"`
+	MaxPeers           int                `yaml:"MaxPeers"`
+	MemPoolPersistence MemPoolPersistence `yaml:"MemPoolPersistence"`
 	// Deprecated: this option is moved to the P2P section.
 	MinPeers int `yaml:"MinPeers"`
 	// Deprecated: please, use Addresses field of P2P section instead, this field will be removed in future versions.
@@ -51,6 +53,7 @@ type ApplicationConfiguration struct {
 	Oracle            OracleConfiguration `yaml:"Oracle"`
 	P2PNotary         P2PNotary           `yaml:"P2PNotary"`
 	StateRoot         StateRoot           `yaml:"StateRoot"`
+	Tracing           Tracing             `yaml:"Tracing"`
 	// ExtensiblePoolSize is the maximum amount of the extensible payloads from a single sender.
 	//
 	// Deprecated: this option is moved to the P2P section.
@@ -58,7 +61,8 @@ type ApplicationConfiguration struct {
 }
 
 // EqualsButServices returns true when the o is the same as a except for services
-// (Oracle, P2PNotary, Pprof, Prometheus, RPC, StateRoot and UnlockWallet sections)
+// (Oracle, P2PNotary, Pprof, Prometheus, RPC, StateRoot, Tracing and UnlockWallet
+// sections)
 // and LogLevel field.
 func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration) bool {
 	if len(a.P2P.Addresses) != len(o.P2P.Addresses) {
@@ -87,6 +91,7 @@ func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration
 		a.ExtensiblePoolSize != o.ExtensiblePoolSize || //nolint:staticcheck // SA1019: a.ExtensiblePoolSize is deprecated
 		a.P2P.ExtensiblePoolSize != o.P2P.ExtensiblePoolSize ||
 		a.LogPath != o.LogPath ||
+		a.MemPoolPersistence != o.MemPoolPersistence ||
 		a.MaxPeers != o.MaxPeers || //nolint:staticcheck // SA1019: a.MaxPeers is deprecated
 		a.P2P.MaxPeers != o.P2P.MaxPeers ||
 		a.MinPeers != o.MinPeers || //nolint:staticcheck // SA1019: a.MinPeers is deprecated
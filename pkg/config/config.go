@@ -29,6 +29,9 @@ const (
 	// DefaultMaxNEP11Tokens is the default maximum number of resulting NEP11 tokens
 	// that can be traversed by `getnep11balances` JSON-RPC handler.
 	DefaultMaxNEP11Tokens = 100
+	// DefaultMaxTraceSteps is the default maximum number of VM instructions
+	// returned by `tracetransaction` JSON-RPC handler.
+	DefaultMaxTraceSteps = 10000
 )
 
 // Version is the version of the node, set at the build time.
@@ -88,7 +91,7 @@ func LoadFile(configPath string) (Config, error) {
 		return Config{}, fmt.Errorf("failed to unmarshal config YAML: %w", err)
 	}
 
-	if len(config.ApplicationConfiguration.UnlockWallet.Path) > 0 && len(config.ApplicationConfiguration.Consensus.UnlockWallet.Path) == 0 {
+	if config.ApplicationConfiguration.UnlockWallet.IsSet() && !config.ApplicationConfiguration.Consensus.UnlockWallet.IsSet() {
 		config.ApplicationConfiguration.Consensus.UnlockWallet = config.ApplicationConfiguration.UnlockWallet
 		config.ApplicationConfiguration.Consensus.Enabled = true
 	}
@@ -4,6 +4,11 @@ package config
 // a part of the ProtocolConfiguration (which is common for every node on the
 // network).
 type Ledger struct {
+	// ExecutionIndex enables notification and transaction indexes allowing
+	// to search for notifications by contract and event name and for
+	// transactions by signer. This value should remain the same for the
+	// same database.
+	ExecutionIndex bool `yaml:"ExecutionIndex"`
 	// GarbageCollectionPeriod sets the number of blocks to wait before
 	// starting the next MPT garbage collection cycle when RemoveUntraceableBlocks
 	// option is used.
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// LightClient contains settings for the light client mode of the node that
+// only syncs block headers and verifies state data using MPT proofs.
+type LightClient struct {
+	// Endpoints is a list of RPC servers used to fetch headers, state roots
+	// and proofs from. They're not trusted, everything received from them is
+	// verified locally.
+	Endpoints []string `yaml:"Endpoints"`
+	// StateValidators is a list of hex-encoded public keys of the designated
+	// state validators, it's used to verify state roots for networks without
+	// StateRootInHeader enabled.
+	StateValidators []string `yaml:"StateValidators"`
+	// SyncInterval is the interval between header synchronization attempts.
+	SyncInterval time.Duration `yaml:"SyncInterval"`
+	// RequestTimeout is the timeout for a single RPC request.
+	RequestTimeout time.Duration `yaml:"RequestTimeout"`
+}
@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// MemPoolPersistence contains settings for memory pool snapshots that allow to
+// keep pooled transactions across node restarts.
+type MemPoolPersistence struct {
+	Enabled bool `yaml:"Enabled"`
+	// FilePath is the snapshot file location.
+	FilePath string `yaml:"FilePath"`
+	// SaveInterval is the interval between periodic snapshots, the snapshot
+	// is only saved on node shutdown if it's zero.
+	SaveInterval time.Duration `yaml:"SaveInterval"`
+}
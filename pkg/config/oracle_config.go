@@ -4,17 +4,24 @@ import "time"
 
 // OracleConfiguration is a config for the oracle module.
 type OracleConfiguration struct {
-	Enabled               bool               `yaml:"Enabled"`
-	AllowPrivateHost      bool               `yaml:"AllowPrivateHost"`
-	AllowedContentTypes   []string           `yaml:"AllowedContentTypes"`
-	Nodes                 []string           `yaml:"Nodes"`
-	NeoFS                 NeoFSConfiguration `yaml:"NeoFS"`
-	MaxTaskTimeout        time.Duration      `yaml:"MaxTaskTimeout"`
-	RefreshInterval       time.Duration      `yaml:"RefreshInterval"`
-	MaxConcurrentRequests int                `yaml:"MaxConcurrentRequests"`
-	RequestTimeout        time.Duration      `yaml:"RequestTimeout"`
-	ResponseTimeout       time.Duration      `yaml:"ResponseTimeout"`
-	UnlockWallet          Wallet             `yaml:"UnlockWallet"`
+	Enabled             bool               `yaml:"Enabled"`
+	AllowPrivateHost    bool               `yaml:"AllowPrivateHost"`
+	AllowedContentTypes []string           `yaml:"AllowedContentTypes"`
+	Nodes               []string           `yaml:"Nodes"`
+	NeoFS               NeoFSConfiguration `yaml:"NeoFS"`
+	// Protocols contains per-scheme settings of oracle protocol handlers.
+	Protocols map[string]OracleProtocolConfiguration `yaml:"Protocols"`
+	// Filters is a list of additional response filter languages to enable,
+	// JSONPath is always supported. Filter results become a part of the
+	// response agreed upon by oracle nodes, so this list (as well as
+	// Protocols) must be the same for all oracle nodes of the network.
+	Filters               []string      `yaml:"Filters"`
+	MaxTaskTimeout        time.Duration `yaml:"MaxTaskTimeout"`
+	RefreshInterval       time.Duration `yaml:"RefreshInterval"`
+	MaxConcurrentRequests int           `yaml:"MaxConcurrentRequests"`
+	RequestTimeout        time.Duration `yaml:"RequestTimeout"`
+	ResponseTimeout       time.Duration `yaml:"ResponseTimeout"`
+	UnlockWallet          Wallet        `yaml:"UnlockWallet"`
 }
 
 // NeoFSConfiguration is a config for the NeoFS service.
@@ -22,3 +29,15 @@ type NeoFSConfiguration struct {
 	Nodes   []string      `yaml:"Nodes"`
 	Timeout time.Duration `yaml:"Timeout"`
 }
+
+// OracleProtocolConfiguration is a config for the oracle protocol handler of
+// some URL scheme.
+type OracleProtocolConfiguration struct {
+	// Disabled turns the handler off, it can be used for built-in
+	// handlers that are enabled by default.
+	Disabled bool `yaml:"Disabled"`
+	// Timeout is the request timeout for this scheme.
+	Timeout time.Duration `yaml:"Timeout"`
+	// Parameters contains handler-specific settings.
+	Parameters map[string]string `yaml:"Parameters"`
+}
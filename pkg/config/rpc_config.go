@@ -7,8 +7,15 @@ import (
 type (
 	// RPC is an RPC service configuration information.
 	RPC struct {
-		BasicService         `yaml:",inline"`
-		EnableCORSWorkaround bool `yaml:"EnableCORSWorkaround"`
+		BasicService `yaml:",inline"`
+		// Auth contains client authentication and request quota settings.
+		Auth                 RPCAuth `yaml:"Auth"`
+		EnableCORSWorkaround bool    `yaml:"EnableCORSWorkaround"`
+		// GasProfilerEnabled enables gas profile collection for invocations
+		// with diagnostics enabled.
+		GasProfilerEnabled bool `yaml:"GasProfilerEnabled"`
+		// GraphQLEnabled enables GraphQL query endpoint at /graphql.
+		GraphQLEnabled bool `yaml:"GraphQLEnabled"`
 		// MaxGasInvoke is the maximum amount of GAS which
 		// can be spent during an RPC call.
 		MaxGasInvoke              fixedn.Fixed8 `yaml:"MaxGasInvoke"`
@@ -16,6 +23,7 @@ type (
 		MaxFindResultItems        int           `yaml:"MaxFindResultItems"`
 		MaxFindStorageResultItems int           `yaml:"MaxFindStoragePageSize"`
 		MaxNEP11Tokens            int           `yaml:"MaxNEP11Tokens"`
+		MaxTraceSteps             int           `yaml:"MaxTraceSteps"`
 		MaxWebSocketClients       int           `yaml:"MaxWebSocketClients"`
 		SessionEnabled            bool          `yaml:"SessionEnabled"`
 		SessionExpirationTime     int           `yaml:"SessionExpirationTime"`
@@ -25,6 +33,37 @@ type (
 		TLSConfig                 TLS           `yaml:"TLSConfig"`
 	}
 
+	// RPCAuth describes RPC client authentication and request quotas.
+	RPCAuth struct {
+		// Required makes the server reject requests without a valid API key.
+		Required bool `yaml:"Required"`
+		// Keys is a list of API keys accepted by the server.
+		Keys []RPCKey `yaml:"Keys"`
+		// PerIPQuota limits requests made without an API key, every client IP
+		// address has its own quota.
+		PerIPQuota RPCQuota `yaml:"PerIPQuota"`
+		// MethodCosts overrides default request costs for the specified
+		// methods.
+		MethodCosts map[string]int `yaml:"MethodCosts"`
+	}
+
+	// RPCKey is an API key with its request quota.
+	RPCKey struct {
+		// Name is used to identify the key holder in logs.
+		Name  string   `yaml:"Name"`
+		Key   string   `yaml:"Key"`
+		Quota RPCQuota `yaml:"Quota"`
+	}
+
+	// RPCQuota is a request rate limit. Every request has a cost (1 for most
+	// methods), Rate is the number of cost units restored every second and
+	// Burst is the maximum number of units that can be accumulated (Rate by
+	// default). Zero Rate means no limit.
+	RPCQuota struct {
+		Rate  int `yaml:"Rate"`
+		Burst int `yaml:"Burst"`
+	}
+
 	// TLS describes SSL/TLS configuration.
 	TLS struct {
 		BasicService `yaml:",inline"`
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// Tracing contains settings for the tracing service that records spans for
+// block processing, RPC calls, VM invocations and network messages and
+// exports them to an OpenTelemetry collector.
+type Tracing struct {
+	Enabled bool `yaml:"Enabled"`
+	// Endpoint is the base URL of the OTLP/HTTP collector, spans are sent to
+	// its /v1/traces path. "http://localhost:4318" is used by default.
+	Endpoint string `yaml:"Endpoint"`
+	// ServiceName is the service name reported to the collector, "neo-go"
+	// by default.
+	ServiceName string `yaml:"ServiceName"`
+	// SamplingRatio is the fraction of traces recorded, it must be in
+	// (0, 1] range. Zero value means all traces are recorded.
+	SamplingRatio float64 `yaml:"SamplingRatio"`
+	// BatchTimeout is the maximum delay before sending spans to the
+	// collector, 5s by default.
+	BatchTimeout time.Duration `yaml:"BatchTimeout"`
+	// Timeout is the export request timeout, 10s by default.
+	Timeout time.Duration `yaml:"Timeout"`
+}
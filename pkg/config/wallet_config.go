@@ -1,7 +1,62 @@
 package config
 
+import "time"
+
 // Wallet is a wallet info.
 type Wallet struct {
 	Path     string `yaml:"Path"`
 	Password string `yaml:"Password"`
+	// ExternalSigner is an optional signer keeping the private key outside
+	// of the node. If Path is also specified, the signer is used for wallet
+	// accounts having its key, otherwise a single standard account is
+	// created for the signer's key.
+	ExternalSigner *ExternalSigner `yaml:"ExternalSigner"`
+}
+
+// ExternalSigner contains the external signer configuration, exactly one of
+// PKCS11 or Remote sections must be specified.
+type ExternalSigner struct {
+	PKCS11 *PKCS11Signer `yaml:"PKCS11"`
+	Remote *RemoteSigner `yaml:"Remote"`
+}
+
+// PKCS11Signer is a configuration of the signer using the key stored in the
+// PKCS#11-compatible device (HSM).
+type PKCS11Signer struct {
+	// Module is the path to the PKCS#11 library of the device.
+	Module string `yaml:"Module"`
+	// TokenLabel is the label of the token holding the key.
+	TokenLabel string `yaml:"TokenLabel"`
+	// PIN is the user PIN of the token.
+	PIN string `yaml:"PIN"`
+	// KeyLabel and KeyID (hex-encoded) specify the key pair to use, at least
+	// one of them must be set.
+	KeyLabel string `yaml:"KeyLabel"`
+	KeyID    string `yaml:"KeyID"`
+}
+
+// RemoteSigner is a configuration of the signer using the remote signing
+// service over HTTP.
+type RemoteSigner struct {
+	// URL is the signing service endpoint.
+	URL string `yaml:"URL"`
+	// PublicKey is the hex-encoded public key of the signing key.
+	PublicKey string `yaml:"PublicKey"`
+	// Token is the shared secret sent as a bearer token in every signing
+	// request, it's mandatory.
+	Token string `yaml:"Token"`
+	// Timeout is the signing request timeout, 10s by default.
+	Timeout time.Duration `yaml:"Timeout"`
+	// CAFile is the PEM-encoded CA certificate file used to verify the
+	// service certificate, system roots are used if it's not set.
+	CAFile string `yaml:"CAFile"`
+	// CertFile and KeyFile are the PEM-encoded client certificate and key
+	// files, they're used for mutual TLS authentication if set.
+	CertFile string `yaml:"CertFile"`
+	KeyFile  string `yaml:"KeyFile"`
+}
+
+// IsSet returns true if the wallet file or the external signer is specified.
+func (w Wallet) IsSet() bool {
+	return len(w.Path) > 0 || w.ExternalSigner != nil
 }
@@ -25,7 +25,10 @@ var _ block.Block = (*neoBlock)(nil)
 // Sign implements the block.Block interface.
 func (n *neoBlock) Sign(key crypto.PrivateKey) error {
 	k := key.(*privateKey)
-	sig := k.PrivateKey.SignHashable(uint32(n.network), &n.Block)
+	sig := k.SignHashable(n.network, &n.Block)
+	if sig == nil {
+		return errors.New("failed to sign block")
+	}
 	n.signature = sig
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -162,8 +163,8 @@ func NewService(cfg Config) (Service, error) {
 
 	var err error
 
-	if len(cfg.Wallet.Path) > 0 {
-		if srv.wallet, err = wallet.NewWalletFromFile(cfg.Wallet.Path); err != nil {
+	if cfg.Wallet.IsSet() {
+		if srv.wallet, err = extsigner.OpenWallet(cfg.Wallet); err != nil {
 			return nil, err
 		}
 
@@ -413,7 +414,7 @@ func (s *service) getKeyPair(pubs []crypto.PublicKey) (int, crypto.PrivateKey, c
 				}
 			}
 
-			return i, &privateKey{PrivateKey: acc.PrivateKey()}, &publicKey{PublicKey: acc.PublicKey()}
+			return i, &privateKey{Account: acc}, &publicKey{PublicKey: acc.PublicKey()}
 		}
 	}
 	return -1, nil, nil
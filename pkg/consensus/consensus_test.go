@@ -254,7 +254,7 @@ func TestService_ValidatePayload(t *testing.T) {
 	srv := newTestService(t)
 	priv, _ := getTestValidator(1)
 	p := new(Payload)
-	p.Sender = priv.GetScriptHash()
+	p.Sender = priv.ScriptHash()
 	p.SetPayload(&prepareRequest{})
 
 	t.Run("invalid validator index", func(t *testing.T) {
@@ -281,7 +281,7 @@ func TestService_ValidatePayload(t *testing.T) {
 
 	t.Run("normal case", func(t *testing.T) {
 		p.SetValidatorIndex(1)
-		p.Sender = priv.GetScriptHash()
+		p.Sender = priv.ScriptHash()
 		require.NoError(t, p.Sign(priv))
 		require.True(t, srv.validatePayload(p))
 	})
@@ -387,7 +387,7 @@ func TestService_OnPayload(t *testing.T) {
 
 	p = new(Payload)
 	p.SetValidatorIndex(1)
-	p.Sender = priv.GetScriptHash()
+	p.Sender = priv.ScriptHash()
 	p.SetPayload(&prepareRequest{})
 	require.NoError(t, p.Sign(priv))
 	require.NoError(t, srv.OnPayload(&p.Extensible))
@@ -536,7 +536,7 @@ func (bq testBlockQueuer) PutBlock(b *coreb.Block) error {
 
 func getTestValidator(i int) (*privateKey, *publicKey) {
 	key := testchain.PrivateKey(i)
-	return &privateKey{PrivateKey: key}, &publicKey{PublicKey: key.PublicKey()}
+	return &privateKey{Account: wallet.NewAccountFromPrivateKey(key)}, &publicKey{PublicKey: key.PublicKey()}
 }
 
 func newSingleTestChain(t *testing.T) *core.Blockchain {
@@ -5,17 +5,18 @@ import (
 	"errors"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 )
 
-// privateKey is a wrapper around keys.PrivateKey
-// which implements the crypto.PrivateKey interface.
+// privateKey is a wrapper around wallet.Account (that can use the decrypted
+// key or an external signer) which implements the crypto.PrivateKey interface.
 type privateKey struct {
-	*keys.PrivateKey
+	*wallet.Account
 }
 
 // Sign implements the dbft's crypto.PrivateKey interface.
 func (p *privateKey) Sign(data []byte) ([]byte, error) {
-	return p.PrivateKey.Sign(data), nil
+	return p.Account.SignHash(sha256.Sum256(data))
 }
 
 // publicKey is a wrapper around keys.PublicKey
@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nspcc-dev/dbft/payload"
@@ -130,7 +131,10 @@ func (p *Payload) EncodeBinary(w *io.BinWriter) {
 // It also sets corresponding verification and invocation scripts.
 func (p *Payload) Sign(key *privateKey) error {
 	p.encodeData()
-	sig := key.PrivateKey.SignHashable(uint32(p.network), &p.Extensible)
+	sig := key.SignHashable(p.network, &p.Extensible)
+	if sig == nil {
+		return errors.New("failed to sign payload")
+	}
 
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
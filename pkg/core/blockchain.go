@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -40,6 +41,10 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -61,6 +66,9 @@ const (
 	defaultStateSyncInterval   = 40000
 )
 
+// tracerName is the name of the tracer used for block processing spans.
+const tracerName = "github.com/nspcc-dev/neo-go/pkg/core"
+
 // stateChangeStage denotes the stage of state modification process.
 type stateChangeStage byte
 
@@ -107,6 +115,10 @@ var (
 	// conflicts with other transaction in the chain or pool according to
 	// Conflicts attribute.
 	ErrHasConflicts = errors.New("has conflicts")
+	// ErrExecutionIndexDisabled is returned when trying to search for
+	// notifications or transactions with ExecutionIndex ledger setting
+	// disabled.
+	ErrExecutionIndexDisabled = errors.New("execution index is disabled")
 )
 var (
 	persistInterval = 1 * time.Second
@@ -187,6 +199,15 @@ type Blockchain struct {
 	events  chan bcEvent
 	subCh   chan any
 	unsubCh chan any
+	// storageSubs is the number of storage change subscribers, it's
+	// maintained by the notification dispatcher and is used to avoid
+	// collecting storage changes when no one needs them.
+	storageSubs uint32
+
+	// vmExecHook is set as vm.OnExecHook for every VM spawned by the chain.
+	vmExecHook vm.OnExecHook
+	// vmProfiler is set as vm.Profiler for every VM spawned by the chain.
+	vmProfiler *vm.Profiler
 }
 
 // StateRoot represents local state root module.
@@ -203,12 +224,14 @@ type StateRoot interface {
 }
 
 // bcEvent is an internal event generated by the Blockchain and then
-// broadcasted to other parties. It joins the new block and associated
-// invocation logs, all the other events visible from outside can be produced
-// from this combination.
+// broadcasted to other parties. It joins the new block, associated
+// invocation logs and storage changes (if there are any subscribers for them),
+// all the other events visible from outside can be produced from this
+// combination.
 type bcEvent struct {
 	block          *block.Block
 	appExecResults []*state.AppExecResult
+	storageChanges []*state.StorageChangeEvent
 }
 
 // transferData is used for transfer caching during storeBlock.
@@ -391,15 +414,7 @@ func (bc *Blockchain) init() error {
 	ver, err := bc.dao.GetVersion()
 	if err != nil {
 		bc.log.Info("no storage version found! creating genesis block")
-		ver = dao.Version{
-			StoragePrefix:              storage.STStorage,
-			StateRootInHeader:          bc.config.StateRootInHeader,
-			P2PSigExtensions:           bc.config.P2PSigExtensions,
-			P2PStateExchangeExtensions: bc.config.P2PStateExchangeExtensions,
-			KeepOnlyLatestState:        bc.config.Ledger.KeepOnlyLatestState,
-			Magic:                      uint32(bc.config.Magic),
-			Value:                      version,
-		}
+		ver = newVersion(bc.config)
 		bc.dao.PutVersion(ver)
 		bc.dao.Version = ver
 		bc.persistent.Version = ver
@@ -411,7 +426,7 @@ func (bc *Blockchain) init() error {
 		if err := bc.stateRoot.Init(0); err != nil {
 			return fmt.Errorf("can't init MPT: %w", err)
 		}
-		return bc.storeBlock(genesisBlock, nil)
+		return bc.storeBlock(context.Background(), genesisBlock, nil)
 	}
 	if ver.Value != version {
 		return fmt.Errorf("storage version mismatch (expected=%s, actual=%s)", version, ver.Value)
@@ -432,6 +447,10 @@ func (bc *Blockchain) init() error {
 		return fmt.Errorf("KeepOnlyLatestState setting mismatch (old=%v, new=%v)",
 			ver.KeepOnlyLatestState, bc.config.Ledger.KeepOnlyLatestState)
 	}
+	if ver.ExecutionIndex != bc.config.Ledger.ExecutionIndex {
+		return fmt.Errorf("ExecutionIndex setting mismatch (old=%v, new=%v)",
+			ver.ExecutionIndex, bc.config.Ledger.ExecutionIndex)
+	}
 	if ver.Magic != uint32(bc.config.Magic) {
 		return fmt.Errorf("protocol configuration Magic mismatch (old=%v, new=%v)",
 			ver.Magic, bc.config.Magic)
@@ -526,6 +545,52 @@ func (bc *Blockchain) init() error {
 	return bc.updateExtensibleWhitelist(bHeight)
 }
 
+// newVersion returns the storage version for the given configuration.
+func newVersion(cfg config.Blockchain) dao.Version {
+	return dao.Version{
+		StoragePrefix:              storage.STStorage,
+		StateRootInHeader:          cfg.StateRootInHeader,
+		P2PSigExtensions:           cfg.P2PSigExtensions,
+		P2PStateExchangeExtensions: cfg.P2PStateExchangeExtensions,
+		KeepOnlyLatestState:        cfg.Ledger.KeepOnlyLatestState,
+		ExecutionIndex:             cfg.Ledger.ExecutionIndex,
+		Magic:                      uint32(cfg.Magic),
+		Value:                      version,
+	}
+}
+
+// InitFork prepares an empty store for the Blockchain starting from the given
+// header of some other chain with the same configuration (a fork of this
+// chain). Blocks, transactions and contract storage items of the original
+// chain aren't copied, the store is expected to provide them by itself when
+// requested (see fork.Store). MPT of the resulting Blockchain is started from
+// scratch, it only contains the items changed by the blocks added on top of the
+// given one, so local state roots differ from the original chain ones.
+func InitFork(st storage.Store, cfg config.Blockchain, h *block.Header) error {
+	_, err := st.Get([]byte{byte(storage.SYSVersion)})
+	if err == nil {
+		return errors.New("store is not empty")
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return err
+	}
+	d := dao.NewSimple(st, cfg.StateRootInHeader, cfg.P2PSigExtensions)
+	d.PutVersion(newVersion(cfg))
+	d.PutCurrentHeader(h.Hash(), h.Index)
+	d.StoreAsCurrentBlock(&block.Block{Header: *h})
+
+	sr := stateroot.NewModule(cfg, nil, zap.NewNop(), d.Store)
+	err = sr.Init(0)
+	if err == nil {
+		_, _, err = sr.AddMPTBatch(h.Index, mpt.Batch{}, d.Store)
+	}
+	if err != nil {
+		return fmt.Errorf("can't init MPT: %w", err)
+	}
+	_, err = d.Persist()
+	return err
+}
+
 // jumpToState is an atomic operation that changes Blockchain state to the one
 // specified by the state sync point p. All the data needed for the jump must be
 // collected by the state sync module.
@@ -667,6 +732,26 @@ func (bc *Blockchain) Reset(height uint32) error {
 	return bc.resetStateInternal(height, none)
 }
 
+// ResetRunning is the same as Reset, but it can be called on a running
+// Blockchain. Block addition is suspended for the time of reset and mempooled
+// transactions that are no longer valid are removed after it. Other services
+// (consensus, state sync, notary, etc.) are not aware of the reset, so this
+// method is only intended to be used by the single-node developer chain.
+func (bc *Blockchain) ResetRunning(height uint32) error {
+	bc.addLock.Lock()
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+	defer bc.addLock.Unlock()
+
+	bc.dao.PutStateSyncPoint(height)
+	err := bc.resetStateInternal(height, none)
+	if err != nil {
+		return err
+	}
+	bc.memPool.RemoveStale(func(tx *transaction.Transaction) bool { return bc.IsTxStillRelevant(tx, nil, false) }, bc)
+	return nil
+}
+
 func (bc *Blockchain) resetStateInternal(height uint32, stage stateChangeStage) error {
 	// Cache isn't yet initialized, so retrieve block height right from DAO.
 	currHeight, err := bc.dao.GetCurrentBlockHeight()
@@ -958,6 +1043,11 @@ func (bc *Blockchain) resetStateInternal(height uint32, stage stateChangeStage)
 			return fmt.Errorf("failed to strip transfer log / transfer info: %w", err)
 		}
 
+		// Reset execution index.
+		if bc.config.Ledger.ExecutionIndex {
+			upperCache.DeleteExecutionIndex(height)
+		}
+
 		upperCache.Store.Put(resetStageKey, []byte{stateResetBit | byte(transfersReset)})
 		bc.log.Info("state root information and NEP transfers are reset", zap.Duration("took", time.Since(p)))
 
@@ -1319,6 +1409,7 @@ func (bc *Blockchain) notificationDispatcher() {
 		txFeed           = make(map[chan *transaction.Transaction]bool)
 		notificationFeed = make(map[chan *state.ContainedNotificationEvent]bool)
 		executionFeed    = make(map[chan *state.AppExecResult]bool)
+		storageFeed      = make(map[chan *state.StorageChangeEvent]bool)
 	)
 	for {
 		select {
@@ -1334,6 +1425,9 @@ func (bc *Blockchain) notificationDispatcher() {
 				notificationFeed[ch] = true
 			case chan *state.AppExecResult:
 				executionFeed[ch] = true
+			case chan *state.StorageChangeEvent:
+				storageFeed[ch] = true
+				atomic.StoreUint32(&bc.storageSubs, uint32(len(storageFeed)))
 			default:
 				panic(fmt.Sprintf("bad subscription: %T", sub))
 			}
@@ -1347,6 +1441,9 @@ func (bc *Blockchain) notificationDispatcher() {
 				delete(notificationFeed, ch)
 			case chan *state.AppExecResult:
 				delete(executionFeed, ch)
+			case chan *state.StorageChangeEvent:
+				delete(storageFeed, ch)
+				atomic.StoreUint32(&bc.storageSubs, uint32(len(storageFeed)))
 			default:
 				panic(fmt.Sprintf("bad unsubscription: %T", unsub))
 			}
@@ -1411,6 +1508,11 @@ func (bc *Blockchain) notificationDispatcher() {
 					}
 				}
 			}
+			for _, change := range event.storageChanges {
+				for ch := range storageFeed {
+					ch <- change
+				}
+			}
 			for ch := range blockFeed {
 				ch <- event.block
 			}
@@ -1431,7 +1533,11 @@ func (bc *Blockchain) Close() {
 
 // AddBlock accepts successive block for the Blockchain, verifies it and
 // stores internally. Eventually it will be persisted to the backing storage.
-func (bc *Blockchain) AddBlock(block *block.Block) error {
+func (bc *Blockchain) AddBlock(block *block.Block) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "Blockchain.AddBlock",
+		trace.WithAttributes(attribute.Int64("index", int64(block.Index))))
+	defer func() { endSpan(span, err) }()
+
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
 
@@ -1475,7 +1581,7 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 			}
 		}
 	}
-	return bc.storeBlock(block, mp)
+	return bc.storeBlock(ctx, block, mp)
 }
 
 // AddHeaders processes the given headers and add them to the
@@ -1546,7 +1652,13 @@ func (bc *Blockchain) GetStateSyncModule() *statesync.Module {
 // storeBlock performs chain update using the block given, it executes all
 // transactions with all appropriate side-effects and updates Blockchain state.
 // This is the only way to change Blockchain state.
-func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error {
+func (bc *Blockchain) storeBlock(ctx context.Context, block *block.Block, txpool *mempool.Pool) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "Blockchain.storeBlock",
+		trace.WithAttributes(
+			attribute.Int64("index", int64(block.Index)),
+			attribute.Int("transactions", len(block.Transactions))))
+	defer func() { endSpan(span, err) }()
+
 	var (
 		cache          = bc.dao.GetPrivate()
 		aerCache       = bc.dao.GetPrivate()
@@ -1589,20 +1701,29 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 			}
 		}
 		for aer := range aerchan {
+			var pos uint32
 			if aer.Container == block.Hash() {
 				if baer1 == nil {
 					baer1 = aer
 				} else {
 					baer2 = aer
+					pos = uint32(len(block.Transactions) + 1)
 				}
 			} else {
 				err = kvcache.StoreAsTransaction(block.Transactions[txCnt], block.Index, aer)
+				if bc.config.Ledger.ExecutionIndex {
+					kvcache.PutTransactionIndex(block.Index, uint16(txCnt+1), block.Transactions[txCnt])
+				}
 				txCnt++
+				pos = uint32(txCnt)
 			}
 			if err != nil {
 				err = fmt.Errorf("failed to store exec result: %w", err)
 				break
 			}
+			if bc.config.Ledger.ExecutionIndex {
+				kvcache.PutNotificationIndex(block.Index, pos, aer)
+			}
 			if aer.Execution.VMState == vmstate.Halt {
 				for j := range aer.Execution.Events {
 					bc.handleNotification(&aer.Execution.Events[j], kvcache, transCache, block, aer.Container)
@@ -1633,7 +1754,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		close(aerdone)
 	}()
 	_ = cache.GetItemCtx() // Prime serialization context cache (it'll be reused by upper layer DAOs).
-	aer, v, err := bc.runPersist(bc.contracts.GetPersistScript(), block, cache, trigger.OnPersist, nil)
+	aer, v, err := bc.runPersist(ctx, bc.contracts.GetPersistScript(), block, cache, trigger.OnPersist, nil)
 	if err != nil {
 		// Release goroutines, don't care about errors, we already have one.
 		close(aerchan)
@@ -1649,7 +1770,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		v.LoadScriptWithFlags(tx.Script, callflag.All)
 		v.GasLimit = tx.SystemFee
 
-		err := systemInterop.Exec()
+		err := systemInterop.ExecContext(ctx)
 		var faultException string
 		if !v.HasFailed() {
 			_, err := systemInterop.DAO.Persist()
@@ -1681,7 +1802,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		aerchan <- aer
 	}
 
-	aer, _, err = bc.runPersist(bc.contracts.GetPostPersistScript(), block, cache, trigger.PostPersist, v)
+	aer, _, err = bc.runPersist(ctx, bc.contracts.GetPostPersistScript(), block, cache, trigger.PostPersist, v)
 	if err != nil {
 		// Release goroutines, don't care about errors, we already have one.
 		close(aerchan)
@@ -1731,6 +1852,11 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		return aererr
 	}
 
+	var storageChanges []*state.StorageChangeEvent
+	if block.Index != 0 && atomic.LoadUint32(&bc.storageSubs) != 0 {
+		storageChanges = bc.collectStorageChanges(block, cache)
+	}
+
 	bc.lock.Lock()
 	_, err = aerCache.Persist()
 	if err != nil {
@@ -1762,11 +1888,63 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	// is no one to read this event. And it doesn't make much sense as event
 	// anyway.
 	if block.Index != 0 {
-		bc.events <- bcEvent{block, appExecResults}
+		bc.events <- bcEvent{block, appExecResults, storageChanges}
 	}
 	return nil
 }
 
+// collectStorageChanges returns contract storage changes made by the given
+// block in a deterministic order. It must be called before the block
+// changes are persisted, because hashes of the contracts destroyed by this
+// block are retrieved from the previous state. Changes of the contracts that
+// can't be resolved (deployed and destroyed by the same block) are skipped,
+// notifications must never affect block processing.
+func (bc *Blockchain) collectStorageChanges(block *block.Block, cache *dao.Simple) []*state.StorageChangeEvent {
+	var (
+		changes = cache.Store.GetStorageChanges()
+		keys    = make([]string, 0, len(changes))
+		hashes  = make(map[int32]util.Uint160)
+		unknown = make(map[int32]bool)
+		res     = make([]*state.StorageChangeEvent, 0, len(changes))
+	)
+	for k := range changes {
+		if len(k) >= 5 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		id := int32(binary.LittleEndian.Uint32([]byte(k[1:5])))
+		h, ok := hashes[id]
+		if !ok {
+			var err error
+			h, err = native.GetContractScriptHash(cache, id)
+			if err != nil {
+				h, err = native.GetContractScriptHash(bc.dao, id)
+			}
+			if err != nil {
+				bc.log.Debug("skipping storage changes of unknown contract",
+					zap.Uint32("block", block.Index),
+					zap.Int32("id", id),
+					zap.Error(err))
+				unknown[id] = true
+			}
+			hashes[id] = h
+		}
+		if unknown[id] {
+			continue
+		}
+		res = append(res, &state.StorageChangeEvent{
+			BlockHash:  block.Hash(),
+			BlockIndex: block.Index,
+			Contract:   h,
+			Key:        []byte(k[5:]),
+			Value:      changes[k],
+		})
+	}
+	return res
+}
+
 func (bc *Blockchain) updateExtensibleWhitelist(height uint32) error {
 	updateCommittee := bc.config.ShouldUpdateCommitteeAt(height)
 	stateVals, sh, err := bc.contracts.Designate.GetDesignatedByRole(bc.dao, noderoles.StateValidator, height)
@@ -1816,7 +1994,7 @@ func (bc *Blockchain) IsExtensibleAllowed(u util.Uint160) bool {
 	return n < len(us)
 }
 
-func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.Simple, trig trigger.Type, v *vm.VM) (*state.AppExecResult, *vm.VM, error) {
+func (bc *Blockchain) runPersist(ctx context.Context, script []byte, block *block.Block, cache *dao.Simple, trig trigger.Type, v *vm.VM) (*state.AppExecResult, *vm.VM, error) {
 	systemInterop := bc.newInteropContext(trig, cache, block, nil)
 	if v == nil {
 		v = systemInterop.SpawnVM()
@@ -1824,7 +2002,7 @@ func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.S
 		systemInterop.ReuseVM(v)
 	}
 	v.LoadScriptWithFlags(script, callflag.All)
-	if err := systemInterop.Exec(); err != nil {
+	if err := systemInterop.ExecContext(ctx); err != nil {
 		return nil, v, fmt.Errorf("VM has failed: %w", err)
 	} else if _, err := systemInterop.DAO.Persist(); err != nil {
 		return nil, v, fmt.Errorf("can't save changes: %w", err)
@@ -2007,6 +2185,30 @@ func (bc *Blockchain) ForEachNEP11Transfer(acc util.Uint160, newestTimestamp uin
 	return bc.dao.SeekNEP11TransferLog(acc, newestTimestamp, f)
 }
 
+// ForEachNotification executes f for each notification index entry of the
+// given contract (and the given event name if it's not empty) emitted in blocks
+// from start to end (inclusive) starting from the oldest one. It continues
+// iteration until false is returned from f. The last non-nil error is returned.
+// ErrExecutionIndexDisabled is returned if ExecutionIndex is not enabled.
+func (bc *Blockchain) ForEachNotification(contract util.Uint160, name string, start, end uint32, f func(*state.NotificationIndexEntry) (bool, error)) error {
+	if !bc.config.Ledger.ExecutionIndex {
+		return ErrExecutionIndexDisabled
+	}
+	return bc.dao.SeekNotificationIndex(contract, name, start, end, f)
+}
+
+// ForEachTransaction executes f for each transaction index entry of the given
+// signer from blocks from start to end (inclusive) starting from the oldest
+// one. It continues iteration until false is returned from f. The last non-nil
+// error is returned. ErrExecutionIndexDisabled is returned if ExecutionIndex is
+// not enabled.
+func (bc *Blockchain) ForEachTransaction(acc util.Uint160, start, end uint32, f func(*state.TransactionIndexEntry) (bool, error)) error {
+	if !bc.config.Ledger.ExecutionIndex {
+		return ErrExecutionIndexDisabled
+	}
+	return bc.dao.SeekTransactionIndex(acc, start, end, f)
+}
+
 // GetNEP17Contracts returns the list of deployed NEP-17 contracts.
 func (bc *Blockchain) GetNEP17Contracts() []util.Uint160 {
 	return bc.contracts.Management.GetNEP17Contracts(bc.dao)
@@ -2085,12 +2287,14 @@ func (bc *Blockchain) LastBatch() *storage.MemBatch {
 }
 
 // persist flushes current in-memory Store contents to the persistent storage.
-func (bc *Blockchain) persist(isSync bool) (time.Duration, error) {
+func (bc *Blockchain) persist(isSync bool) (duration time.Duration, err error) {
+	_, span := otel.Tracer(tracerName).Start(context.Background(), "Blockchain.persist",
+		trace.WithAttributes(attribute.Bool("sync", isSync)))
+	defer func() { endSpan(span, err) }()
+
 	var (
 		start     = time.Now()
-		duration  time.Duration
 		persisted int
-		err       error
 	)
 
 	if isSync {
@@ -2124,6 +2328,7 @@ func (bc *Blockchain) persist(isSync bool) (time.Duration, error) {
 		// update monitoring metrics.
 		updatePersistedHeightMetric(bHeight)
 	}
+	span.SetAttributes(attribute.Int("keys", persisted))
 
 	return duration, nil
 }
@@ -2301,6 +2506,17 @@ func (bc *Blockchain) SubscribeForExecutions(ch chan *state.AppExecResult) {
 	bc.subCh <- ch
 }
 
+// SubscribeForStorageChanges adds given channel to contract storage change
+// event broadcasting, so when a new block is added to the chain you'll receive
+// every storage item changed by it via this channel (before the block itself
+// is sent to SubscribeForBlocks subscribers). Make sure it's read from
+// regularly as not reading these events might affect other Blockchain
+// functions. Make sure you're not changing the received events, as it may
+// affect the functionality of Blockchain and other subscribers.
+func (bc *Blockchain) SubscribeForStorageChanges(ch chan *state.StorageChangeEvent) {
+	bc.subCh <- ch
+}
+
 // UnsubscribeFromBlocks unsubscribes given channel from new block notifications,
 // you can close it afterwards. Passing non-subscribed channel is a no-op, but
 // the method can read from this channel (discarding any read data).
@@ -2358,6 +2574,21 @@ unsubloop:
 	}
 }
 
+// UnsubscribeFromStorageChanges unsubscribes given channel from contract
+// storage change notifications, you can close it afterwards. Passing
+// non-subscribed channel is a no-op, but the method can read from this channel
+// (discarding any read data).
+func (bc *Blockchain) UnsubscribeFromStorageChanges(ch chan *state.StorageChangeEvent) {
+unsubloop:
+	for {
+		select {
+		case <-ch:
+		case bc.unsubCh <- ch:
+			break unsubloop
+		}
+	}
+}
+
 // CalculateClaimable calculates the amount of GAS generated by owning specified
 // amount of NEO between specified blocks.
 func (bc *Blockchain) CalculateClaimable(acc util.Uint160, endHeight uint32) (*big.Int, error) {
@@ -2742,33 +2973,89 @@ func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transact
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fake block for height %d: %w", nextBlockHeight, err)
 	}
+	dTrie, err := bc.getHistoricDAO(b.Index)
+	if err != nil {
+		return nil, err
+	}
+	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
+	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
+	return systemInterop, nil
+}
+
+// GetTransactionReplayVM returns an interop context with VM set up to execute
+// the given persisted transaction again in the same conditions it was
+// executed in originally: the state of the previous block is used with
+// OnPersist and all preceding transactions of the same block applied. The
+// transaction script is not loaded (the caller is expected to load it with
+// callflag.All and transaction's system fee as the GAS limit). Historic states
+// are required for this, so it doesn't work with KeepOnlyLatestState.
+func (bc *Blockchain) GetTransactionReplayVM(h util.Uint256) (*interop.Context, error) {
+	if bc.config.Ledger.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	_, height, err := bc.dao.GetTransaction(h)
+	if err != nil {
+		return nil, err
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	d, err := bc.getHistoricDAO(b.Index)
+	if err != nil {
+		return nil, err
+	}
+	_, v, err := bc.runPersist(context.Background(), bc.contracts.GetPersistScript(), b, d, trigger.OnPersist, nil)
+	if err != nil {
+		return nil, fmt.Errorf("onPersist failed: %w", err)
+	}
+	for _, tx := range b.Transactions {
+		ic := bc.newInteropContext(trigger.Application, d, b, tx)
+		if tx.Hash() == h {
+			_ = ic.SpawnVM()
+			return ic, nil
+		}
+		ic.ReuseVM(v)
+		v.LoadScriptWithFlags(tx.Script, callflag.All)
+		v.GasLimit = tx.SystemFee
+		_ = ic.Exec()
+		if !v.HasFailed() {
+			if _, err := ic.DAO.Persist(); err != nil {
+				return nil, fmt.Errorf("failed to persist invocation results: %w", err)
+			}
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not found in block %d", h.StringLE(), b.Index)
+}
+
+// getHistoricDAO returns DAO backed by the MPT state of the block preceding
+// the given one with native caches initialized.
+func (bc *Blockchain) getHistoricDAO(index uint32) (*dao.Simple, error) {
 	var mode = mpt.ModeAll
 	if bc.config.Ledger.RemoveUntraceableBlocks {
-		if b.Index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
-			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", b.Index)
+		if index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
+			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", index)
 		}
 		mode |= mpt.ModeGCFlag
 	}
-	if b.Index < 1 || b.Index > bc.BlockHeight()+1 {
-		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", b.Index, bc.blockHeight)
+	if index < 1 || index > bc.BlockHeight()+1 {
+		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", index, bc.blockHeight)
 	}
 	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
-	sr, err := bc.stateRoot.GetStateRoot(b.Index - 1)
+	sr, err := bc.stateRoot.GetStateRoot(index - 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index, err)
+		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", index, err)
 	}
 	s := mpt.NewTrieStore(sr.Root, mode, storage.NewPrivateMemCachedStore(bc.dao.Store))
 	dTrie := dao.NewSimple(s, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
 	dTrie.Version = bc.dao.Version
 	// Initialize native cache before passing DAO to interop context constructor, because
 	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
-	err = bc.initializeNativeCache(b.Index, dTrie)
+	err = bc.initializeNativeCache(index, dTrie)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
 	}
-	systemInterop := bc.newInteropContext(t, dTrie, b, tx)
-	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
-	return systemInterop, nil
+	return dTrie, nil
 }
 
 // getFakeNextBlock returns fake block with the specified index and pre-filled Timestamp field.
@@ -2954,6 +3241,8 @@ func (bc *Blockchain) newInteropContext(trigger trigger.Type, d *dao.Simple, blo
 	}
 	ic := interop.NewContext(trigger, bc, d, baseExecFee, baseStorageFee, native.GetContract, bc.contracts.Contracts, contract.LoadToken, block, tx, bc.log)
 	ic.Functions = systemInterops
+	ic.ExecHook = bc.vmExecHook
+	ic.Profiler = bc.vmProfiler
 	switch {
 	case tx != nil:
 		ic.Container = tx
@@ -2964,6 +3253,23 @@ func (bc *Blockchain) newInteropContext(trigger trigger.Type, d *dao.Simple, blo
 	return ic
 }
 
+// SetVMExecHook sets the hook to be called before each instruction executed
+// by any VM spawned by the chain (both for block processing and test
+// invocations). It's intended to be used for testing and debugging purposes
+// (like coverage collection) and it's not thread-safe, so it should be set
+// before any blocks are processed. nil removes the hook.
+func (bc *Blockchain) SetVMExecHook(h vm.OnExecHook) {
+	bc.vmExecHook = h
+}
+
+// SetVMProfiler sets the gas profiler to be used by any VM spawned by the
+// chain (both for block processing and test invocations). It's intended to be
+// used for testing and debugging and it's not thread-safe, so it should be set
+// before any blocks are processed. nil disables profiling.
+func (bc *Blockchain) SetVMProfiler(p *vm.Profiler) {
+	bc.vmProfiler = p
+}
+
 // P2PSigExtensionsEnabled defines whether P2P signature extensions are enabled.
 func (bc *Blockchain) P2PSigExtensionsEnabled() bool {
 	return bc.config.P2PSigExtensions
@@ -3006,3 +3312,12 @@ func (bc *Blockchain) GetStoragePrice() int64 {
 	}
 	return bc.contracts.Policy.GetStoragePriceInternal(bc.dao)
 }
+
+// endSpan records an error (if any) in the span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -1,6 +1,7 @@
 package core_test
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -37,6 +38,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -46,6 +49,12 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func newLevelDBForTestingWithPath(t testing.TB, dbPath string) (storage.Store, string) {
@@ -141,6 +150,15 @@ func TestBlockchain_StartFromExistingDB(t *testing.T) {
 		require.Error(t, err)
 		require.True(t, strings.Contains(err.Error(), "KeepOnlyLatestState setting mismatch"), err)
 	})
+	t.Run("mismatch ExecutionIndex", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
+			customConfig(c)
+			c.Ledger.ExecutionIndex = true
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "ExecutionIndex setting mismatch"), err)
+	})
 	t.Run("Magic mismatch", func(t *testing.T) {
 		ps = newPS(t)
 		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.Blockchain) {
@@ -800,6 +818,48 @@ func TestBlockchain_GetTransaction(t *testing.T) {
 	assert.Equal(t, b.Transactions[0], tx)
 }
 
+func TestBlockchain_GetTransactionReplayVM(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInv := e.NewInvoker(e.NativeHash(t, nativenames.Gas), acc)
+	to := util.Uint160{1, 2, 3}
+
+	// The second transaction depends on the first one from the same block.
+	tx1 := gasInv.PrepareInvoke(t, "transfer", acc.ScriptHash(), to, 100, nil)
+	tx2 := gasInv.PrepareInvoke(t, "balanceOf", to)
+	e.AddNewBlock(t, tx1, tx2)
+	e.CheckHalt(t, tx2.Hash(), stackitem.Make(100))
+	e.AddNewBlock(t)
+
+	for _, tx := range []*transaction.Transaction{tx1, tx2} {
+		aer := e.GetTxExecResult(t, tx.Hash())
+		ic, err := bc.GetTransactionReplayVM(tx.Hash())
+		require.NoError(t, err)
+		ic.VM.LoadScriptWithFlags(tx.Script, callflag.All)
+		ic.VM.GasLimit = tx.SystemFee
+		require.NoError(t, ic.Exec())
+		require.Equal(t, aer.GasConsumed, ic.VM.GasConsumed())
+		require.Equal(t, aer.Stack, ic.VM.Estack().ToArray())
+		require.Equal(t, len(aer.Events), len(ic.Notifications))
+		for i := range aer.Events {
+			require.Equal(t, aer.Events[i], ic.Notifications[i])
+		}
+		ic.Finalize()
+	}
+	require.Equal(t, int64(100), bc.GetUtilityTokenBalance(to).Int64()) // Not changed by replays.
+
+	_, err := bc.GetTransactionReplayVM(util.Uint256{1, 2, 3})
+	require.Error(t, err)
+
+	bcLatest, accLatest := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.Ledger.KeepOnlyLatestState = true
+	})
+	eLatest := neotest.NewExecutor(t, bcLatest, accLatest, accLatest)
+	h := eLatest.InvokeScript(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{accLatest})
+	_, err = bcLatest.GetTransactionReplayVM(h)
+	require.Error(t, err)
+}
+
 func TestBlockchain_GetClaimable(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 
@@ -938,6 +998,150 @@ func TestBlockchain_Subscriptions(t *testing.T) {
 	e.GenerateNewBlocks(t, 2*chBufSize)
 }
 
+func TestBlockchain_StorageChangeSubscription(t *testing.T) {
+	const chBufSize = 64
+	blockCh := make(chan *block.Block, chBufSize)
+	storageCh := make(chan *state.StorageChangeEvent, chBufSize)
+
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	bc.SubscribeForStorageChanges(storageCh)
+	bc.SubscribeForBlocks(blockCh)
+
+	receiver := util.Uint160{1, 2, 3}
+	e.ValidatorInvoker(gasHash).Invoke(t, true, "transfer", acc.ScriptHash(), receiver, 100500, nil)
+	require.Eventually(t, func() bool { return len(blockCh) != 0 }, time.Second, 10*time.Millisecond)
+	b := <-blockCh
+
+	var (
+		gasID   = bc.GetContractState(gasHash).ID
+		balKey  = append([]byte{20}, receiver.BytesBE()...)
+		found   bool
+		lastKey []byte
+	)
+	require.NotEmpty(t, storageCh)
+	for len(storageCh) != 0 {
+		change := <-storageCh
+		require.Equal(t, b.Hash(), change.BlockHash)
+		require.Equal(t, b.Index, change.BlockIndex)
+		if !change.Contract.Equals(gasHash) {
+			continue
+		}
+		require.True(t, lastKey == nil || bytes.Compare(lastKey, change.Key) < 0, "unordered changes")
+		lastKey = change.Key
+		if bytes.Equal(change.Key, balKey) {
+			require.Equal(t, bc.GetStorageItem(gasID, balKey), state.StorageItem(change.Value))
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	bc.UnsubscribeFromStorageChanges(storageCh)
+	bc.UnsubscribeFromBlocks(blockCh)
+	// Ensure that new blocks are processed correctly after unsubscription.
+	e.GenerateNewBlocks(t, 2)
+	require.Empty(t, storageCh)
+}
+
+func TestBlockchain_Tracing(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+
+	h := e.InvokeScript(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{acc})
+
+	var (
+		addBlock, storeBlock sdktrace.ReadOnlySpan
+		runs                 []sdktrace.ReadOnlySpan
+	)
+	for _, s := range sr.Ended() {
+		switch s.Name() {
+		case "Blockchain.AddBlock":
+			addBlock = s
+		case "Blockchain.storeBlock":
+			storeBlock = s
+		case "VM.Run":
+			if s.Parent().IsValid() {
+				runs = append(runs, s)
+			}
+		}
+	}
+	require.NotNil(t, addBlock)
+	require.NotNil(t, storeBlock)
+	require.Equal(t, addBlock.SpanContext().SpanID(), storeBlock.Parent().SpanID())
+	require.Contains(t, addBlock.Attributes(), attribute.Int64("index", int64(bc.BlockHeight())))
+
+	// OnPersist, transaction and PostPersist.
+	require.Equal(t, 3, len(runs))
+	for _, r := range runs {
+		require.Equal(t, storeBlock.SpanContext().SpanID(), r.Parent().SpanID())
+	}
+	require.Contains(t, runs[0].Attributes(), attribute.String("trigger", trigger.OnPersist.String()))
+	require.Contains(t, runs[1].Attributes(), attribute.String("tx", h.StringLE()))
+	require.Contains(t, runs[1].Attributes(), attribute.String("state", vmstate.Halt.String()))
+	require.Contains(t, runs[2].Attributes(), attribute.String("trigger", trigger.PostPersist.String()))
+
+	err := bc.AddBlock(e.NewUnsignedBlock(t))
+	require.Error(t, err)
+	spans := sr.Ended()
+	last := spans[len(spans)-1]
+	require.Equal(t, "Blockchain.AddBlock", last.Name())
+	require.Equal(t, codes.Error, last.Status().Code)
+}
+
+func TestBlockchain_StorageChangeSubscriptionDestroyed(t *testing.T) {
+	const chBufSize = 64
+	blockCh := make(chan *block.Block, chBufSize)
+	storageCh := make(chan *state.StorageChangeEvent, chBufSize)
+
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	bc.SubscribeForStorageChanges(storageCh)
+	bc.SubscribeForBlocks(blockCh)
+
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/management"
+		"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	)
+	func Put() {
+		storage.Put(storage.GetContext(), "key", "value")
+	}
+	func Destroy() {
+		management.Destroy()
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		Name:        "Destroyed",
+		Permissions: []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	})
+
+	// Contract is deployed, changes its storage and is destroyed in a single block.
+	txDeploy := e.NewDeployTx(t, bc, c, nil)
+	txPut := e.SignTx(t, e.NewUnsignedTx(t, c.Hash, "put"), 1_0000_0000, acc)
+	txDestroy := e.SignTx(t, e.NewUnsignedTx(t, c.Hash, "destroy"), 1_0000_0000, acc)
+	e.AddNewBlock(t, txDeploy, txPut, txDestroy)
+	e.CheckHalt(t, txDeploy.Hash())
+	e.CheckHalt(t, txPut.Hash())
+	e.CheckHalt(t, txDestroy.Hash())
+	require.Nil(t, bc.GetContractState(c.Hash))
+
+	require.Eventually(t, func() bool { return len(blockCh) != 0 }, time.Second, 10*time.Millisecond)
+	b := <-blockCh
+	for len(storageCh) != 0 {
+		change := <-storageCh
+		require.Equal(t, b.Hash(), change.BlockHash)
+		require.False(t, change.Contract.Equals(c.Hash))
+	}
+
+	// Chain is still operational.
+	e.AddNewBlock(t)
+	require.Equal(t, b.Index+1, bc.BlockHeight())
+}
+
 func TestBlockchain_RemoveUntraceable(t *testing.T) {
 	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
 	check := func(t *testing.T, bc *core.Blockchain, tHash, bHash, sHash util.Uint256, errorExpected bool) {
@@ -1062,6 +1266,70 @@ func TestBlockchain_InvalidNotification(t *testing.T) {
 	cValidatorInvoker.Invoke(t, stackitem.NewInterop(nil), "invalidStack2")
 }
 
+func TestBlockchain_ExecutionIndex(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		bc, _ := chain.NewSingle(t)
+		err := bc.ForEachNotification(util.Uint160{}, "", 0, 1, func(*state.NotificationIndexEntry) (bool, error) { return true, nil })
+		require.ErrorIs(t, err, core.ErrExecutionIndexDisabled)
+		err = bc.ForEachTransaction(util.Uint160{}, 0, 1, func(*state.TransactionIndexEntry) (bool, error) { return true, nil })
+		require.ErrorIs(t, err, core.ErrExecutionIndexDisabled)
+	})
+
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.Ledger.ExecutionIndex = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoHash := e.NativeHash(t, nativenames.Neo)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	neoValidatorInvoker := e.ValidatorInvoker(neoHash)
+
+	txH := neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	h := bc.BlockHeight()
+	faultH := neoValidatorInvoker.InvokeFail(t, "", "unknownMethod")
+
+	var neoTransfers []*state.NotificationIndexEntry
+	require.NoError(t, bc.ForEachNotification(neoHash, "Transfer", h, bc.BlockHeight(), func(e *state.NotificationIndexEntry) (bool, error) {
+		neoTransfers = append(neoTransfers, e)
+		return true, nil
+	}))
+	require.Equal(t, 1, len(neoTransfers))
+	require.Equal(t, txH, neoTransfers[0].Container)
+	require.Equal(t, h, neoTransfers[0].BlockIndex)
+	require.Equal(t, uint32(1), neoTransfers[0].Position)
+	require.Equal(t, trigger.Application, neoTransfers[0].Trigger)
+	aer, err := bc.GetAppExecResults(txH, trigger.Application)
+	require.NoError(t, err)
+	require.Equal(t, neoTransfers[0].Name, aer[0].Events[neoTransfers[0].Index].Name)
+
+	// GAS is burnt in OnPersist and minted in PostPersist for every block.
+	var gasNotifications []*state.NotificationIndexEntry
+	require.NoError(t, bc.ForEachNotification(gasHash, "", h, h, func(e *state.NotificationIndexEntry) (bool, error) {
+		gasNotifications = append(gasNotifications, e)
+		return true, nil
+	}))
+	require.True(t, len(gasNotifications) >= 2)
+	require.Equal(t, trigger.OnPersist, gasNotifications[0].Trigger)
+	require.Equal(t, uint32(0), gasNotifications[0].Position)
+	last := gasNotifications[len(gasNotifications)-1]
+	require.Equal(t, trigger.PostPersist, last.Trigger)
+	require.Equal(t, uint32(2), last.Position)
+
+	var txs []util.Uint256
+	require.NoError(t, bc.ForEachTransaction(acc.ScriptHash(), h, bc.BlockHeight(), func(e *state.TransactionIndexEntry) (bool, error) {
+		require.True(t, e.Sender)
+		txs = append(txs, e.Hash)
+		return true, nil
+	}))
+	require.Equal(t, []util.Uint256{txH, faultH}, txs)
+
+	txs = txs[:0]
+	require.NoError(t, bc.ForEachTransaction(acc.ScriptHash(), 0, bc.BlockHeight(), func(e *state.TransactionIndexEntry) (bool, error) {
+		txs = append(txs, e.Hash)
+		return false, nil
+	}))
+	require.Equal(t, 1, len(txs))
+}
+
 // Test that deletion of non-existent doesn't result in error in tx or block addition.
 func TestBlockchain_MPTDeleteNoKey(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
@@ -2268,6 +2536,24 @@ func TestBlockchain_ResetStateErrors(t *testing.T) {
 
 // TestBlockchain_ResetState is based on knowledge about basic chain transactions,
 // it performs basic chain reset and checks that reset chain has proper state.
+func TestBlockchain_ResetRunning(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	h := bc.BlockHeight()
+	for i := 0; i < 3; i++ {
+		e.AddNewBlock(t)
+	}
+	require.ErrorContains(t, bc.Reset(h), "can't reset state of the running blockchain")
+	require.NoError(t, bc.ResetRunning(h))
+	require.Equal(t, h, bc.BlockHeight())
+	require.Equal(t, h, bc.HeaderHeight())
+
+	// Chain can still be extended after reset.
+	e.AddNewBlock(t)
+	require.Equal(t, h+1, bc.BlockHeight())
+}
+
 func TestBlockchain_ResetState(t *testing.T) {
 	// Create the DB.
 	db, path := newLevelDBForTestingWithPath(t, t.TempDir())
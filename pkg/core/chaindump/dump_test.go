@@ -1,11 +1,16 @@
 package chaindump_test
 
 import (
+	"bytes"
 	"errors"
+	"hash/crc32"
+	gio "io"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/nspcc-dev/neo-go/internal/basicchain"
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
 	"github.com/nspcc-dev/neo-go/pkg/io"
@@ -90,3 +95,147 @@ func testDumpAndRestore(t *testing.T, dumpF, restoreF func(c *config.Blockchain)
 		})
 	})
 }
+
+// streamReader hides Seek method of the underlying reader.
+type streamReader struct {
+	r gio.Reader
+}
+
+func (s streamReader) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+func TestBlockchain_DumpAndRestoreIndexed(t *testing.T) {
+	bc, validators, committee := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+		c.P2PSigExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, validators, committee)
+
+	basicchain.Init(t, "../../../", e)
+	height := bc.BlockHeight()
+	require.True(t, height > 10) // ensure that test is valid
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, chaindump.DumpIndexed(bc, buf, 0, height+1, 3))
+	dump := buf.Bytes()
+
+	newChain := func(t *testing.T) *core.Blockchain {
+		bc2, _, _ := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+			c.P2PSigExtensions = true
+		})
+		return bc2
+	}
+	newReader := func(t *testing.T, r gio.Reader, indexed bool) *chaindump.IndexedReader {
+		r, ok, err := chaindump.Detect(r)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ir, err := chaindump.NewIndexedReader(r)
+		require.NoError(t, err)
+		require.Equal(t, chaindump.IndexedHeader{Start: 0, Count: height + 1, ChunkSize: 3}, ir.Header())
+		require.Equal(t, indexed, ir.HasIndex())
+		return ir
+	}
+	checkChain := func(t *testing.T, bc2 *core.Blockchain) {
+		require.Equal(t, height, bc2.BlockHeight())
+		require.Equal(t, bc.CurrentBlockHash(), bc2.CurrentBlockHash())
+	}
+
+	t.Run("legacy detection", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		w.WriteU32LE(height + 1)
+		require.NoError(t, chaindump.Dump(bc, w.BinWriter, 0, height+1))
+		_, ok, err := chaindump.Detect(bytes.NewReader(w.Bytes()))
+		require.NoError(t, err)
+		require.False(t, ok)
+		_, ok, err = chaindump.Detect(streamReader{bytes.NewReader(w.Bytes())})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+	t.Run("full", func(t *testing.T) {
+		bc2 := newChain(t)
+		ir := newReader(t, bytes.NewReader(dump), true)
+		require.NoError(t, ir.Restore(bc2, 0, height+1, nil))
+		checkChain(t, bc2)
+	})
+	for name, indexed := range map[string]bool{"seek": true, "stream": false} {
+		t.Run("resume, "+name, func(t *testing.T) {
+			bc2 := newChain(t)
+			var r gio.Reader = bytes.NewReader(dump)
+			if !indexed {
+				r = streamReader{r}
+			}
+			ir := newReader(t, r, indexed)
+			require.NoError(t, ir.Restore(bc2, 0, 5, nil))
+			require.Equal(t, uint32(4), bc2.BlockHeight())
+
+			r = bytes.NewReader(dump)
+			if !indexed {
+				r = streamReader{r}
+			}
+			ir = newReader(t, r, indexed)
+			var lastIndex uint32
+			require.NoError(t, ir.Restore(bc2, 5, height-4, func(b *block.Block) error {
+				lastIndex = b.Index
+				return nil
+			}))
+			require.Equal(t, height, lastIndex)
+			checkChain(t, bc2)
+		})
+	}
+	t.Run("out of range", func(t *testing.T) {
+		bc2 := newChain(t)
+		ir := newReader(t, bytes.NewReader(dump), true)
+		require.Error(t, ir.Restore(bc2, 1, height+1, nil))
+	})
+	t.Run("interrupted dump", func(t *testing.T) {
+		// Last 5 bytes of the last chunk and the index are missing.
+		cut := dump[:len(dump)-5-(int(height/3)+1)*8-4-8-len("NEOGOIDX")]
+		bc2 := newChain(t)
+		ir := newReader(t, bytes.NewReader(cut), false)
+		lastChunk := height - height%3
+		require.NoError(t, ir.Restore(bc2, 0, lastChunk, nil))
+		require.Equal(t, lastChunk-1, bc2.BlockHeight())
+		require.Error(t, newReader(t, bytes.NewReader(cut), false).Restore(bc2, lastChunk, height+1-lastChunk, nil))
+	})
+	t.Run("corrupted chunk", func(t *testing.T) {
+		bad := append([]byte(nil), dump...)
+		bad[len(chaindump.IndexedMagic)+14+16+1] ^= 0xff // First chunk payload.
+		bc2 := newChain(t)
+		ir := newReader(t, bytes.NewReader(bad), true)
+		require.ErrorIs(t, ir.Restore(bc2, 0, height+1, nil), chaindump.ErrChecksumMismatch)
+		require.Equal(t, uint32(0), bc2.BlockHeight())
+	})
+	t.Run("corrupted index", func(t *testing.T) {
+		bad := append([]byte(nil), dump...)
+		bad[len(bad)-8-len("NEOGOIDX")-4-1] ^= 0xff // Last index entry.
+		_, err := chaindump.NewIndexedReader(bytes.NewReader(bad))
+		require.ErrorIs(t, err, chaindump.ErrChecksumMismatch)
+	})
+	t.Run("oversized chunk", func(t *testing.T) {
+		bc2 := newChain(t)
+		enc, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		// Single-block chunk that is decompressed into much more than
+		// a block can take.
+		payload := enc.EncodeAll(make([]byte, 4*bc2.GetConfig().MaxBlockSize), nil)
+		require.NoError(t, enc.Close())
+
+		w := io.NewBufBinWriter()
+		w.WriteBytes([]byte(chaindump.IndexedMagic))
+		w.WriteB(0) // Version.
+		w.WriteB(1) // Zstd.
+		w.WriteU32LE(0)
+		w.WriteU32LE(1)
+		w.WriteU32LE(1)
+		w.WriteU32LE(0)
+		w.WriteU32LE(1)
+		w.WriteU32LE(uint32(len(payload)))
+		w.WriteU32LE(crc32.Checksum(payload, crc32.MakeTable(crc32.Castagnoli)))
+		w.WriteBytes(payload)
+		require.NoError(t, w.Err)
+
+		ir, err := chaindump.NewIndexedReader(streamReader{bytes.NewReader(w.Bytes())})
+		require.NoError(t, err)
+		require.ErrorContains(t, ir.Restore(bc2, 0, 1, nil), "failed to decompress chunk")
+	})
+}
@@ -0,0 +1,797 @@
+package chaindump
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// Supported export formats.
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// Exported datasets, every one of them is stored in a separate directory.
+const (
+	ExportBlocks         = "blocks"
+	ExportTransactions   = "transactions"
+	ExportSigners        = "signers"
+	ExportAppLogs        = "applogs"
+	ExportNotifications  = "notifications"
+	ExportNEP17Transfers = "nep17transfers"
+	ExportNEP11Transfers = "nep11transfers"
+)
+
+// ExportCheckpointFile is the name of the file with the resume point stored in
+// the export directory.
+const ExportCheckpointFile = "export.json"
+
+// DefaultExportPartitionSize is the default number of blocks per exported
+// file.
+const DefaultExportPartitionSize = 10000
+
+// ExportDatasets contains all datasets in the order of export.
+var ExportDatasets = []string{ExportBlocks, ExportTransactions, ExportSigners,
+	ExportAppLogs, ExportNotifications, ExportNEP17Transfers, ExportNEP11Transfers}
+
+// ExportSource is an interface to get chain data to export from.
+type ExportSource interface {
+	BlockHeight() uint32
+	GetAppExecResults(util.Uint256, trigger.Type) ([]state.AppExecResult, error)
+	GetBlock(hash util.Uint256) (*block.Block, error)
+	GetHeaderHash(uint32) util.Uint256
+}
+
+// ExportOptions are parameters of Export.
+type ExportOptions struct {
+	// Dir is the directory to export data to.
+	Dir string
+	// Format is either ExportFormatNDJSON or ExportFormatCSV.
+	Format string
+	// Datasets to export, all ExportDatasets are exported if empty.
+	Datasets []string
+	// Start is the first block to export. The export continues from the
+	// resume point if the directory already contains some data, Start and
+	// Count still define the last block to export then.
+	Start uint32
+	// Count is the number of blocks to export, everything up to the current
+	// chain height is exported if it's 0.
+	Count uint32
+	// PartitionSize is the number of blocks per file, DefaultExportPartitionSize
+	// is used if it's 0. Partitions are aligned to multiples of it.
+	PartitionSize uint32
+	// Workers is the number of partitions exported in parallel (1 if not set).
+	Workers int
+	// Progress is called (from a single goroutine) after every exported
+	// partition if set.
+	Progress func(from, to uint32)
+}
+
+// exportCheckpoint is the resume point stored in ExportCheckpointFile. All
+// blocks before Next are exported completely.
+type exportCheckpoint struct {
+	Format        string   `json:"format"`
+	Datasets      []string `json:"datasets"`
+	PartitionSize uint32   `json:"partitionsize"`
+	Next          uint32   `json:"next"`
+}
+
+type exportPartition struct {
+	from, to uint32
+}
+
+type exportResult struct {
+	part exportPartition
+	err  error
+}
+
+// Export streams blocks, transactions, signers, application logs,
+// notifications and token transfers from the given range to files
+// partitioned by height (Dir/<dataset>/<from>-<to>.<format>). Partitions are
+// exported by a number of parallel workers, complete files only appear when
+// the whole partition is exported, the resume point is updated when all
+// partitions before it are done, so interrupted exports can be continued by
+// calling Export with the same directory and parameters again. NEP-17 and
+// NEP-11 transfers are detected the same way the node does it for transfer
+// logs: by `Transfer` notifications with 3 and 4 parameters correspondingly.
+func Export(ctx context.Context, bc ExportSource, opts ExportOptions) error {
+	if opts.Format != ExportFormatNDJSON && opts.Format != ExportFormatCSV {
+		return fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	if len(opts.Datasets) == 0 {
+		opts.Datasets = ExportDatasets
+	}
+	for _, ds := range opts.Datasets {
+		if _, ok := exportHeaders[ds]; !ok {
+			return fmt.Errorf("unknown dataset %q", ds)
+		}
+	}
+	if opts.PartitionSize == 0 {
+		opts.PartitionSize = DefaultExportPartitionSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	var end = bc.BlockHeight()
+	if opts.Count != 0 && opts.Start+opts.Count-1 < end {
+		end = opts.Start + opts.Count - 1
+	}
+	for _, ds := range opts.Datasets {
+		if err := os.MkdirAll(filepath.Join(opts.Dir, ds), os.ModePerm); err != nil {
+			return err
+		}
+	}
+	cp, err := readExportCheckpoint(opts.Dir)
+	if err != nil {
+		return err
+	}
+	if cp != nil {
+		if cp.Format != opts.Format || cp.PartitionSize != opts.PartitionSize ||
+			strings.Join(cp.Datasets, ",") != strings.Join(opts.Datasets, ",") {
+			return errors.New("the directory contains export with different format, datasets or partition size")
+		}
+		opts.Start = cp.Next
+	} else {
+		cp = &exportCheckpoint{
+			Format:        opts.Format,
+			Datasets:      opts.Datasets,
+			PartitionSize: opts.PartitionSize,
+			Next:          opts.Start,
+		}
+	}
+	if opts.Start > end {
+		return nil
+	}
+
+	var parts []exportPartition
+	for from := opts.Start; from <= end; {
+		to := (from/opts.PartitionSize+1)*opts.PartitionSize - 1
+		if to > end || to < from { // The latter is an overflow.
+			to = end
+		}
+		parts = append(parts, exportPartition{from: from, to: to})
+		if to == end {
+			break
+		}
+		from = to + 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		jobs    = make(chan exportPartition)
+		results = make(chan exportResult)
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				results <- exportResult{part: p, err: exportPart(ctx, bc, opts, p)}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, p := range parts {
+			select {
+			case jobs <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var done = make(map[uint32]uint32)
+	for res := range results {
+		if res.err != nil {
+			if err == nil {
+				err = fmt.Errorf("failed to export blocks %d-%d: %w", res.part.from, res.part.to, res.err)
+			}
+			cancel()
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if opts.Progress != nil {
+			opts.Progress(res.part.from, res.part.to)
+		}
+		done[res.part.from] = res.part.to
+		var advanced bool
+		for to, ok := done[cp.Next]; ok; to, ok = done[cp.Next] {
+			delete(done, cp.Next)
+			cp.Next = to + 1
+			advanced = true
+		}
+		if advanced {
+			if err = writeExportCheckpoint(opts.Dir, cp); err != nil {
+				cancel()
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func readExportCheckpoint(dir string) (*exportCheckpoint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ExportCheckpointFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cp := new(exportCheckpoint)
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid resume point: %w", err)
+	}
+	return cp, nil
+}
+
+func writeExportCheckpoint(dir string, cp *exportCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(dir, ExportCheckpointFile)
+	if err := os.WriteFile(name+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(name+".tmp", name)
+}
+
+// exportPart exports a single partition to temporary files and renames them
+// once everything is written.
+func exportPart(ctx context.Context, bc ExportSource, opts ExportOptions, p exportPartition) (err error) {
+	var (
+		names   = make(map[string]string, len(opts.Datasets))
+		writers = make(map[string]rowWriter, len(opts.Datasets))
+	)
+	defer func() {
+		for _, w := range writers {
+			if cErr := w.close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+		for _, name := range names {
+			if err != nil {
+				_ = os.Remove(name + ".tmp")
+				continue
+			}
+			if rErr := os.Rename(name+".tmp", name); rErr != nil && err == nil {
+				err = rErr
+			}
+		}
+	}()
+	for _, ds := range opts.Datasets {
+		name := filepath.Join(opts.Dir, ds, fmt.Sprintf("%010d-%010d.%s", p.from, p.to, opts.Format))
+		w, err := newRowWriter(name+".tmp", opts.Format, exportHeaders[ds])
+		if err != nil {
+			return err
+		}
+		names[ds] = name
+		writers[ds] = w
+	}
+	for i := p.from; i <= p.to; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := bc.GetBlock(bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		if err := exportBlock(bc, b, writers); err != nil {
+			return fmt.Errorf("block %d: %w", i, err)
+		}
+		if i == p.to { // Overflow protection.
+			break
+		}
+	}
+	return nil
+}
+
+// exportBlock writes all the data related to the given block.
+func exportBlock(bc ExportSource, b *block.Block, writers map[string]rowWriter) error {
+	var write = func(ds string, r exportRow) error {
+		if w, ok := writers[ds]; ok {
+			return w.write(r)
+		}
+		return nil
+	}
+	if err := write(ExportBlocks, newBlockRow(b)); err != nil {
+		return err
+	}
+	execs, err := bc.GetAppExecResults(b.Hash(), trigger.All)
+	if err != nil {
+		return fmt.Errorf("failed to get block application logs: %w", err)
+	}
+	var sorted = make([]state.AppExecResult, 0, len(execs)+len(b.Transactions))
+	for _, e := range execs {
+		if e.Trigger == trigger.OnPersist {
+			sorted = append(sorted, e)
+		}
+	}
+	for i, tx := range b.Transactions {
+		if err := write(ExportTransactions, newTransactionRow(b, i, tx)); err != nil {
+			return err
+		}
+		for j := range tx.Signers {
+			if err := write(ExportSigners, newSignerRow(b, tx, j)); err != nil {
+				return err
+			}
+		}
+		txExecs, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return fmt.Errorf("failed to get application log of %s: %w", tx.Hash().StringLE(), err)
+		}
+		sorted = append(sorted, txExecs...)
+	}
+	for _, e := range execs {
+		if e.Trigger == trigger.PostPersist {
+			sorted = append(sorted, e)
+		}
+	}
+	for _, e := range sorted {
+		if err := write(ExportAppLogs, newAppLogRow(b, &e)); err != nil {
+			return err
+		}
+		for i := range e.Events {
+			if err := write(ExportNotifications, newNotificationRow(b, &e, i)); err != nil {
+				return err
+			}
+			r, isNEP11 := newTransferRow(b, &e, i)
+			if r == nil {
+				continue
+			}
+			ds := ExportNEP17Transfers
+			if isNEP11 {
+				ds = ExportNEP11Transfers
+			}
+			if err := write(ds, r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportRow is a single record of some dataset, it's serialized to JSON as
+// is for NDJSON and has a set of columns for CSV.
+type exportRow interface {
+	csvRecord() []string
+}
+
+// exportHeaders contains CSV headers of all datasets (matching JSON fields).
+var exportHeaders = map[string][]string{
+	ExportBlocks:         {"index", "hash", "size", "version", "previousblockhash", "merkleroot", "time", "nonce", "primary", "nextconsensus", "txcount"},
+	ExportTransactions:   {"hash", "blockindex", "index", "size", "version", "nonce", "sender", "sysfee", "netfee", "validuntilblock", "attributes", "script"},
+	ExportSigners:        {"txhash", "blockindex", "index", "account", "scopes", "allowedcontracts", "allowedgroups", "rules"},
+	ExportAppLogs:        {"container", "blockindex", "trigger", "vmstate", "gasconsumed", "exception", "stack", "notifications"},
+	ExportNotifications:  {"container", "blockindex", "trigger", "index", "contract", "eventname", "state"},
+	ExportNEP17Transfers: {"container", "blockindex", "time", "index", "contract", "from", "to", "amount"},
+	ExportNEP11Transfers: {"container", "blockindex", "time", "index", "contract", "from", "to", "amount", "tokenid"},
+}
+
+type blockRow struct {
+	Index         uint32       `json:"index"`
+	Hash          util.Uint256 `json:"hash"`
+	Size          int          `json:"size"`
+	Version       uint32       `json:"version"`
+	PrevHash      util.Uint256 `json:"previousblockhash"`
+	MerkleRoot    util.Uint256 `json:"merkleroot"`
+	Timestamp     uint64       `json:"time"`
+	Nonce         uint64       `json:"nonce,string"`
+	PrimaryIndex  byte         `json:"primary"`
+	NextConsensus string       `json:"nextconsensus"`
+	TxCount       int          `json:"txcount"`
+}
+
+func newBlockRow(b *block.Block) *blockRow {
+	return &blockRow{
+		Index:         b.Index,
+		Hash:          b.Hash(),
+		Size:          io.GetVarSize(b),
+		Version:       b.Version,
+		PrevHash:      b.PrevHash,
+		MerkleRoot:    b.MerkleRoot,
+		Timestamp:     b.Timestamp,
+		Nonce:         b.Nonce,
+		PrimaryIndex:  b.PrimaryIndex,
+		NextConsensus: address.Uint160ToString(b.NextConsensus),
+		TxCount:       len(b.Transactions),
+	}
+}
+
+func (r *blockRow) csvRecord() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.Index), 10),
+		"0x" + r.Hash.StringLE(),
+		strconv.Itoa(r.Size),
+		strconv.FormatUint(uint64(r.Version), 10),
+		"0x" + r.PrevHash.StringLE(),
+		"0x" + r.MerkleRoot.StringLE(),
+		strconv.FormatUint(r.Timestamp, 10),
+		strconv.FormatUint(r.Nonce, 10),
+		strconv.Itoa(int(r.PrimaryIndex)),
+		r.NextConsensus,
+		strconv.Itoa(r.TxCount),
+	}
+}
+
+type transactionRow struct {
+	Hash            util.Uint256            `json:"hash"`
+	BlockIndex      uint32                  `json:"blockindex"`
+	Index           int                     `json:"index"`
+	Size            int                     `json:"size"`
+	Version         uint8                   `json:"version"`
+	Nonce           uint32                  `json:"nonce"`
+	Sender          string                  `json:"sender"`
+	SystemFee       int64                   `json:"sysfee"`
+	NetworkFee      int64                   `json:"netfee"`
+	ValidUntilBlock uint32                  `json:"validuntilblock"`
+	Attributes      []transaction.Attribute `json:"attributes"`
+	Script          []byte                  `json:"script"`
+}
+
+func newTransactionRow(b *block.Block, i int, tx *transaction.Transaction) *transactionRow {
+	var attrs = tx.Attributes
+	if attrs == nil {
+		attrs = []transaction.Attribute{}
+	}
+	return &transactionRow{
+		Hash:            tx.Hash(),
+		BlockIndex:      b.Index,
+		Index:           i,
+		Size:            tx.Size(),
+		Version:         tx.Version,
+		Nonce:           tx.Nonce,
+		Sender:          address.Uint160ToString(tx.Sender()),
+		SystemFee:       tx.SystemFee,
+		NetworkFee:      tx.NetworkFee,
+		ValidUntilBlock: tx.ValidUntilBlock,
+		Attributes:      attrs,
+		Script:          tx.Script,
+	}
+}
+
+func (r *transactionRow) csvRecord() []string {
+	return []string{
+		"0x" + r.Hash.StringLE(),
+		strconv.FormatUint(uint64(r.BlockIndex), 10),
+		strconv.Itoa(r.Index),
+		strconv.Itoa(r.Size),
+		strconv.Itoa(int(r.Version)),
+		strconv.FormatUint(uint64(r.Nonce), 10),
+		r.Sender,
+		strconv.FormatInt(r.SystemFee, 10),
+		strconv.FormatInt(r.NetworkFee, 10),
+		strconv.FormatUint(uint64(r.ValidUntilBlock), 10),
+		jsonColumn(r.Attributes),
+		base64.StdEncoding.EncodeToString(r.Script),
+	}
+}
+
+type signerRow struct {
+	TxHash           util.Uint256              `json:"txhash"`
+	BlockIndex       uint32                    `json:"blockindex"`
+	Index            int                       `json:"index"`
+	Account          string                    `json:"account"`
+	Scopes           transaction.WitnessScope  `json:"scopes"`
+	AllowedContracts []util.Uint160            `json:"allowedcontracts"`
+	AllowedGroups    []string                  `json:"allowedgroups"`
+	Rules            []transaction.WitnessRule `json:"rules"`
+}
+
+func newSignerRow(b *block.Block, tx *transaction.Transaction, i int) *signerRow {
+	s := tx.Signers[i]
+	r := &signerRow{
+		TxHash:           tx.Hash(),
+		BlockIndex:       b.Index,
+		Index:            i,
+		Account:          address.Uint160ToString(s.Account),
+		Scopes:           s.Scopes,
+		AllowedContracts: s.AllowedContracts,
+		AllowedGroups:    make([]string, len(s.AllowedGroups)),
+		Rules:            s.Rules,
+	}
+	for j, g := range s.AllowedGroups {
+		r.AllowedGroups[j] = hex.EncodeToString(g.Bytes())
+	}
+	if r.AllowedContracts == nil {
+		r.AllowedContracts = []util.Uint160{}
+	}
+	if r.Rules == nil {
+		r.Rules = []transaction.WitnessRule{}
+	}
+	return r
+}
+
+func (r *signerRow) csvRecord() []string {
+	var contracts = make([]string, len(r.AllowedContracts))
+	for i, c := range r.AllowedContracts {
+		contracts[i] = "0x" + c.StringLE()
+	}
+	scopes, _ := r.Scopes.MarshalJSON()
+	return []string{
+		"0x" + r.TxHash.StringLE(),
+		strconv.FormatUint(uint64(r.BlockIndex), 10),
+		strconv.Itoa(r.Index),
+		r.Account,
+		strings.Trim(string(scopes), `"`),
+		strings.Join(contracts, ","),
+		strings.Join(r.AllowedGroups, ","),
+		jsonColumn(r.Rules),
+	}
+}
+
+type appLogRow struct {
+	Container     util.Uint256      `json:"container"`
+	BlockIndex    uint32            `json:"blockindex"`
+	Trigger       string            `json:"trigger"`
+	VMState       string            `json:"vmstate"`
+	GasConsumed   int64             `json:"gasconsumed"`
+	Exception     string            `json:"exception"`
+	Stack         []json.RawMessage `json:"stack"`
+	Notifications int               `json:"notifications"`
+}
+
+func newAppLogRow(b *block.Block, e *state.AppExecResult) *appLogRow {
+	r := &appLogRow{
+		Container:     e.Container,
+		BlockIndex:    b.Index,
+		Trigger:       e.Trigger.String(),
+		VMState:       e.VMState.String(),
+		GasConsumed:   e.GasConsumed,
+		Exception:     e.FaultException,
+		Stack:         make([]json.RawMessage, len(e.Stack)),
+		Notifications: len(e.Events),
+	}
+	for i := range e.Stack {
+		r.Stack[i] = itemJSON(e.Stack[i])
+	}
+	return r
+}
+
+func (r *appLogRow) csvRecord() []string {
+	return []string{
+		"0x" + r.Container.StringLE(),
+		strconv.FormatUint(uint64(r.BlockIndex), 10),
+		r.Trigger,
+		r.VMState,
+		strconv.FormatInt(r.GasConsumed, 10),
+		r.Exception,
+		jsonColumn(r.Stack),
+		strconv.Itoa(r.Notifications),
+	}
+}
+
+type notificationRow struct {
+	Container  util.Uint256    `json:"container"`
+	BlockIndex uint32          `json:"blockindex"`
+	Trigger    string          `json:"trigger"`
+	Index      int             `json:"index"`
+	Contract   util.Uint160    `json:"contract"`
+	Name       string          `json:"eventname"`
+	State      json.RawMessage `json:"state"`
+}
+
+func newNotificationRow(b *block.Block, e *state.AppExecResult, i int) *notificationRow {
+	return &notificationRow{
+		Container:  e.Container,
+		BlockIndex: b.Index,
+		Trigger:    e.Trigger.String(),
+		Index:      i,
+		Contract:   e.Events[i].ScriptHash,
+		Name:       e.Events[i].Name,
+		State:      itemJSON(e.Events[i].Item),
+	}
+}
+
+func (r *notificationRow) csvRecord() []string {
+	return []string{
+		"0x" + r.Container.StringLE(),
+		strconv.FormatUint(uint64(r.BlockIndex), 10),
+		r.Trigger,
+		strconv.Itoa(r.Index),
+		"0x" + r.Contract.StringLE(),
+		r.Name,
+		string(r.State),
+	}
+}
+
+// transferRow is a NEP-17 or NEP-11 transfer, empty From and To are used for
+// minting and burning, TokenID is only set for NEP-11 transfers.
+type transferRow struct {
+	Container  util.Uint256 `json:"container"`
+	BlockIndex uint32       `json:"blockindex"`
+	Timestamp  uint64       `json:"time"`
+	Index      int          `json:"index"`
+	Contract   util.Uint160 `json:"contract"`
+	From       string       `json:"from"`
+	To         string       `json:"to"`
+	Amount     string       `json:"amount"`
+	TokenID    *string      `json:"tokenid,omitempty"`
+}
+
+// newTransferRow parses i-th notification of the execution as a token
+// transfer, nil is returned if it's not a transfer. Notifications of faulted
+// executions are kept, but transfers are only applied on HALT, so nil is
+// returned for them as well.
+func newTransferRow(b *block.Block, e *state.AppExecResult, i int) (*transferRow, bool) {
+	if e.VMState != vmstate.Halt {
+		return nil, false
+	}
+	note := e.Events[i]
+	if note.Name != "Transfer" {
+		return nil, false
+	}
+	arr, ok := note.Item.Value().([]stackitem.Item)
+	if !ok || !(len(arr) == 3 || len(arr) == 4) {
+		return nil, false
+	}
+	from, err := transferAddress(arr[0])
+	if err != nil {
+		return nil, false
+	}
+	to, err := transferAddress(arr[1])
+	if err != nil {
+		return nil, false
+	}
+	amount, err := arr[2].TryInteger()
+	if err != nil {
+		return nil, false
+	}
+	r := &transferRow{
+		Container:  e.Container,
+		BlockIndex: b.Index,
+		Timestamp:  b.Timestamp,
+		Index:      i,
+		Contract:   note.ScriptHash,
+		From:       from,
+		To:         to,
+		Amount:     amount.String(),
+	}
+	if len(arr) == 4 {
+		id, err := arr[3].TryBytes()
+		if err != nil {
+			return nil, false
+		}
+		s := hex.EncodeToString(id)
+		r.TokenID = &s
+	}
+	return r, r.TokenID != nil
+}
+
+// transferAddress converts transfer sender or receiver to address, Null is
+// converted to an empty string.
+func transferAddress(itm stackitem.Item) (string, error) {
+	if _, ok := itm.(stackitem.Null); ok {
+		return "", nil
+	}
+	b, err := itm.TryBytes()
+	if err != nil {
+		return "", err
+	}
+	u, err := util.Uint160DecodeBytesBE(b)
+	if err != nil {
+		return "", err
+	}
+	return address.Uint160ToString(u), nil
+}
+
+func (r *transferRow) csvRecord() []string {
+	res := []string{
+		"0x" + r.Container.StringLE(),
+		strconv.FormatUint(uint64(r.BlockIndex), 10),
+		strconv.FormatUint(r.Timestamp, 10),
+		strconv.Itoa(r.Index),
+		"0x" + r.Contract.StringLE(),
+		r.From,
+		r.To,
+		r.Amount,
+	}
+	if r.TokenID != nil {
+		res = append(res, *r.TokenID)
+	}
+	return res
+}
+
+// itemJSON converts stack item to JSON the same way application logs do.
+func itemJSON(itm stackitem.Item) json.RawMessage {
+	data, err := stackitem.ToJSONWithTypes(itm)
+	if err != nil {
+		data = []byte(fmt.Sprintf(`"error: %v"`, err))
+	}
+	return data
+}
+
+// jsonColumn serializes complex value to JSON for CSV column.
+func jsonColumn(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// rowWriter writes dataset rows to a file.
+type rowWriter interface {
+	write(exportRow) error
+	close() error
+}
+
+type fileRowWriter struct {
+	f   *os.File
+	buf *bufio.Writer
+	enc *json.Encoder
+	csv *csv.Writer
+}
+
+func newRowWriter(name string, format string, header []string) (rowWriter, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &fileRowWriter{f: f, buf: bufio.NewWriter(f)}
+	if format == ExportFormatCSV {
+		w.csv = csv.NewWriter(w.buf)
+		if err := w.csv.Write(header); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	} else {
+		w.enc = json.NewEncoder(w.buf)
+	}
+	return w, nil
+}
+
+func (w *fileRowWriter) write(r exportRow) error {
+	if w.csv != nil {
+		return w.csv.Write(r.csvRecord())
+	}
+	return w.enc.Encode(r)
+}
+
+func (w *fileRowWriter) close() error {
+	var err error
+	if w.csv != nil {
+		w.csv.Flush()
+		err = w.csv.Error()
+	}
+	if fErr := w.buf.Flush(); err == nil {
+		err = fErr
+	}
+	if cErr := w.f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
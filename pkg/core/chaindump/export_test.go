@@ -0,0 +1,181 @@
+package chaindump_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/basicchain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+// readNDJSON returns all records of the dataset from the export directory.
+func readNDJSON(t *testing.T, dir string, ds string) []map[string]any {
+	files, err := filepath.Glob(filepath.Join(dir, ds, "*.ndjson"))
+	require.NoError(t, err)
+	var res []map[string]any
+	for _, name := range files {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, 1024*1024)
+		for sc.Scan() {
+			var m map[string]any
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+			res = append(res, m)
+		}
+		require.NoError(t, sc.Err())
+		require.NoError(t, f.Close())
+	}
+	return res
+}
+
+func TestExport(t *testing.T) {
+	bc, validators, committee := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+		c.P2PSigExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	basicchain.Init(t, "../../../", e)
+
+	var txCount int
+	for i := uint32(0); i <= bc.BlockHeight(); i++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(i))
+		require.NoError(t, err)
+		txCount += len(b.Transactions)
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		dir := t.TempDir()
+		var parts int
+		opts := chaindump.ExportOptions{
+			Dir:           dir,
+			Format:        chaindump.ExportFormatNDJSON,
+			Count:         bc.BlockHeight() - 2,
+			PartitionSize: 5,
+			Workers:       3,
+			Progress:      func(from, to uint32) { parts++ },
+		}
+		require.NoError(t, chaindump.Export(context.Background(), bc, opts))
+		require.Equal(t, int(bc.BlockHeight()-3)/5+1, parts)
+
+		// Resume up to the current height.
+		opts.Count = 0
+		require.NoError(t, chaindump.Export(context.Background(), bc, opts))
+		data, err := os.ReadFile(filepath.Join(dir, chaindump.ExportCheckpointFile))
+		require.NoError(t, err)
+		var cp map[string]any
+		require.NoError(t, json.Unmarshal(data, &cp))
+		require.EqualValues(t, bc.BlockHeight()+1, cp["next"])
+
+		blocks := readNDJSON(t, dir, chaindump.ExportBlocks)
+		require.Equal(t, int(bc.BlockHeight()+1), len(blocks))
+		seen := make(map[float64]bool)
+		for _, b := range blocks {
+			seen[b["index"].(float64)] = true
+		}
+		require.Equal(t, len(blocks), len(seen))
+
+		require.Equal(t, txCount, len(readNDJSON(t, dir, chaindump.ExportTransactions)))
+		require.True(t, len(readNDJSON(t, dir, chaindump.ExportSigners)) >= txCount)
+		// Every block has OnPersist and PostPersist executions.
+		require.Equal(t, txCount+2*len(blocks), len(readNDJSON(t, dir, chaindump.ExportAppLogs)))
+		require.NotEmpty(t, readNDJSON(t, dir, chaindump.ExportNotifications))
+		require.NotEmpty(t, readNDJSON(t, dir, chaindump.ExportNEP17Transfers))
+		nep11 := readNDJSON(t, dir, chaindump.ExportNEP11Transfers)
+		require.NotEmpty(t, nep11)
+		require.NotEmpty(t, nep11[0]["tokenid"])
+
+		tmp, err := filepath.Glob(filepath.Join(dir, "*", "*.tmp"))
+		require.NoError(t, err)
+		require.Empty(t, tmp)
+
+		// Parameters can't be changed for the same directory.
+		opts.Format = chaindump.ExportFormatCSV
+		require.Error(t, chaindump.Export(context.Background(), bc, opts))
+	})
+	t.Run("csv", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, chaindump.Export(context.Background(), bc, chaindump.ExportOptions{
+			Dir:      dir,
+			Format:   chaindump.ExportFormatCSV,
+			Datasets: []string{chaindump.ExportTransactions},
+		}))
+		_, err := os.Stat(filepath.Join(dir, chaindump.ExportBlocks))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		files, err := filepath.Glob(filepath.Join(dir, chaindump.ExportTransactions, "*.csv"))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(files))
+		f, err := os.Open(files[0])
+		require.NoError(t, err)
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, txCount+1, len(records))
+		require.Equal(t, "hash", records[0][0])
+	})
+	t.Run("bad parameters", func(t *testing.T) {
+		require.Error(t, chaindump.Export(context.Background(), bc, chaindump.ExportOptions{
+			Dir:    t.TempDir(),
+			Format: "xml",
+		}))
+		require.Error(t, chaindump.Export(context.Background(), bc, chaindump.ExportOptions{
+			Dir:      t.TempDir(),
+			Format:   chaindump.ExportFormatNDJSON,
+			Datasets: []string{"accounts"},
+		}))
+	})
+	t.Run("canceled", func(t *testing.T) {
+		dir := t.TempDir()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.Error(t, chaindump.Export(ctx, bc, chaindump.ExportOptions{
+			Dir:    dir,
+			Format: chaindump.ExportFormatNDJSON,
+		}))
+		_, err := os.Stat(filepath.Join(dir, chaindump.ExportCheckpointFile))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestExportFaultedTransfer(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+
+	// Transfer is made and notified, but the transaction FAULTs afterwards.
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, gasHash, "transfer", callflag.All, acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	emit.Opcodes(w.BinWriter, opcode.ABORT)
+	require.NoError(t, w.Err)
+	txHash := e.InvokeScriptCheckFAULT(t, w.Bytes(), []neotest.Signer{acc}, "ABORT")
+
+	dir := t.TempDir()
+	require.NoError(t, chaindump.Export(context.Background(), bc, chaindump.ExportOptions{
+		Dir:      dir,
+		Format:   chaindump.ExportFormatNDJSON,
+		Datasets: []string{chaindump.ExportNotifications, chaindump.ExportNEP17Transfers},
+	}))
+	var notified bool
+	for _, n := range readNDJSON(t, dir, chaindump.ExportNotifications) {
+		notified = notified || n["container"] == "0x"+txHash.StringLE()
+	}
+	require.True(t, notified)
+	for _, tr := range readNDJSON(t, dir, chaindump.ExportNEP17Transfers) {
+		require.NotEqual(t, "0x"+txHash.StringLE(), tr["container"])
+	}
+}
@@ -0,0 +1,446 @@
+package chaindump
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	gio "io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// Indexed dump format is a sequence of independently compressed chunks of
+// blocks with a block height index at the end of the file:
+//
+//	header: magic | version (u8) | compression (u8) | start (u32) | count (u32) | chunk size (u32)
+//	chunk:  first block (u32) | number of blocks (u32) | payload size (u32) | payload CRC32C (u32) | payload
+//	index:  chunk offset (u64) for every chunk | index CRC32C (u32)
+//	footer: index offset (u64) | index magic
+//
+// Chunk payload is a compressed sequence of blocks each prefixed with its
+// size (u32). All integers are little-endian. Every chunk except the last one
+// contains exactly chunk size blocks, so the chunk containing some particular
+// block can be calculated from the header, the index only contains chunk
+// offsets. Index is written after all chunks, so dumps written to streams are
+// supported, an interrupted dump can still be restored (sequentially) up to
+// the last complete chunk.
+const (
+	// IndexedMagic is the magic prefix of indexed dumps.
+	IndexedMagic = "NEOGODMP"
+	// indexMagic is the magic suffix of indexed dumps.
+	indexMagic = "NEOGOIDX"
+
+	// DefaultChunkSize is the default number of blocks in a single chunk.
+	DefaultChunkSize = 1000
+
+	indexedVersion  = 0
+	compressionZstd = 1
+
+	headerSize      = len(IndexedMagic) + 1 + 1 + 4 + 4 + 4
+	chunkHeaderSize = 4 + 4 + 4 + 4
+	footerSize      = 8 + len(indexMagic)
+
+	// maxChunkPayload is a sanity limit for the chunk payload size.
+	maxChunkPayload = 1 << 30
+)
+
+// ErrChecksumMismatch is returned when dump data doesn't match its checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// IndexedHeader is the header of indexed dump.
+type IndexedHeader struct {
+	// Start is the index of the first block in the dump.
+	Start uint32
+	// Count is the number of blocks in the dump.
+	Count uint32
+	// ChunkSize is the number of blocks in a single chunk.
+	ChunkSize uint32
+}
+
+// IndexedReader reads blocks from indexed dumps.
+type IndexedReader struct {
+	r      gio.Reader
+	header IndexedHeader
+	// index contains chunk offsets, it's nil if the dump is read from a
+	// stream or doesn't have an index (like the one that was interrupted).
+	index []uint64
+	// pos is the offset of the next unread chunk (relative to the dump
+	// start).
+	pos uint64
+	// base is the position of the dump start in the underlying reader
+	// (if it's seekable).
+	base int64
+}
+
+// countingWriter counts the number of bytes written.
+type countingWriter struct {
+	w gio.Writer
+	n uint64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += uint64(n)
+	return n, err
+}
+
+// Detect checks whether the data read from r is an indexed dump. It returns
+// a reader that should be used instead of r to read the data from the same
+// position (r itself if it's seekable).
+func Detect(r gio.Reader) (gio.Reader, bool, error) {
+	magic := make([]byte, len(IndexedMagic))
+	if s, ok := r.(gio.ReadSeeker); ok {
+		if pos, err := s.Seek(0, gio.SeekCurrent); err == nil {
+			n, err := gio.ReadFull(s, magic)
+			if err != nil && !errors.Is(err, gio.ErrUnexpectedEOF) && !errors.Is(err, gio.EOF) {
+				return nil, false, err
+			}
+			if _, err := s.Seek(pos, gio.SeekStart); err != nil {
+				return nil, false, err
+			}
+			return s, n == len(magic) && string(magic) == IndexedMagic, nil
+		}
+	}
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(IndexedMagic))
+	if err != nil && !errors.Is(err, gio.EOF) {
+		return nil, false, err
+	}
+	return br, string(magic) == IndexedMagic, nil
+}
+
+// DumpIndexed writes count blocks from start to the provided writer in the
+// indexed format using chunks of chunkSize blocks (DefaultChunkSize if 0).
+// Unlike Dump, it writes the header itself.
+func DumpIndexed(bc DumperRestorer, w gio.Writer, start, count, chunkSize uint32) error {
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return err
+	}
+	defer enc.Close()
+
+	var (
+		cw      = &countingWriter{w: w}
+		bw      = io.NewBinWriterFromIO(cw)
+		offsets []uint64
+		payload = io.NewBufBinWriter()
+		comp    []byte
+	)
+	bw.WriteBytes([]byte(IndexedMagic))
+	bw.WriteB(indexedVersion)
+	bw.WriteB(compressionZstd)
+	bw.WriteU32LE(start)
+	bw.WriteU32LE(count)
+	bw.WriteU32LE(chunkSize)
+	for first := start; first < start+count; first += chunkSize {
+		n := chunkSize
+		if start+count-first < n {
+			n = start + count - first
+		}
+		payload.Reset()
+		for i := first; i < first+n; i++ {
+			b, err := bc.GetBlock(bc.GetHeaderHash(i))
+			if err != nil {
+				return fmt.Errorf("failed to get block %d: %w", i, err)
+			}
+			buf := io.NewBufBinWriter()
+			b.EncodeBinary(buf.BinWriter)
+			if buf.Err != nil {
+				return buf.Err
+			}
+			payload.WriteU32LE(uint32(buf.Len()))
+			payload.WriteBytes(buf.Bytes())
+		}
+		if payload.Err != nil {
+			return payload.Err
+		}
+		comp = enc.EncodeAll(payload.Bytes(), comp[:0])
+
+		offsets = append(offsets, cw.n)
+		bw.WriteU32LE(first)
+		bw.WriteU32LE(n)
+		bw.WriteU32LE(uint32(len(comp)))
+		bw.WriteU32LE(crc32.Checksum(comp, crcTable))
+		bw.WriteBytes(comp)
+		if bw.Err != nil {
+			return bw.Err
+		}
+	}
+
+	indexOffset := cw.n
+	idx := io.NewBufBinWriter()
+	for _, off := range offsets {
+		idx.WriteU64LE(off)
+	}
+	index := idx.Bytes()
+	bw.WriteBytes(index)
+	bw.WriteU32LE(crc32.Checksum(index, crcTable))
+	bw.WriteU64LE(indexOffset)
+	bw.WriteBytes([]byte(indexMagic))
+	return bw.Err
+}
+
+// NewIndexedReader reads the indexed dump header from r and returns a reader
+// for it. If r is seekable, it also loads the block index (if present), so
+// that the dump can be restored from any height without reading all preceding
+// chunks.
+func NewIndexedReader(r gio.Reader) (*IndexedReader, error) {
+	ir := &IndexedReader{r: r}
+	if s, ok := r.(gio.Seeker); ok {
+		pos, err := s.Seek(0, gio.SeekCurrent)
+		if err != nil {
+			ir.base = -1 // Not really seekable (like pipe).
+		} else {
+			ir.base = pos
+		}
+	} else {
+		ir.base = -1
+	}
+
+	hdr := make([]byte, headerSize)
+	if _, err := gio.ReadFull(r, hdr); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if string(hdr[:len(IndexedMagic)]) != IndexedMagic {
+		return nil, errors.New("not an indexed dump")
+	}
+	hdr = hdr[len(IndexedMagic):]
+	if hdr[0] != indexedVersion {
+		return nil, fmt.Errorf("unsupported dump version %d", hdr[0])
+	}
+	if hdr[1] != compressionZstd {
+		return nil, fmt.Errorf("unsupported compression type %d", hdr[1])
+	}
+	ir.header = IndexedHeader{
+		Start:     binary.LittleEndian.Uint32(hdr[2:]),
+		Count:     binary.LittleEndian.Uint32(hdr[6:]),
+		ChunkSize: binary.LittleEndian.Uint32(hdr[10:]),
+	}
+	if ir.header.ChunkSize == 0 {
+		return nil, errors.New("invalid chunk size")
+	}
+	ir.pos = uint64(headerSize)
+	if ir.base >= 0 {
+		if err := ir.loadIndex(); err != nil {
+			return nil, err
+		}
+	}
+	return ir, nil
+}
+
+// Header returns the dump header.
+func (ir *IndexedReader) Header() IndexedHeader {
+	return ir.header
+}
+
+// HasIndex returns true if the block index was loaded from the dump.
+func (ir *IndexedReader) HasIndex() bool {
+	return ir.index != nil
+}
+
+// loadIndex reads the index from the end of the seekable dump. Missing index
+// is not an error (dump can be interrupted), but a corrupted one is.
+func (ir *IndexedReader) loadIndex() error {
+	s := ir.r.(gio.ReadSeeker)
+	end, err := s.Seek(0, gio.SeekEnd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = s.Seek(ir.base+int64(ir.pos), gio.SeekStart)
+	}()
+	chunks := uint64(ir.header.Count+ir.header.ChunkSize-1) / uint64(ir.header.ChunkSize)
+	size := end - ir.base
+	if size < int64(headerSize+footerSize) {
+		return nil
+	}
+	footer := make([]byte, footerSize)
+	if _, err := s.Seek(end-int64(footerSize), gio.SeekStart); err != nil {
+		return err
+	}
+	if _, err := gio.ReadFull(s, footer); err != nil {
+		return err
+	}
+	if string(footer[8:]) != indexMagic {
+		return nil
+	}
+	offset := binary.LittleEndian.Uint64(footer)
+	if offset+chunks*8+4+uint64(footerSize) != uint64(size) {
+		return errors.New("invalid index offset")
+	}
+	index := make([]byte, chunks*8+4)
+	if _, err := s.Seek(ir.base+int64(offset), gio.SeekStart); err != nil {
+		return err
+	}
+	if _, err := gio.ReadFull(s, index); err != nil {
+		return err
+	}
+	if crc32.Checksum(index[:chunks*8], crcTable) != binary.LittleEndian.Uint32(index[chunks*8:]) {
+		return fmt.Errorf("index: %w", ErrChecksumMismatch)
+	}
+	ir.index = make([]uint64, chunks)
+	for i := range ir.index {
+		ir.index[i] = binary.LittleEndian.Uint64(index[i*8:])
+	}
+	return nil
+}
+
+// Restore restores count blocks starting from the block with index from (which
+// is absolute, not relative to the dump start). Chunk checksums and block
+// indexes are verified. f is called after addition of every block. Genesis
+// block is not added (it's always present in the chain), but its hash is
+// checked instead.
+func (ir *IndexedReader) Restore(bc DumperRestorer, from, count uint32, f func(b *block.Block) error) error {
+	hdr := ir.header
+	if from < hdr.Start || uint64(from)+uint64(count) > uint64(hdr.Start)+uint64(hdr.Count) {
+		return fmt.Errorf("dump contains blocks %d-%d, can't read %d starting from %d",
+			hdr.Start, uint64(hdr.Start)+uint64(hdr.Count)-1, count, from)
+	}
+	if count == 0 {
+		return nil
+	}
+	var (
+		cfg = bc.GetConfig()
+		// Every block is prefixed with its size, chunks can't contain more
+		// than ChunkSize blocks.
+		maxData = uint64(hdr.ChunkSize) * (4 + uint64(cfg.MaxBlockSize))
+	)
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxData))
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	chunk := uint64(from-hdr.Start) / uint64(hdr.ChunkSize)
+	if ir.index != nil {
+		if err := ir.seek(ir.index[chunk]); err != nil {
+			return err
+		}
+	}
+
+	var (
+		stateRootInHeader = cfg.StateRootInHeader
+		next              = from
+		end               = from + count
+		raw               []byte
+	)
+	for next < end {
+		first, n, payload, err := ir.readChunk(chunkStart(next, hdr), &raw)
+		if err != nil {
+			return err
+		}
+		if payload == nil { // Skipped.
+			continue
+		}
+		data, err := dec.DecodeAll(payload, nil)
+		if err != nil {
+			return fmt.Errorf("failed to decompress chunk %d: %w", first, err)
+		}
+		r := io.NewBinReaderFromBuf(data)
+		for i := first; i < first+n && next < end; i++ {
+			size := r.ReadU32LE()
+			if r.Err == nil && uint64(size) > uint64(len(data)) {
+				return fmt.Errorf("invalid block %d size", i)
+			}
+			buf := make([]byte, size)
+			r.ReadBytes(buf)
+			if r.Err != nil {
+				return fmt.Errorf("failed to read block %d: %w", i, r.Err)
+			}
+			if i < next {
+				continue
+			}
+			b := block.New(stateRootInHeader)
+			br := io.NewBinReaderFromBuf(buf)
+			b.DecodeBinary(br)
+			if br.Err != nil {
+				return fmt.Errorf("failed to decode block %d: %w", i, br.Err)
+			}
+			if b.Index != i {
+				return fmt.Errorf("unexpected block %d at %d", b.Index, i)
+			}
+			if b.Index == 0 {
+				if b.Hash() != bc.GetHeaderHash(0) {
+					return errors.New("genesis block mismatch")
+				}
+			} else if err := bc.AddBlock(b); err != nil {
+				return fmt.Errorf("failed to add block %d: %w", i, err)
+			}
+			if f != nil {
+				if err := f(b); err != nil {
+					return err
+				}
+			}
+			next++
+		}
+	}
+	return nil
+}
+
+// chunkStart returns the index of the first block of the chunk containing
+// block h.
+func chunkStart(h uint32, hdr IndexedHeader) uint32 {
+	return h - (h-hdr.Start)%hdr.ChunkSize
+}
+
+// seek moves the reader to the given offset relative to the dump start.
+func (ir *IndexedReader) seek(offset uint64) error {
+	if _, err := ir.r.(gio.Seeker).Seek(ir.base+int64(offset), gio.SeekStart); err != nil {
+		return err
+	}
+	ir.pos = offset
+	return nil
+}
+
+// readChunk reads the next chunk verifying its checksum. Chunks preceding
+// the one starting from want are skipped without reading their payload (if
+// possible), nil payload is returned for them.
+func (ir *IndexedReader) readChunk(want uint32, buf *[]byte) (uint32, uint32, []byte, error) {
+	ch := make([]byte, chunkHeaderSize)
+	if _, err := gio.ReadFull(ir.r, ch); err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read chunk header: %w", err)
+	}
+	var (
+		first = binary.LittleEndian.Uint32(ch)
+		n     = binary.LittleEndian.Uint32(ch[4:])
+		size  = binary.LittleEndian.Uint32(ch[8:])
+		sum   = binary.LittleEndian.Uint32(ch[12:])
+	)
+	if n == 0 || n > ir.header.ChunkSize || size > maxChunkPayload {
+		return 0, 0, nil, fmt.Errorf("invalid chunk %d header", first)
+	}
+	if first > want {
+		return 0, 0, nil, fmt.Errorf("unexpected chunk %d, expected %d", first, want)
+	}
+	ir.pos += uint64(chunkHeaderSize) + uint64(size)
+	if first < want {
+		if ir.base >= 0 {
+			return first, n, nil, ir.seek(ir.pos)
+		}
+		_, err := gio.CopyN(gio.Discard, ir.r, int64(size))
+		return first, n, nil, err
+	}
+	if first != want {
+		return 0, 0, nil, fmt.Errorf("unexpected chunk %d, expected %d", first, want)
+	}
+	if cap(*buf) < int(size) {
+		*buf = make([]byte, size)
+	}
+	payload := (*buf)[:size]
+	if _, err := gio.ReadFull(ir.r, payload); err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to read chunk %d: %w", first, err)
+	}
+	if crc32.Checksum(payload, crcTable) != sum {
+		return 0, 0, nil, fmt.Errorf("chunk %d: %w", first, ErrChecksumMismatch)
+	}
+	return first, n, payload, nil
+}
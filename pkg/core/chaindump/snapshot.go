@@ -0,0 +1,319 @@
+package chaindump
+
+import (
+	"errors"
+	"fmt"
+	gio "io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/statesync"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// State snapshot contains everything the state sync module needs to perform
+// the state jump to the state sync point P:
+//
+//	header:  magic | version (u8) | compression (u8)
+//	payload: network magic (u32) | signed state root for P
+//	         header count (u32) | headers 0..P+1
+//	         MPT nodes for P (var bytes each) | empty var bytes
+//	         block count (u32) | blocks up to P
+//
+// Everything except the header is compressed as a single stream. The snapshot
+// has no checksums, its data is verified cryptographically during restore:
+// headers are checked against the genesis block and the witnesses of the
+// previous ones, the state root and MPT nodes are checked against the state
+// root from the P+1 header and blocks are checked against the headers. Blocks
+// are optional, if they're not included, the node fetches them from the
+// network. Since the state root is trusted only because it's included into
+// the verified header, snapshots are limited to networks with
+// StateRootInHeader protocol extension enabled.
+const (
+	// SnapshotMagic is the magic prefix of state snapshots.
+	SnapshotMagic = "NEOGOSNP"
+
+	snapshotVersion = 0
+
+	// snapshotBatchSize is the number of headers or MPT nodes passed to the
+	// state sync module at once.
+	snapshotBatchSize = 1000
+)
+
+// SnapshotDumper is an interface to get state snapshot data from.
+type SnapshotDumper interface {
+	DumperRestorer
+	BlockHeight() uint32
+	GetHeader(hash util.Uint256) (*block.Header, error)
+	GetStateRoot(height uint32) (*state.MPTRoot, error)
+	GetStateSyncModule() *statesync.Module
+}
+
+// SnapshotRestorer is an interface to restore state snapshot to.
+type SnapshotRestorer interface {
+	BlockHeight() uint32
+	GetConfig() config.Blockchain
+	GetHeader(hash util.Uint256) (*block.Header, error)
+	GetHeaderHash(uint32) util.Uint256
+	GetStateSyncModule() *statesync.Module
+	HeaderHeight() uint32
+}
+
+// SnapshotBlocksStart returns the index of the first block required to
+// perform the state jump to the point p.
+func SnapshotBlocksStart(cfg config.Blockchain, p uint32) uint32 {
+	if p > cfg.MaxTraceableBlocks {
+		return p - cfg.MaxTraceableBlocks + 1
+	}
+	return 1
+}
+
+// DumpSnapshot writes state snapshot for the state sync point p to w. p must
+// be a state sync point with a validated (signed) state root. Blocks required
+// for the state jump are included only if withBlocks is set.
+func DumpSnapshot(bc SnapshotDumper, w gio.Writer, p uint32, withBlocks bool) error {
+	cfg := bc.GetConfig()
+	if !cfg.StateRootInHeader || cfg.StateSyncInterval <= 0 {
+		return errors.New("state synchronisation is not supported by the protocol configuration")
+	}
+	interval := uint32(cfg.StateSyncInterval)
+	if p%interval != 0 || p < 2*interval {
+		return fmt.Errorf("%d is not a valid state sync point (interval is %d)", p, interval)
+	}
+	if p >= bc.BlockHeight() {
+		return fmt.Errorf("block %d is required for state sync point %d, chain height is %d", p+1, p, bc.BlockHeight())
+	}
+	root, err := bc.GetStateRoot(p)
+	if err != nil {
+		return fmt.Errorf("failed to get state root %d: %w", p, err)
+	}
+	if len(root.Witness) == 0 {
+		return fmt.Errorf("state root %d is not validated", p)
+	}
+
+	bw := io.NewBinWriterFromIO(w)
+	bw.WriteBytes([]byte(SnapshotMagic))
+	bw.WriteB(snapshotVersion)
+	bw.WriteB(compressionZstd)
+	if bw.Err != nil {
+		return bw.Err
+	}
+	enc, err := zstd.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	defer enc.Close()
+	bw = io.NewBinWriterFromIO(enc)
+
+	bw.WriteU32LE(uint32(cfg.Magic))
+	root.EncodeBinary(bw)
+	bw.WriteU32LE(p + 2)
+	for i := uint32(0); i <= p+1; i++ {
+		h, err := bc.GetHeader(bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		if i == p+1 && h.PrevStateRoot != root.Root {
+			return fmt.Errorf("state root %d doesn't match the header %d", p, i)
+		}
+		h.EncodeBinary(bw)
+		if bw.Err != nil {
+			return bw.Err
+		}
+	}
+
+	err = bc.GetStateSyncModule().Traverse(root.Root, func(_ mpt.Node, nodeBytes []byte) bool {
+		bw.WriteVarBytes(nodeBytes)
+		return bw.Err != nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to traverse MPT: %w", err)
+	}
+	bw.WriteVarBytes(nil)
+
+	if !withBlocks {
+		bw.WriteU32LE(0)
+		if bw.Err != nil {
+			return bw.Err
+		}
+		return enc.Close()
+	}
+	start := SnapshotBlocksStart(cfg, p)
+	bw.WriteU32LE(p - start + 1)
+	for i := start; i <= p; i++ {
+		b, err := bc.GetBlock(bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		b.EncodeBinary(bw)
+		if bw.Err != nil {
+			return bw.Err
+		}
+	}
+	return enc.Close()
+}
+
+// RestoreSnapshot reads state snapshot from r and passes its data to the
+// state sync module of the fresh chain. The snapshot state root is trusted
+// only if it matches the one from the verified header, so it's supported for
+// StateRootInHeader networks only (P2PStateExchangeExtensions require it
+// anyway). If the snapshot contains blocks, the chain jumps to the snapshot
+// state, otherwise the state sync process is completed when the node fetches
+// the remaining blocks from the network. Snapshot state sync point is
+// returned.
+func RestoreSnapshot(bc SnapshotRestorer, r gio.Reader) (uint32, error) {
+	cfg := bc.GetConfig()
+	if !(cfg.P2PStateExchangeExtensions && cfg.Ledger.RemoveUntraceableBlocks) {
+		return 0, errors.New("snapshot can only be restored with P2PStateExchangeExtensions and RemoveUntraceableBlocks enabled")
+	}
+	if bc.BlockHeight() != 0 || bc.HeaderHeight() != 0 {
+		return 0, errors.New("snapshot can only be restored to the empty database")
+	}
+
+	hdr := make([]byte, len(SnapshotMagic)+2)
+	if _, err := gio.ReadFull(r, hdr); err != nil {
+		return 0, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if string(hdr[:len(SnapshotMagic)]) != SnapshotMagic {
+		return 0, errors.New("not a state snapshot")
+	}
+	if v := hdr[len(SnapshotMagic)]; v != snapshotVersion {
+		return 0, fmt.Errorf("unsupported snapshot version %d", v)
+	}
+	if c := hdr[len(SnapshotMagic)+1]; c != compressionZstd {
+		return 0, fmt.Errorf("unsupported snapshot compression %d", c)
+	}
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer dec.Close()
+	br := io.NewBinReaderFromIO(dec)
+
+	if magic := br.ReadU32LE(); br.Err == nil && magic != uint32(cfg.Magic) {
+		return 0, fmt.Errorf("snapshot network magic mismatch: %d vs %d", magic, cfg.Magic)
+	}
+	root := new(state.MPTRoot)
+	root.DecodeBinary(br)
+	if br.Err != nil {
+		return 0, fmt.Errorf("failed to read state root: %w", br.Err)
+	}
+	p := root.Index
+	if interval := uint32(cfg.StateSyncInterval); p%interval != 0 {
+		return 0, fmt.Errorf("%d is not a valid state sync point (interval is %d)", p, interval)
+	}
+	m := bc.GetStateSyncModule()
+	if err = m.Init(p); err != nil {
+		return 0, fmt.Errorf("failed to initialize state sync module: %w", err)
+	}
+	if !m.IsActive() {
+		return 0, fmt.Errorf("state sync point %d is too low", p)
+	}
+
+	if err = restoreSnapshotHeaders(bc, m, br, p); err != nil {
+		return 0, err
+	}
+	h, err := bc.GetHeader(bc.GetHeaderHash(p + 1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get header %d: %w", p+1, err)
+	}
+	if h.PrevStateRoot != root.Root {
+		return 0, fmt.Errorf("state root %d doesn't match the header %d", p, p+1)
+	}
+
+	nodes := make([][]byte, 0, snapshotBatchSize)
+	for {
+		n := br.ReadVarBytes()
+		if br.Err != nil {
+			return 0, fmt.Errorf("failed to read MPT node: %w", br.Err)
+		}
+		if len(n) != 0 {
+			nodes = append(nodes, n)
+		}
+		if len(nodes) == snapshotBatchSize || (len(n) == 0 && len(nodes) != 0) {
+			// The same node may be included several times, all of its copies
+			// after the last one requested are just skipped.
+			if m.NeedMPTNodes() {
+				if err = m.AddMPTNodes(nodes); err != nil {
+					return 0, err
+				}
+			}
+			nodes = nodes[:0]
+		}
+		if len(n) == 0 {
+			break
+		}
+	}
+	if m.NeedMPTNodes() {
+		return 0, errors.New("snapshot doesn't contain all MPT nodes")
+	}
+	count := br.ReadU32LE()
+	if br.Err != nil {
+		return 0, fmt.Errorf("failed to read block count: %w", br.Err)
+	}
+	if count == 0 {
+		return p, nil
+	}
+	if start := SnapshotBlocksStart(cfg, p); count != p-start+1 {
+		return 0, fmt.Errorf("snapshot contains %d blocks, %d expected", count, p-start+1)
+	}
+	for i := uint32(0); i < count; i++ {
+		b := block.New(cfg.StateRootInHeader)
+		b.DecodeBinary(br)
+		if br.Err != nil {
+			return 0, fmt.Errorf("failed to read block: %w", br.Err)
+		}
+		if b.Hash() != bc.GetHeaderHash(b.Index) {
+			return 0, fmt.Errorf("block %d doesn't match the header", b.Index)
+		}
+		if err = m.AddBlock(b); err != nil {
+			return 0, fmt.Errorf("failed to add block %d: %w", b.Index, err)
+		}
+	}
+	if m.IsActive() || bc.BlockHeight() != p {
+		return 0, fmt.Errorf("failed to jump to state sync point %d", p)
+	}
+	return p, nil
+}
+
+// restoreSnapshotHeaders adds headers 1..p+1 from the snapshot to the chain,
+// the genesis one is checked against the chain's genesis.
+func restoreSnapshotHeaders(bc SnapshotRestorer, m *statesync.Module, br *io.BinReader, p uint32) error {
+	count := br.ReadU32LE()
+	if br.Err != nil {
+		return fmt.Errorf("failed to read header count: %w", br.Err)
+	}
+	if count != p+2 {
+		return fmt.Errorf("snapshot contains %d headers, %d expected", count, p+2)
+	}
+	stateRootInHeader := bc.GetConfig().StateRootInHeader
+	hdrs := make([]*block.Header, 0, snapshotBatchSize)
+	for i := uint32(0); i < count; i++ {
+		h := &block.Header{StateRootEnabled: stateRootInHeader}
+		h.DecodeBinary(br)
+		if br.Err != nil {
+			return fmt.Errorf("failed to read header %d: %w", i, br.Err)
+		}
+		if i == 0 {
+			if h.Hash() != bc.GetHeaderHash(0) {
+				return errors.New("snapshot genesis block doesn't match the chain's one")
+			}
+			continue
+		}
+		hdrs = append(hdrs, h)
+		if len(hdrs) == snapshotBatchSize || i == count-1 {
+			if err := m.AddHeaders(hdrs...); err != nil {
+				return fmt.Errorf("failed to add headers: %w", err)
+			}
+			hdrs = hdrs[:0]
+		}
+	}
+	if m.NeedHeaders() {
+		return errors.New("headers are not synchronised")
+	}
+	return nil
+}
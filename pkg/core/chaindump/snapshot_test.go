@@ -0,0 +1,168 @@
+package chaindump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	corestate "github.com/nspcc-dev/neo-go/pkg/core/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	snapshotInterval     = 4
+	snapshotMaxTraceable = 5
+	snapshotPoint        = 3 * snapshotInterval
+)
+
+func snapshotSpoutCfg(c *config.Blockchain) {
+	c.StateRootInHeader = true
+	c.P2PStateExchangeExtensions = true
+	c.StateSyncInterval = snapshotInterval
+	c.MaxTraceableBlocks = snapshotMaxTraceable
+}
+
+func snapshotBoltCfg(c *config.Blockchain) {
+	snapshotSpoutCfg(c)
+	c.Ledger.KeepOnlyLatestState = true
+	c.Ledger.RemoveUntraceableBlocks = true
+}
+
+func signStateRoot(r *state.MPTRoot, magic netmode.Magic, priv *keys.PrivateKey) {
+	script, _ := smartcontract.CreateDefaultMultiSigRedeemScript(keys.PublicKeys{priv.PublicKey()})
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, priv.SignHashable(uint32(magic), r))
+	r.Witness = []transaction.Witness{{
+		InvocationScript:   w.Bytes(),
+		VerificationScript: script,
+	}}
+}
+
+func TestSnapshot(t *testing.T) {
+	bc, validators, committee := chain.NewMultiWithCustomConfig(t, snapshotSpoutCfg)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	designation := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validators, committee)
+	designation.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(noderoles.StateValidator), []any{priv.PublicKey().Bytes()})
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	for bc.BlockHeight() <= snapshotPoint+snapshotInterval {
+		gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), util.Uint160{byte(bc.BlockHeight())}, 1, nil)
+	}
+
+	srMod := bc.GetStateModule().(*corestate.Module) // Take full responsibility here.
+	r, err := bc.GetStateRoot(snapshotPoint)
+	require.NoError(t, err)
+	signStateRoot(r, bc.GetConfig().Magic, priv)
+	require.NoError(t, srMod.AddStateRoot(r))
+
+	dump := func(t *testing.T, withBlocks bool) []byte {
+		buf := new(bytes.Buffer)
+		require.NoError(t, chaindump.DumpSnapshot(bc, buf, snapshotPoint, withBlocks))
+		return buf.Bytes()
+	}
+	checkState := func(t *testing.T, bcBolt *core.Blockchain) {
+		require.Equal(t, uint32(snapshotPoint), bcBolt.BlockHeight())
+		expected, err := bc.GetStateRoot(snapshotPoint)
+		require.NoError(t, err)
+		require.Equal(t, expected.Root, bcBolt.GetStateModule().CurrentLocalStateRoot())
+		for i := bcBolt.BlockHeight() + 1; i <= bc.BlockHeight(); i++ {
+			b, err := bc.GetBlock(bc.GetHeaderHash(i))
+			require.NoError(t, err)
+			require.NoError(t, bcBolt.AddBlock(b))
+		}
+		require.Equal(t, bc.GetStateModule().CurrentLocalStateRoot(), bcBolt.GetStateModule().CurrentLocalStateRoot())
+	}
+
+	t.Run("dump errors", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.Error(t, chaindump.DumpSnapshot(bc, buf, snapshotPoint+1, true))                  // Not a sync point.
+		require.Error(t, chaindump.DumpSnapshot(bc, buf, snapshotInterval, true))                 // Too low.
+		require.Error(t, chaindump.DumpSnapshot(bc, buf, snapshotPoint-snapshotInterval, true))   // Not validated.
+		require.Error(t, chaindump.DumpSnapshot(bc, buf, snapshotPoint+2*snapshotInterval, true)) // Too high.
+	})
+	t.Run("with blocks", func(t *testing.T) {
+		bcBolt, _, _ := chain.NewMultiWithCustomConfig(t, snapshotBoltCfg)
+		p, err := chaindump.RestoreSnapshot(bcBolt, bytes.NewReader(dump(t, true)))
+		require.NoError(t, err)
+		require.Equal(t, uint32(snapshotPoint), p)
+		checkState(t, bcBolt)
+	})
+	t.Run("without blocks", func(t *testing.T) {
+		bcBolt, _, _ := chain.NewMultiWithCustomConfig(t, snapshotBoltCfg)
+		p, err := chaindump.RestoreSnapshot(bcBolt, bytes.NewReader(dump(t, false)))
+		require.NoError(t, err)
+		require.Equal(t, uint32(snapshotPoint), p)
+		require.Equal(t, uint32(0), bcBolt.BlockHeight())
+		require.Equal(t, uint32(snapshotPoint+1), bcBolt.HeaderHeight())
+
+		// The rest is done by the state sync module of the node.
+		m := bcBolt.GetStateSyncModule()
+		require.NoError(t, m.Init(snapshotPoint))
+		require.True(t, m.IsActive())
+		require.False(t, m.NeedHeaders())
+		require.False(t, m.NeedMPTNodes())
+		for i := chaindump.SnapshotBlocksStart(bc.GetConfig(), snapshotPoint); i <= snapshotPoint; i++ {
+			b, err := bc.GetBlock(bc.GetHeaderHash(i))
+			require.NoError(t, err)
+			require.NoError(t, m.AddBlock(b))
+		}
+		require.False(t, m.IsActive())
+		checkState(t, bcBolt)
+	})
+	t.Run("restore errors", func(t *testing.T) {
+		data := dump(t, true)
+
+		bcArchive, _, _ := chain.NewMultiWithCustomConfig(t, snapshotSpoutCfg)
+		_, err := chaindump.RestoreSnapshot(bcArchive, bytes.NewReader(data))
+		require.Error(t, err)
+
+		bcBolt, validatorsBolt, committeeBolt := chain.NewMultiWithCustomConfig(t, snapshotBoltCfg)
+		neotest.NewExecutor(t, bcBolt, validatorsBolt, committeeBolt).AddNewBlock(t)
+		_, err = chaindump.RestoreSnapshot(bcBolt, bytes.NewReader(data))
+		require.Error(t, err) // Not empty.
+
+		bcBolt, _, _ = chain.NewMultiWithCustomConfig(t, snapshotBoltCfg)
+		_, err = chaindump.RestoreSnapshot(bcBolt, bytes.NewReader([]byte("NEOGODMP")))
+		require.Error(t, err)
+		_, err = chaindump.RestoreSnapshot(bcBolt, bytes.NewReader(data[:len(data)/2]))
+		require.Error(t, err)
+	})
+	t.Run("bad root", func(t *testing.T) {
+		data := dump(t, true)
+		hdrLen := len(chaindump.SnapshotMagic) + 2
+		payload, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		raw, err := payload.DecodeAll(data[hdrLen:], nil)
+		require.NoError(t, err)
+		payload.Close()
+		// Network magic, state root version and index precede the root hash.
+		raw[4+1+4] ^= 0xff
+		enc, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		data = enc.EncodeAll(raw, append([]byte{}, data[:hdrLen]...))
+
+		bcBolt, _, _ := chain.NewMultiWithCustomConfig(t, snapshotBoltCfg)
+		_, err = chaindump.RestoreSnapshot(bcBolt, bytes.NewReader(data))
+		require.ErrorContains(t, err, "doesn't match the header")
+		require.Equal(t, uint32(0), bcBolt.BlockHeight())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	iocore "io"
+	"math"
 	"math/big"
 	"sync"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 )
 
 // HasTransaction errors.
@@ -246,6 +248,182 @@ func (dao *Simple) PutTokenTransferLog(acc util.Uint160, start uint64, index uin
 
 // -- end transfer log.
 
+// -- start execution index.
+
+const (
+	// notificationIndexSuffixLen is the length of notification index key
+	// suffix containing block index, execution position and notification
+	// index.
+	notificationIndexSuffixLen = 4 + 4 + 4
+	// transactionIndexSuffixLen is the length of transaction index key
+	// suffix containing block index and transaction position.
+	transactionIndexSuffixLen = 4 + 2
+)
+
+// makeNotificationIndexKey returns notification index key, its prefix (without
+// suffix) can be used for seeking.
+func makeNotificationIndexKey(contract util.Uint160, name string, height uint32, pos uint32, index uint32) []byte {
+	prefixLen := 1 + util.Uint160Size + 1 + len(name)
+	key := make([]byte, prefixLen+notificationIndexSuffixLen)
+	key[0] = byte(storage.IXNotifications)
+	copy(key[1:], contract.BytesBE())
+	key[1+util.Uint160Size] = byte(len(name))
+	copy(key[2+util.Uint160Size:], name)
+	binary.BigEndian.PutUint32(key[prefixLen:], height)
+	binary.BigEndian.PutUint32(key[prefixLen+4:], pos)
+	binary.BigEndian.PutUint32(key[prefixLen+8:], index)
+	return key
+}
+
+// makeTransactionIndexKey returns transaction index key, its prefix (without
+// suffix) can be used for seeking.
+func makeTransactionIndexKey(acc util.Uint160, height uint32, pos uint16) []byte {
+	key := make([]byte, 1+util.Uint160Size+transactionIndexSuffixLen)
+	key[0] = byte(storage.IXTransactions)
+	copy(key[1:], acc.BytesBE())
+	binary.BigEndian.PutUint32(key[1+util.Uint160Size:], height)
+	binary.BigEndian.PutUint16(key[1+util.Uint160Size+4:], pos)
+	return key
+}
+
+// PutNotificationIndex adds notifications of the given successful execution
+// to the notification index. Every notification is indexed twice: by contract
+// hash only and by contract hash and event name. pos is the execution position
+// in the block (see state.NotificationIndexEntry).
+func (dao *Simple) PutNotificationIndex(height uint32, pos uint32, aer *state.AppExecResult) {
+	if aer.VMState != vmstate.Halt {
+		return
+	}
+	val := make([]byte, util.Uint256Size+1)
+	copy(val, aer.Container.BytesBE())
+	val[util.Uint256Size] = byte(aer.Trigger)
+	for i := range aer.Events {
+		ev := &aer.Events[i]
+		for _, name := range []string{"", ev.Name} {
+			dao.Store.Put(makeNotificationIndexKey(ev.ScriptHash, name, height, pos, uint32(i)), val)
+			if ev.Name == "" {
+				break
+			}
+		}
+	}
+}
+
+// PutTransactionIndex adds the given transaction to the transaction index of
+// every transaction signer. pos is the transaction position in the block
+// starting from 1.
+func (dao *Simple) PutTransactionIndex(height uint32, pos uint16, tx *transaction.Transaction) {
+	for i := range tx.Signers {
+		val := make([]byte, util.Uint256Size+1)
+		copy(val, tx.Hash().BytesBE())
+		if i == 0 {
+			val[util.Uint256Size] = 1
+		}
+		dao.Store.Put(makeTransactionIndexKey(tx.Signers[i].Account, height, pos), val)
+	}
+}
+
+// SeekNotificationIndex executes f for each notification index entry of the
+// given contract (and the given event name if it's not empty) emitted in
+// blocks from start to end (inclusive) in ascending order. It continues
+// iteration until false is returned from f. The last non-nil error is
+// returned.
+func (dao *Simple) SeekNotificationIndex(contract util.Uint160, name string, start, end uint32, f func(*state.NotificationIndexEntry) (bool, error)) error {
+	if len(name) > math.MaxUint8 {
+		return nil
+	}
+	var (
+		key     = makeNotificationIndexKey(contract, name, start, 0, 0)
+		prefix  = key[:len(key)-notificationIndexSuffixLen]
+		seekErr error
+	)
+	dao.Store.Seek(storage.SeekRange{
+		Prefix: prefix,
+		Start:  key[len(prefix) : len(prefix)+4],
+	}, func(k, v []byte) bool {
+		if len(k) != len(prefix)+notificationIndexSuffixLen || len(v) != util.Uint256Size+1 {
+			seekErr = fmt.Errorf("%w: invalid notification index entry", ErrInternalDBInconsistency)
+			return false
+		}
+		e := &state.NotificationIndexEntry{
+			BlockIndex: binary.BigEndian.Uint32(k[len(prefix):]),
+			Position:   binary.BigEndian.Uint32(k[len(prefix)+4:]),
+			Index:      binary.BigEndian.Uint32(k[len(prefix)+8:]),
+			Trigger:    trigger.Type(v[util.Uint256Size]),
+			ScriptHash: contract,
+			Name:       name,
+		}
+		if e.BlockIndex > end {
+			return false
+		}
+		e.Container, _ = util.Uint256DecodeBytesBE(v[:util.Uint256Size])
+		cont, err := f(e)
+		if err != nil {
+			seekErr = err
+		}
+		return cont
+	})
+	return seekErr
+}
+
+// SeekTransactionIndex executes f for each transaction signed by the given
+// account in blocks from start to end (inclusive) in ascending order. It
+// continues iteration until false is returned from f. The last non-nil error
+// is returned.
+func (dao *Simple) SeekTransactionIndex(acc util.Uint160, start, end uint32, f func(*state.TransactionIndexEntry) (bool, error)) error {
+	var (
+		key     = makeTransactionIndexKey(acc, start, 0)
+		prefix  = key[:len(key)-transactionIndexSuffixLen]
+		seekErr error
+	)
+	dao.Store.Seek(storage.SeekRange{
+		Prefix: prefix,
+		Start:  key[len(prefix) : len(prefix)+4],
+	}, func(k, v []byte) bool {
+		if len(k) != len(prefix)+transactionIndexSuffixLen || len(v) != util.Uint256Size+1 {
+			seekErr = fmt.Errorf("%w: invalid transaction index entry", ErrInternalDBInconsistency)
+			return false
+		}
+		e := &state.TransactionIndexEntry{
+			BlockIndex: binary.BigEndian.Uint32(k[len(prefix):]),
+			Position:   binary.BigEndian.Uint16(k[len(prefix)+4:]),
+			Sender:     v[util.Uint256Size] != 0,
+		}
+		if e.BlockIndex > end {
+			return false
+		}
+		e.Hash, _ = util.Uint256DecodeBytesBE(v[:util.Uint256Size])
+		cont, err := f(e)
+		if err != nil {
+			seekErr = err
+		}
+		return cont
+	})
+	return seekErr
+}
+
+// DeleteExecutionIndex removes all notification and transaction index entries
+// for blocks newer than the given height.
+func (dao *Simple) DeleteExecutionIndex(height uint32) {
+	for _, p := range []struct {
+		prefix    storage.KeyPrefix
+		suffixLen int
+	}{
+		{storage.IXNotifications, notificationIndexSuffixLen},
+		{storage.IXTransactions, transactionIndexSuffixLen},
+	} {
+		dao.Store.Seek(storage.SeekRange{
+			Prefix: []byte{byte(p.prefix)},
+		}, func(k, v []byte) bool {
+			if len(k) > p.suffixLen && binary.BigEndian.Uint32(k[len(k)-p.suffixLen:]) > height {
+				dao.Store.Delete(slice.Copy(k))
+			}
+			return true
+		})
+	}
+}
+
+// -- end execution index.
+
 // -- start notification event.
 
 func (dao *Simple) makeExecutableKey(hash util.Uint256) []byte {
@@ -440,6 +618,7 @@ type Version struct {
 	P2PSigExtensions           bool
 	P2PStateExchangeExtensions bool
 	KeepOnlyLatestState        bool
+	ExecutionIndex             bool
 	Magic                      uint32
 	Value                      string
 }
@@ -449,6 +628,7 @@ const (
 	p2pSigExtensionsBit
 	p2pStateExchangeExtensionsBit
 	keepOnlyLatestStateBit
+	executionIndexBit
 )
 
 // FromBytes decodes v from a byte-slice.
@@ -476,6 +656,7 @@ func (v *Version) FromBytes(data []byte) error {
 	v.P2PSigExtensions = data[i+2]&p2pSigExtensionsBit != 0
 	v.P2PStateExchangeExtensions = data[i+2]&p2pStateExchangeExtensionsBit != 0
 	v.KeepOnlyLatestState = data[i+2]&keepOnlyLatestStateBit != 0
+	v.ExecutionIndex = data[i+2]&executionIndexBit != 0
 
 	m := i + 3
 	if len(data) == m+4 {
@@ -499,6 +680,9 @@ func (v *Version) Bytes() []byte {
 	if v.KeepOnlyLatestState {
 		mask |= keepOnlyLatestStateBit
 	}
+	if v.ExecutionIndex {
+		mask |= executionIndexBit
+	}
 	res := append([]byte(v.Value), '\x00', byte(v.StoragePrefix), mask, 0, 0, 0, 0)
 	binary.LittleEndian.PutUint32(res[len(res)-4:], v.Magic)
 	return res
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"encoding/binary"
+	"math"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
@@ -14,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -371,3 +373,78 @@ func TestPutGetStateSyncCurrentBlockHeight(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, actual)
 }
+
+func TestExecutionIndex(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false, false)
+	c1, c2 := random.Uint160(), random.Uint160()
+	mkAER := func(st vmstate.State, evs ...state.NotificationEvent) *state.AppExecResult {
+		return &state.AppExecResult{
+			Container: random.Uint256(),
+			Execution: state.Execution{
+				Trigger: trigger.Application,
+				VMState: st,
+				Events:  evs,
+			},
+		}
+	}
+	aers := []*state.AppExecResult{
+		mkAER(vmstate.Halt, state.NotificationEvent{ScriptHash: c1, Name: "A"}, state.NotificationEvent{ScriptHash: c2, Name: "B"}),
+		mkAER(vmstate.Fault, state.NotificationEvent{ScriptHash: c1, Name: "A"}),
+		mkAER(vmstate.Halt, state.NotificationEvent{ScriptHash: c1, Name: "B"}, state.NotificationEvent{ScriptHash: c1, Name: "A"}),
+	}
+	for i, aer := range aers {
+		dao.PutNotificationIndex(uint32(i+1), 1, aer)
+	}
+
+	collect := func(contract util.Uint160, name string, start, end uint32) []state.NotificationIndexEntry {
+		var res []state.NotificationIndexEntry
+		require.NoError(t, dao.SeekNotificationIndex(contract, name, start, end, func(e *state.NotificationIndexEntry) (bool, error) {
+			res = append(res, *e)
+			return true, nil
+		}))
+		return res
+	}
+	res := collect(c1, "", 0, 100)
+	require.Equal(t, 3, len(res))
+	require.Equal(t, state.NotificationIndexEntry{BlockIndex: 1, Position: 1, Index: 0, Container: aers[0].Container,
+		Trigger: trigger.Application, ScriptHash: c1}, res[0])
+	require.Equal(t, uint32(3), res[1].BlockIndex)
+	require.Equal(t, uint32(0), res[1].Index)
+	require.Equal(t, uint32(1), res[2].Index)
+
+	res = collect(c1, "A", 0, 100)
+	require.Equal(t, 2, len(res))
+	require.Equal(t, "A", res[0].Name)
+	require.Equal(t, uint32(1), res[1].Index)
+	require.Equal(t, 1, len(collect(c1, "A", 2, 100)))
+	require.Equal(t, 1, len(collect(c1, "A", 0, 2)))
+	require.Equal(t, 1, len(collect(c2, "B", 0, 100)))
+	require.Equal(t, 0, len(collect(c2, "A", 0, 100)))
+
+	// PostPersist position of the block with the maximum number of transactions.
+	dao.PutNotificationIndex(10, math.MaxUint16+1, aers[0])
+	res = collect(c2, "B", 10, 10)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, uint32(math.MaxUint16+1), res[0].Position)
+
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
+	tx.Signers = []transaction.Signer{{Account: c1}, {Account: c2}}
+	dao.PutTransactionIndex(5, 2, tx)
+	var txs []state.TransactionIndexEntry
+	require.NoError(t, dao.SeekTransactionIndex(c2, 0, 10, func(e *state.TransactionIndexEntry) (bool, error) {
+		txs = append(txs, *e)
+		return true, nil
+	}))
+	require.Equal(t, []state.TransactionIndexEntry{{BlockIndex: 5, Position: 2, Hash: tx.Hash()}}, txs)
+	require.NoError(t, dao.SeekTransactionIndex(c1, 0, 10, func(e *state.TransactionIndexEntry) (bool, error) {
+		require.True(t, e.Sender)
+		return true, nil
+	}))
+
+	dao.DeleteExecutionIndex(2)
+	require.Equal(t, 1, len(collect(c1, "", 0, 100)))
+	require.NoError(t, dao.SeekTransactionIndex(c1, 0, 10, func(e *state.TransactionIndexEntry) (bool, error) {
+		t.Fatal("unexpected entry")
+		return false, nil
+	}))
+}
@@ -0,0 +1,545 @@
+/*
+Package fork provides a Store that allows to run a Blockchain on top of the state
+of some other (remote) chain at the given height without synchronizing it.
+
+Contract storage items, blocks, transactions and header hashes of the original
+chain are fetched lazily from the remote RPC node when they're requested by
+the Blockchain and cached locally. Changes made by the Blockchain are stored
+locally and never sent to the remote node, so the Store can be used to
+investigate and modify the state of the original chain freely (see VM CLI and
+neotest.chain.NewFork).
+
+The remote node must be able to serve historical states for the given height
+(getstate/findstates RPC calls), unless it's the latest one. Local state
+roots differ from the original chain ones (see core.InitFork for details).
+*/
+package fork
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// RPC is a set of remote node methods used by the Store, it's implemented by
+// rpcclient.Client.
+type RPC interface {
+	FindStates(stateroot util.Uint256, historicalContractHash util.Uint160, historicalPrefix []byte,
+		start []byte, maxCount *int) (result.FindStates, error)
+	GetBlockByHash(hash util.Uint256) (*block.Block, error)
+	GetBlockByIndex(index uint32) (*block.Block, error)
+	GetBlockHash(index uint32) (util.Uint256, error)
+	GetRawTransactionVerbose(hash util.Uint256) (*result.TransactionOutputRaw, error)
+	GetState(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) ([]byte, error)
+	GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+}
+
+// Store is a storage.Store backed by the remote chain state at some height.
+// It's safe for concurrent use.
+type Store struct {
+	remote RPC
+	height uint32
+	root   util.Uint256
+	cfg    config.Blockchain
+	local  *storage.MemoryStore
+	mgmt   util.Uint160
+
+	// lock protects the fields below, it's never held during remote
+	// requests.
+	lock sync.Mutex
+	// absent contains keys that are known to be missing, either on the
+	// remote side or deleted locally.
+	absent map[string]struct{}
+	// fetched contains contract storage prefixes all remote items of which
+	// are fetched already.
+	fetched map[string]struct{}
+	// contracts contains contract hashes by their IDs.
+	contracts map[int32]util.Uint160
+	// fetching contains remote requests in progress by the key requested.
+	fetching map[string]*fetchCall
+	// seeking contains remote requests in progress by the prefix requested.
+	seeking map[string]*fetchCall
+}
+
+// fetchCall is a remote request in progress, done is closed when it's
+// finished.
+type fetchCall struct {
+	done chan struct{}
+	err  error
+}
+
+const (
+	// headerBatchCount is the number of header hashes in a single
+	// IXHeaderHashList page, it matches the one used by core.
+	headerBatchCount = 2000
+
+	// fetchWorkers is the maximum number of concurrent requests made to the
+	// remote node.
+	fetchWorkers = 16
+)
+
+// New creates a Store forked from the remote chain at the given height and
+// initializes it for the Blockchain with the given configuration (it must
+// match the remote chain one). Everything is cached in memory.
+func New(remote RPC, cfg config.Blockchain, height uint32) (*Store, error) {
+	sr, err := remote.GetStateRootByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state root for %d: %w", height, err)
+	}
+	b, err := remote.GetBlockByIndex(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	s := &Store{
+		remote:    remote,
+		height:    height,
+		root:      sr.Root,
+		cfg:       cfg,
+		local:     storage.NewMemoryStore(),
+		mgmt:      state.CreateNativeContractHash(nativenames.Management),
+		absent:    make(map[string]struct{}),
+		fetched:   make(map[string]struct{}),
+		contracts: make(map[int32]util.Uint160),
+		fetching:  make(map[string]*fetchCall),
+		seeking:   make(map[string]*fetchCall),
+	}
+	err = core.InitFork(s.local, cfg, &b.Header)
+	if err != nil {
+		return nil, err
+	}
+	err = s.prefetch(b)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Height returns the height of the remote chain the Store is forked at.
+func (s *Store) Height() uint32 {
+	return s.height
+}
+
+// prefetch fetches the data needed to initialize the Blockchain in parallel:
+// the latest complete page of header hashes and all blocks after it up to the
+// fork point (header hashes of them are restored from blocks by the
+// Blockchain).
+func (s *Store) prefetch(top *block.Block) error {
+	var stored = ((s.height + 1) / headerBatchCount) * headerBatchCount
+
+	if stored >= headerBatchCount {
+		key := make([]byte, 5)
+		key[0] = byte(storage.IXHeaderHashList)
+		binary.BigEndian.PutUint32(key[1:], stored-headerBatchCount)
+		_, err := s.Get(key)
+		if err != nil {
+			return fmt.Errorf("failed to get header hashes: %w", err)
+		}
+	}
+	var blocks []*block.Block
+	if s.height > stored {
+		blocks = make([]*block.Block, s.height-stored)
+	}
+	err := parallel(len(blocks), func(i int) error {
+		var err error
+		blocks[i], err = s.remote.GetBlockByIndex(stored + uint32(i))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get blocks: %w", err)
+	}
+	for _, b := range append(blocks, top) {
+		err = s.putBlock(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get implements the storage.Store interface.
+func (s *Store) Get(key []byte) ([]byte, error) {
+	v, err := s.local.Get(key)
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return v, err
+	}
+	s.lock.Lock()
+	_, absent := s.absent[string(key)]
+	fetch := s.fetcher(key)
+	s.lock.Unlock()
+	if absent {
+		return nil, storage.ErrKeyNotFound
+	}
+	if fetch != nil {
+		err = s.fetchOnce(s.fetching, string(key), func() error {
+			// It could've been fetched by a concurrent request already.
+			if _, err := s.local.Get(key); err == nil {
+				return nil
+			}
+			return fetch(key)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, err = s.local.Get(key)
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		s.absent[string(key)] = struct{}{}
+	}
+	return v, err
+}
+
+// fetcher returns the function fetching remote item with the given key or
+// nil if there is nothing to fetch. It must be called with the lock held.
+func (s *Store) fetcher(key []byte) func([]byte) error {
+	switch storage.KeyPrefix(key[0]) {
+	case storage.STStorage:
+		if len(key) >= 5 && !s.isFetched(key) {
+			return s.fetchItem
+		}
+	case storage.DataExecutable:
+		if len(key) == 1+util.Uint256Size {
+			return s.fetchExecutable
+		}
+	case storage.IXHeaderHashList:
+		if len(key) == 5 {
+			return s.fetchHeaderHashes
+		}
+	}
+	return nil
+}
+
+// fetchOnce calls f unless there is a request for the same key (in the given
+// set of calls) in progress already, in which case it waits for that request
+// to finish and returns its result. It must be called without the lock held.
+func (s *Store) fetchOnce(calls map[string]*fetchCall, key string, f func() error) error {
+	s.lock.Lock()
+	if c, ok := calls[key]; ok {
+		s.lock.Unlock()
+		<-c.done
+		return c.err
+	}
+	c := &fetchCall{done: make(chan struct{})}
+	calls[key] = c
+	s.lock.Unlock()
+
+	c.err = f()
+
+	s.lock.Lock()
+	delete(calls, key)
+	s.lock.Unlock()
+	close(c.done)
+	return c.err
+}
+
+// isFetched checks whether all remote items of the contract storage prefix the
+// key belongs to are already fetched.
+func (s *Store) isFetched(key []byte) bool {
+	for i := len(key); i >= 5; i-- {
+		if _, ok := s.fetched[string(key[:i])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// contractHash returns the hash of the contract with the given ID using the
+// mapping stored by the native Management contract.
+func (s *Store) contractHash(id int32) (util.Uint160, error) {
+	if id == native.ManagementContractID {
+		return s.mgmt, nil
+	}
+	s.lock.Lock()
+	h, ok := s.contracts[id]
+	s.lock.Unlock()
+	if ok {
+		return h, nil
+	}
+	var (
+		mgmtID int32 = native.ManagementContractID
+		key          = append([]byte{byte(storage.STStorage), 0, 0, 0, 0}, native.MakeContractHashKey(id)...)
+	)
+	binary.LittleEndian.PutUint32(key[1:], uint32(mgmtID))
+	v, err := s.Get(key)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	h, err = util.Uint160DecodeBytesBE(v)
+	if err != nil {
+		return h, fmt.Errorf("invalid hash of contract %d: %w", id, err)
+	}
+	s.lock.Lock()
+	s.contracts[id] = h
+	s.lock.Unlock()
+	return h, nil
+}
+
+// fetchItem fetches contract storage item with the given key.
+func (s *Store) fetchItem(key []byte) error {
+	h, err := s.contractHash(int32(binary.LittleEndian.Uint32(key[1:])))
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+	v, err := s.remote.GetState(s.root, h, key[5:])
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get storage item of %s: %w", h.StringLE(), err)
+	}
+	s.lock.Lock()
+	s.putRemote(map[string][]byte{string(key): v})
+	s.lock.Unlock()
+	return nil
+}
+
+// fetchPrefix fetches all contract storage items with the given prefix. It
+// must be called without the lock held.
+func (s *Store) fetchPrefix(prefix []byte) error {
+	s.lock.Lock()
+	fetched := s.isFetched(prefix)
+	s.lock.Unlock()
+	if fetched {
+		return nil
+	}
+	return s.fetchOnce(s.seeking, string(prefix), func() error {
+		return s.findItems(prefix)
+	})
+}
+
+// findItems fetches all remote contract storage items with the given prefix.
+func (s *Store) findItems(prefix []byte) error {
+	id := int32(binary.LittleEndian.Uint32(prefix[1:]))
+	h, err := s.contractHash(id)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return err
+	}
+	var (
+		start []byte
+		kvs   = make(map[string][]byte)
+	)
+	for err == nil {
+		var res result.FindStates
+		res, err = s.remote.FindStates(s.root, h, prefix[5:], start, nil)
+		if err != nil {
+			if !isNotFound(err) {
+				return fmt.Errorf("failed to find storage items of %s: %w", h.StringLE(), err)
+			}
+			break
+		}
+		for _, kv := range res.Results {
+			kvs[string(prefix[:5])+string(kv.Key)] = kv.Value
+		}
+		if !res.Truncated || len(res.Results) == 0 {
+			break
+		}
+		start = res.Results[len(res.Results)-1].Key
+	}
+	s.lock.Lock()
+	s.putRemote(kvs)
+	s.fetched[string(prefix)] = struct{}{}
+	s.lock.Unlock()
+	return nil
+}
+
+// fetchExecutable fetches the block or the transaction (along with its block)
+// with the hash from the given key.
+func (s *Store) fetchExecutable(key []byte) error {
+	h, err := util.Uint256DecodeBytesBE(key[1:])
+	if err != nil {
+		return err
+	}
+	b, err := s.remote.GetBlockByHash(h)
+	if err != nil {
+		if !isNotFound(err) {
+			return fmt.Errorf("failed to get block %s: %w", h.StringLE(), err)
+		}
+		tx, err := s.remote.GetRawTransactionVerbose(h)
+		if err != nil {
+			if isNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get transaction %s: %w", h.StringLE(), err)
+		}
+		b, err = s.remote.GetBlockByHash(tx.Blockhash)
+		if err != nil {
+			return fmt.Errorf("failed to get block %s: %w", tx.Blockhash.StringLE(), err)
+		}
+	}
+	return s.putBlock(b)
+}
+
+// putBlock stores the given block and its transactions if it's not newer than
+// the fork point.
+func (s *Store) putBlock(b *block.Block) error {
+	if b.Index > s.height {
+		return nil
+	}
+	d := dao.NewSimple(storage.NewMemoryStore(), s.cfg.StateRootInHeader, s.cfg.P2PSigExtensions)
+	err := d.StoreAsBlock(b, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, tx := range b.Transactions {
+		err = d.StoreAsTransaction(tx, b.Index, nil)
+		if err != nil {
+			return err
+		}
+	}
+	kvs := make(map[string][]byte)
+	d.Store.Seek(storage.SeekRange{Prefix: []byte{byte(storage.DataExecutable)}}, func(k, v []byte) bool {
+		kvs[string(k)] = slice.Copy(v)
+		return true
+	})
+	s.lock.Lock()
+	s.putRemote(kvs)
+	s.lock.Unlock()
+	return nil
+}
+
+// fetchHeaderHashes fetches a complete page of header hashes with the given
+// key.
+func (s *Store) fetchHeaderHashes(key []byte) error {
+	start := binary.BigEndian.Uint32(key[1:])
+	if start%headerBatchCount != 0 || start+headerBatchCount-1 > s.height {
+		return nil
+	}
+	hashes := make([]util.Uint256, headerBatchCount)
+	err := parallel(headerBatchCount, func(i int) error {
+		var err error
+		hashes[i], err = s.remote.GetBlockHash(start + uint32(i))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get header hashes: %w", err)
+	}
+	buf := io.NewBufBinWriter()
+	buf.WriteArray(hashes)
+	if buf.Err != nil {
+		return buf.Err
+	}
+	s.lock.Lock()
+	s.putRemote(map[string][]byte{string(key): buf.Bytes()})
+	s.lock.Unlock()
+	return nil
+}
+
+// putRemote stores the given remote items locally unless they're changed
+// locally already. It must be called with the lock held.
+func (s *Store) putRemote(kvs map[string][]byte) {
+	var puts, stor = make(map[string][]byte), make(map[string][]byte)
+	for k, v := range kvs {
+		if _, ok := s.absent[k]; ok {
+			continue
+		}
+		if _, err := s.local.Get([]byte(k)); err == nil {
+			continue
+		}
+		if storage.KeyPrefix(k[0]) == storage.STStorage {
+			stor[k] = v
+		} else {
+			puts[k] = v
+		}
+	}
+	_ = s.local.PutChangeSet(puts, stor)
+}
+
+// PutChangeSet implements the storage.Store interface. Changes are never sent
+// to the remote node.
+func (s *Store) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, m := range []map[string][]byte{puts, stor} {
+		for k, v := range m {
+			if v == nil {
+				s.absent[k] = struct{}{}
+			} else {
+				delete(s.absent, k)
+			}
+		}
+	}
+	return s.local.PutChangeSet(puts, stor)
+}
+
+// Seek implements the storage.Store interface. Remote contract storage items
+// are only fetched for prefixes including contract ID, other data is only
+// available if it was fetched before or stored locally. Seek can't return an
+// error, so if remote items can't be fetched, only the local ones are
+// iterated over.
+func (s *Store) Seek(rng storage.SeekRange, f func(k, v []byte) bool) {
+	if len(rng.Prefix) >= 5 && storage.KeyPrefix(rng.Prefix[0]) == storage.STStorage {
+		_ = s.fetchPrefix(rng.Prefix)
+	}
+	s.local.Seek(rng, f)
+}
+
+// SeekGC implements the storage.Store interface, it only affects local data.
+func (s *Store) SeekGC(rng storage.SeekRange, keep func(k, v []byte) bool) error {
+	return s.local.SeekGC(rng, keep)
+}
+
+// Close implements the storage.Store interface, it drops local data, but
+// doesn't close the remote connection.
+func (s *Store) Close() error {
+	return s.local.Close()
+}
+
+// isNotFound checks whether the given remote error means that the requested
+// item doesn't exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, neorpc.ErrUnknownBlock) ||
+		errors.Is(err, neorpc.ErrUnknownTransaction) ||
+		errors.Is(err, neorpc.ErrUnknownContract) ||
+		errors.Is(err, neorpc.ErrUnknownStorageItem)
+}
+
+// parallel calls f for all indices in [0, n) using at most fetchWorkers
+// goroutines and returns the first error encountered.
+func parallel(n int, f func(i int) error) error {
+	var (
+		wg    sync.WaitGroup
+		idx   = make(chan int)
+		errMu sync.Mutex
+		first error
+	)
+	for w := 0; w < fetchWorkers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				if err := f(i); err != nil {
+					errMu.Lock()
+					if first == nil {
+						first = err
+					}
+					errMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return first
+}
@@ -0,0 +1,293 @@
+package fork_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+// remote implements fork.RPC over the local chain the way RPC server does.
+type remote struct {
+	bc *core.Blockchain
+}
+
+func (r remote) FindStates(root util.Uint256, h util.Uint160, prefix []byte, start []byte, maxCount *int) (result.FindStates, error) {
+	var res result.FindStates
+	cs := r.bc.GetContractState(h)
+	if cs == nil {
+		return res, neorpc.ErrUnknownContract
+	}
+	count := 2 // Small pages to test paging.
+	if maxCount != nil {
+		count = *maxCount
+	}
+	if len(start) > 0 {
+		start = start[len(prefix):]
+	}
+	kvs, err := r.bc.GetStateModule().FindStates(root, mptKey(cs.ID, prefix), start, count+1)
+	if err != nil {
+		return res, err
+	}
+	if len(kvs) == count+1 {
+		res.Truncated = true
+		kvs = kvs[:count]
+	}
+	for _, kv := range kvs {
+		res.Results = append(res.Results, result.KeyValue{Key: kv.Key[4:], Value: kv.Value})
+	}
+	return res, nil
+}
+
+func (r remote) GetBlockByHash(h util.Uint256) (*block.Block, error) {
+	b, err := r.bc.GetBlock(h)
+	if err != nil {
+		return nil, neorpc.ErrUnknownBlock
+	}
+	return b, nil
+}
+
+func (r remote) GetBlockByIndex(i uint32) (*block.Block, error) {
+	return r.GetBlockByHash(r.bc.GetHeaderHash(i))
+}
+
+func (r remote) GetBlockHash(i uint32) (util.Uint256, error) {
+	h := r.bc.GetHeaderHash(i)
+	if h.Equals(util.Uint256{}) {
+		return h, neorpc.ErrUnknownBlock
+	}
+	return h, nil
+}
+
+func (r remote) GetRawTransactionVerbose(h util.Uint256) (*result.TransactionOutputRaw, error) {
+	tx, height, err := r.bc.GetTransaction(h)
+	if err != nil {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+	return &result.TransactionOutputRaw{
+		Transaction:         *tx,
+		TransactionMetadata: result.TransactionMetadata{Blockhash: r.bc.GetHeaderHash(height)},
+	}, nil
+}
+
+func (r remote) GetState(root util.Uint256, h util.Uint160, key []byte) ([]byte, error) {
+	cs := r.bc.GetContractState(h)
+	if cs == nil {
+		return nil, neorpc.ErrUnknownContract
+	}
+	v, err := r.bc.GetStateModule().GetState(root, mptKey(cs.ID, key))
+	if err != nil {
+		return nil, neorpc.ErrUnknownStorageItem
+	}
+	return v, nil
+}
+
+func (r remote) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	return r.bc.GetStateModule().GetStateRoot(height)
+}
+
+func mptKey(id int32, key []byte) []byte {
+	k := make([]byte, 4, 4+len(key))
+	binary.LittleEndian.PutUint32(k, uint32(id))
+	return append(k, key...)
+}
+
+func TestFork(t *testing.T) {
+	src, validator := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, src, validator, validator)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	a := e.NewAccount(t)
+	b := e.NewAccount(t)
+	txHash := e.NewInvoker(gasHash, a).Invoke(t, true, "transfer", a.ScriptHash(), b.ScriptHash(), 1, nil)
+	e.GenerateNewBlocks(t, 2003) // Have a complete page of header hashes.
+	h := src.BlockHeight()
+
+	bc, validator := chain.NewFork(t, src.GetConfig(), remote{src}, h)
+	require.Equal(t, h, bc.BlockHeight())
+	require.Equal(t, src.CurrentBlockHash(), bc.CurrentBlockHash())
+	for _, i := range []uint32{0, 1, 1999, 2000, h} {
+		require.Equal(t, src.GetHeaderHash(i), bc.GetHeaderHash(i))
+	}
+	tx, height, err := bc.GetTransaction(txHash)
+	require.NoError(t, err)
+	require.Equal(t, txHash, tx.Hash())
+	blk, err := bc.GetBlock(bc.GetHeaderHash(height))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(blk.Transactions))
+
+	balA, balB := src.GetUtilityTokenBalance(a.ScriptHash()), src.GetUtilityTokenBalance(b.ScriptHash())
+	require.Equal(t, balA, bc.GetUtilityTokenBalance(a.ScriptHash()))
+	require.Equal(t, balB, bc.GetUtilityTokenBalance(b.ScriptHash()))
+
+	// Modify the state on behalf of remote accounts.
+	fe := neotest.NewExecutor(t, bc, validator, validator)
+	impA, impB := neotest.NewImpersonatedSigner(a.ScriptHash()), neotest.NewImpersonatedSigner(b.ScriptHash())
+	fe.NewInvoker(gasHash, impA, impB).Invoke(t, true, "transfer", b.ScriptHash(), a.ScriptHash(), balB, nil)
+	require.Equal(t, h+1, bc.BlockHeight())
+	require.Equal(t, 0, bc.GetUtilityTokenBalance(b.ScriptHash()).Sign())
+	require.Equal(t, 1, bc.GetUtilityTokenBalance(a.ScriptHash()).Cmp(balA))
+
+	// Remote chain is not affected.
+	require.Equal(t, h, src.BlockHeight())
+	require.Equal(t, balA, src.GetUtilityTokenBalance(a.ScriptHash()))
+	require.Equal(t, balB, src.GetUtilityTokenBalance(b.ScriptHash()))
+}
+
+func TestStore(t *testing.T) {
+	src, validator := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, src, validator, validator)
+	gas := src.GetContractState(e.NativeHash(t, nativenames.Gas))
+	accs := []util.Uint160{e.NewAccount(t).ScriptHash(), e.NewAccount(t).ScriptHash(), e.NewAccount(t).ScriptHash()}
+	h := src.BlockHeight()
+
+	st, err := fork.New(remote{src}, src.GetConfig(), h)
+	require.NoError(t, err)
+	require.Equal(t, h, st.Height())
+
+	balanceKey := func(acc util.Uint160) []byte {
+		return append(gasPrefix(gas.ID), acc.BytesBE()...)
+	}
+	seekBalances := func() map[util.Uint160]*big.Int {
+		res := make(map[util.Uint160]*big.Int)
+		st.Seek(storage.SeekRange{Prefix: gasPrefix(gas.ID)}, func(k, v []byte) bool {
+			acc, err := util.Uint160DecodeBytesBE(k[6:])
+			require.NoError(t, err)
+			bal, err := state.NEP17BalanceFromBytes(v)
+			require.NoError(t, err)
+			res[acc] = &bal.Balance
+			return true
+		})
+		return res
+	}
+
+	v, err := st.Get(balanceKey(accs[0]))
+	require.NoError(t, err)
+	bal, err := state.NEP17BalanceFromBytes(v)
+	require.NoError(t, err)
+	require.Equal(t, src.GetUtilityTokenBalance(accs[0]), &bal.Balance)
+
+	_, err = st.Get(balanceKey(util.Uint160{1, 2, 3}))
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	_, err = st.Get(append([]byte{byte(storage.DataExecutable)}, util.Uint256{1, 2, 3}.BytesBE()...))
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	balances := seekBalances()
+	for _, acc := range accs {
+		require.Equal(t, src.GetUtilityTokenBalance(acc), balances[acc])
+	}
+
+	// Local changes take precedence over remote data.
+	require.NoError(t, st.PutChangeSet(nil, map[string][]byte{
+		string(balanceKey(accs[1])):         nil,
+		string(balanceKey(util.Uint160{1})): v,
+	}))
+	balances = seekBalances()
+	require.NotContains(t, balances, accs[1])
+	require.Contains(t, balances, accs[2])
+	require.Equal(t, &bal.Balance, balances[util.Uint160{1}])
+	_, err = st.Get(balanceKey(accs[1]))
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	require.NoError(t, st.Close())
+}
+
+// blockingRemote is a remote that blocks storage item requests for the given
+// key until released.
+type blockingRemote struct {
+	remote
+	key     []byte
+	calls   *atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r blockingRemote) GetState(root util.Uint256, h util.Uint160, key []byte) ([]byte, error) {
+	if bytes.Equal(key, r.key) {
+		if r.calls.Inc() == 1 {
+			close(r.started)
+		}
+		<-r.release
+	}
+	return r.remote.GetState(root, h, key)
+}
+
+func TestStoreConcurrentGet(t *testing.T) {
+	src, validator := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, src, validator, validator)
+	gas := src.GetContractState(e.NativeHash(t, nativenames.Gas))
+	accA, accB := e.NewAccount(t).ScriptHash(), e.NewAccount(t).ScriptHash()
+
+	r := blockingRemote{
+		remote:  remote{src},
+		key:     append([]byte{20}, accA.BytesBE()...),
+		calls:   atomic.NewInt32(0),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	st, err := fork.New(r, src.GetConfig(), src.BlockHeight())
+	require.NoError(t, err)
+
+	keyA := append(gasPrefix(gas.ID), accA.BytesBE()...)
+	keyB := append(gasPrefix(gas.ID), accB.BytesBE()...)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := st.Get(keyA)
+			require.NoError(t, err)
+		}()
+	}
+	<-r.started
+
+	// Other keys can be fetched while the request is in progress.
+	_, err = st.Get(keyB)
+	require.NoError(t, err)
+
+	close(r.release)
+	wg.Wait()
+	require.Equal(t, int32(1), r.calls.Load())
+}
+
+// invalidParamsRemote is a remote rejecting all storage item requests.
+type invalidParamsRemote struct {
+	remote
+}
+
+func (r invalidParamsRemote) GetState(util.Uint256, util.Uint160, []byte) ([]byte, error) {
+	return nil, neorpc.ErrInvalidParams
+}
+
+func TestStoreRemoteError(t *testing.T) {
+	src, validator := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, src, validator, validator)
+	gas := src.GetContractState(e.NativeHash(t, nativenames.Gas))
+
+	st, err := fork.New(invalidParamsRemote{remote{src}}, src.GetConfig(), src.BlockHeight())
+	require.NoError(t, err)
+
+	// Only explicit unknown item errors mean missing items.
+	_, err = st.Get(append(gasPrefix(gas.ID), util.Uint160{1, 2, 3}.BytesBE()...))
+	require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	require.NotErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func gasPrefix(id int32) []byte {
+	k := []byte{byte(storage.STStorage), 0, 0, 0, 0, 20}
+	binary.LittleEndian.PutUint32(k[1:], uint32(id))
+	return k
+}
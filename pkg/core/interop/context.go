@@ -26,6 +26,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 )
 
@@ -34,6 +37,9 @@ const (
 	DefaultBaseExecFee = 30
 )
 
+// tracerName is the name of the tracer used for VM execution spans.
+const tracerName = "github.com/nspcc-dev/neo-go/pkg/vm"
+
 // Ledger is the interface to Blockchain required for Context functionality.
 type Ledger interface {
 	BlockHeight() uint32
@@ -67,6 +73,13 @@ type Context struct {
 	loadToken        func(ic *Context, id int32) error
 	GetRandomCounter uint32
 	signers          []transaction.Signer
+
+	// ExecHook is set as vm.OnExecHook for every VM spawned by the context.
+	ExecHook vm.OnExecHook
+	// Profiler is set as vm.Profiler for every VM spawned by the context.
+	Profiler *vm.Profiler
+	// Tracer is set as vm.Tracer for every VM spawned by the context.
+	Tracer *vm.Tracer
 }
 
 // NewContext returns new interop context.
@@ -340,6 +353,9 @@ func (ic *Context) initVM(v *vm.VM) {
 	v.GasLimit = -1
 	v.SyscallHandler = ic.SyscallHandler
 	v.SetPriceGetter(ic.GetPrice)
+	v.SetOnExecHook(ic.ExecHook)
+	v.SetProfiler(ic.Profiler)
+	v.SetTracer(ic.Tracer)
 	ic.VM = v
 }
 
@@ -367,8 +383,37 @@ func (ic *Context) Finalize() {
 
 // Exec executes loaded VM script and calls registered finalizers to release the occupied resources.
 func (ic *Context) Exec() error {
+	return ic.ExecContext(context.Background())
+}
+
+// ExecContext is the same as Exec, but the execution tracing span is recorded as a
+// child of the span from the given context (if any).
+func (ic *Context) ExecContext(ctx context.Context) error {
 	defer ic.Finalize()
-	return ic.VM.Run()
+	return ic.RunContext(ctx)
+}
+
+// RunContext executes loaded VM script recording the execution tracing span as a
+// child of the span from the given context (if any). Unlike ExecContext it doesn't
+// call finalizers, so the caller is responsible for it.
+func (ic *Context) RunContext(ctx context.Context) error {
+	_, span := otel.Tracer(tracerName).Start(ctx, "VM.Run")
+	defer span.End()
+	err := ic.VM.Run()
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.Stringer("trigger", ic.Trigger),
+			attribute.String("state", ic.VM.State().String()),
+			attribute.Int64("gas", ic.VM.GasConsumed()))
+		if ic.Tx != nil {
+			span.SetAttributes(attribute.String("tx", ic.Tx.Hash().StringLE()))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	return err
 }
 
 // BlockHeight returns current block height got from Context's block if it's set.
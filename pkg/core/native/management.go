@@ -96,6 +96,12 @@ func MakeContractKey(h util.Uint160) []byte {
 	return makeUint160Key(PrefixContract, h)
 }
 
+// MakeContractHashKey creates a key of the contract hash stored by Management
+// for the contract with the given ID.
+func MakeContractHashKey(id int32) []byte {
+	return putHashKey(make([]byte, 5), id)
+}
+
 // newManagement creates a new Management native contract.
 func newManagement() *Management {
 	var m = &Management{
@@ -476,6 +482,50 @@ func (m *Management) Update(ic *interop.Context, hash util.Uint160, neff *nef.Fi
 	return &contract, nil
 }
 
+// Override replaces script and/or manifest of the contract with the given hash
+// in the given DAO or creates a new contract with this hash if there is none.
+// Unlike Update it doesn't check for contract name and doesn't change update
+// counter, it's intended to be used for test invocations with state overrides only.
+func (m *Management) Override(ic *interop.Context, hash util.Uint160, neff *nef.File, manif *manifest.Manifest) (*state.Contract, error) {
+	var contract state.Contract
+
+	oldcontract, err := GetContract(ic.DAO, hash)
+	if err == nil {
+		if oldcontract.ID < 0 {
+			return nil, errors.New("native contract can't be overridden")
+		}
+		contract = *oldcontract // Make a copy, don't ruin (potentially) cached contract.
+	} else {
+		if neff == nil || manif == nil {
+			return nil, errors.New("both NEF and manifest are required for a new contract")
+		}
+		contract.Hash = hash
+		contract.ID, err = m.getNextContractID(ic.DAO)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if neff != nil {
+		contract.NEF = *neff
+	}
+	if manif != nil {
+		err = manif.IsValid(hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		contract.Manifest = *manif
+	}
+	err = checkScriptAndMethods(ic, contract.NEF.Script, contract.Manifest.ABI.Methods)
+	if err != nil {
+		return nil, err
+	}
+	err = PutContractState(ic.DAO, &contract)
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
 // destroy is an implementation of destroy update method, it's run under
 // VM protections, so it's OK for it to panic instead of returning errors.
 func (m *Management) destroy(ic *interop.Context, sis []stackitem.Item) stackitem.Item {
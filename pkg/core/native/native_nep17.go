@@ -261,6 +261,41 @@ func (c *nep17TokenNative) balanceOfInternal(d *dao.Simple, h util.Uint160) *big
 	return balance
 }
 
+// SetBalance sets the balance of the given account and adjusts total supply
+// accordingly using the given invocation context. It doesn't emit
+// notifications, for NEO the balance height is updated to the current block,
+// but GAS accrued for the previous balance is not distributed. It's only
+// suitable for temporary DAOs used by test invocations.
+func (c *nep17TokenNative) SetBalance(ic *interop.Context, h util.Uint160, amount *big.Int) error {
+	if amount.Sign() < 0 {
+		return errors.New("negative balance")
+	}
+	d := ic.DAO
+	delta := new(big.Int).Sub(amount, c.balanceOfInternal(d, h))
+	if delta.Sign() == 0 {
+		return nil
+	}
+	key := makeAccountKey(h)
+	si := d.GetStorageItem(c.ID, key)
+	if si == nil {
+		si = state.StorageItem{}
+	}
+	// GAS distribution callback is dropped intentionally.
+	_, err := c.incBalance(ic, h, &si, delta, nil)
+	if err != nil {
+		return err
+	}
+	if si == nil {
+		d.DeleteStorageItem(c.ID, key)
+	} else {
+		d.PutStorageItem(c.ID, key, si)
+	}
+	buf, supply := c.getTotalSupply(d)
+	supply.Add(supply, delta)
+	c.saveTotalSupply(d, buf, supply)
+	return nil
+}
+
 func (c *nep17TokenNative) mint(ic *interop.Context, h util.Uint160, amount *big.Int, callOnPayment bool) {
 	if amount.Sign() == 0 {
 		return
@@ -0,0 +1,43 @@
+package state
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// NotificationIndexEntry is an entry of the notification index, it references
+// a single notification emitted by some contract during the successful
+// execution of some script container.
+type NotificationIndexEntry struct {
+	// BlockIndex is the index of the block where the notification was emitted.
+	BlockIndex uint32
+	// Position is the execution position in the block: 0 for OnPersist
+	// trigger execution, 1..N for the block transactions and N+1 for
+	// PostPersist trigger execution (which doesn't fit into uint16 for
+	// blocks with the maximum number of transactions).
+	Position uint32
+	// Index is the notification index in the execution.
+	Index uint32
+	// Container is the hash of the script container (block or transaction).
+	Container util.Uint256
+	// Trigger is the trigger of the execution.
+	Trigger trigger.Type
+	// ScriptHash is the hash of the contract that emitted the notification.
+	ScriptHash util.Uint160
+	// Name is the event name.
+	Name string
+}
+
+// TransactionIndexEntry is an entry of the transaction index, it references
+// a single transaction signed by some account.
+type TransactionIndexEntry struct {
+	// BlockIndex is the index of the block containing the transaction.
+	BlockIndex uint32
+	// Position is the transaction position in the block starting from 1.
+	Position uint16
+	// Hash is the transaction hash.
+	Hash util.Uint256
+	// Sender is true if the account is the transaction sender (the first
+	// signer).
+	Sender bool
+}
@@ -0,0 +1,21 @@
+package state
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// StorageChangeEvent represents a single contract storage item change made by
+// a persisted block. Changes of the same key made by different transactions of
+// the block are merged, so only the resulting value is available.
+type StorageChangeEvent struct {
+	// BlockHash is the hash of the block that has changed the item.
+	BlockHash util.Uint256 `json:"blockhash"`
+	// BlockIndex is the index of the block that has changed the item.
+	BlockIndex uint32 `json:"blockindex"`
+	// Contract is the hash of the contract owning the storage item.
+	Contract util.Uint160 `json:"contract"`
+	// Key is the storage item key (without contract ID).
+	Key []byte `json:"key"`
+	// Value is the new storage item value, it's nil for deleted items.
+	Value []byte `json:"value"`
+}
@@ -194,7 +194,11 @@ func (s *Module) Init(height uint32) error {
 	}
 	s.currentLocal.Store(r.Root)
 	s.localHeight.Store(r.Index)
-	s.mpt = mpt.NewTrie(mpt.NewHashNode(r.Root), s.mode, s.Store)
+	var root mpt.Node
+	if !r.Root.Equals(util.Uint256{}) { // Zero root is the empty trie.
+		root = mpt.NewHashNode(r.Root)
+	}
+	s.mpt = mpt.NewTrie(root, s.mode, s.Store)
 	return nil
 }
 
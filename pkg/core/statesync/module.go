@@ -233,8 +233,9 @@ func (s *Module) defineSyncStage() error {
 		err = s.billet.Traverse(func(_ []byte, n mpt.Node, _ []byte) bool {
 			nPaths, ok := pool.TryGet(n.Hash())
 			if !ok {
-				// if this situation occurs, then it's a bug in MPT pool or Traverse.
-				panic("failed to get MPT node from the pool")
+				// The same node can be referenced from several paths, it's
+				// already processed for all of them (including its children).
+				return false
 			}
 			pool.Remove(n.Hash())
 			childrenPaths := make(map[util.Uint256][][]byte)
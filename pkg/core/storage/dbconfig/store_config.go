@@ -4,11 +4,12 @@ Package dbconfig is a micropackage that contains storage DB configuration option
 package dbconfig
 
 type (
-	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB'.
+	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB', 'pebbleDB'.
 	DBConfiguration struct {
-		Type           string         `yaml:"Type"`
-		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
-		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		Type            string          `yaml:"Type"`
+		LevelDBOptions  LevelDBOptions  `yaml:"LevelDBOptions"`
+		BoltDBOptions   BoltDBOptions   `yaml:"BoltDBOptions"`
+		PebbleDBOptions PebbleDBOptions `yaml:"PebbleDBOptions"`
 	}
 	// LevelDBOptions configuration for LevelDB.
 	LevelDBOptions struct {
@@ -20,4 +21,22 @@ type (
 		FilePath string `yaml:"FilePath"`
 		ReadOnly bool   `yaml:"ReadOnly"`
 	}
+	// PebbleDBOptions configuration for PebbleDB. Zero values of tuning
+	// parameters mean Pebble defaults.
+	PebbleDBOptions struct {
+		DataDirectoryPath string `yaml:"DataDirectoryPath"`
+		ReadOnly          bool   `yaml:"ReadOnly"`
+		// CacheSize is the size of block cache in bytes.
+		CacheSize int64 `yaml:"CacheSize"`
+		// MemTableSize is the size of a single memtable in bytes.
+		MemTableSize int `yaml:"MemTableSize"`
+		// MaxConcurrentCompactions limits the number of parallel compactions.
+		MaxConcurrentCompactions int `yaml:"MaxConcurrentCompactions"`
+		// L0CompactionThreshold is the number of L0 read-amplification
+		// units that triggers L0 compaction.
+		L0CompactionThreshold int `yaml:"L0CompactionThreshold"`
+		// L0StopWritesThreshold is the number of L0 read-amplification
+		// units that stops writes until compaction catches up.
+		L0StopWritesThreshold int `yaml:"L0StopWritesThreshold"`
+	}
 )
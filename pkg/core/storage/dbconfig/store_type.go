@@ -6,6 +6,8 @@ const (
 	BoltDB = "boltdb"
 	// LevelDB represents Level DB storage name.
 	LevelDB = "leveldb"
+	// PebbleDB represents Pebble DB storage name.
+	PebbleDB = "pebbledb"
 	// InMemoryDB represents in-memory storage name.
 	InMemoryDB = "inmemory"
 )
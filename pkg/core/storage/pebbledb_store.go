@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// PebbleDBStore is an LSM-based storage implementation for storing and
+// retrieving blockchain data.
+type PebbleDBStore struct {
+	db *pebble.DB
+}
+
+// NewPebbleDBStore returns a new PebbleDBStore object that will
+// initialize the database found at the given path.
+func NewPebbleDBStore(cfg dbconfig.PebbleDBOptions) (*PebbleDBStore, error) {
+	opts := &pebble.Options{
+		MemTableSize:          cfg.MemTableSize,
+		L0CompactionThreshold: cfg.L0CompactionThreshold,
+		L0StopWritesThreshold: cfg.L0StopWritesThreshold,
+	}
+	if cfg.ReadOnly {
+		opts.ReadOnly = true
+		opts.ErrorIfNotExists = true
+	}
+	if cfg.CacheSize > 0 {
+		cache := pebble.NewCache(cfg.CacheSize)
+		defer cache.Unref() // DB holds its own reference.
+		opts.Cache = cache
+	}
+	if cfg.MaxConcurrentCompactions > 0 {
+		n := cfg.MaxConcurrentCompactions
+		opts.MaxConcurrentCompactions = func() int { return n }
+	}
+	db, err := pebble.Open(cfg.DataDirectoryPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PebbleDB instance: %w", err)
+	}
+	return &PebbleDBStore{db: db}, nil
+}
+
+// Get implements the Store interface.
+func (s *PebbleDBStore) Get(key []byte) ([]byte, error) {
+	value, closer, err := s.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			err = ErrKeyNotFound
+		}
+		return nil, err
+	}
+	// Value is only valid until closer.Close().
+	value = slice.Copy(value)
+	return value, closer.Close()
+}
+
+// PutChangeSet implements the Store interface.
+func (s *PebbleDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	b := s.db.NewBatch()
+	defer b.Close()
+	for _, m := range []map[string][]byte{puts, stores} {
+		for k := range m {
+			var err error
+			if m[k] != nil {
+				err = b.Set([]byte(k), m[k], nil)
+			} else {
+				err = b.Delete([]byte(k), nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return b.Commit(pebble.Sync)
+}
+
+// Seek implements the Store interface.
+func (s *PebbleDBStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	s.seek(rng, f)
+}
+
+// SeekGC implements the Store interface.
+func (s *PebbleDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	var err error
+
+	b := s.db.NewBatch()
+	defer b.Close()
+	// Iterator works over a consistent DB snapshot, so deletions are
+	// accumulated in a batch and applied at once.
+	s.seek(rng, func(k, v []byte) bool {
+		if !keep(k, v) {
+			err = b.Delete(k, nil)
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return b.Commit(pebble.Sync)
+}
+
+func (s *PebbleDBStore) seek(rng SeekRange, f func(k, v []byte) bool) {
+	var (
+		rang = seekRangeToPrefixes(rng)
+		next func() bool
+		ok   bool
+	)
+
+	iter := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: rang.Start,
+		UpperBound: rang.Limit,
+	})
+	if !rng.Backwards {
+		ok = iter.First()
+		next = iter.Next
+	} else {
+		ok = iter.Last()
+		next = iter.Prev
+	}
+
+	for ; ok; ok = next() {
+		if !f(iter.Key(), iter.Value()) {
+			break
+		}
+	}
+	_ = iter.Close()
+}
+
+// Close implements the Store interface.
+func (s *PebbleDBStore) Close() error {
+	return s.db.Close()
+}
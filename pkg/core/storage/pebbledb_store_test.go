@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func newPebbleDBForTesting(t testing.TB) Store {
+	dir := t.TempDir()
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: dir,
+		CacheSize:         1 << 20,
+	}
+	newPebbleStore, err := NewPebbleDBStore(opts)
+	require.Nil(t, err, "NewPebbleDBStore error")
+	return newPebbleStore
+}
+
+func TestROPebbleDB(t *testing.T) {
+	dir := t.TempDir()
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: dir,
+		ReadOnly:          true,
+	}
+
+	// If DB doesn't exist, then error should be returned.
+	_, err := NewPebbleDBStore(opts)
+	require.Error(t, err)
+
+	// Create the DB and try to open it in RO mode.
+	opts.ReadOnly = false
+	store, err := NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	opts.ReadOnly = true
+
+	store, err = NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+	// Changes must be prohibited.
+	putErr := store.PutChangeSet(map[string][]byte{"one": []byte("one")}, nil)
+	require.ErrorIs(t, putErr, pebble.ErrReadOnly)
+}
@@ -27,6 +27,8 @@ const (
 	STNEP17Transfers               KeyPrefix = 0x73
 	STTokenTransferInfo            KeyPrefix = 0x74
 	IXHeaderHashList               KeyPrefix = 0x80
+	IXNotifications                KeyPrefix = 0x81
+	IXTransactions                 KeyPrefix = 0x82
 	SYSCurrentBlock                KeyPrefix = 0xc0
 	SYSCurrentHeader               KeyPrefix = 0xc1
 	SYSStateSyncCurrentBlockHeight KeyPrefix = 0xc2
@@ -131,6 +133,8 @@ func NewStore(cfg dbconfig.DBConfiguration) (Store, error) {
 		store = NewMemoryStore()
 	case dbconfig.BoltDB:
 		store, err = NewBoltDBStore(cfg.BoltDBOptions)
+	case dbconfig.PebbleDB:
+		store, err = NewPebbleDBStore(cfg.PebbleDBOptions)
 	default:
 		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
 	}
@@ -0,0 +1,549 @@
+/*
+Package lightclient implements a light client for Neo N3 networks.
+
+The light client doesn't process blocks and doesn't keep the contract storage,
+it only syncs and verifies the chain of block headers starting from the
+genesis block of the configured network and tracks validated state roots. All
+the data is fetched from a set of (untrusted) RPC servers and verified
+locally, so storage items and balances returned by the client are backed by
+MPT proofs checked against the validated state root.
+
+State roots are taken from block headers for networks with StateRootInHeader
+enabled, otherwise they're fetched via the RPC and their witnesses are checked
+against the configured list of state validators (that are designated by the
+committee and run the stateroot service). This list is not updated when the
+committee designates new state validators, roots signed by them are rejected
+with ErrStateValidatorsMismatch until the configuration is changed.
+*/
+package lightclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// RPC is a set of RPC methods used by the light client, it's implemented by
+// rpcclient.Client (that needs to be initialized, see Dial).
+type RPC interface {
+	GetBlockCount() (uint32, error)
+	GetBlockHeaderByIndex(index uint32) (*block.Header, error)
+	GetStateHeight() (*result.StateHeight, error)
+	GetStateRootByHeight(height uint32) (*state.MPTRoot, error)
+	GetProof(stateroot util.Uint256, contract util.Uint160, key []byte) (*result.ProofWithKey, error)
+}
+
+// Client is a light client that keeps the latest verified header and state root.
+type Client struct {
+	proto   config.ProtocolConfiguration
+	cfg     config.LightClient
+	clients []RPC
+	store   storage.Store
+	log     *zap.Logger
+
+	// validators is the state validators multisignature account, it's only
+	// used if state roots are not included into headers.
+	validators util.Uint160
+
+	lock        sync.RWMutex
+	header      *block.Header
+	root        *state.MPTRoot
+	contractIDs map[util.Uint160]int32
+
+	started *atomic.Bool
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+// Various query errors.
+var (
+	// ErrUnverifiedAbsence is returned when every configured RPC server
+	// reports that the requested storage item doesn't exist. MPT proofs can't
+	// be used to prove the absence of the key, so this answer is not verified
+	// and is only as reliable as the weakest agreement of all servers: any of
+	// them could be wrong or lying. It must not be treated as a proof of the
+	// item absence (like zero balance).
+	ErrUnverifiedAbsence = errors.New("item absence is not verified")
+	// ErrNoStateRoot is returned for queries made before any state root is
+	// validated.
+	ErrNoStateRoot = errors.New("no validated state root")
+	// ErrInvalidProof is returned when the proof received from the RPC server
+	// doesn't match the validated state root.
+	ErrInvalidProof = errors.New("invalid proof")
+	// ErrUnsupportedToken is returned by GetNEP17Balance for tokens other
+	// than native NEO and GAS.
+	ErrUnsupportedToken = errors.New("unsupported token")
+	// ErrStateValidatorsMismatch is returned by Sync when the state root is
+	// signed by some account other than the one of configured state
+	// validators. Usually it means that new state validators are designated
+	// and the configuration needs to be updated.
+	ErrStateValidatorsMismatch = fmt.Errorf("%w: state root is not signed by configured state validators", ErrInvalidWitness)
+)
+
+// nep17AccountPrefix is the storage prefix of account balances used by native
+// NEP-17 contracts.
+const nep17AccountPrefix = 20
+
+// headerBatchSize is the number of headers after which the client state is
+// persisted during synchronization.
+const headerBatchSize = 1000
+
+// Keys used to store the client state.
+var (
+	headerKey = []byte{byte(storage.SYSCurrentHeader)}
+	rootKey   = []byte{byte(storage.DataMPTAux)}
+)
+
+var (
+	managementHash = state.CreateNativeContractHash(nativenames.Management)
+	neoHash        = state.CreateNativeContractHash(nativenames.Neo)
+	gasHash        = state.CreateNativeContractHash(nativenames.Gas)
+)
+
+// New creates a light client for the network described by proto. The data is
+// fetched from the given RPC clients, the state of the client (the latest
+// verified header and state root) is kept in the store, so that the
+// synchronization continues from the point where it was stopped. The store
+// can't be shared with a full node.
+func New(proto config.ProtocolConfiguration, cfg config.LightClient, clients []RPC, store storage.Store, log *zap.Logger) (*Client, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("no RPC endpoints")
+	}
+	if _, err := store.Get([]byte{byte(storage.SYSVersion)}); err == nil {
+		return nil, errors.New("the DB belongs to a full node")
+	}
+	c := &Client{
+		proto:       proto,
+		cfg:         cfg,
+		clients:     clients,
+		store:       store,
+		log:         log,
+		contractIDs: make(map[util.Uint160]int32),
+		started:     atomic.NewBool(false),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if c.cfg.SyncInterval <= 0 {
+		c.cfg.SyncInterval = proto.TimePerBlock
+		if c.cfg.SyncInterval <= 0 {
+			c.cfg.SyncInterval = 15 * time.Second
+		}
+	}
+	if !proto.StateRootInHeader {
+		if len(cfg.StateValidators) == 0 {
+			return nil, errors.New("state validators are required if state roots are not included into headers")
+		}
+		pubs, err := keys.NewPublicKeysFromStrings(cfg.StateValidators)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state validators: %w", err)
+		}
+		script, err := smartcontract.CreateDefaultMultiSigRedeemScript(pubs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state validators: %w", err)
+		}
+		c.validators = hash.Hash160(script)
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load restores the client state from the store or initializes it with the
+// genesis block header.
+func (c *Client) load() error {
+	data, err := c.store.Get(headerKey)
+	if err != nil {
+		if !errors.Is(err, storage.ErrKeyNotFound) {
+			return err
+		}
+		genesis, err := core.CreateGenesisBlock(c.proto)
+		if err != nil {
+			return fmt.Errorf("failed to create genesis block: %w", err)
+		}
+		c.header = &genesis.Header
+		return nil
+	}
+	h := &block.Header{StateRootEnabled: c.proto.StateRootInHeader}
+	r := io.NewBinReaderFromBuf(data)
+	h.DecodeBinary(r)
+	if r.Err != nil {
+		return fmt.Errorf("failed to decode stored header: %w", r.Err)
+	}
+	c.header = h
+	if c.proto.StateRootInHeader {
+		c.root = rootFromHeader(h)
+		return nil
+	}
+	data, err = c.store.Get(rootKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+	root := new(state.MPTRoot)
+	r = io.NewBinReaderFromBuf(data)
+	root.DecodeBinary(r)
+	if r.Err != nil {
+		return fmt.Errorf("failed to decode stored state root: %w", r.Err)
+	}
+	c.root = root
+	return nil
+}
+
+// persist saves the current client state to the store.
+func (c *Client) persist() error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var changes = map[string][]byte{}
+	w := io.NewBufBinWriter()
+	c.header.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	changes[string(headerKey)] = w.Bytes()
+	if c.root != nil && !c.proto.StateRootInHeader {
+		w = io.NewBufBinWriter()
+		c.root.EncodeBinary(w.BinWriter)
+		if w.Err != nil {
+			return w.Err
+		}
+		changes[string(rootKey)] = w.Bytes()
+	}
+	return c.store.PutChangeSet(changes, nil)
+}
+
+// rootFromHeader returns the state root of the previous block stored in h.
+func rootFromHeader(h *block.Header) *state.MPTRoot {
+	if h.Index == 0 {
+		return nil
+	}
+	return &state.MPTRoot{Index: h.Index - 1, Root: h.PrevStateRoot}
+}
+
+// Header returns the latest verified header.
+func (c *Client) Header() *block.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.header
+}
+
+// HeaderHeight returns the index of the latest verified header.
+func (c *Client) HeaderHeight() uint32 {
+	return c.Header().Index
+}
+
+// StateRoot returns the latest validated state root, it's nil if there is no
+// such root yet.
+func (c *Client) StateRoot() *state.MPTRoot {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.root
+}
+
+// Start runs periodic synchronization in a separate goroutine. The client
+// only starts once, subsequent calls to Start are no-op.
+func (c *Client) Start() {
+	if !c.started.CompareAndSwap(false, true) {
+		return
+	}
+	c.log.Info("starting light client", zap.Uint32("header height", c.HeaderHeight()))
+	go c.syncLoop()
+}
+
+// Shutdown stops the client. It can only be called once, subsequent calls
+// to Shutdown on the same instance are no-op. The instance that was stopped can
+// not be started again by calling Start (use a new instance if needed).
+func (c *Client) Shutdown() {
+	if !c.started.CompareAndSwap(true, false) {
+		return
+	}
+	c.log.Info("stopping light client")
+	close(c.quit)
+	<-c.done
+}
+
+func (c *Client) syncLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-c.quit
+		cancel()
+	}()
+	t := time.NewTicker(c.cfg.SyncInterval)
+	defer t.Stop()
+	for {
+		if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+			c.log.Warn("light client synchronization failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			close(c.done)
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Sync fetches and verifies new headers and updates the validated state root.
+// It returns when the client reaches the height reported by the RPC servers,
+// when no valid header can be fetched or when ctx is done. The synchronization
+// progress is kept even if an error is returned.
+func (c *Client) Sync(ctx context.Context) error {
+	var target uint32
+	for _, cl := range c.clients {
+		count, err := cl.GetBlockCount()
+		if err == nil && count > 0 && count-1 > target {
+			target = count - 1
+		}
+	}
+	var (
+		err     error
+		start   = c.HeaderHeight()
+		updated bool
+	)
+	for h := c.Header(); h.Index < target; {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		h, err = c.fetchHeader(h)
+		if err != nil {
+			break
+		}
+		c.lock.Lock()
+		c.header = h
+		if c.proto.StateRootInHeader {
+			c.root = rootFromHeader(h)
+		}
+		c.lock.Unlock()
+		updated = true
+		if (h.Index-start)%headerBatchSize == 0 {
+			if err = c.persist(); err != nil {
+				return fmt.Errorf("failed to persist state: %w", err)
+			}
+			updated = false
+		}
+	}
+	if !c.proto.StateRootInHeader && err == nil {
+		var ok bool
+		ok, err = c.updateStateRoot()
+		updated = updated || ok
+	}
+	if updated {
+		if perr := c.persist(); perr != nil {
+			return fmt.Errorf("failed to persist state: %w", perr)
+		}
+	}
+	if c.HeaderHeight() != start {
+		c.log.Debug("light client synchronized",
+			zap.Uint32("header height", c.HeaderHeight()),
+			zap.Uint32("target", target))
+	}
+	return err
+}
+
+// fetchHeader returns the verified header following prev, endpoints are tried
+// one by one until a valid header is received.
+func (c *Client) fetchHeader(prev *block.Header) (*block.Header, error) {
+	var lastErr error
+	for _, cl := range c.clients {
+		h, err := cl.GetBlockHeaderByIndex(prev.Index + 1)
+		if err == nil {
+			err = verifyHeader(c.proto.Magic, h, prev)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return h, nil
+	}
+	return nil, fmt.Errorf("failed to get valid header %d: %w", prev.Index+1, lastErr)
+}
+
+// updateStateRoot fetches the latest validated state root and checks its
+// witness against the configured state validators account. It returns true if
+// the root was updated.
+func (c *Client) updateStateRoot() (bool, error) {
+	var (
+		best    = c.StateRoot()
+		lastErr error
+		updated bool
+	)
+	for _, cl := range c.clients {
+		sh, err := cl.GetStateHeight()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if best != nil && sh.Validated <= best.Index {
+			continue
+		}
+		root, err := cl.GetStateRootByHeight(sh.Validated)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if root.Index != sh.Validated {
+			lastErr = fmt.Errorf("state root index mismatch: %d != %d", root.Index, sh.Validated)
+			continue
+		}
+		if len(root.Witness) != 1 {
+			lastErr = fmt.Errorf("%w: state root %d is not signed", ErrInvalidWitness, root.Index)
+			continue
+		}
+		if signer := root.Witness[0].ScriptHash(); signer != c.validators {
+			lastErr = fmt.Errorf("%w: state root %d is signed by %s, StateValidators setting may be outdated",
+				ErrStateValidatorsMismatch, root.Index, address.Uint160ToString(signer))
+			continue
+		}
+		if err = verifyWitness(c.proto.Magic, c.validators, root, &root.Witness[0]); err != nil {
+			lastErr = fmt.Errorf("state root %d: %w", root.Index, err)
+			continue
+		}
+		best = root
+		updated = true
+	}
+	if updated {
+		c.lock.Lock()
+		c.root = best
+		c.lock.Unlock()
+		return true, nil
+	}
+	return false, lastErr
+}
+
+// GetStorage returns the verified value of the contract storage item as of the
+// latest validated state root.
+func (c *Client) GetStorage(contract util.Uint160, key []byte) ([]byte, error) {
+	root := c.StateRoot()
+	if root == nil {
+		return nil, ErrNoStateRoot
+	}
+	id, err := c.getContractID(root.Root, contract)
+	if err != nil {
+		return nil, err
+	}
+	return c.getVerifiedItem(root.Root, contract, id, key)
+}
+
+// GetNEP17Balance returns the verified balance of the account for native NEO
+// or GAS token as of the latest validated state root. Balances of other tokens
+// are stored in contract-specific format and can't be decoded by the client,
+// use GetStorage for them. Accounts without balance can't be verified, so
+// ErrUnverifiedAbsence is returned for them instead of zero value.
+func (c *Client) GetNEP17Balance(token util.Uint160, acc util.Uint160) (*big.Int, error) {
+	if token != neoHash && token != gasHash {
+		return nil, ErrUnsupportedToken
+	}
+	val, err := c.GetStorage(token, append([]byte{nep17AccountPrefix}, acc.BytesBE()...))
+	if err != nil {
+		return nil, err
+	}
+	if token == neoHash {
+		b, err := state.NEOBalanceFromBytes(val)
+		if err != nil {
+			return nil, err
+		}
+		return &b.Balance, nil
+	}
+	b, err := state.NEP17BalanceFromBytes(val)
+	if err != nil {
+		return nil, err
+	}
+	return &b.Balance, nil
+}
+
+// getContractID returns the ID of the contract using the verified contract
+// state stored by the management contract.
+func (c *Client) getContractID(root util.Uint256, h util.Uint160) (int32, error) {
+	c.lock.RLock()
+	id, ok := c.contractIDs[h]
+	c.lock.RUnlock()
+	if ok {
+		return id, nil
+	}
+	val, err := c.getVerifiedItem(root, managementHash, native.ManagementContractID, native.MakeContractKey(h))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contract %s state: %w", h.StringLE(), err)
+	}
+	cs := new(state.Contract)
+	if err = stackitem.DeserializeConvertible(val, cs); err != nil {
+		return 0, fmt.Errorf("failed to decode contract %s state: %w", h.StringLE(), err)
+	}
+	if cs.Hash != h {
+		return 0, fmt.Errorf("contract hash mismatch: %s != %s", cs.Hash.StringLE(), h.StringLE())
+	}
+	// Contract ID never changes, so it's safe to cache it.
+	c.lock.Lock()
+	c.contractIDs[h] = cs.ID
+	c.lock.Unlock()
+	return cs.ID, nil
+}
+
+// getVerifiedItem fetches the proof of the storage item from RPC servers and
+// returns the value it proves for the root. ErrUnverifiedAbsence is returned
+// only if all RPC servers report the item to be missing.
+func (c *Client) getVerifiedItem(root util.Uint256, contract util.Uint160, id int32, key []byte) ([]byte, error) {
+	var (
+		lastErr  error
+		notFound int
+		skey     = makeStorageKey(id, key)
+	)
+	for _, cl := range c.clients {
+		p, err := cl.GetProof(root, contract, key)
+		if err != nil {
+			if errors.Is(err, neorpc.ErrUnknownStorageItem) {
+				notFound++
+			}
+			lastErr = err
+			continue
+		}
+		if !bytes.Equal(p.Key, skey) {
+			lastErr = fmt.Errorf("%w: key mismatch", ErrInvalidProof)
+			continue
+		}
+		val, ok := mpt.VerifyProof(root, skey, p.Proof)
+		if !ok {
+			lastErr = ErrInvalidProof
+			continue
+		}
+		return val, nil
+	}
+	if notFound == len(c.clients) {
+		return nil, ErrUnverifiedAbsence
+	}
+	return nil, lastErr
+}
+
+func makeStorageKey(id int32, key []byte) []byte {
+	skey := make([]byte, 4+len(key))
+	binary.LittleEndian.PutUint32(skey, uint32(id))
+	copy(skey[4:], key)
+	return skey
+}
@@ -0,0 +1,253 @@
+package lightclient_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/lightclient"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// chainRPC serves light client requests using the full node, it can also
+// behave maliciously.
+type chainRPC struct {
+	bc *core.Blockchain
+	// srKey is used to sign state roots.
+	srKey *keys.PrivateKey
+	// bad makes it return headers and proofs with broken signatures and
+	// hashes.
+	bad bool
+	// hide makes it deny the existence of any storage item.
+	hide bool
+}
+
+func (c *chainRPC) GetBlockCount() (uint32, error) {
+	return c.bc.BlockHeight() + 1, nil
+}
+
+func (c *chainRPC) GetBlockHeaderByIndex(index uint32) (*block.Header, error) {
+	h, err := c.bc.GetHeader(c.bc.GetHeaderHash(index))
+	if err != nil {
+		return nil, err
+	}
+	if c.bad {
+		hdr := *h
+		hdr.Script.InvocationScript = slice.Copy(h.Script.InvocationScript)
+		hdr.Script.InvocationScript[10] ^= 0xff
+		return &hdr, nil
+	}
+	return h, nil
+}
+
+func (c *chainRPC) GetStateHeight() (*result.StateHeight, error) {
+	return &result.StateHeight{Local: c.bc.BlockHeight(), Validated: c.bc.BlockHeight()}, nil
+}
+
+func (c *chainRPC) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
+	r, err := c.bc.GetStateModule().GetStateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+	sr := *r
+	if c.srKey != nil {
+		script, err := smartcontract.CreateDefaultMultiSigRedeemScript(keys.PublicKeys{c.srKey.PublicKey()})
+		if err != nil {
+			return nil, err
+		}
+		sig := c.srKey.SignHashable(uint32(c.bc.GetConfig().Magic), &sr)
+		sr.Witness = []transaction.Witness{{
+			InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
+			VerificationScript: script,
+		}}
+	}
+	return &sr, nil
+}
+
+func (c *chainRPC) GetProof(root util.Uint256, contract util.Uint160, key []byte) (*result.ProofWithKey, error) {
+	if c.hide {
+		return nil, neorpc.ErrUnknownStorageItem
+	}
+	cs := c.bc.GetContractState(contract)
+	if cs == nil {
+		return nil, neorpc.ErrUnknownContract
+	}
+	skey := append(idToLE(cs.ID), key...)
+	proof, err := c.bc.GetStateModule().GetStateProof(root, skey)
+	if err != nil {
+		if errors.Is(err, mpt.ErrNotFound) {
+			return nil, neorpc.ErrUnknownStorageItem
+		}
+		return nil, err
+	}
+	if c.bad {
+		proof[0] = slice.Copy(proof[0])
+		proof[0][len(proof[0])-1] ^= 0xff
+	}
+	return &result.ProofWithKey{Key: skey, Proof: proof}, nil
+}
+
+func idToLE(id int32) []byte {
+	return []byte{byte(id), byte(id >> 8), byte(id >> 16), byte(id >> 24)}
+}
+
+func newClient(t *testing.T, bc *core.Blockchain, cfg config.LightClient, store storage.Store, rpcs ...lightclient.RPC) *lightclient.Client {
+	c, err := lightclient.New(bc.GetConfig().ProtocolConfiguration, cfg, rpcs, store, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_StateRootInHeader(t *testing.T) {
+	bc, validators, committee := chain.NewMultiWithCustomConfig(t, func(c *config.Blockchain) {
+		c.StateRootInHeader = true
+	})
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	acc := e.NewAccount(t, 0)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), acc.ScriptHash(), 12345, nil)
+	e.AddNewBlock(t)
+
+	store := storage.NewMemoryStore()
+	honest := &chainRPC{bc: bc}
+	c := newClient(t, bc, config.LightClient{}, store, &chainRPC{bc: bc, bad: true}, honest)
+	require.Equal(t, uint32(0), c.HeaderHeight())
+	require.Nil(t, c.StateRoot())
+
+	require.NoError(t, c.Sync(context.Background()))
+	require.Equal(t, bc.BlockHeight(), c.HeaderHeight())
+	require.Equal(t, bc.CurrentHeaderHash(), c.Header().Hash())
+	expected, err := bc.GetStateModule().GetStateRoot(bc.BlockHeight() - 1)
+	require.NoError(t, err)
+	require.Equal(t, expected.Index, c.StateRoot().Index)
+	require.Equal(t, expected.Root, c.StateRoot().Root)
+
+	t.Run("balance", func(t *testing.T) {
+		b, err := c.GetNEP17Balance(gas.Hash, acc.ScriptHash())
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(12345), b)
+
+		// Absence can't be proven, so there is no zero balance.
+		_, err = c.GetNEP17Balance(e.NativeHash(t, nativenames.Neo), acc.ScriptHash())
+		require.ErrorIs(t, err, lightclient.ErrUnverifiedAbsence)
+
+		_, err = c.GetNEP17Balance(util.Uint160{1, 2, 3}, acc.ScriptHash())
+		require.ErrorIs(t, err, lightclient.ErrUnsupportedToken)
+	})
+	t.Run("storage", func(t *testing.T) {
+		// NEO contract stores the committee under prefix 14.
+		val, err := c.GetStorage(e.NativeHash(t, nativenames.Neo), []byte{14})
+		require.NoError(t, err)
+		require.NotEmpty(t, val)
+
+		_, err = c.GetStorage(e.NativeHash(t, nativenames.Neo), []byte{0xff, 0xff})
+		require.ErrorIs(t, err, lightclient.ErrUnverifiedAbsence)
+
+		_, err = c.GetStorage(util.Uint160{1, 2, 3}, []byte{1})
+		require.ErrorIs(t, err, lightclient.ErrUnverifiedAbsence)
+	})
+	t.Run("hidden item", func(t *testing.T) {
+		hiding := &chainRPC{bc: bc, hide: true}
+		mixed := newClient(t, bc, config.LightClient{}, storage.NewMemoryStore(), hiding, honest)
+		require.NoError(t, mixed.Sync(context.Background()))
+		b, err := mixed.GetNEP17Balance(gas.Hash, acc.ScriptHash())
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(12345), b)
+
+		liar := newClient(t, bc, config.LightClient{}, storage.NewMemoryStore(), hiding)
+		require.NoError(t, liar.Sync(context.Background()))
+		_, err = liar.GetNEP17Balance(gas.Hash, acc.ScriptHash())
+		require.ErrorIs(t, err, lightclient.ErrUnverifiedAbsence)
+	})
+	t.Run("invalid data only", func(t *testing.T) {
+		bad := newClient(t, bc, config.LightClient{}, storage.NewMemoryStore(), &chainRPC{bc: bc, bad: true})
+		require.Error(t, bad.Sync(context.Background()))
+		require.Equal(t, uint32(0), bad.HeaderHeight())
+		_, err := bad.GetStorage(gas.Hash, []byte{11})
+		require.ErrorIs(t, err, lightclient.ErrNoStateRoot)
+	})
+	t.Run("restore", func(t *testing.T) {
+		restored := newClient(t, bc, config.LightClient{}, store, honest)
+		require.Equal(t, c.Header().Hash(), restored.Header().Hash())
+		require.Equal(t, c.StateRoot(), restored.StateRoot())
+
+		e.AddNewBlock(t)
+		require.NoError(t, restored.Sync(context.Background()))
+		require.Equal(t, bc.BlockHeight(), restored.HeaderHeight())
+	})
+}
+
+func TestClient_ValidatedStateRoots(t *testing.T) {
+	bc, validators, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	acc := e.NewAccount(t, 0)
+	gas := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	gas.Invoke(t, true, "transfer", e.Validator.ScriptHash(), acc.ScriptHash(), 42, nil)
+
+	srKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	cfg := config.LightClient{StateValidators: []string{hex.EncodeToString(srKey.PublicKey().Bytes())}}
+
+	_, err = lightclient.New(bc.GetConfig().ProtocolConfiguration, config.LightClient{}, []lightclient.RPC{&chainRPC{bc: bc}}, storage.NewMemoryStore(), zaptest.NewLogger(t))
+	require.Error(t, err)
+
+	t.Run("unsigned", func(t *testing.T) {
+		c := newClient(t, bc, cfg, storage.NewMemoryStore(), &chainRPC{bc: bc})
+		err := c.Sync(context.Background())
+		require.ErrorIs(t, err, lightclient.ErrInvalidWitness)
+		require.NotErrorIs(t, err, lightclient.ErrStateValidatorsMismatch)
+		require.Equal(t, bc.BlockHeight(), c.HeaderHeight())
+		require.Nil(t, c.StateRoot())
+	})
+	t.Run("wrong signer", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		c := newClient(t, bc, cfg, storage.NewMemoryStore(), &chainRPC{bc: bc, srKey: other})
+		err = c.Sync(context.Background())
+		require.ErrorIs(t, err, lightclient.ErrInvalidWitness)
+		require.ErrorIs(t, err, lightclient.ErrStateValidatorsMismatch)
+		require.ErrorContains(t, err, "StateValidators")
+		require.Nil(t, c.StateRoot())
+	})
+	t.Run("good", func(t *testing.T) {
+		store := storage.NewMemoryStore()
+		c := newClient(t, bc, cfg, store, &chainRPC{bc: bc}, &chainRPC{bc: bc, srKey: srKey})
+		require.NoError(t, c.Sync(context.Background()))
+		require.Equal(t, bc.BlockHeight(), c.StateRoot().Index)
+
+		b, err := c.GetNEP17Balance(gas.Hash, acc.ScriptHash())
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(42), b)
+
+		restored := newClient(t, bc, cfg, store, &chainRPC{bc: bc})
+		require.Equal(t, c.StateRoot().Index, restored.StateRoot().Index)
+		require.Equal(t, c.StateRoot().Root, restored.StateRoot().Root)
+	})
+}
+
+func TestNew_FullNodeDB(t *testing.T) {
+	bc, _ := chain.NewSingle(t)
+	store := storage.NewMemoryStore()
+	require.NoError(t, store.PutChangeSet(map[string][]byte{string([]byte{byte(storage.SYSVersion)}): {1}}, nil))
+	_, err := lightclient.New(bc.GetConfig().ProtocolConfiguration, config.LightClient{}, []lightclient.RPC{&chainRPC{bc: bc}}, store, zaptest.NewLogger(t))
+	require.Error(t, err)
+}
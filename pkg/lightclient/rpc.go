@@ -0,0 +1,49 @@
+package lightclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+// endpoint is an RPC client that is initialized lazily, so that endpoints
+// unavailable on startup can still be used later.
+type endpoint struct {
+	*rpcclient.Client
+
+	initLock sync.Mutex
+	initDone bool
+}
+
+// Dial creates RPC clients for the endpoints specified in the configuration.
+// The clients are initialized on the first header request.
+func Dial(ctx context.Context, cfg config.LightClient) ([]RPC, error) {
+	var res = make([]RPC, 0, len(cfg.Endpoints))
+	for _, addr := range cfg.Endpoints {
+		c, err := rpcclient.New(ctx, addr, rpcclient.Options{RequestTimeout: cfg.RequestTimeout})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RPC client for %s: %w", addr, err)
+		}
+		res = append(res, &endpoint{Client: c})
+	}
+	return res, nil
+}
+
+// GetBlockHeaderByIndex implements the RPC interface, it initializes the
+// client if needed.
+func (e *endpoint) GetBlockHeaderByIndex(index uint32) (*block.Header, error) {
+	e.initLock.Lock()
+	if !e.initDone {
+		if err := e.Init(); err != nil {
+			e.initLock.Unlock()
+			return nil, err
+		}
+		e.initDone = true
+	}
+	e.initLock.Unlock()
+	return e.Client.GetBlockHeaderByIndex(index)
+}
@@ -0,0 +1,92 @@
+package lightclient
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Various header verification errors.
+var (
+	ErrHdrIndexMismatch    = errors.New("previous header index doesn't match")
+	ErrHdrHashMismatch     = errors.New("previous header hash doesn't match")
+	ErrHdrInvalidTimestamp = errors.New("block is not newer than the previous one")
+	ErrInvalidWitness      = errors.New("invalid witness")
+)
+
+// verifyHeader checks that curr is a valid successor of the trusted prev header.
+func verifyHeader(net netmode.Magic, curr, prev *block.Header) error {
+	if prev.Index+1 != curr.Index {
+		return ErrHdrIndexMismatch
+	}
+	if prev.Hash() != curr.PrevHash {
+		return ErrHdrHashMismatch
+	}
+	if prev.Timestamp >= curr.Timestamp {
+		return ErrHdrInvalidTimestamp
+	}
+	return verifyWitness(net, prev.NextConsensus, curr, &curr.Script)
+}
+
+// verifyWitness checks that w is a valid witness of hh for the account h. Only
+// standard signature and multisignature verification scripts are supported, they
+// are checked natively without running the VM.
+func verifyWitness(net netmode.Magic, h util.Uint160, hh hash.Hashable, w *transaction.Witness) error {
+	if w.ScriptHash() != h {
+		return fmt.Errorf("%w: verification script hash mismatch", ErrInvalidWitness)
+	}
+	sigs, err := parseSignatures(w.InvocationScript)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidWitness, err)
+	}
+	var (
+		m    = 1
+		pubs [][]byte
+	)
+	if pub, ok := vm.ParseSignatureContract(w.VerificationScript); ok {
+		pubs = [][]byte{pub}
+	} else if m, pubs, ok = vm.ParseMultiSigContract(w.VerificationScript); !ok {
+		return fmt.Errorf("%w: unsupported verification script", ErrInvalidWitness)
+	}
+	if len(sigs) != m {
+		return fmt.Errorf("%w: expected %d signatures, got %d", ErrInvalidWitness, m, len(sigs))
+	}
+	var digest = hash.NetSha256(uint32(net), hh)
+	// Signatures must follow keys order, the same way CheckMultisig handles them.
+	var k int
+	for _, sig := range sigs {
+		for ; k < len(pubs); k++ {
+			pub, err := keys.NewPublicKeyFromBytes(pubs[k], elliptic.P256())
+			if err == nil && pub.Verify(sig, digest[:]) {
+				break
+			}
+		}
+		if k == len(pubs) {
+			return fmt.Errorf("%w: signature check failed", ErrInvalidWitness)
+		}
+		k++
+	}
+	return nil
+}
+
+// parseSignatures returns signatures pushed by the standard invocation script.
+func parseSignatures(script []byte) ([][]byte, error) {
+	var sigs [][]byte
+	for len(script) > 0 {
+		if len(script) < 2+keys.SignatureLen || script[0] != byte(opcode.PUSHDATA1) || script[1] != keys.SignatureLen {
+			return nil, errors.New("invalid invocation script")
+		}
+		sigs = append(sigs, script[2:2+keys.SignatureLen])
+		script = script[2+keys.SignatureLen:]
+	}
+	return sigs, nil
+}
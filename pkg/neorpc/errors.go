@@ -136,6 +136,15 @@ const (
 	ErrInvalidProofCode = -607
 	// ErrExecutionFailedCode is returned from a call made a VM execution, but it has failed.
 	ErrExecutionFailedCode = -608
+	// ErrUnauthorizedCode is returned if API key is required by the server, but
+	// it's missing or invalid. NeoGo-specific.
+	ErrUnauthorizedCode = -609
+	// ErrRateLimitExceededCode is returned if the client has exceeded its request
+	// quota. NeoGo-specific.
+	ErrRateLimitExceededCode = -610
+	// ErrDevModeDisabledCode is returned for developer chain methods if the node
+	// is not running in developer mode. NeoGo-specific.
+	ErrDevModeDisabledCode = -611
 )
 
 var (
@@ -268,6 +277,15 @@ var (
 	// ErrExecutionFailed represents an error with code [ErrExecutionFailedCode].
 	// Call made a VM execution, but it has failed.
 	ErrExecutionFailed = NewErrorWithCode(ErrExecutionFailedCode, "Execution failed")
+	// ErrUnauthorized represents an error with code [ErrUnauthorizedCode].
+	// API key is required by the server, but it's missing or invalid.
+	ErrUnauthorized = NewErrorWithCode(ErrUnauthorizedCode, "Unauthorized")
+	// ErrRateLimitExceeded represents an error with code [ErrRateLimitExceededCode].
+	// The client has exceeded its request quota.
+	ErrRateLimitExceeded = NewErrorWithCode(ErrRateLimitExceededCode, "Rate limit exceeded")
+	// ErrDevModeDisabled represents an error with code [ErrDevModeDisabledCode].
+	// The node is not running in developer mode.
+	ErrDevModeDisabled = NewErrorWithCode(ErrDevModeDisabledCode, "Developer mode is not enabled")
 )
 
 // NewError is an Error constructor that takes Error contents from its parameters.
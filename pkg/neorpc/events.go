@@ -22,6 +22,8 @@ const (
 	ExecutionEventID
 	// NotaryRequestEventID is used for the `notary_request_event` event.
 	NotaryRequestEventID
+	// StorageChangeEventID is used for the `storage_changed` event.
+	StorageChangeEventID
 	// MissedEventID notifies user of missed events.
 	MissedEventID EventID = 255
 )
@@ -39,6 +41,8 @@ func (e EventID) String() string {
 		return "transaction_executed"
 	case NotaryRequestEventID:
 		return "notary_request_event"
+	case StorageChangeEventID:
+		return "storage_changed"
 	case MissedEventID:
 		return "event_missed"
 	default:
@@ -59,6 +63,8 @@ func GetEventIDFromString(s string) (EventID, error) {
 		return ExecutionEventID, nil
 	case "notary_request_event":
 		return NotaryRequestEventID, nil
+	case "storage_changed":
+		return StorageChangeEventID, nil
 	case "event_missed":
 		return MissedEventID, nil
 	default:
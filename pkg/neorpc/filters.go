@@ -2,6 +2,7 @@ package neorpc
 
 import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 )
 
 type (
@@ -38,6 +39,14 @@ type (
 		State     *string       `json:"state,omitempty"`
 		Container *util.Uint256 `json:"container,omitempty"`
 	}
+	// StorageFilter is a wrapper structure used for contract storage change
+	// events. It allows to choose changes of the specified contract storage
+	// and/or changes of the items with keys starting with the specified
+	// prefix. nil value treated as missing filter.
+	StorageFilter struct {
+		Contract *util.Uint160 `json:"contract,omitempty"`
+		Prefix   []byte        `json:"prefix,omitempty"`
+	}
 )
 
 // Copy creates a deep copy of the BlockFilter. It handles nil BlockFilter correctly.
@@ -111,3 +120,19 @@ func (f *ExecutionFilter) Copy() *ExecutionFilter {
 	}
 	return res
 }
+
+// Copy creates a deep copy of the StorageFilter. It handles nil StorageFilter correctly.
+func (f *StorageFilter) Copy() *StorageFilter {
+	if f == nil {
+		return nil
+	}
+	var res = new(StorageFilter)
+	if f.Contract != nil {
+		res.Contract = new(util.Uint160)
+		*res.Contract = *f.Contract
+	}
+	if f.Prefix != nil {
+		res.Prefix = slice.Copy(f.Prefix)
+	}
+	return res
+}
@@ -114,3 +114,27 @@ func TestExecutionFilterCopy(t *testing.T) {
 	*bf.Container = util.Uint256{3, 2, 1}
 	require.NotEqual(t, bf, tf)
 }
+
+func TestStorageFilterCopy(t *testing.T) {
+	var bf, tf *StorageFilter
+
+	require.Nil(t, bf.Copy())
+
+	bf = new(StorageFilter)
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+
+	bf.Contract = &util.Uint160{1, 2, 3}
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	*bf.Contract = util.Uint160{3, 2, 1}
+	require.NotEqual(t, bf, tf)
+
+	bf.Prefix = []byte{1, 2, 3}
+
+	tf = bf.Copy()
+	require.Equal(t, bf, tf)
+	bf.Prefix[0] = 42
+	require.NotEqual(t, bf, tf)
+}
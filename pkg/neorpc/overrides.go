@@ -0,0 +1,47 @@
+package neorpc
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+type (
+	// StateOverrides is a set of chain state changes that are applied before
+	// test invocation (NeoGo extension to invokefunction and invokescript
+	// calls). They're applied to the temporary state used by the invocation
+	// only (contracts first, then storage items, then balances), the real
+	// chain state is never affected.
+	StateOverrides struct {
+		Contracts []ContractOverride `json:"contracts,omitempty"`
+		Storage   []StorageOverride  `json:"storage,omitempty"`
+		Balances  []BalanceOverride  `json:"balances,omitempty"`
+	}
+
+	// ContractOverride replaces the contract with the given hash. NEF is a
+	// serialized NEF file and either it or Manifest can be omitted for
+	// already deployed contracts to keep the current value. If there is no
+	// contract with the given hash, a new one is created (both NEF and
+	// manifest are required then). Native contracts can't be overridden.
+	ContractOverride struct {
+		Hash     util.Uint160       `json:"hash"`
+		NEF      []byte             `json:"nef,omitempty"`
+		Manifest *manifest.Manifest `json:"manifest,omitempty"`
+	}
+
+	// StorageOverride sets the value of the contract storage item, nil
+	// Value deletes it.
+	StorageOverride struct {
+		Contract util.Uint160 `json:"contract"`
+		Key      []byte       `json:"key"`
+		Value    []byte       `json:"value"`
+	}
+
+	// BalanceOverride sets the balance of the account for NEO or GAS native
+	// token. Amount is an integer in token's base units (like for NEP-17
+	// balances).
+	BalanceOverride struct {
+		Token   util.Uint160 `json:"token"`
+		Account util.Uint160 `json:"account"`
+		Amount  string       `json:"amount"`
+	}
+)
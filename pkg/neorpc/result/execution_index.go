@@ -0,0 +1,90 @@
+package result
+
+import (
+	"encoding/json"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// FindNotifications is a result of the findnotifications RPC call.
+type FindNotifications struct {
+	Notifications []FoundNotification `json:"notifications"`
+	// Truncated is set if there are more notifications matching the
+	// request (they can be retrieved using the next page).
+	Truncated bool `json:"truncated"`
+}
+
+// FoundNotification is a single notification returned by the findnotifications
+// RPC call.
+type FoundNotification struct {
+	// BlockIndex is the index of the block the notification was emitted in.
+	BlockIndex uint32
+	// Container is the hash of the script container (transaction or block).
+	Container util.Uint256
+	// Trigger is the type of the execution that emitted the notification.
+	Trigger trigger.Type
+	// NotifyIndex is the index of the notification in the execution.
+	NotifyIndex uint32
+	// Event is the notification itself.
+	Event state.NotificationEvent
+}
+
+// foundNotificationAux is an auxiliary struct for FoundNotification JSON
+// marshalling.
+type foundNotificationAux struct {
+	BlockIndex  uint32                  `json:"blockindex"`
+	Container   util.Uint256            `json:"container"`
+	Trigger     string                  `json:"trigger"`
+	NotifyIndex uint32                  `json:"notifyindex"`
+	Event       state.NotificationEvent `json:"event"`
+}
+
+// FindTransactions is a result of the findtransactions RPC call.
+type FindTransactions struct {
+	Address      string             `json:"address"`
+	Transactions []FoundTransaction `json:"transactions"`
+	// Truncated is set if there are more transactions matching the request
+	// (they can be retrieved using the next page).
+	Truncated bool `json:"truncated"`
+}
+
+// FoundTransaction is a single transaction returned by the findtransactions
+// RPC call.
+type FoundTransaction struct {
+	Hash       util.Uint256 `json:"hash"`
+	BlockIndex uint32       `json:"blockindex"`
+	// Sender is set if the account is the transaction sender (not just one
+	// of its signers).
+	Sender bool `json:"sender"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n FoundNotification) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&foundNotificationAux{
+		BlockIndex:  n.BlockIndex,
+		Container:   n.Container,
+		Trigger:     n.Trigger.String(),
+		NotifyIndex: n.NotifyIndex,
+		Event:       n.Event,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (n *FoundNotification) UnmarshalJSON(data []byte) error {
+	aux := new(foundNotificationAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	trig, err := trigger.FromString(aux.Trigger)
+	if err != nil {
+		return err
+	}
+	n.BlockIndex = aux.BlockIndex
+	n.Container = aux.Container
+	n.Trigger = trig
+	n.NotifyIndex = aux.NotifyIndex
+	n.Event = aux.Event
+	return nil
+}
@@ -30,6 +30,24 @@ type Invoke struct {
 type InvokeDiag struct {
 	Changes     []dboper.Operation  `json:"storagechanges"`
 	Invocations []*invocations.Tree `json:"invokedcontracts"`
+	GasProfile  *GasProfile         `json:"gasprofile,omitempty"`
+}
+
+// GasProfile is a flat gas profile of invocation, it's only returned by
+// servers with gas profiling enabled. Every entry contains its own (self)
+// cost, all lists are sorted by GAS spent in descending order.
+type GasProfile struct {
+	Opcodes  []GasProfileEntry `json:"opcodes"`
+	Syscalls []GasProfileEntry `json:"syscalls"`
+	Methods  []GasProfileEntry `json:"methods"`
+}
+
+// GasProfileEntry is a single gas profile entry for opcode, syscall or
+// contract method.
+type GasProfileEntry struct {
+	Name         string `json:"name"`
+	Instructions int64  `json:"instructions"`
+	Gas          int64  `json:"gas,string"`
 }
 
 type invokeAux struct {
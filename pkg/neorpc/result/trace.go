@@ -0,0 +1,38 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/invocations"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// TransactionTrace is the result of tracetransaction call, it describes the
+// execution of persisted transaction repeated against the state it was
+// originally executed with.
+type TransactionTrace struct {
+	TxHash         util.Uint256  `json:"txid"`
+	BlockIndex     uint32        `json:"blockindex"`
+	VMState        vmstate.State `json:"vmstate"`
+	GasConsumed    int64         `json:"gasconsumed,string"`
+	FaultException string        `json:"exception,omitempty"`
+	// Trace contains executed instructions, it's omitted for
+	// neorpc.TraceDetailNone level. Full traces can be replayed by VM CLI.
+	Trace          *vm.Trace                 `json:"trace,omitempty"`
+	Invocations    []*invocations.Tree       `json:"invokedcontracts"`
+	StorageReads   []StorageAccess           `json:"storagereads"`
+	StorageChanges []StorageAccess           `json:"storagechanges"`
+	Notifications  []state.NotificationEvent `json:"notifications"`
+}
+
+// StorageAccess is a contract storage operation performed during the traced
+// execution.
+type StorageAccess struct {
+	// State is Read or NotFound for reads and Added, Changed or Deleted for
+	// changes.
+	State    string       `json:"state"`
+	Contract util.Uint160 `json:"contract"`
+	Key      []byte       `json:"key"`
+	Value    []byte       `json:"value,omitempty"`
+}
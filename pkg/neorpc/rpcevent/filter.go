@@ -1,6 +1,8 @@
 package rpcevent
 
 import (
+	"bytes"
+
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -83,6 +85,12 @@ func Matches(f Comparator, r Container) bool {
 			}
 		}
 		return senderOk && signerOK
+	case neorpc.StorageChangeEventID:
+		filt := filter.(neorpc.StorageFilter)
+		change := r.EventPayload().(*state.StorageChangeEvent)
+		hashOk := filt.Contract == nil || change.Contract.Equals(*filt.Contract)
+		prefixOk := bytes.HasPrefix(change.Key, filt.Prefix)
+		return hashOk && prefixOk
 	}
 	return false
 }
@@ -82,6 +82,10 @@ func TestMatches(t *testing.T) {
 			},
 		},
 	}
+	stContainer := testContainer{
+		id:  neorpc.StorageChangeEventID,
+		pld: &state.StorageChangeEvent{Contract: contract, Key: []byte{1, 2, 3}},
+	}
 	missedContainer := testContainer{
 		id: neorpc.MissedEventID,
 	}
@@ -277,6 +281,39 @@ func TestMatches(t *testing.T) {
 			container: ntrContainer,
 			expected:  true,
 		},
+		{
+			name:       "storage change, no filter",
+			comparator: testComparator{id: neorpc.StorageChangeEventID},
+			container:  stContainer,
+			expected:   true,
+		},
+		{
+			name: "storage change, contract mismatch",
+			comparator: testComparator{
+				id:     neorpc.StorageChangeEventID,
+				filter: neorpc.StorageFilter{Contract: &badUint160},
+			},
+			container: stContainer,
+			expected:  false,
+		},
+		{
+			name: "storage change, prefix mismatch",
+			comparator: testComparator{
+				id:     neorpc.StorageChangeEventID,
+				filter: neorpc.StorageFilter{Prefix: []byte{1, 3}},
+			},
+			container: stContainer,
+			expected:  false,
+		},
+		{
+			name: "storage change, filter match",
+			comparator: testComparator{
+				id:     neorpc.StorageChangeEventID,
+				filter: neorpc.StorageFilter{Contract: &contract, Prefix: []byte{1, 2}},
+			},
+			container: stContainer,
+			expected:  true,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
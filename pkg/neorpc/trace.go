@@ -0,0 +1,24 @@
+package neorpc
+
+// Trace detail levels for tracetransaction call.
+const (
+	// TraceDetailNone omits instructions from the trace.
+	TraceDetailNone = "none"
+	// TraceDetailOpcodes includes executed instructions with GAS consumed
+	// after every one of them, it's the default level.
+	TraceDetailOpcodes = "opcodes"
+	// TraceDetailFull additionally includes evaluation stack and slot
+	// changes made by every instruction.
+	TraceDetailFull = "full"
+)
+
+// TraceOptions are optional parameters of tracetransaction call (NeoGo
+// extension).
+type TraceOptions struct {
+	// Detail is one of TraceDetail* levels, TraceDetailOpcodes is used if
+	// it's empty.
+	Detail string `json:"detail,omitempty"`
+	// MaxSteps limits the number of instructions returned, the server limit
+	// is used if it's 0 or exceeds the server one.
+	MaxSteps int `json:"maxsteps,omitempty"`
+}
@@ -34,6 +34,9 @@ type Executor struct {
 	Committee     Signer
 	CommitteeHash util.Uint160
 	Contracts     map[string]*Contract
+
+	coverage *Coverage
+	profiler *Profiler
 }
 
 // NewExecutor creates a new executor instance from the provided blockchain and committee.
@@ -57,6 +60,15 @@ func (e *Executor) TopBlock(t testing.TB) *block.Block {
 	return b
 }
 
+// EnableCoverage enables coverage collection for the contracts executed by
+// the chain. Contracts deployed via DeployContract* methods are registered in
+// the collector automatically, others can be added with Coverage.AddContract.
+// It should be called before any contract invocations.
+func (e *Executor) EnableCoverage(c *Coverage) {
+	e.coverage = c
+	e.Chain.SetVMExecHook(c.onExec)
+}
+
 // NativeHash returns a native contract hash by the name.
 func (e *Executor) NativeHash(t testing.TB, name string) util.Uint160 {
 	h, err := e.Chain.GetNativeContractScriptHash(name)
@@ -146,6 +158,12 @@ func (e *Executor) DeployContract(t testing.TB, c *Contract, data any) util.Uint
 // data is an optional argument to `_deploy`.
 // It returns the hash of the deploy transaction.
 func (e *Executor) DeployContractBy(t testing.TB, signer Signer, c *Contract, data any) util.Uint256 {
+	if e.coverage != nil {
+		e.coverage.AddContract(c)
+	}
+	if e.profiler != nil {
+		e.profiler.AddContract(c)
+	}
 	tx := NewDeployTxBy(t, e.Chain, signer, c, data)
 	e.AddNewBlock(t, tx)
 	e.CheckHalt(t, tx.Hash())
@@ -9,6 +9,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/fork"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
@@ -165,6 +166,23 @@ func NewSingleWithCustomConfigAndStore(t testing.TB, f func(cfg *config.Blockcha
 	return bc, neotest.NewMultiSigner(committeeAcc)
 }
 
+// NewFork creates a new blockchain instance forked from the remote chain at the
+// given height (see fork.Store) and setups cleanup functions. The configuration
+// must match the remote chain one, but block verification is always disabled,
+// so blocks signed by the Signer returned (the same as the NewSingle one) are
+// accepted by the chain. Transactions on behalf of the remote chain accounts
+// can be made with neotest.NewImpersonatedSigner.
+func NewFork(t testing.TB, cfg config.Blockchain, remote fork.RPC, height uint32) (*core.Blockchain, neotest.Signer) {
+	cfg.SkipBlockVerification = true
+	st, err := fork.New(remote, cfg, height)
+	require.NoError(t, err)
+	bc, err := core.NewBlockchain(st, cfg, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	return bc, neotest.NewMultiSigner(committeeAcc)
+}
+
 // NewMulti creates a new blockchain instance with four validators and six
 // committee members. Otherwise, it does not differ much from NewSingle. The
 // second value returned contains the validators Signer, the third -- the committee one.
@@ -0,0 +1,158 @@
+package neotest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Coverage is a code coverage collector for contracts compiled from Go
+// sources. It records instructions executed by the VM and maps them to the
+// contract source code via sequence points from the contract debug info. The
+// result can be written as a standard Go cover profile, so that `go tool cover`
+// can be used to analyze it.
+//
+// Usually a single Coverage is created for the whole test package and enabled
+// for every Executor via Executor.EnableCoverage, the profile is then written
+// from TestMain after all tests are done. Coverage is safe for concurrent use.
+type Coverage struct {
+	lock sync.Mutex
+	// scripts maps contract hash to the coverage data of its current script.
+	scripts map[util.Uint160]*scriptCoverage
+	// all contains coverage data for all scripts registered (including
+	// the ones replaced by contract updates).
+	all []*scriptCoverage
+}
+
+// scriptCoverage is coverage data for a single contract script.
+type scriptCoverage struct {
+	debugInfo *compiler.DebugInfo
+	// offsets contains all executed instruction offsets.
+	offsets map[int]struct{}
+}
+
+// coverBlock is a single Go cover profile block.
+type coverBlock struct {
+	file      string
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+}
+
+// NewCoverage returns a new empty coverage collector.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		scripts: make(map[util.Uint160]*scriptCoverage),
+	}
+}
+
+// AddContract registers the contract in the collector, only registered
+// contracts are tracked. Contracts without debug info are ignored. If there is
+// a contract with the same hash registered already, it's replaced (which is
+// useful for contract updates), but its data is still present in the profile.
+func (c *Coverage) AddContract(ctr *Contract) {
+	if ctr.DebugInfo == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if sc, ok := c.scripts[ctr.Hash]; ok && sc.debugInfo == ctr.DebugInfo {
+		return
+	}
+	sc := &scriptCoverage{
+		debugInfo: ctr.DebugInfo,
+		offsets:   make(map[int]struct{}),
+	}
+	c.scripts[ctr.Hash] = sc
+	c.all = append(c.all, sc)
+}
+
+// onExec is a vm.OnExecHook recording executed instructions.
+func (c *Coverage) onExec(scriptHash util.Uint160, offset int, _ opcode.Opcode) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if sc, ok := c.scripts[scriptHash]; ok {
+		sc.offsets[offset] = struct{}{}
+	}
+}
+
+// WriteProfile writes collected data in the Go cover profile format (using
+// "set" mode). Every sequence point is treated as a single statement which is
+// covered if the instruction it points to was executed.
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	c.lock.Lock()
+	blocks := make(map[coverBlock]bool)
+	for _, sc := range c.all {
+		sc.fillBlocks(blocks)
+	}
+	c.lock.Unlock()
+
+	keys := make([]coverBlock, 0, len(blocks))
+	for b := range blocks {
+		keys = append(keys, b)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		if a.startLine != b.startLine {
+			return a.startLine < b.startLine
+		}
+		if a.startCol != b.startCol {
+			return a.startCol < b.startCol
+		}
+		if a.endLine != b.endLine {
+			return a.endLine < b.endLine
+		}
+		return a.endCol < b.endCol
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "mode: set")
+	for _, b := range keys {
+		var count int
+		if blocks[b] {
+			count = 1
+		}
+		fmt.Fprintf(bw, "%s:%d.%d,%d.%d 1 %d\n", b.file, b.startLine, b.startCol, b.endLine, b.endCol, count)
+	}
+	return bw.Flush()
+}
+
+// fillBlocks adds all sequence points of the script to blocks marking the ones
+// that were executed.
+func (sc *scriptCoverage) fillBlocks(blocks map[coverBlock]bool) {
+	docs := make([]string, len(sc.debugInfo.Documents))
+	for i, d := range sc.debugInfo.Documents {
+		// Relative paths are treated as import paths by `go tool cover`.
+		if abs, err := filepath.Abs(d); err == nil {
+			d = abs
+		}
+		docs[i] = d
+	}
+	for _, m := range sc.debugInfo.Methods {
+		for _, sp := range m.SeqPoints {
+			if sp.Document < 0 || sp.Document >= len(docs) {
+				continue
+			}
+			b := coverBlock{
+				file:      docs[sp.Document],
+				startLine: sp.StartLine,
+				startCol:  sp.StartCol,
+				endLine:   sp.EndLine,
+				endCol:    sp.EndCol,
+			}
+			_, covered := sc.offsets[sp.Opcode]
+			blocks[b] = blocks[b] || covered
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package neotest_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	cov := neotest.NewCoverage()
+	e.EnableCoverage(cov)
+
+	src := `package foo
+func Sign(a int) int {
+	if a < 0 {
+		return -1
+	}
+	return 1
+}
+func Unused() int {
+	return 42
+}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Foo"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+	c.Invoke(t, 1, "sign", 5)
+	_, err := c.TestInvoke(t, "sign", 3)
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, cov.WriteProfile(buf))
+
+	file, err := filepath.Abs("contract.go")
+	require.NoError(t, err)
+	expected := []string{
+		"mode: set",
+		file + ":4.3,4.12 1 0", // return -1
+		file + ":6.2,6.10 1 1", // return 1
+		file + ":9.2,9.11 1 0", // return 42
+	}
+	require.Equal(t, strings.Join(expected, "\n")+"\n", buf.String())
+}
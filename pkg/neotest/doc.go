@@ -15,5 +15,32 @@ Usually it's used like this:
 Higher-order methods provided in Executor and ContractInvoker hide the details
 of transaction creation for the most part, but there are lower-level methods as
 well that can be used for specific tasks.
+
+Code coverage can be collected for contracts compiled with Compile* functions.
+A Coverage collector is created with NewCoverage and enabled for the Executor
+via EnableCoverage, then WriteProfile outputs the data in the standard Go cover
+profile format, so `go tool cover -html` can be used to inspect it:
+
+	var cov = neotest.NewCoverage()
+
+	func TestMain(m *testing.M) {
+		code := m.Run()
+		f, _ := os.Create("contract.cover")
+		_ = cov.WriteProfile(f)
+		_ = f.Close()
+		os.Exit(code)
+	}
+
+Gas consumption can be profiled in a similar way with a Profiler created by
+NewProfiler and enabled via EnableProfiler. It attributes GAS and instruction
+counts to opcodes, syscalls, native contract methods and Go functions of the
+contracts compiled with Compile* functions. Report returns a flat summary and
+WritePprof outputs the data in pprof format, so `go tool pprof` can be used to
+analyze it.
+
+Tests can also be run against the state of some real network with a chain
+created by chain.NewFork, it fetches the data from the remote RPC node on
+demand. NewImpersonatedSigner can then be used to send transactions on behalf
+of remote accounts since forked chains don't verify witnesses.
 */
 package neotest
@@ -0,0 +1,105 @@
+package neotest
+
+import (
+	"io"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
+)
+
+// Profiler is a gas profiler for contract invocations. It attributes GAS and
+// instruction counts to opcodes, syscalls, native contract methods and (for
+// contracts compiled from Go sources) Go functions. Results can be obtained as
+// a Report or written as a pprof profile, so that `go tool pprof` can be used
+// to analyze them.
+//
+// Usually a single Profiler is created for the whole test package and enabled
+// for every Executor via Executor.EnableProfiler. Profiler is safe for
+// concurrent use.
+type Profiler struct {
+	*vm.Profiler
+
+	lock sync.Mutex
+	// debugInfo maps contract hash to its debug info.
+	debugInfo map[util.Uint160]*compiler.DebugInfo
+	// chains contains all chains the profiler is enabled for, they're used
+	// to get manifests for contracts without debug info.
+	chains []*core.Blockchain
+}
+
+// NewProfiler returns a new empty profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		Profiler:  vm.NewProfiler(),
+		debugInfo: make(map[util.Uint160]*compiler.DebugInfo),
+	}
+}
+
+// EnableProfiler enables gas profiling for all invocations performed by the
+// chain. Contracts deployed via DeployContract* methods are registered in the
+// profiler automatically, others can be added with Profiler.AddContract. It
+// should be called before any contract invocations.
+func (e *Executor) EnableProfiler(p *Profiler) {
+	e.profiler = p
+	p.lock.Lock()
+	p.chains = append(p.chains, e.Chain)
+	p.lock.Unlock()
+	e.Chain.SetVMProfiler(p.Profiler)
+}
+
+// AddContract registers the contract debug info in the profiler, so that
+// GAS can be attributed to Go functions and source lines. Contracts without
+// debug info are resolved using their manifests.
+func (p *Profiler) AddContract(ctr *Contract) {
+	if ctr.DebugInfo == nil {
+		return
+	}
+	p.lock.Lock()
+	p.debugInfo[ctr.Hash] = ctr.DebugInfo
+	p.lock.Unlock()
+}
+
+// Report returns a flat report for the data collected so far.
+func (p *Profiler) Report() *profile.Report {
+	samples := p.Samples()
+	return profile.NewReport(samples, p.resolver(samples))
+}
+
+// WritePprof writes the data collected so far as a gzipped pprof profile.
+func (p *Profiler) WritePprof(w io.Writer) error {
+	samples := p.Samples()
+	return profile.WritePprof(w, samples, p.resolver(samples))
+}
+
+// resolver returns a resolver for all scripts used by the given samples.
+func (p *Profiler) resolver(samples []vm.ProfileSample) profile.Resolver {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var (
+		res  profile.Resolvers
+		seen = make(map[util.Uint160]bool)
+	)
+	for _, s := range samples {
+		for _, f := range s.Stack {
+			if seen[f.ScriptHash] {
+				continue
+			}
+			seen[f.ScriptHash] = true
+			if di, ok := p.debugInfo[f.ScriptHash]; ok {
+				res = append(res, profile.NewDebugInfoResolver(f.ScriptHash, di))
+			}
+			for _, bc := range p.chains {
+				if cs := bc.GetContractState(f.ScriptHash); cs != nil {
+					res = append(res, profile.NewManifestResolver(f.ScriptHash, &cs.Manifest))
+					break
+				}
+			}
+		}
+	}
+	return res
+}
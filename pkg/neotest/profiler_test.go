@@ -0,0 +1,72 @@
+package neotest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	p := neotest.NewProfiler()
+	e.EnableProfiler(p)
+
+	src := `package foo
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+)
+func Balance() int {
+	return gas.BalanceOf(interop.Hash160(gas.Hash))
+}
+func Loop(n int) int {
+	var s int
+	for i := 0; i < n; i++ {
+		s += i
+	}
+	return s
+}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{
+		Name:        "Foo",
+		Permissions: []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	})
+	e.DeployContract(t, ctr, nil)
+	p.Reset()
+
+	c := e.CommitteeInvoker(ctr.Hash)
+	_, err := c.TestInvoke(t, "loop", 100)
+	require.NoError(t, err)
+	_, err = c.TestInvoke(t, "balance")
+	require.NoError(t, err)
+
+	rep := p.Report()
+	require.True(t, rep.Gas > 0)
+	names := make(map[string]profile.Entry)
+	for _, f := range rep.Functions {
+		names[f.Name] = f
+	}
+	require.Contains(t, names, "foo.Loop")
+	require.Contains(t, names, "foo.Balance")
+	require.Contains(t, names, "GasToken.balanceOf")
+	require.True(t, names["foo.Loop"].Instructions > 100)
+
+	var syscalls []string
+	for _, s := range rep.Syscalls {
+		syscalls = append(syscalls, s.Name)
+	}
+	require.Contains(t, syscalls, interopnames.SystemContractCall)
+	require.Contains(t, syscalls, interopnames.SystemContractCallNative)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, p.WritePprof(buf))
+	require.True(t, buf.Len() > 0)
+}
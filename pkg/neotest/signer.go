@@ -163,6 +163,40 @@ func (m multiSigner) Single(n int) SingleSigner {
 	return NewSingleSigner(wallet.NewAccountFromPrivateKey(m.accounts[n].PrivateKey()))
 }
 
+// impersonatedSigner represents a signer for an account without keys.
+type impersonatedSigner util.Uint160
+
+// NewImpersonatedSigner returns a signer for the given account that doesn't
+// have any keys and produces empty witnesses. Transactions signed by it are
+// only accepted by chains that don't verify blocks (see
+// config.Ledger.SkipBlockVerification), like the ones created by
+// chain.NewFork, but it allows to act on behalf of any account there. Network
+// fee for such signers is not calculated.
+func NewImpersonatedSigner(h util.Uint160) Signer {
+	return impersonatedSigner(h)
+}
+
+// Script implements Signer interface.
+func (s impersonatedSigner) Script() []byte {
+	return nil
+}
+
+// ScriptHash implements Signer interface.
+func (s impersonatedSigner) ScriptHash() util.Uint160 {
+	return util.Uint160(s)
+}
+
+// SignHashable implements Signer interface.
+func (s impersonatedSigner) SignHashable(uint32, hash.Hashable) []byte {
+	return nil
+}
+
+// SignTx implements Signer interface.
+func (s impersonatedSigner) SignTx(_ netmode.Magic, tx *transaction.Transaction) error {
+	tx.Scripts = append(tx.Scripts, transaction.Witness{})
+	return nil
+}
+
 func checkMultiSigner(t testing.TB, s Signer) {
 	ms, ok := s.(multiSigner)
 	require.True(t, ok, "expected to be a multi-signer")
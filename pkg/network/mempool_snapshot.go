@@ -0,0 +1,135 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"go.uber.org/zap"
+)
+
+// memPoolSnapshotVersion is the version of the memory pool snapshot file
+// format.
+const memPoolSnapshotVersion = 0
+
+// memPoolSnapshot contains pooled items saved to the file.
+type memPoolSnapshot struct {
+	Transactions   []*transaction.Transaction
+	NotaryRequests []*payload.P2PNotaryRequest
+}
+
+// EncodeBinary implements the io.Serializable interface.
+func (m *memPoolSnapshot) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(memPoolSnapshotVersion)
+	w.WriteArray(m.Transactions)
+	w.WriteArray(m.NotaryRequests)
+}
+
+// DecodeBinary implements the io.Serializable interface.
+func (m *memPoolSnapshot) DecodeBinary(r *io.BinReader) {
+	if v := r.ReadB(); r.Err == nil && v != memPoolSnapshotVersion {
+		r.Err = fmt.Errorf("unsupported snapshot version %d", v)
+		return
+	}
+	r.ReadArray(&m.Transactions)
+	r.ReadArray(&m.NotaryRequests)
+}
+
+// saveMemPools writes verified memory pool transactions and P2P notary
+// requests to the snapshot file.
+func (s *Server) saveMemPools() error {
+	var snap = memPoolSnapshot{
+		Transactions: s.mempool.GetVerifiedTransactions(),
+	}
+	if s.chain.P2PSigExtensionsEnabled() {
+		for _, fb := range s.notaryRequestPool.GetVerifiedTransactions() {
+			if data, ok := s.notaryRequestPool.TryGetData(fb.Hash()); ok {
+				snap.NotaryRequests = append(snap.NotaryRequests, data.(*payload.P2PNotaryRequest))
+			}
+		}
+	}
+	w := io.NewBufBinWriter()
+	snap.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	s.memPoolSnapshotLock.Lock()
+	defer s.memPoolSnapshotLock.Unlock()
+	// Write to a temporary file first to not corrupt the previous snapshot.
+	tmp := s.MemPoolPersistence.FilePath + ".tmp"
+	if err := os.WriteFile(tmp, w.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.MemPoolPersistence.FilePath); err != nil {
+		return err
+	}
+	s.log.Debug("memory pool snapshot saved",
+		zap.Int("transactions", len(snap.Transactions)),
+		zap.Int("notary requests", len(snap.NotaryRequests)))
+	return nil
+}
+
+// loadMemPools reads the memory pool snapshot file and adds its transactions
+// and P2P notary requests to the pools, the ones that can't pass verification
+// anymore are dropped.
+func (s *Server) loadMemPools() error {
+	data, err := os.ReadFile(s.MemPoolPersistence.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var snap memPoolSnapshot
+	r := io.NewBinReaderFromBuf(data)
+	snap.DecodeBinary(r)
+	if r.Err != nil {
+		return fmt.Errorf("invalid snapshot: %w", r.Err)
+	}
+	var txs, reqs int
+	for _, tx := range snap.Transactions {
+		if err := s.chain.PoolTx(tx); err != nil {
+			s.log.Debug("dropping transaction from memory pool snapshot",
+				zap.Stringer("hash", tx.Hash()), zap.Error(err))
+			continue
+		}
+		txs++
+	}
+	if s.chain.P2PSigExtensionsEnabled() {
+		for _, r := range snap.NotaryRequests {
+			if err := s.verifyAndPoolNotaryRequest(r); err != nil {
+				s.log.Debug("dropping P2PNotaryRequest from memory pool snapshot",
+					zap.Stringer("fallback hash", r.FallbackTransaction.Hash()), zap.Error(err))
+				continue
+			}
+			reqs++
+		}
+	}
+	s.log.Info("memory pool snapshot loaded",
+		zap.Int("transactions", txs),
+		zap.Int("dropped transactions", len(snap.Transactions)-txs),
+		zap.Int("notary requests", reqs),
+		zap.Int("dropped notary requests", len(snap.NotaryRequests)-reqs))
+	return nil
+}
+
+// memPoolSnapshotLoop saves memory pool snapshots periodically until the
+// server is stopped.
+func (s *Server) memPoolSnapshotLoop() {
+	t := time.NewTicker(s.MemPoolPersistence.SaveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-t.C:
+			if err := s.saveMemPools(); err != nil {
+				s.log.Warn("failed to save memory pool snapshot", zap.Error(err))
+			}
+		}
+	}
+}
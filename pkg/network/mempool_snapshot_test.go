@@ -0,0 +1,126 @@
+package network
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newMemPoolSnapshotTestServer(t *testing.T, path string) (*Server, *fakechain.FakeChain) {
+	bc := fakechain.NewFakeChain()
+	bc.UtilityTokenBalance = big.NewInt(1_0000_0000)
+	s, err := newServerFromConstructors(ServerConfig{
+		Addresses:          []config.AnnounceableAddress{{Address: ":0"}},
+		MemPoolPersistence: config.MemPoolPersistence{Enabled: true, FilePath: path},
+	}, bc, new(fakechain.FakeStateSync), zaptest.NewLogger(t), newFakeTransp, newTestDiscovery)
+	require.NoError(t, err)
+	return s, bc
+}
+
+func newMemPoolSnapshotTestTx(nonce uint32) *transaction.Transaction {
+	tx := transaction.New([]byte{byte(nonce)}, 0)
+	tx.Nonce = nonce
+	tx.ValidUntilBlock = 100
+	tx.Signers = []transaction.Signer{{Account: random.Uint160()}, {Account: random.Uint160()}}
+	tx.Scripts = []transaction.Witness{{}, {}}
+	return tx
+}
+
+func TestMemPoolSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mempool.bin")
+	s1, bc1 := newMemPoolSnapshotTestServer(t, path)
+
+	// Nothing to load.
+	require.NoError(t, s1.loadMemPools())
+
+	txs := []*transaction.Transaction{newMemPoolSnapshotTestTx(1), newMemPoolSnapshotTestTx(2), newMemPoolSnapshotTestTx(3)}
+	for _, tx := range txs {
+		require.NoError(t, s1.mempool.Add(tx, bc1))
+	}
+	mainTx := newMemPoolSnapshotTestTx(4)
+	mainTx.Attributes = []transaction.Attribute{{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 1}}}
+	fallbackTx := newMemPoolSnapshotTestTx(5)
+	fallbackTx.Attributes = []transaction.Attribute{
+		{Type: transaction.NotValidBeforeT, Value: &transaction.NotValidBefore{Height: 50}},
+		{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: mainTx.Hash()}},
+		{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 0}},
+	}
+	fallbackTx.Scripts[0].InvocationScript = append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, make([]byte, keys.SignatureLen)...)
+	r := &payload.P2PNotaryRequest{
+		MainTransaction:     mainTx,
+		FallbackTransaction: fallbackTx,
+		Witness:             transaction.Witness{InvocationScript: []byte{1, 2, 3}, VerificationScript: []byte{4, 5, 6}},
+	}
+	require.NoError(t, s1.notaryRequestPool.Add(r.FallbackTransaction, bc1, r))
+	require.NoError(t, s1.saveMemPools())
+
+	var snap memPoolSnapshot
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, testserdes.DecodeBinary(data, &snap))
+	require.Equal(t, 3, len(snap.Transactions))
+	require.Equal(t, 1, len(snap.NotaryRequests))
+	require.Equal(t, r.Hash(), snap.NotaryRequests[0].Hash())
+
+	s2, bc2 := newMemPoolSnapshotTestServer(t, path)
+	var pooled []util.Uint256
+	bc2.PoolTxF = func(tx *transaction.Transaction) error {
+		if tx.Nonce == 2 {
+			return errors.New("expired")
+		}
+		pooled = append(pooled, tx.Hash())
+		return nil
+	}
+	require.NoError(t, s2.loadMemPools())
+	require.ElementsMatch(t, []util.Uint256{txs[0].Hash(), txs[2].Hash()}, pooled)
+
+	t.Run("bad file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte{1, 2, 3}, 0644))
+		require.Error(t, s2.loadMemPools())
+
+		w := io.NewBufBinWriter()
+		w.WriteB(memPoolSnapshotVersion + 1)
+		require.NoError(t, os.WriteFile(path, w.Bytes(), 0644))
+		require.Error(t, s2.loadMemPools())
+	})
+}
+
+func TestMemPoolSnapshotOnShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mempool.bin")
+	s, bc := newMemPoolSnapshotTestServer(t, path)
+	tx := newMemPoolSnapshotTestTx(1)
+	require.NoError(t, s.mempool.Add(tx, bc))
+
+	go s.Start()
+	s.Shutdown()
+
+	var snap memPoolSnapshot
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, testserdes.DecodeBinary(data, &snap))
+	require.Equal(t, 1, len(snap.Transactions))
+	require.Equal(t, tx.Hash(), snap.Transactions[0].Hash())
+
+	t.Run("no path", func(t *testing.T) {
+		_, err := newServerFromConstructors(ServerConfig{
+			Addresses:          []config.AnnounceableAddress{{Address: ":0"}},
+			MemPoolPersistence: config.MemPoolPersistence{Enabled: true},
+		}, bc, new(fakechain.FakeStateSync), zaptest.NewLogger(t), newFakeTransp, newTestDiscovery)
+		require.Error(t, err)
+	})
+}
@@ -29,6 +29,10 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/network/extpool"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -42,6 +46,9 @@ const (
 	defaultBroadcastFactor    = 0
 	maxBlockBatch             = 200
 	peerTimeFactor            = 1000
+
+	// tracerName is the name of the tracer used for network message spans.
+	tracerName = "github.com/nspcc-dev/neo-go/pkg/network"
 )
 
 var (
@@ -138,6 +145,9 @@ type (
 
 		stateSync StateSync
 
+		// memPoolSnapshotLock prevents concurrent memory pool snapshot writes.
+		memPoolSnapshotLock sync.Mutex
+
 		log *zap.Logger
 	}
 
@@ -239,6 +249,10 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		s.BroadcastFactor = defaultBroadcastFactor
 	}
 
+	if s.MemPoolPersistence.Enabled && s.MemPoolPersistence.FilePath == "" {
+		return nil, errors.New("memory pool snapshot file path is not configured")
+	}
+
 	if len(s.ServerConfig.Addresses) == 0 {
 		return nil, errors.New("no bind addresses configured")
 	}
@@ -272,6 +286,14 @@ func (s *Server) Start() {
 
 	s.tryStartServices()
 	s.initStaleMemPools()
+	if s.MemPoolPersistence.Enabled {
+		if err := s.loadMemPools(); err != nil {
+			s.log.Warn("failed to load memory pool snapshot", zap.Error(err))
+		}
+		if s.MemPoolPersistence.SaveInterval > 0 {
+			go s.memPoolSnapshotLoop()
+		}
+	}
 
 	var txThreads = optimalNumOfThreads()
 	for i := 0; i < txThreads; i++ {
@@ -310,6 +332,11 @@ func (s *Server) Shutdown() {
 	if s.chain.P2PSigExtensionsEnabled() {
 		s.notaryRequestPool.StopSubscriptions()
 	}
+	if s.MemPoolPersistence.Enabled {
+		if err := s.saveMemPools(); err != nil {
+			s.log.Warn("failed to save memory pool snapshot", zap.Error(err))
+		}
+	}
 	close(s.quit)
 	<-s.relayFin
 }
@@ -1303,7 +1330,7 @@ func getRequestBlocksPayload(p Peer, currHeight uint32, lastRequestedHeight *ato
 }
 
 // handleMessage processes the given message.
-func (s *Server) handleMessage(peer Peer, msg *Message) error {
+func (s *Server) handleMessage(peer Peer, msg *Message) (err error) {
 	s.log.Debug("got msg",
 		zap.Stringer("addr", peer.RemoteAddr()),
 		zap.Stringer("type", msg.Command))
@@ -1311,6 +1338,21 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 	start := time.Now()
 	defer func() { addCmdTimeMetric(msg.Command, time.Since(start)) }()
 
+	_, span := otel.Tracer(tracerName).Start(context.Background(), "Server.handleMessage",
+		trace.WithSpanKind(trace.SpanKindConsumer))
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.Stringer("command", msg.Command),
+			attribute.Stringer("peer", peer.RemoteAddr()))
+	}
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	if peer.Handshaked() {
 		if inv, ok := msg.Payload.(*payload.Inventory); ok {
 			if !inv.Type.Valid(s.chain.P2PSigExtensionsEnabled()) || len(inv.Hashes) == 0 {
@@ -76,6 +76,9 @@ type (
 
 		// BroadcastFactor is the factor (0-100) for fan-out optimization.
 		BroadcastFactor int
+
+		// MemPoolPersistence is memory pool snapshot configuration.
+		MemPoolPersistence config.MemPoolPersistence
 	}
 )
 
@@ -147,6 +150,7 @@ func NewServerConfig(cfg config.Config) (ServerConfig, error) {
 		StateRootCfg:       appConfig.StateRoot,
 		ExtensiblePoolSize: extPoolSize,
 		BroadcastFactor:    broadcastFactor,
+		MemPoolPersistence: appConfig.MemPoolPersistence,
 	}
 	return c, nil
 }
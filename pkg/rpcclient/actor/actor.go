@@ -39,7 +39,9 @@ type RPCActor interface {
 // SignerAccount represents combination of the transaction.Signer and the
 // corresponding wallet.Account. It's used to create and sign transactions, each
 // transaction has a set of signers that must witness the transaction with their
-// signatures.
+// signatures. Account can use a decrypted private key or an external signer
+// keeping the key in HSM or remote signing service (see
+// wallet.NewAccountFromSigner and extsigner package).
 type SignerAccount struct {
 	Signer  transaction.Signer
 	Account *wallet.Account
@@ -221,6 +223,9 @@ func (a *Actor) Sign(tx *transaction.Transaction) error {
 				return fmt.Errorf("failed to add contract-based witness for signer #%d (%s): "+
 					"%d parameters must be provided to construct invocation script", i, signer.Account.Address, paramNum)
 			}
+			if signer.Account.CanSign() { // External signer failure.
+				return fmt.Errorf("failed to add witness for signer #%d (%s): %w", i, signer.Account.Address, err)
+			}
 			return fmt.Errorf("failed to add witness for signer #%d (%s): account should be unlocked to add the signature. "+
 				"Store partially-signed transaction and then use 'wallet sign' command to cosign it", i, signer.Account.Address)
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
@@ -239,6 +240,46 @@ func TestSign(t *testing.T) {
 	require.Error(t, err)
 }
 
+type testSigner struct {
+	key *keys.PrivateKey
+	err error
+}
+
+func (s *testSigner) PublicKey() *keys.PublicKey {
+	return s.key.PublicKey()
+}
+
+func (s *testSigner) SignHash(digest util.Uint256) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.key.SignHash(digest), nil
+}
+
+func TestSignExternal(t *testing.T) {
+	client, _ := testRPCAndAccount(t)
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	signer := &testSigner{key: key}
+
+	a, err := NewSimple(client, wallet.NewAccountFromSigner(signer))
+	require.NoError(t, err)
+
+	script := []byte{1, 2, 3}
+	client.invRes = &result.Invoke{State: "HALT", GasConsumed: 3, Script: script}
+
+	tx, err := a.MakeUnsignedRun(script, nil)
+	require.NoError(t, err)
+	require.NoError(t, a.Sign(tx))
+	require.Equal(t, 1, len(tx.Scripts))
+	require.Equal(t, key.PublicKey().GetVerificationScript(), tx.Scripts[0].VerificationScript)
+	require.True(t, key.PublicKey().VerifyHashable(tx.Scripts[0].InvocationScript[2:], uint32(netmode.UnitTestNet), tx))
+
+	signer.err = errors.New("HSM is on fire")
+	tx.Scripts = nil
+	require.ErrorIs(t, a.Sign(tx), signer.err)
+}
+
 func TestSenders(t *testing.T) {
 	client, acc := testRPCAndAccount(t)
 	a, err := NewSimple(client, acc)
@@ -18,7 +18,9 @@ problems.
 
 These layers are:
 
-  - Basic RPC API, rpcclient package itself.
+  - Basic RPC API, rpcclient package itself. The failover package provides a
+    client using several RPC servers with health checks and request retries
+    that can be used instead of the basic one by all upper layers.
 
   - Generic invocation/transaction API represented by invoker, unwrap (auxiliary,
     but very convenient) and actor packages. These allow to perform test
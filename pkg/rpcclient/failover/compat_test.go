@@ -0,0 +1,20 @@
+package failover_test
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/failover"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+)
+
+func TestRPCClientCompat(t *testing.T) {
+	_ = failover.RPC(&rpcclient.Client{})
+	_ = failover.RPC(&rpcclient.WSClient{})
+}
+
+func TestRPCActorCompat(t *testing.T) {
+	_ = invoker.RPCInvoke(&failover.Client{})
+	_ = actor.RPCActor(&failover.Client{})
+}
@@ -0,0 +1,38 @@
+package failover_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/failover"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/neo"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+func ExampleClient() {
+	// No error checking done at all, intentionally.
+	c, _ := failover.New(context.Background(), []string{"url1", "url2", "url3"}, failover.Options{
+		Options:             rpcclient.Options{RequestTimeout: 5 * time.Second},
+		HealthCheckInterval: 10 * time.Second,
+		MaxHeightLag:        1,
+	})
+	defer c.Close()
+
+	// Client can be used with all wrappers instead of the regular rpcclient.Client,
+	// reads are served by any up-to-date server.
+	neoToken := neo.NewReader(invoker.New(c, nil))
+	acc, _ := address.StringToUint160("NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq")
+	balance, _ := neoToken.BalanceOf(acc)
+	_ = balance
+
+	// Transactions are sent the same way.
+	w, _ := wallet.NewWalletFromFile("somewhere")
+	defer w.Close()
+	a, _ := actor.NewSimple(c, w.Accounts[0])
+	txid, vub, _ := neo.New(a).Vote(w.Accounts[0].ScriptHash(), nil)
+	_, _ = txid, vub
+}
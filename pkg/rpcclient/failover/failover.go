@@ -0,0 +1,411 @@
+/*
+Package failover provides an RPC client that uses several RPC servers.
+
+It checks the block height of every server periodically and routes requests to
+the servers that are available and not lagging behind the others (spreading
+the load between them). Safe (read-only) requests failing because of a server
+or network problem are retried with another server. Iterator sessions are
+bound to the server that has created them.
+
+Client implements invoker.RPCInvoke and actor.RPCActor interfaces, so it can be
+used with the invoker, actor and all contract-specific wrapper packages the
+same way as a regular rpcclient.Client.
+*/
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/atomic"
+)
+
+// Default settings.
+const (
+	DefaultHealthCheckInterval = 5 * time.Second
+	DefaultSessionLifetime     = time.Hour
+)
+
+// RPC is a set of methods needed from every server, rpcclient.Client
+// implements it.
+type RPC interface {
+	actor.RPCActor
+
+	Close()
+}
+
+// Options are settings for the Client.
+type Options struct {
+	// Options are used to create rpcclient.Client for every endpoint.
+	rpcclient.Options
+
+	// HealthCheckInterval is the interval between server height checks,
+	// DefaultHealthCheckInterval is used if not set.
+	HealthCheckInterval time.Duration
+	// MaxHeightLag is the number of blocks a server can lag behind the
+	// highest one to still be used for requests. Lagging servers are only
+	// used when no up-to-date servers are available.
+	MaxHeightLag uint32
+	// SessionLifetime is the time iterator sessions are bound to the server
+	// if not terminated explicitly, DefaultSessionLifetime is used if not
+	// set. It should be no less than server's SessionExpirationTime.
+	SessionLifetime time.Duration
+}
+
+// Client is an RPC client using several servers.
+type Client struct {
+	opts    Options
+	servers []*server
+	next    *atomic.Uint32
+
+	lock     sync.RWMutex
+	sessions map[uuid.UUID]session
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// server is an RPC server with its state.
+type server struct {
+	rpc RPC
+	// healthy is false if the last request to the server failed.
+	healthy *atomic.Bool
+	height  *atomic.Uint32
+}
+
+// session is an iterator session bound to some server.
+type session struct {
+	server  *server
+	created time.Time
+}
+
+// ErrNoServers is returned when no RPC servers are given.
+var ErrNoServers = errors.New("no RPC servers")
+
+// ErrUnknownSession is returned for iterator sessions not created by the Client
+// (or expired ones).
+var ErrUnknownSession = errors.New("unknown session")
+
+// New creates rpcclient.Client for every endpoint and returns a Client using
+// them. It checks the servers once before returning, so at least one of them
+// must be available.
+func New(ctx context.Context, endpoints []string, opts Options) (*Client, error) {
+	rpcs := make([]RPC, 0, len(endpoints))
+	for _, e := range endpoints {
+		c, err := rpcclient.New(ctx, e, opts.Options)
+		if err != nil {
+			for _, c := range rpcs {
+				c.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", e, err)
+		}
+		rpcs = append(rpcs, c)
+	}
+	return NewFromRPC(rpcs, opts)
+}
+
+// NewFromRPC returns a Client using the given RPC servers. It checks the
+// servers once before returning, so at least one of them must be available.
+func NewFromRPC(rpcs []RPC, opts Options) (*Client, error) {
+	if len(rpcs) == 0 {
+		return nil, ErrNoServers
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if opts.SessionLifetime <= 0 {
+		opts.SessionLifetime = DefaultSessionLifetime
+	}
+	c := &Client{
+		opts:     opts,
+		servers:  make([]*server, 0, len(rpcs)),
+		next:     atomic.NewUint32(0),
+		sessions: make(map[uuid.UUID]session),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, r := range rpcs {
+		c.servers = append(c.servers, &server{
+			rpc:     r,
+			healthy: atomic.NewBool(false),
+			height:  atomic.NewUint32(0),
+		})
+	}
+	if !c.checkServers() {
+		for _, r := range rpcs {
+			r.Close()
+		}
+		return nil, errors.New("no RPC servers available")
+	}
+	go c.run()
+	return c, nil
+}
+
+// Close stops health checks and closes all server connections.
+func (c *Client) Close() {
+	select {
+	case <-c.quit:
+		return
+	default:
+	}
+	close(c.quit)
+	<-c.done
+	for _, s := range c.servers {
+		s.rpc.Close()
+	}
+}
+
+func (c *Client) run() {
+	t := time.NewTicker(c.opts.HealthCheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			c.checkServers()
+			c.dropExpiredSessions()
+		case <-c.quit:
+			close(c.done)
+			return
+		}
+	}
+}
+
+// checkServers updates the height of every server concurrently and returns
+// true if at least one of them is available.
+func (c *Client) checkServers() bool {
+	var (
+		wg        sync.WaitGroup
+		available = atomic.NewBool(false)
+	)
+	for _, s := range c.servers {
+		wg.Add(1)
+		go func(s *server) {
+			defer wg.Done()
+			count, err := s.rpc.GetBlockCount()
+			if err != nil {
+				s.healthy.Store(false)
+				return
+			}
+			s.height.Store(count)
+			s.healthy.Store(true)
+			available.Store(true)
+		}(s)
+	}
+	wg.Wait()
+	return available.Load()
+}
+
+func (c *Client) dropExpiredSessions() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, s := range c.sessions {
+		if time.Since(s.created) > c.opts.SessionLifetime {
+			delete(c.sessions, id)
+		}
+	}
+}
+
+// order returns servers in the order they should be tried for a request:
+// healthy up-to-date servers rotated for every request, then lagging ones
+// from the highest to the lowest and unhealthy ones at the end.
+func (c *Client) order() []*server {
+	var (
+		best                       uint32
+		actual, lagging, unhealthy []*server
+	)
+	for _, s := range c.servers {
+		if s.healthy.Load() && s.height.Load() > best {
+			best = s.height.Load()
+		}
+	}
+	for _, s := range c.servers {
+		switch {
+		case !s.healthy.Load():
+			unhealthy = append(unhealthy, s)
+		case s.height.Load()+c.opts.MaxHeightLag >= best:
+			actual = append(actual, s)
+		default:
+			lagging = append(lagging, s)
+		}
+	}
+	res := make([]*server, 0, len(c.servers))
+	if len(actual) != 0 {
+		n := int(c.next.Inc() % uint32(len(actual)))
+		res = append(res, actual[n:]...)
+		res = append(res, actual[:n]...)
+	}
+	sort.SliceStable(lagging, func(i, j int) bool {
+		return lagging[i].height.Load() > lagging[j].height.Load()
+	})
+	res = append(res, lagging...)
+	return append(res, unhealthy...)
+}
+
+// isServerError checks whether the error is caused by the server or network
+// problems, so the request can succeed with another server.
+func isServerError(err error) bool {
+	var rpcErr *neorpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == neorpc.InternalServerErrorCode
+	}
+	return true
+}
+
+// do performs the request with the best server. If retry is set, failed
+// requests are repeated with other servers in order.
+func (c *Client) do(retry bool, f func(RPC) error) error {
+	var err error
+	for _, s := range c.order() {
+		err = f(s.rpc)
+		if err == nil || !isServerError(err) {
+			return err
+		}
+		s.healthy.Store(false)
+		if !retry {
+			break
+		}
+	}
+	return err
+}
+
+// invoke performs the invocation request and binds the session (if any) to
+// the server.
+func (c *Client) invoke(f func(RPC) (*result.Invoke, error)) (*result.Invoke, error) {
+	var res *result.Invoke
+	err := c.do(true, func(r RPC) error {
+		var err error
+		res, err = f(r)
+		if err == nil && res.Session != uuid.Nil {
+			c.bindSession(res.Session, r)
+		}
+		return err
+	})
+	return res, err
+}
+
+func (c *Client) bindSession(id uuid.UUID, r RPC) {
+	for _, s := range c.servers {
+		if s.rpc == r {
+			c.lock.Lock()
+			c.sessions[id] = session{server: s, created: time.Now()}
+			c.lock.Unlock()
+			return
+		}
+	}
+}
+
+func (c *Client) getSession(id uuid.UUID) (*server, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	s, ok := c.sessions[id]
+	if !ok {
+		return nil, ErrUnknownSession
+	}
+	return s.server, nil
+}
+
+// InvokeContractVerify implements the invoker.RPCInvoke interface.
+func (c *Client) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return c.invoke(func(r RPC) (*result.Invoke, error) {
+		return r.InvokeContractVerify(contract, params, signers, witnesses...)
+	})
+}
+
+// InvokeFunction implements the invoker.RPCInvoke interface.
+func (c *Client) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.invoke(func(r RPC) (*result.Invoke, error) {
+		return r.InvokeFunction(contract, operation, params, signers)
+	})
+}
+
+// InvokeScript implements the invoker.RPCInvoke interface.
+func (c *Client) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.invoke(func(r RPC) (*result.Invoke, error) {
+		return r.InvokeScript(script, signers)
+	})
+}
+
+// TerminateSession implements the invoker.RPCSessions interface. The request
+// is sent to the server that has created the session.
+func (c *Client) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	s, err := c.getSession(sessionID)
+	if err != nil {
+		return false, err
+	}
+	res, err := s.rpc.TerminateSession(sessionID)
+	if err == nil || !isServerError(err) {
+		c.lock.Lock()
+		delete(c.sessions, sessionID)
+		c.lock.Unlock()
+	}
+	return res, err
+}
+
+// TraverseIterator implements the invoker.RPCSessions interface. The request
+// is sent to the server that has created the session.
+func (c *Client) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount int) ([]stackitem.Item, error) {
+	s, err := c.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.rpc.TraverseIterator(sessionID, iteratorID, maxItemsCount)
+}
+
+// CalculateNetworkFee implements the actor.RPCActor interface.
+func (c *Client) CalculateNetworkFee(tx *transaction.Transaction) (int64, error) {
+	var res int64
+	err := c.do(true, func(r RPC) error {
+		var err error
+		res, err = r.CalculateNetworkFee(tx)
+		return err
+	})
+	return res, err
+}
+
+// GetBlockCount implements the actor.RPCActor interface.
+func (c *Client) GetBlockCount() (uint32, error) {
+	var res uint32
+	err := c.do(true, func(r RPC) error {
+		var err error
+		res, err = r.GetBlockCount()
+		return err
+	})
+	return res, err
+}
+
+// GetVersion implements the actor.RPCActor interface.
+func (c *Client) GetVersion() (*result.Version, error) {
+	var res *result.Version
+	err := c.do(true, func(r RPC) error {
+		var err error
+		res, err = r.GetVersion()
+		return err
+	})
+	return res, err
+}
+
+// SendRawTransaction implements the actor.RPCActor interface. It's not
+// retried with other servers, since the transaction could be accepted by
+// the failed one.
+func (c *Client) SendRawTransaction(tx *transaction.Transaction) (util.Uint256, error) {
+	var res util.Uint256
+	err := c.do(false, func(r RPC) error {
+		var err error
+		res, err = r.SendRawTransaction(tx)
+		return err
+	})
+	return res, err
+}
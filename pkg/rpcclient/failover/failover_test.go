@@ -0,0 +1,290 @@
+package failover
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRPC struct {
+	lock    sync.Mutex
+	height  uint32
+	err     error
+	calls   int
+	closed  bool
+	session uuid.UUID
+}
+
+func (r *fakeRPC) call() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.calls++
+	return r.err
+}
+
+func (r *fakeRPC) set(height uint32, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.height, r.err = height, err
+}
+
+func (r *fakeRPC) stats() (int, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	calls := r.calls
+	r.calls = 0
+	return calls, r.closed
+}
+
+func (r *fakeRPC) invoke() (*result.Invoke, error) {
+	if err := r.call(); err != nil {
+		return nil, err
+	}
+	return &result.Invoke{
+		State:   "HALT",
+		Stack:   []stackitem.Item{stackitem.Make("FAKE")},
+		Session: r.session,
+	}, nil
+}
+
+func (r *fakeRPC) InvokeContractVerify(util.Uint160, []smartcontract.Parameter, []transaction.Signer, ...transaction.Witness) (*result.Invoke, error) {
+	return r.invoke()
+}
+func (r *fakeRPC) InvokeFunction(util.Uint160, string, []smartcontract.Parameter, []transaction.Signer) (*result.Invoke, error) {
+	return r.invoke()
+}
+func (r *fakeRPC) InvokeScript([]byte, []transaction.Signer) (*result.Invoke, error) {
+	return r.invoke()
+}
+func (r *fakeRPC) CalculateNetworkFee(*transaction.Transaction) (int64, error) {
+	return 1, r.call()
+}
+func (r *fakeRPC) GetBlockCount() (uint32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.height, r.err
+}
+func (r *fakeRPC) GetVersion() (*result.Version, error) {
+	return &result.Version{}, r.call()
+}
+func (r *fakeRPC) SendRawTransaction(*transaction.Transaction) (util.Uint256, error) {
+	return util.Uint256{1}, r.call()
+}
+func (r *fakeRPC) TerminateSession(uuid.UUID) (bool, error) {
+	return true, r.call()
+}
+func (r *fakeRPC) TraverseIterator(uuid.UUID, uuid.UUID, int) ([]stackitem.Item, error) {
+	return []stackitem.Item{stackitem.Make(1)}, r.call()
+}
+func (r *fakeRPC) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.closed = true
+}
+
+func newFakes(heights ...uint32) ([]*fakeRPC, []RPC) {
+	fakes := make([]*fakeRPC, 0, len(heights))
+	rpcs := make([]RPC, 0, len(heights))
+	for _, h := range heights {
+		f := &fakeRPC{height: h}
+		fakes = append(fakes, f)
+		rpcs = append(rpcs, f)
+	}
+	return fakes, rpcs
+}
+
+func TestNewFromRPC(t *testing.T) {
+	_, err := NewFromRPC(nil, Options{})
+	require.ErrorIs(t, err, ErrNoServers)
+
+	fakes, rpcs := newFakes(1, 1)
+	for _, f := range fakes {
+		f.err = errors.New("down")
+	}
+	_, err = NewFromRPC(rpcs, Options{})
+	require.Error(t, err)
+	for _, f := range fakes {
+		_, closed := f.stats()
+		require.True(t, closed)
+	}
+
+	fakes[0].set(10, nil)
+	c, err := NewFromRPC(rpcs, Options{})
+	require.NoError(t, err)
+	require.True(t, c.servers[0].healthy.Load())
+	require.False(t, c.servers[1].healthy.Load())
+	c.Close()
+	c.Close()
+	for _, f := range fakes {
+		_, closed := f.stats()
+		require.True(t, closed)
+	}
+}
+
+func TestRouting(t *testing.T) {
+	fakes, rpcs := newFakes(10, 10, 9, 5)
+	c, err := NewFromRPC(rpcs, Options{HealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	t.Run("balanced", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			_, err := c.GetVersion()
+			require.NoError(t, err)
+		}
+		for i, expected := range []int{5, 5, 0, 0} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+	})
+	t.Run("lag", func(t *testing.T) {
+		c.opts.MaxHeightLag = 1
+		t.Cleanup(func() { c.opts.MaxHeightLag = 0 })
+		for i := 0; i < 9; i++ {
+			_, err := c.CalculateNetworkFee(new(transaction.Transaction))
+			require.NoError(t, err)
+		}
+		for i, expected := range []int{3, 3, 3, 0} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+	})
+	t.Run("failover", func(t *testing.T) {
+		fakes[0].set(10, errors.New("connection refused"))
+		fakes[1].set(10, neorpc.NewInternalServerError("resyncing"))
+		for i := 0; i < 2; i++ {
+			_, err := c.InvokeFunction(util.Uint160{}, "method", nil, nil)
+			require.NoError(t, err)
+		}
+		for i, expected := range []int{1, 1, 2, 0} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+		require.False(t, c.servers[0].healthy.Load())
+		require.False(t, c.servers[1].healthy.Load())
+
+		// Request errors are not retried.
+		fakes[2].set(9, neorpc.NewInvalidParamsError("bad"))
+		_, err := c.GetVersion()
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+		for i, expected := range []int{0, 0, 1, 0} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+		require.True(t, c.servers[2].healthy.Load())
+
+		// Transactions are not resent.
+		fakes[2].set(9, errors.New("timeout"))
+		_, err = c.SendRawTransaction(new(transaction.Transaction))
+		require.Error(t, err)
+		for i, expected := range []int{0, 0, 1, 0} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+
+		// The lagging one is the only healthy server now.
+		_, err = c.GetVersion()
+		require.NoError(t, err)
+		for i, expected := range []int{0, 0, 0, 1} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+
+		// Unhealthy servers are the last resort.
+		fakes[3].set(5, errors.New("down"))
+		_, err = c.GetVersion()
+		require.Error(t, err)
+		for i, expected := range []int{1, 1, 1, 1} {
+			calls, _ := fakes[i].stats()
+			require.Equal(t, expected, calls, i)
+		}
+	})
+	t.Run("recovery", func(t *testing.T) {
+		for _, f := range fakes {
+			f.set(11, nil)
+		}
+		require.True(t, c.checkServers())
+		for i := range c.servers {
+			require.True(t, c.servers[i].healthy.Load())
+			require.Equal(t, uint32(11), c.servers[i].height.Load())
+		}
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	fakes, rpcs := newFakes(1, 5)
+	c, err := NewFromRPC(rpcs, Options{HealthCheckInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	fakes[0].set(7, nil)
+	fakes[1].set(5, errors.New("down"))
+	require.Eventually(t, func() bool {
+		return c.servers[0].height.Load() == 7 && !c.servers[1].healthy.Load()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSessions(t *testing.T) {
+	fakes, rpcs := newFakes(10, 10)
+	fakes[0].session = uuid.New()
+	fakes[1].session = uuid.New()
+	c, err := NewFromRPC(rpcs, Options{HealthCheckInterval: time.Hour, SessionLifetime: time.Minute})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	var sessions []uuid.UUID
+	for i := 0; i < 2; i++ {
+		res, err := c.InvokeScript([]byte{1}, nil)
+		require.NoError(t, err)
+		sessions = append(sessions, res.Session)
+	}
+	require.ElementsMatch(t, []uuid.UUID{fakes[0].session, fakes[1].session}, sessions)
+	_, _ = fakes[0].stats()
+	_, _ = fakes[1].stats()
+
+	for i := 0; i < 3; i++ {
+		_, err = c.TraverseIterator(fakes[1].session, uuid.New(), 10)
+		require.NoError(t, err)
+	}
+	calls, _ := fakes[1].stats()
+	require.Equal(t, 3, calls)
+	calls, _ = fakes[0].stats()
+	require.Equal(t, 0, calls)
+
+	ok, err := c.TerminateSession(fakes[1].session)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = c.TraverseIterator(fakes[1].session, uuid.New(), 10)
+	require.ErrorIs(t, err, ErrUnknownSession)
+	_, err = c.TerminateSession(uuid.New())
+	require.ErrorIs(t, err, ErrUnknownSession)
+
+	c.opts.SessionLifetime = 0
+	c.dropExpiredSessions()
+	_, err = c.TraverseIterator(fakes[0].session, uuid.New(), 10)
+	require.ErrorIs(t, err, ErrUnknownSession)
+}
+
+func TestWrappers(t *testing.T) {
+	fakes, rpcs := newFakes(10, 10)
+	fakes[0].set(10, errors.New("down"))
+	c, err := NewFromRPC(rpcs, Options{HealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	sym, err := nep17.NewReader(invoker.New(c, nil), util.Uint160{1, 2, 3}).Symbol()
+	require.NoError(t, err)
+	require.Equal(t, "FAKE", sym)
+}
@@ -12,5 +12,7 @@ func TestRPCInvokerRPCClientCompat(t *testing.T) {
 	_ = invoker.RPCInvoke(&rpcclient.WSClient{})
 	_ = invoker.RPCInvokeHistoric(&rpcclient.Client{})
 	_ = invoker.RPCInvokeHistoric(&rpcclient.WSClient{})
+	_ = invoker.RPCInvokeOverrides(&rpcclient.Client{})
+	_ = invoker.RPCInvokeOverrides(&rpcclient.WSClient{})
 	_ = invoker.RPCSessions(&rpcclient.WSClient{})
 }
@@ -2,8 +2,9 @@
 Package invoker provides a convenient wrapper to perform test calls via RPC client.
 
 This layer builds on top of the basic RPC client and simplifies performing
-test function invocations and script runs. It also makes historic calls (NeoGo
-extension) transparent, allowing to use the same API as for regular calls.
+test function invocations and script runs. It also makes historic calls and
+calls with state overrides (NeoGo extensions) transparent, allowing to use the
+same API as for regular calls.
 Results of these calls can be interpreted by upper layer packages like actor
 (to create transactions) or unwrap (to retrieve data from return values).
 */
@@ -15,6 +16,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -57,6 +59,15 @@ type RPCInvokeHistoric interface {
 	InvokeScriptWithState(stateroot util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error)
 }
 
+// RPCInvokeOverrides is a set of RPC methods needed to execute things at the
+// current blockchain height with some state overrides applied.
+type RPCInvokeOverrides interface {
+	RPCSessions
+
+	InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+	InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error)
+}
+
 // Invoker allows to test-execute things using RPC client. Its API simplifies
 // reusing the same signers list for a series of invocations and at the
 // same time uses regular Go types for call parameters. It doesn't do anything with
@@ -74,6 +85,11 @@ type historicConverter struct {
 	root   *util.Uint256
 }
 
+type overridesConverter struct {
+	client    RPCInvokeOverrides
+	overrides *neorpc.StateOverrides
+}
+
 // New creates an Invoker to test-execute things at the current blockchain height.
 // If you only want to read data from the contract using its safe methods normally
 // (but contract-specific in general case) it's OK to pass nil for signers (that
@@ -99,6 +115,17 @@ func NewHistoricWithState(rootOrBlock util.Uint256, client RPCInvokeHistoric, si
 	}, signers)
 }
 
+// NewWithOverrides creates an Invoker to test-execute things at the current
+// blockchain height with the given state overrides applied (contracts, storage
+// items and native token balances can be replaced this way). Verify is not
+// supported by such Invoker.
+func NewWithOverrides(overrides *neorpc.StateOverrides, client RPCInvokeOverrides, signers []transaction.Signer) *Invoker {
+	return New(&overridesConverter{
+		client:    client,
+		overrides: overrides,
+	}, signers)
+}
+
 func (h *historicConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	if h.height != nil {
 		return h.client.InvokeScriptAtHeight(*h.height, script, signers)
@@ -137,6 +164,26 @@ func (h *historicConverter) TraverseIterator(sessionID, iteratorID uuid.UUID, ma
 	return h.client.TraverseIterator(sessionID, iteratorID, maxItemsCount)
 }
 
+func (o *overridesConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return o.client.InvokeScriptWithOverrides(script, signers, o.overrides)
+}
+
+func (o *overridesConverter) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return o.client.InvokeFunctionWithOverrides(contract, operation, params, signers, o.overrides)
+}
+
+func (o *overridesConverter) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return nil, errors.New("verification is not supported with state overrides")
+}
+
+func (o *overridesConverter) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	return o.client.TerminateSession(sessionID)
+}
+
+func (o *overridesConverter) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount int) ([]stackitem.Item, error) {
+	return o.client.TraverseIterator(sessionID, iteratorID, maxItemsCount)
+}
+
 // Call invokes a method of the contract with the given parameters (and
 // Invoker-specific list of signers) and returns the result as is.
 func (v *Invoker) Call(contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -47,6 +48,12 @@ func (r *rpcInv) InvokeScriptAtHeight(height uint32, script []byte, signers []tr
 func (r *rpcInv) InvokeScriptWithState(stateroot util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	return r.resInv, r.err
 }
+func (r *rpcInv) InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
+func (r *rpcInv) InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	return r.resInv, r.err
+}
 func (r *rpcInv) TerminateSession(sessionID uuid.UUID) (bool, error) {
 	return r.resTrm, r.err
 }
@@ -111,8 +118,21 @@ func TestInvoker(t *testing.T) {
 		require.Panics(t, func() { _, _ = inv.Verify(util.Uint160{}, nil, "param") })
 		require.Panics(t, func() { _, _ = inv.Run([]byte{1}) })
 	})
+	t.Run("overrides", func(t *testing.T) {
+		inv := NewWithOverrides(&neorpc.StateOverrides{}, ri, nil)
+		res, err := inv.Call(util.Uint160{}, "method", 42)
+		require.NoError(t, err)
+		require.Equal(t, resExp, res)
+
+		res, err = inv.Run([]byte{1})
+		require.NoError(t, err)
+		require.Equal(t, resExp, res)
+
+		_, err = inv.Verify(util.Uint160{}, nil)
+		require.Error(t, err)
+	})
 	t.Run("terminate session", func(t *testing.T) {
-		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil)} {
+		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil), NewWithOverrides(nil, ri, nil)} {
 			ri.err = errors.New("")
 			require.Error(t, inv.TerminateSession(uuid.UUID{}))
 			ri.err = nil
@@ -123,7 +143,7 @@ func TestInvoker(t *testing.T) {
 		}
 	})
 	t.Run("traverse iterator", func(t *testing.T) {
-		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil)} {
+		for _, inv := range []*Invoker{New(ri, nil), NewHistoricWithState(util.Uint256{}, ri, nil), NewWithOverrides(nil, ri, nil)} {
 			res, err := inv.TraverseIterator(uuid.UUID{}, &result.Iterator{
 				Values: []stackitem.Item{stackitem.Make(42)},
 			}, 0)
@@ -16,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativeprices"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
@@ -163,8 +164,18 @@ func (c *Client) GetBlockHash(index uint32) (util.Uint256, error) {
 // according to the specified script hash. In-header stateroot option must be
 // initialized with Init before calling this method.
 func (c *Client) GetBlockHeader(hash util.Uint256) (*block.Header, error) {
+	return c.getBlockHeader(hash.StringLE())
+}
+
+// GetBlockHeaderByIndex returns the block header by its index. In-header
+// stateroot option must be initialized with Init before calling this method.
+func (c *Client) GetBlockHeaderByIndex(index uint32) (*block.Header, error) {
+	return c.getBlockHeader(index)
+}
+
+func (c *Client) getBlockHeader(param any) (*block.Header, error) {
 	var (
-		params = []any{hash.StringLE()}
+		params = []any{param}
 		resp   []byte
 		h      *block.Header
 	)
@@ -395,6 +406,54 @@ func (c *Client) GetNEP17Transfers(address util.Uint160, start, stop *uint64, li
 	return resp, nil
 }
 
+// FindNotifications is a wrapper for findnotifications RPC (NeoGo extension,
+// requires ExecutionIndex to be enabled on the server). It returns
+// notifications of the given contract with the given event name (any name if
+// it's empty) emitted in blocks from start to stop (inclusive) in ascending
+// order. Limit and page parameters are optional, but page can only be
+// specified along with limit.
+func (c *Client) FindNotifications(contract util.Uint160, eventName string, start, stop uint32, limit, page *int) (*result.FindNotifications, error) {
+	params, err := packIndexParams([]any{contract.StringLE(), eventName}, start, stop, limit, page)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(result.FindNotifications)
+	if err := c.performRequest("findnotifications", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FindTransactions is a wrapper for findtransactions RPC (NeoGo extension,
+// requires ExecutionIndex to be enabled on the server). It returns hashes of
+// transactions signed by the given account from blocks from start to stop
+// (inclusive) in ascending order. Limit and page parameters are optional, but
+// page can only be specified along with limit.
+func (c *Client) FindTransactions(account util.Uint160, start, stop uint32, limit, page *int) (*result.FindTransactions, error) {
+	params, err := packIndexParams([]any{account.StringLE()}, start, stop, limit, page)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(result.FindTransactions)
+	if err := c.performRequest("findtransactions", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func packIndexParams(params []any, start, stop uint32, limit, page *int) ([]any, error) {
+	params = append(params, start, stop)
+	if limit != nil {
+		params = append(params, *limit)
+		if page != nil {
+			params = append(params, *page)
+		}
+	} else if page != nil {
+		return nil, errors.New("bad parameters")
+	}
+	return params, nil
+}
+
 // GetPeers returns a list of the nodes that the node is currently connected to/disconnected from.
 func (c *Client) GetPeers() (*result.GetPeers, error) {
 	var resp = &result.GetPeers{}
@@ -779,6 +838,38 @@ func (c *Client) InvokeContractVerifyWithState(stateOrBlock util.Uint256, contra
 	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
 }
 
+// InvokeScriptWithOverrides returns the result of the given script after
+// running it true the VM using the current chain state changed by the given
+// overrides (NeoGo extension).
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptWithOverrides(script []byte, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{script}
+	return c.invokeWithOverrides("invokescript", p, signers, overrides)
+}
+
+// InvokeFunctionWithOverrides returns the results after calling the smart
+// contract with the given operation and parameters using the current chain
+// state changed by the given overrides (NeoGo extension).
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionWithOverrides(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var p = []any{contract.StringLE(), operation, params}
+	return c.invokeWithOverrides("invokefunction", p, signers, overrides)
+}
+
+// invokeWithOverrides is an inner wrapper for Invoke*WithOverrides functions,
+// overrides go after signers and verbose flag.
+func (c *Client) invokeWithOverrides(method string, p []any, signers []transaction.Signer, overrides *neorpc.StateOverrides) (*result.Invoke, error) {
+	var resp = new(result.Invoke)
+	if signers == nil {
+		signers = []transaction.Signer{}
+	}
+	p = append(p, signers, false, overrides)
+	if err := c.performRequest(method, p, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // invokeSomething is an inner wrapper for Invoke* functions.
 func (c *Client) invokeSomething(method string, p []any, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var resp = new(result.Invoke)
@@ -1030,8 +1121,12 @@ func (c *Client) SignAndPushP2PNotaryRequest(mainTx *transaction.Transaction, fa
 		MainTransaction:     mainTx,
 		FallbackTransaction: fallbackTx,
 	}
+	sig, err := acc.SignHash(hash.NetSha256(uint32(m), req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign notary request: %w", err)
+	}
 	req.Witness = transaction.Witness{
-		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, acc.SignHashable(m, req)...),
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
 		VerificationScript: acc.GetVerificationScript(),
 	}
 	actualHash, err := c.SubmitP2PNotaryRequest(req)
@@ -1285,3 +1380,69 @@ func (c *Client) TerminateSession(sessionID uuid.UUID) (bool, error) {
 
 	return resp, nil
 }
+
+// TraceTransaction re-executes the given persisted transaction against the
+// state it was executed with originally and returns its execution trace
+// (NeoGo extension). Nil opts mean default trace options. It requires the
+// server to keep historic states (KeepOnlyLatestState should be disabled).
+func (c *Client) TraceTransaction(hash util.Uint256, opts *neorpc.TraceOptions) (*result.TransactionTrace, error) {
+	var (
+		params = []any{hash.StringLE()}
+		resp   = new(result.TransactionTrace)
+	)
+	if opts != nil {
+		params = append(params, opts)
+	}
+	if err := c.performRequest("tracetransaction", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MintBlocks creates n new blocks on the developer mode node and returns their
+// hashes. NeoGo-specific, works only for nodes started with --dev.
+func (c *Client) MintBlocks(n int) ([]util.Uint256, error) {
+	var (
+		params = []any{n}
+		resp   []util.Uint256
+	)
+	if err := c.performRequest("mintblocks", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AdvanceTime shifts timestamps of subsequent blocks of the developer mode node
+// by the given number of seconds and returns the timestamp (in milliseconds)
+// the next block will have. NeoGo-specific, works only for nodes started with
+// --dev.
+func (c *Client) AdvanceTime(seconds uint32) (uint64, error) {
+	var (
+		params = []any{seconds}
+		resp   uint64
+	)
+	if err := c.performRequest("advancetime", params, &resp); err != nil {
+		return 0, err
+	}
+	return resp, nil
+}
+
+// Snapshot saves the current chain state of the developer mode node and
+// returns its identifier to be used with Revert. NeoGo-specific, works only for
+// nodes started with --dev.
+func (c *Client) Snapshot() (uint32, error) {
+	var resp uint32
+	if err := c.performRequest("snapshot", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp, nil
+}
+
+// Revert restores the chain state of the developer mode node saved by Snapshot
+// with the given identifier. This snapshot and all of the ones created after
+// it can't be used after that. NeoGo-specific, works only for nodes started
+// with --dev.
+func (c *Client) Revert(id uint32) error {
+	var resp bool
+	return c.performRequest("revert", []any{id}, &resp)
+}
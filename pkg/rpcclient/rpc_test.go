@@ -253,6 +253,17 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 				return &b.Header
 			},
 		},
+		{
+			name: "by index, positive",
+			invoke: func(c *Client) (any, error) {
+				return c.GetBlockHeaderByIndex(1)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":"` + base64Header1 + `"}`,
+			result: func(c *Client) any {
+				b := getResultBlock1()
+				return &b.Header
+			},
+		},
 		{
 			name: "verbose_positive",
 			invoke: func(c *Client) (i any, err error) {
@@ -288,6 +299,58 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"advancetime": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.AdvanceTime(3600)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":1700003600000}`,
+			result: func(c *Client) any {
+				return uint64(1700003600000)
+			},
+		},
+	},
+	"mintblocks": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.MintBlocks(1)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":["0x773dd2dae4a9c9275290f89b443cd36e0b1f9aa1d8a2e8ea4c5e0a8ab7451a1c"]}`,
+			result: func(c *Client) any {
+				h, err := util.Uint256DecodeStringLE("773dd2dae4a9c9275290f89b443cd36e0b1f9aa1d8a2e8ea4c5e0a8ab7451a1c")
+				if err != nil {
+					panic(err)
+				}
+				return []util.Uint256{h}
+			},
+		},
+	},
+	"revert": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return nil, c.Revert(1)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":true}`,
+			result: func(c *Client) any {
+				return nil
+			},
+		},
+	},
+	"snapshot": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				return c.Snapshot()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":1}`,
+			result: func(c *Client) any {
+				return uint32(1)
+			},
+		},
+	},
 	"getblocksysfee": {
 		{
 			name: "positive",
@@ -892,6 +955,60 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			fails:          true,
 		},
 	},
+	"findnotifications": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				cHash, _ := util.Uint160DecodeStringLE("ef4073a0f2b305a38ec4050e4d3d28bc40ea63f5")
+				limit := 1
+				return c.FindNotifications(cHash, "Transfer", 1, 10, &limit, nil)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"notifications":[{"blockindex":5,"container":"0xdf7683ece554ecfb85cf41492c5f143215dd43ef9ec61181a28f922da06aba58","trigger":"Application","notifyindex":1,"event":{"contract":"0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5","eventname":"Transfer","state":{"type":"Array","value":[{"type":"Integer","value":"1"}]}}}],"truncated":true}}`,
+			result: func(c *Client) any {
+				cHash, _ := util.Uint160DecodeStringLE("ef4073a0f2b305a38ec4050e4d3d28bc40ea63f5")
+				txHash, _ := util.Uint256DecodeStringLE("df7683ece554ecfb85cf41492c5f143215dd43ef9ec61181a28f922da06aba58")
+				return &result.FindNotifications{
+					Notifications: []result.FoundNotification{{
+						BlockIndex:  5,
+						Container:   txHash,
+						Trigger:     trigger.Application,
+						NotifyIndex: 1,
+						Event: state.NotificationEvent{
+							ScriptHash: cHash,
+							Name:       "Transfer",
+							Item:       stackitem.NewArray([]stackitem.Item{stackitem.Make(1)}),
+						},
+					}},
+					Truncated: true,
+				}
+			},
+		},
+	},
+	"findtransactions": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (any, error) {
+				hash, err := address.StringToUint160("NcEkNmgWmf7HQVQvzhxpengpnt4DXjmZLe")
+				if err != nil {
+					panic(err)
+				}
+				limit, page := 1, 2
+				return c.FindTransactions(hash, 0, 100, &limit, &page)
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"address":"NcEkNmgWmf7HQVQvzhxpengpnt4DXjmZLe","transactions":[{"hash":"0xdf7683ece554ecfb85cf41492c5f143215dd43ef9ec61181a28f922da06aba58","blockindex":7,"sender":true}],"truncated":false}}`,
+			result: func(c *Client) any {
+				txHash, _ := util.Uint256DecodeStringLE("df7683ece554ecfb85cf41492c5f143215dd43ef9ec61181a28f922da06aba58")
+				return &result.FindTransactions{
+					Address: "NcEkNmgWmf7HQVQvzhxpengpnt4DXjmZLe",
+					Transactions: []result.FoundTransaction{{
+						Hash:       txHash,
+						BlockIndex: 7,
+						Sender:     true,
+					}},
+				}
+			},
+		},
+	},
 	"findstates": {
 		{
 			name: "positive",
@@ -1629,6 +1746,20 @@ var rpcClientErrorCases = map[string][]rpcClientErrorCase{
 				return c.GetNEP17Transfers(util.Uint160{}, &start, &stop, nil, &page)
 			},
 		},
+		{
+			name: "findnotifications_invalid_params_error",
+			invoke: func(c *Client) (any, error) {
+				var page int
+				return c.FindNotifications(util.Uint160{}, "", 0, 1, nil, &page)
+			},
+		},
+		{
+			name: "findtransactions_invalid_params_error",
+			invoke: func(c *Client) (any, error) {
+				var page int
+				return c.FindTransactions(util.Uint160{}, 0, 1, nil, &page)
+			},
+		},
 		{
 			name: "getrawtransaction_invalid_params_error",
 			invoke: func(c *Client) (any, error) {
@@ -54,6 +54,11 @@ import (
 // will also be closed on disconnection from server or on situation when it's
 // impossible to send a subsequent notification to the subscriber's channel and
 // CloseNotificationChannelIfFull option is on.
+//
+// If Reconnect option is on, WSClient tries to restore the connection after
+// its loss and reissues all subscriptions, receiver channels are closed only
+// if it's not possible. ConnectionGap is then sent to GapReceiver (if set) to
+// notify the user about blocks that may have been missed.
 type WSClient struct {
 	Client
 	// Notifications is a channel that is used to send events received from
@@ -91,6 +96,25 @@ type WSClient struct {
 
 	respLock     sync.RWMutex
 	respChannels map[uint64]chan *neorpc.Response
+
+	// subsMgmtLock serializes subscription management in reconnect mode.
+	subsMgmtLock sync.Mutex
+	// serverIDs maps subscription IDs returned to the user to the current
+	// server-side subscription IDs (empty for subscriptions that are to be
+	// reissued). subParams contains subscription parameters used to reissue
+	// them. connGen is incremented on every connection loss. gapFrom is the
+	// first block of the gap that is not yet reported to the user (if
+	// gapPending is set). All of them are used in reconnect mode only and must
+	// be accessed with subscriptionsLock taken.
+	serverIDs  map[string]string
+	subParams  map[string][]any
+	lastSubID  uint64
+	connGen    uint64
+	gapFrom    uint32
+	gapPending bool
+
+	// lastBlock is the index of the latest block known to the client.
+	lastBlock atomic.Uint32
 }
 
 // WSOptions defines options for the web-socket RPC client. It contains a
@@ -106,6 +130,42 @@ type WSOptions struct {
 	// thus it's still the caller's duty to call Unsubscribe() for this
 	// subscription.
 	CloseNotificationChannelIfFull bool
+	// Reconnect enables automatic reconnection to the server in case of
+	// connection loss. Subscriptions are reissued transparently after
+	// reconnection, receiver channels are kept open and subscription IDs
+	// returned from Receive* methods stay valid (they're generated by the
+	// client in this mode and don't match the server-side ones). Requests
+	// pending at the moment of connection loss fail with ErrWSConnLost, new
+	// ones wait for reconnection. Receiver channels are closed only if
+	// reconnection fails (see ReconnectAttempts) or if the subscription can't
+	// be reissued after reconnection.
+	Reconnect bool
+	// ReconnectAttempts is the maximum number of consecutive reconnection
+	// attempts, zero means no limit.
+	ReconnectAttempts int
+	// ReconnectMinDelay is the delay before the first reconnection attempt,
+	// it's doubled for every subsequent attempt up to ReconnectMaxDelay.
+	// Default values are used if not set.
+	ReconnectMinDelay time.Duration
+	ReconnectMaxDelay time.Duration
+	// GapReceiver is an optional channel receiving ConnectionGap after every
+	// successful reconnection and resubscription in reconnect mode. It must be
+	// read from to avoid blocking the client and it's never closed by the
+	// client.
+	GapReceiver chan<- ConnectionGap
+}
+
+// ConnectionGap describes a range of blocks for which events could be missed
+// because of connection loss in reconnect mode. Blocks from From to To (both
+// inclusive) need to be processed by the user to fill the gap. If From is
+// bigger than To then no blocks were accepted by the server while the client
+// was disconnected (but mempool-related events still could be missed).
+// Notice that the range is exact only if there is a block subscription,
+// otherwise From is based on the height known to the client at the moment of
+// previous (re)connection.
+type ConnectionGap struct {
+	From uint32
+	To   uint32
 }
 
 // notificationReceiver is an interface aimed to provide WS subscriber functionality
@@ -360,6 +420,53 @@ func (r *notaryRequestReceiver) Close() {
 	close(r.ch)
 }
 
+// storageChangeReceiver stores information about storage changes subscriber.
+type storageChangeReceiver struct {
+	filter *neorpc.StorageFilter
+	ch     chan<- *state.StorageChangeEvent
+}
+
+// EventID implements neorpc.Comparator interface.
+func (r *storageChangeReceiver) EventID() neorpc.EventID {
+	return neorpc.StorageChangeEventID
+}
+
+// Filter implements neorpc.Comparator interface.
+func (r *storageChangeReceiver) Filter() any {
+	if r.filter == nil {
+		return nil
+	}
+	return *r.filter
+}
+
+// Receiver implements notificationReceiver interface.
+func (r *storageChangeReceiver) Receiver() any {
+	return r.ch
+}
+
+// TrySend implements notificationReceiver interface.
+func (r *storageChangeReceiver) TrySend(ntf Notification, nonBlocking bool) (bool, bool) {
+	if rpcevent.Matches(r, ntf) {
+		if nonBlocking {
+			select {
+			case r.ch <- ntf.Value.(*state.StorageChangeEvent):
+			default:
+				return true, true
+			}
+		} else {
+			r.ch <- ntf.Value.(*state.StorageChangeEvent)
+		}
+
+		return true, false
+	}
+	return false, false
+}
+
+// Close implements notificationReceiver interface.
+func (r *storageChangeReceiver) Close() {
+	close(r.ch)
+}
+
 // naiveReceiver is a structure leaved for deprecated single channel based notifications
 // delivering.
 //
@@ -449,6 +556,10 @@ const (
 
 	// Write deadline.
 	wsWriteLimit = wsPingPeriod / 2
+
+	// Default reconnection delays.
+	defaultReconnectMinDelay = time.Second
+	defaultReconnectMaxDelay = 30 * time.Second
 )
 
 // ErrNilNotificationReceiver is returned when notification receiver channel is nil.
@@ -467,23 +578,19 @@ var errConnClosedByUser = errors.New("connection closed by user")
 // You should call Init method to initialize the network magic the client is
 // operating on.
 func NewWS(ctx context.Context, endpoint string, opts WSOptions) (*WSClient, error) {
-	dialer := websocket.Dialer{HandshakeTimeout: opts.DialTimeout}
-	ws, resp, err := dialer.DialContext(ctx, endpoint, nil)
-	if resp != nil && resp.Body != nil { // Can be non-nil even with error returned.
-		defer resp.Body.Close() // Not exactly required by websocket, but let's do this for bodyclose checker.
-	}
+	ws, err := dialWS(ctx, endpoint, opts.DialTimeout)
 	if err != nil {
-		if resp != nil && resp.Body != nil {
-			var srvErr neorpc.HeaderAndError
-
-			dec := json.NewDecoder(resp.Body)
-			decErr := dec.Decode(&srvErr)
-			if decErr == nil && srvErr.Error != nil {
-				err = srvErr.Error
-			}
-		}
 		return nil, err
 	}
+	if opts.ReconnectMinDelay <= 0 {
+		opts.ReconnectMinDelay = defaultReconnectMinDelay
+	}
+	if opts.ReconnectMaxDelay <= 0 {
+		opts.ReconnectMaxDelay = defaultReconnectMaxDelay
+	}
+	if opts.ReconnectMaxDelay < opts.ReconnectMinDelay {
+		opts.ReconnectMaxDelay = opts.ReconnectMinDelay
+	}
 	wsc := &WSClient{
 		Client:        Client{},
 		Notifications: make(chan Notification),
@@ -497,6 +604,8 @@ func NewWS(ctx context.Context, endpoint string, opts WSOptions) (*WSClient, err
 		requests:      make(chan *neorpc.Request),
 		subscriptions: make(map[string]notificationReceiver),
 		receivers:     make(map[any][]string),
+		serverIDs:     make(map[string]string),
+		subParams:     make(map[string][]any),
 	}
 
 	err = initClient(ctx, &wsc.Client, endpoint, opts.Options)
@@ -506,11 +615,40 @@ func NewWS(ctx context.Context, endpoint string, opts WSOptions) (*WSClient, err
 	wsc.Client.cli = nil
 
 	go wsc.wsReader()
-	go wsc.wsWriter()
 	wsc.requestF = wsc.makeWsRequest
+	if opts.Reconnect {
+		count, err := wsc.GetBlockCount()
+		if err != nil {
+			wsc.Close()
+			return nil, fmt.Errorf("failed to get block count: %w", err)
+		}
+		wsc.lastBlock.Store(count - 1)
+	}
 	return wsc, nil
 }
 
+// dialWS establishes websocket connection to the given endpoint.
+func dialWS(ctx context.Context, endpoint string, timeout time.Duration) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: timeout}
+	ws, resp, err := dialer.DialContext(ctx, endpoint, nil)
+	if resp != nil && resp.Body != nil { // Can be non-nil even with error returned.
+		defer resp.Body.Close() // Not exactly required by websocket, but let's do this for bodyclose checker.
+	}
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			var srvErr neorpc.HeaderAndError
+
+			dec := json.NewDecoder(resp.Body)
+			decErr := dec.Decode(&srvErr)
+			if decErr == nil && srvErr.Error != nil {
+				err = srvErr.Error
+			}
+		}
+		return nil, err
+	}
+	return ws, nil
+}
+
 // Close closes connection to the remote side rendering this client instance
 // unusable.
 func (c *WSClient) Close() {
@@ -528,10 +666,50 @@ func (c *WSClient) Close() {
 }
 
 func (c *WSClient) wsReader() {
-	c.ws.SetReadLimit(wsReadLimit)
-	c.ws.SetPongHandler(func(string) error {
-		err := c.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
+	var connCloseErr error
+	for {
+		stop, finished := make(chan struct{}), make(chan struct{})
+		go c.wsWriter(c.ws, stop, finished)
+		connCloseErr = c.readConn(c.ws)
+		if !c.wsOpts.Reconnect || c.closeCalled.Load() {
+			break
+		}
+		close(stop)
+		<-finished
+		gen := c.dropConn()
+		ws, err := c.reconnect(connCloseErr)
 		if err != nil {
+			connCloseErr = err
+			break
+		}
+		c.ws = ws
+		go c.resubscribe(gen)
+	}
+	if connCloseErr != nil {
+		c.setCloseErr(connCloseErr)
+	}
+	close(c.done)
+	c.respLock.Lock()
+	for _, ch := range c.respChannels {
+		close(ch)
+	}
+	c.respChannels = nil
+	c.respLock.Unlock()
+	c.subscriptionsLock.Lock()
+	for rcvrCh, ids := range c.receivers {
+		c.dropSubCh(rcvrCh, ids[0], true)
+	}
+	c.subscriptionsLock.Unlock()
+	c.Client.ctxCancel()
+}
+
+// readConn reads and dispatches messages from the given connection until
+// it's broken or closed. It returns the reason of connection closing.
+func (c *WSClient) readConn(ws *websocket.Conn) error {
+	ws.SetReadLimit(wsReadLimit)
+	ws.SetPongHandler(func(string) error {
+		err := ws.SetReadDeadline(time.Now().Add(wsPongLimit))
+		if err != nil && !c.wsOpts.Reconnect {
 			c.setCloseErr(fmt.Errorf("failed to set pong read deadline: %w", err))
 		}
 		return err
@@ -540,12 +718,12 @@ func (c *WSClient) wsReader() {
 readloop:
 	for {
 		rr := new(requestResponse)
-		err := c.ws.SetReadDeadline(time.Now().Add(wsPongLimit))
+		err := ws.SetReadDeadline(time.Now().Add(wsPongLimit))
 		if err != nil {
 			connCloseErr = fmt.Errorf("failed to set response read deadline: %w", err)
 			break readloop
 		}
-		err = c.ws.ReadJSON(rr)
+		err = ws.ReadJSON(rr)
 		if err != nil {
 			// Timeout/connection loss/malformed response.
 			connCloseErr = fmt.Errorf("failed to read JSON response (timeout/connection loss/malformed response): %w", err)
@@ -581,6 +759,8 @@ readloop:
 				ntf.Value = new(state.AppExecResult)
 			case neorpc.NotaryRequestEventID:
 				ntf.Value = new(result.NotaryRequestEvent)
+			case neorpc.StorageChangeEventID:
+				ntf.Value = new(state.StorageChangeEvent)
 			case neorpc.MissedEventID:
 				// No value.
 			default:
@@ -596,6 +776,9 @@ readloop:
 					break readloop
 				}
 			}
+			if b, ok := ntf.Value.(*block.Block); ok {
+				c.updateLastBlock(b.Index)
+			}
 			c.notifySubscribers(ntf)
 		} else if rr.ID != nil && (rr.Error != nil || rr.Result != nil) {
 			id, err := strconv.ParseUint(string(rr.ID), 10, 64)
@@ -604,6 +787,9 @@ readloop:
 				break readloop // Malformed response (invalid response ID).
 			}
 			ch := c.getResponseChannel(id)
+			if ch == nil && c.wsOpts.Reconnect {
+				continue // Response to the request failed because of the previous connection loss.
+			}
 			if ch == nil {
 				connCloseErr = fmt.Errorf("unknown response channel for response %d", id)
 				break readloop // Unknown response (unexpected response ID).
@@ -615,22 +801,135 @@ readloop:
 			break readloop
 		}
 	}
-	if connCloseErr != nil {
-		c.setCloseErr(connCloseErr)
-	}
-	close(c.done)
+	return connCloseErr
+}
+
+// dropConn fails all requests pending on the lost connection and marks all
+// subscriptions to be reissued. It returns the new connection generation.
+func (c *WSClient) dropConn() uint64 {
 	c.respLock.Lock()
 	for _, ch := range c.respChannels {
 		close(ch)
 	}
-	c.respChannels = nil
+	c.respChannels = make(map[uint64]chan *neorpc.Response)
 	c.respLock.Unlock()
+
 	c.subscriptionsLock.Lock()
-	for rcvrCh, ids := range c.receivers {
-		c.dropSubCh(rcvrCh, ids[0], true)
+	defer c.subscriptionsLock.Unlock()
+	for id := range c.serverIDs {
+		c.serverIDs[id] = ""
+	}
+	if !c.gapPending {
+		c.gapFrom = c.lastBlock.Load() + 1
+		c.gapPending = true
+	}
+	c.connGen++
+	return c.connGen
+}
+
+// reconnect tries to establish a new connection to the server with an
+// exponential backoff. It fails if the client is closed or if the number of
+// attempts is exceeded.
+func (c *WSClient) reconnect(connErr error) (*websocket.Conn, error) {
+	var delay = c.wsOpts.ReconnectMinDelay
+	for i := 0; c.wsOpts.ReconnectAttempts <= 0 || i < c.wsOpts.ReconnectAttempts; i++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.shutdown:
+			timer.Stop()
+			return nil, errConnClosedByUser
+		case <-timer.C:
+		}
+		ws, err := dialWS(c.Client.ctx, c.endpoint.String(), c.opts.DialTimeout)
+		if err == nil {
+			return ws, nil
+		}
+		connErr = err
+		delay *= 2
+		if delay > c.wsOpts.ReconnectMaxDelay {
+			delay = c.wsOpts.ReconnectMaxDelay
+		}
+	}
+	return nil, fmt.Errorf("failed to reconnect after %d attempts: %w", c.wsOpts.ReconnectAttempts, connErr)
+}
+
+// resubscribe reissues subscriptions after reconnection and sends the gap
+// notification. It stops if the connection of generation gen is lost, the next
+// reconnection handles everything then. Subscriptions that can't be reissued
+// are dropped closing their receiver channels.
+func (c *WSClient) resubscribe(gen uint64) {
+	c.subsMgmtLock.Lock()
+	defer c.subsMgmtLock.Unlock()
+
+	c.subscriptionsLock.RLock()
+	var ids []string
+	for id, srvID := range c.serverIDs {
+		if srvID == "" {
+			ids = append(ids, id)
+		}
+	}
+	c.subscriptionsLock.RUnlock()
+
+	for _, id := range ids {
+		c.subscriptionsLock.RLock()
+		params := c.subParams[id]
+		c.subscriptionsLock.RUnlock()
+
+		var resp string
+		err := c.performRequest("subscribe", params, &resp)
+		if errors.Is(err, ErrWSConnLost) {
+			return
+		}
+		c.subscriptionsLock.Lock()
+		if c.connGen != gen {
+			c.subscriptionsLock.Unlock()
+			return
+		}
+		if err != nil {
+			ch := c.subscriptions[id].Receiver()
+			if _, ok := c.receivers[ch]; ok {
+				c.dropSubCh(ch, id, true)
+			}
+			delete(c.subscriptions, id)
+			delete(c.serverIDs, id)
+			delete(c.subParams, id)
+		} else {
+			c.serverIDs[id] = resp
+		}
+		c.subscriptionsLock.Unlock()
+	}
+
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return
+	}
+	c.subscriptionsLock.Lock()
+	if c.connGen != gen {
+		c.subscriptionsLock.Unlock()
+		return
 	}
+	gap := ConnectionGap{From: c.gapFrom, To: count - 1}
+	c.gapPending = false
 	c.subscriptionsLock.Unlock()
-	c.Client.ctxCancel()
+
+	c.updateLastBlock(gap.To)
+	if c.wsOpts.GapReceiver != nil {
+		select {
+		case c.wsOpts.GapReceiver <- gap:
+		case <-c.done:
+		}
+	}
+}
+
+// updateLastBlock updates the latest known block index if the given one is
+// higher.
+func (c *WSClient) updateLastBlock(index uint32) {
+	for {
+		last := c.lastBlock.Load()
+		if index <= last || c.lastBlock.CompareAndSwap(last, index) {
+			return
+		}
+	}
 }
 
 // dropSubCh closes corresponding subscriber's channel and removes it from the
@@ -653,9 +952,13 @@ func (c *WSClient) dropSubCh(rcvrCh any, id string, ignoreCloseNotificationChann
 	}
 }
 
-func (c *WSClient) wsWriter() {
+// wsWriter sends requests and pings to the given connection until the client
+// is shut down or the connection is broken. In reconnect mode it can also be
+// stopped via the stop channel, finished is closed upon exit.
+func (c *WSClient) wsWriter(ws *websocket.Conn, stop <-chan struct{}, finished chan<- struct{}) {
 	pingTicker := time.NewTicker(wsPingPeriod)
-	defer c.ws.Close()
+	defer close(finished)
+	defer ws.Close()
 	defer pingTicker.Stop()
 	var connCloseErr error
 writeloop:
@@ -665,30 +968,34 @@ writeloop:
 			return
 		case <-c.done:
 			return
+		case <-stop:
+			return
 		case req, ok := <-c.requests:
 			if !ok {
 				return
 			}
-			if err := c.ws.SetWriteDeadline(time.Now().Add(c.opts.RequestTimeout)); err != nil {
+			if err := ws.SetWriteDeadline(time.Now().Add(c.opts.RequestTimeout)); err != nil {
 				connCloseErr = fmt.Errorf("failed to set request write deadline: %w", err)
 				break writeloop
 			}
-			if err := c.ws.WriteJSON(req); err != nil {
+			if err := ws.WriteJSON(req); err != nil {
 				connCloseErr = fmt.Errorf("failed to write JSON request (%s / %d): %w", req.Method, len(req.Params), err)
 				break writeloop
 			}
 		case <-pingTicker.C:
-			if err := c.ws.SetWriteDeadline(time.Now().Add(wsWriteLimit)); err != nil {
+			if err := ws.SetWriteDeadline(time.Now().Add(wsWriteLimit)); err != nil {
 				connCloseErr = fmt.Errorf("failed to set ping write deadline: %w", err)
 				break writeloop
 			}
-			if err := c.ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+			if err := ws.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				connCloseErr = fmt.Errorf("failed to write ping message: %w", err)
 				break writeloop
 			}
 		}
 	}
-	if connCloseErr != nil {
+	// Connection errors are not fatal in reconnect mode, the reader will
+	// notice connection closing and reconnect.
+	if connCloseErr != nil && !c.wsOpts.Reconnect {
 		c.setCloseErr(connCloseErr)
 	}
 }
@@ -748,6 +1055,9 @@ func (c *WSClient) makeWsRequest(r *neorpc.Request) (*neorpc.Response, error) {
 	select {
 	case <-c.done:
 		return nil, fmt.Errorf("%w: before sending the request", ErrWSConnLost)
+	case <-ch:
+		// Response channel is closed on connection loss in reconnect mode.
+		return nil, fmt.Errorf("%w: before sending the request", ErrWSConnLost)
 	case c.requests <- r:
 	}
 	select {
@@ -763,8 +1073,18 @@ func (c *WSClient) makeWsRequest(r *neorpc.Request) (*neorpc.Response, error) {
 }
 
 func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver) (string, error) {
-	var resp string
-
+	var (
+		resp string
+		gen  uint64
+	)
+
+	if c.wsOpts.Reconnect {
+		c.subsMgmtLock.Lock()
+		defer c.subsMgmtLock.Unlock()
+		c.subscriptionsLock.RLock()
+		gen = c.connGen
+		c.subscriptionsLock.RUnlock()
+	}
 	if err := c.performRequest("subscribe", params, &resp); err != nil {
 		return "", err
 	}
@@ -772,10 +1092,22 @@ func (c *WSClient) performSubscription(params []any, rcvr notificationReceiver)
 	c.subscriptionsLock.Lock()
 	defer c.subscriptionsLock.Unlock()
 
-	c.subscriptions[resp] = rcvr
+	id := resp
+	if c.wsOpts.Reconnect {
+		c.lastSubID++
+		id = strconv.FormatUint(c.lastSubID, 10)
+		if c.connGen != gen {
+			// The connection was lost after the request had been made,
+			// so the subscription is to be reissued.
+			resp = ""
+		}
+		c.serverIDs[id] = resp
+		c.subParams[id] = params
+	}
+	c.subscriptions[id] = rcvr
 	ch := rcvr.Receiver()
-	c.receivers[ch] = append(c.receivers[ch], resp)
-	return resp, nil
+	c.receivers[ch] = append(c.receivers[ch], id)
+	return id, nil
 }
 
 // SubscribeForNewBlocks adds subscription for new block events to this instance
@@ -1002,6 +1334,27 @@ func (c *WSClient) ReceiveNotaryRequests(flt *neorpc.TxFilter, rcvr chan<- *resu
 	return c.performSubscription(params, r)
 }
 
+// ReceiveStorageChanges registers provided channel as a receiver for contract
+// storage change events. Every storage item changed by a new block is sent
+// separately before the block itself. Events can be filtered by the given
+// StorageFilter, nil value doesn't add any filter. See WSClient comments for
+// generic Receive* behaviour details.
+func (c *WSClient) ReceiveStorageChanges(flt *neorpc.StorageFilter, rcvr chan<- *state.StorageChangeEvent) (string, error) {
+	if rcvr == nil {
+		return "", ErrNilNotificationReceiver
+	}
+	params := []any{"storage_changed"}
+	if flt != nil {
+		flt = flt.Copy()
+		params = append(params, *flt)
+	}
+	r := &storageChangeReceiver{
+		filter: flt,
+		ch:     rcvr,
+	}
+	return c.performSubscription(params, r)
+}
+
 // Unsubscribe removes subscription for the given event stream. It will return an
 // error in case if there's no subscription with the provided ID. Call to Unsubscribe
 // doesn't block notifications receive process for given subscriber, thus, ensure
@@ -1052,12 +1405,28 @@ func (c *WSClient) UnsubscribeAll() error {
 // after WS RPC unsubscription request is completed. Until then the subscriber channel
 // may still receive WS notifications.
 func (c *WSClient) performUnsubscription(id string) error {
-	var resp bool
-	if err := c.performRequest("unsubscribe", []any{id}, &resp); err != nil {
-		return err
+	var srvID = id
+	if c.wsOpts.Reconnect {
+		var ok bool
+
+		c.subsMgmtLock.Lock()
+		defer c.subsMgmtLock.Unlock()
+		c.subscriptionsLock.RLock()
+		srvID, ok = c.serverIDs[id]
+		c.subscriptionsLock.RUnlock()
+		if !ok {
+			return errors.New("no subscription with this ID")
+		}
 	}
-	if !resp {
-		return errors.New("unsubscribe method returned false result")
+	// Subscriptions to be reissued don't exist on the server side.
+	if srvID != "" {
+		var resp bool
+		if err := c.performRequest("unsubscribe", []any{srvID}, &resp); err != nil {
+			return err
+		}
+		if !resp {
+			return errors.New("unsubscribe method returned false result")
+		}
 	}
 
 	c.subscriptionsLock.Lock()
@@ -1081,6 +1450,8 @@ func (c *WSClient) performUnsubscription(id string) error {
 		c.receivers[ch] = ids
 	}
 	delete(c.subscriptions, id)
+	delete(c.serverIDs, id)
+	delete(c.subParams, id)
 	return nil
 }
 
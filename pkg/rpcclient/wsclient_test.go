@@ -49,6 +49,7 @@ func TestWSClientSubscription(t *testing.T) {
 	aerCh := make(chan *state.AppExecResult)
 	ntfCh := make(chan *state.ContainedNotificationEvent)
 	ntrCh := make(chan *result.NotaryRequestEvent)
+	stCh := make(chan *state.StorageChangeEvent)
 	var cases = map[string]func(*WSClient) (string, error){
 		"blocks": func(wsc *WSClient) (string, error) {
 			return wsc.ReceiveBlocks(nil, bCh)
@@ -65,6 +66,9 @@ func TestWSClientSubscription(t *testing.T) {
 		"notary requests": func(wsc *WSClient) (string, error) {
 			return wsc.ReceiveNotaryRequests(nil, ntrCh)
 		},
+		"storage changes": func(wsc *WSClient) (string, error) {
+			return wsc.ReceiveStorageChanges(nil, stCh)
+		},
 	}
 	t.Run("good", func(t *testing.T) {
 		for name, f := range cases {
@@ -150,6 +154,8 @@ func TestWSClientEvents(t *testing.T) {
 		`{"jsonrpc":"2.0","method":"transaction_executed","params":[{"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","trigger":"Application","vmstate":"HALT","gasconsumed":"22910000","stack":[],"notifications":[{"contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","eventname":"contract call","state":{"type":"Array","value":[{"type":"ByteString","value":"dHJhbnNmZXI="},{"type":"Array","value":[{"type":"ByteString","value":"dpFiJB7t+XwkgWUq3xug9b9XQxs="},{"type":"ByteString","value":"MW6FEDkBnTnfwsN9bD/uGf1YCYc="},{"type":"Integer","value":"1000"}]}]}},{"contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","eventname":"transfer","state":{"type":"Array","value":[{"type":"ByteString","value":"dpFiJB7t+XwkgWUq3xug9b9XQxs="},{"type":"ByteString","value":"MW6FEDkBnTnfwsN9bD/uGf1YCYc="},{"type":"Integer","value":"1000"}]}}]}]}`,
 		`{"jsonrpc":"2.0","method":"notification_from_execution","params":[{"container":"0xe1cd5e57e721d2a2e05fb1f08721b12057b25ab1dd7fd0f33ee1639932fdfad7","contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","eventname":"contract call","state":{"type":"Array","value":[{"type":"ByteString","value":"dHJhbnNmZXI="},{"type":"Array","value":[{"type":"ByteString","value":"dpFiJB7t+XwkgWUq3xug9b9XQxs="},{"type":"ByteString","value":"MW6FEDkBnTnfwsN9bD/uGf1YCYc="},{"type":"Integer","value":"1000"}]}]}}]}`,
 		`{"jsonrpc":"2.0","method":"transaction_executed","params":[{"container":"0xf97a72b7722c109f909a8bc16c22368c5023d85828b09b127b237aace33cf099","trigger":"Application","vmstate":"HALT","gasconsumed":"6042610","stack":[],"notifications":[{"contract":"0xe65ff7b3a02d207b584a5c27057d4e9862ef01da","eventname":"contract call","state":{"type":"Array","value":[{"type":"ByteString","value":"dHJhbnNmZXI="},{"type":"Array","value":[{"type":"ByteString","value":"MW6FEDkBnTnfwsN9bD/uGf1YCYc="},{"type":"ByteString","value":"IHKCdK+vw29DoHHTKM+j5inZy7A="},{"type":"Integer","value":"123"}]}]}},{"contract":"0xe65ff7b3a02d207b584a5c27057d4e9862ef01da","eventname":"transfer","state":{"type":"Array","value":[{"type":"ByteString","value":"MW6FEDkBnTnfwsN9bD/uGf1YCYc="},{"type":"ByteString","value":"IHKCdK+vw29DoHHTKM+j5inZy7A="},{"type":"Integer","value":"123"}]}}]}]}`,
+		`{"jsonrpc":"2.0","method":"storage_changed","params":[{"blockhash":"0x813e3f7a7a5b8f5e0a2e8b6e7de2e1bd5e8e1c54e5d2d3c1b1a6b5c4d3e2f1a0","blockindex":1,"contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","key":"AQI=","value":"Aw=="}]}`,
+		`{"jsonrpc":"2.0","method":"storage_changed","params":[{"blockhash":"0x813e3f7a7a5b8f5e0a2e8b6e7de2e1bd5e8e1c54e5d2d3c1b1a6b5c4d3e2f1a0","blockindex":1,"contract":"0x1b4357bff5a01bdf2a6581247cf9ed1e24629176","key":"AgE=","value":null}]}`,
 		fmt.Sprintf(`{"jsonrpc":"2.0","method":"block_added","params":[%s]}`, b1Verbose),
 		`{"jsonrpc":"2.0","method":"event_missed","params":[]}`, // the last one, will trigger receiver channels closing.
 	}
@@ -185,6 +191,7 @@ func TestWSClientEvents(t *testing.T) {
 	aerCh2 := make(chan *state.AppExecResult)
 	aerCh3 := make(chan *state.AppExecResult)
 	ntfCh := make(chan *state.ContainedNotificationEvent)
+	stCh := make(chan *state.StorageChangeEvent)
 	halt := "HALT"
 	fault := "FAULT"
 	wsc.subscriptionsLock.Lock()
@@ -209,26 +216,31 @@ func TestWSClientEvents(t *testing.T) {
 	wsc.subscriptions["8"] = &naiveReceiver{eventID: neorpc.BlockEventID, ch: wsc.Notifications}     // check duplicating subscriptions
 	wsc.subscriptions["9"] = &naiveReceiver{eventID: neorpc.ExecutionEventID, ch: wsc.Notifications} // check different events
 	wsc.receivers[wsc.Notifications] = []string{"7", "8", "9"}
+
+	wsc.subscriptions["10"] = &storageChangeReceiver{filter: &neorpc.StorageFilter{Prefix: []byte{1}}, ch: stCh}
+	wsc.receivers[chan<- *state.StorageChangeEvent(stCh)] = []string{"10"}
 	wsc.subscriptionsLock.Unlock()
 
 	var (
 		b1Cnt, b2Cnt                                      int
 		aer1Cnt, aer2Cnt, aer3Cnt                         int
 		ntfCnt                                            int
+		stCnt                                             int
 		defaultCount                                      int
 		expectedb1Cnt, expectedb2Cnt                      = 1, 1      // single Block event
 		expectedaer1Cnt, expectedaer2Cnt, expectedaer3Cnt = 2, 2, 0   // two HALTED AERs
 		expectedntfCnt                                    = 1         // single notification event
+		expectedstCnt                                     = 1         // single storage change with matching prefix
 		expectedDefaultCnt                                = 1 + 2 + 1 // single Block event + two AERs + missed event
 		aer                                               *state.AppExecResult
 	)
 	for b1Cnt+b2Cnt+
 		aer1Cnt+aer2Cnt+aer3Cnt+
-		ntfCnt+
+		ntfCnt+stCnt+
 		defaultCount !=
 		expectedb1Cnt+expectedb2Cnt+
 			expectedaer1Cnt+expectedaer2Cnt+expectedaer3Cnt+
-			expectedntfCnt+
+			expectedntfCnt+expectedstCnt+
 			expectedDefaultCnt {
 		select {
 		case _, ok = <-bCh1:
@@ -256,6 +268,12 @@ func TestWSClientEvents(t *testing.T) {
 			if ok {
 				ntfCnt++
 			}
+		case st, ok := <-stCh:
+			if ok {
+				require.Equal(t, []byte{1, 2}, st.Key)
+				require.Equal(t, []byte{3}, st.Value)
+				stCnt++
+			}
 		case _, ok = <-wsc.Notifications:
 			if ok {
 				defaultCount++
@@ -270,6 +288,7 @@ func TestWSClientEvents(t *testing.T) {
 	assert.Equal(t, expectedaer2Cnt, aer2Cnt)
 	assert.Equal(t, expectedaer3Cnt, aer3Cnt)
 	assert.Equal(t, expectedntfCnt, ntfCnt)
+	assert.Equal(t, expectedstCnt, stCnt)
 	assert.Equal(t, expectedDefaultCnt, defaultCount)
 
 	// Channels must be closed by server
@@ -287,6 +306,8 @@ func TestWSClientEvents(t *testing.T) {
 	require.False(t, ok)
 	_, ok = <-ntfCh
 	require.False(t, ok)
+	_, ok = <-stCh
+	require.False(t, ok)
 }
 
 func TestWSClientNonBlockingEvents(t *testing.T) {
@@ -587,6 +608,20 @@ func TestWSFilteredSubscriptions(t *testing.T) {
 				require.Equal(t, util.Uint256{1, 2, 3}, *filt.Container)
 			},
 		},
+		{"storage changes contract and prefix",
+			func(t *testing.T, wsc *WSClient) {
+				contract := util.Uint160{1, 2, 3, 4, 5}
+				_, err := wsc.ReceiveStorageChanges(&neorpc.StorageFilter{Contract: &contract, Prefix: []byte{6, 7}}, make(chan *state.StorageChangeEvent))
+				require.NoError(t, err)
+			},
+			func(t *testing.T, p *params.Params) {
+				param := p.Value(1)
+				filt := new(neorpc.StorageFilter)
+				require.NoError(t, json.Unmarshal(param.RawMessage, filt))
+				require.Equal(t, util.Uint160{1, 2, 3, 4, 5}, *filt.Contract)
+				require.Equal(t, []byte{6, 7}, filt.Prefix)
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -791,3 +826,176 @@ func TestWSClient_ConnClosedError(t *testing.T) {
 		require.True(t, strings.Contains(err.Error(), "failed to read JSON response (timeout/connection loss/malformed response)"), err.Error())
 	})
 }
+
+// reconnectTestServer is a WS server allowing to break client connections.
+type reconnectTestServer struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	count  uint32
+	refuse bool
+	connN  int
+	conns  []*websocket.Conn
+	// subs and unsubs contain "connection:event" and "connection:id"
+	// records for subscribe and unsubscribe requests.
+	subs   []string
+	unsubs []string
+}
+
+func newReconnectTestServer(t *testing.T, count uint32) *reconnectTestServer {
+	s := &reconnectTestServer{count: count}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.lock.Lock()
+		if s.refuse {
+			s.lock.Unlock()
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var upgrader = websocket.Upgrader{}
+		ws, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			s.lock.Unlock()
+			return
+		}
+		connN := s.connN
+		s.connN++
+		s.conns = append(s.conns, ws)
+		s.lock.Unlock()
+
+		var lastSubID int
+		for {
+			r := params.NewIn()
+			if err := ws.ReadJSON(r); err != nil {
+				break
+			}
+			var res string
+			s.lock.Lock()
+			switch r.Method {
+			case "getblockcount":
+				res = strconv.FormatUint(uint64(s.count), 10)
+			case "subscribe":
+				event, _ := r.RawParams[0].GetString()
+				s.subs = append(s.subs, fmt.Sprintf("%d:%s", connN, event))
+				res = strconv.Quote(strconv.Itoa(lastSubID))
+				lastSubID++
+			case "unsubscribe":
+				id, _ := r.RawParams[0].GetString()
+				s.unsubs = append(s.unsubs, fmt.Sprintf("%d:%s", connN, id))
+				res = "true"
+			}
+			s.lock.Unlock()
+			resp := fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": %s}`, string(r.RawID), res)
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(resp)); err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// dropConns closes all client connections and sets new block count.
+func (s *reconnectTestServer) dropConns(count uint32, refuse bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.count = count
+	s.refuse = refuse
+	for _, ws := range s.conns {
+		ws.Close()
+	}
+	s.conns = s.conns[:0]
+}
+
+func (s *reconnectTestServer) getLogs() ([]string, []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.subs...), append([]string{}, s.unsubs...)
+}
+
+func TestWSClientReconnect(t *testing.T) {
+	t.Run("resubscribe", func(t *testing.T) {
+		srv := newReconnectTestServer(t, 10)
+		gapCh := make(chan ConnectionGap)
+		wsc, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), WSOptions{
+			Reconnect:         true,
+			ReconnectMinDelay: 10 * time.Millisecond,
+			GapReceiver:       gapCh,
+		})
+		require.NoError(t, err)
+		t.Cleanup(wsc.Close)
+
+		bCh := make(chan *block.Block)
+		bID, err := wsc.ReceiveBlocks(nil, bCh)
+		require.NoError(t, err)
+		require.Equal(t, "1", bID)
+		aerCh := make(chan *state.AppExecResult)
+		aerID, err := wsc.ReceiveExecutions(nil, aerCh)
+		require.NoError(t, err)
+		require.Equal(t, "2", aerID)
+
+		srv.dropConns(15, false)
+		select {
+		case gap := <-gapCh:
+			require.Equal(t, ConnectionGap{From: 10, To: 14}, gap)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no gap notification")
+		}
+		subs, _ := srv.getLogs()
+		sort.Strings(subs)
+		require.Equal(t, []string{"0:block_added", "0:transaction_executed", "1:block_added", "1:transaction_executed"}, subs)
+		require.NoError(t, wsc.GetError())
+
+		// Subscriptions are still valid and channels are not closed.
+		require.NoError(t, wsc.Unsubscribe(bID))
+		_, unsubs := srv.getLogs()
+		require.Equal(t, 1, len(unsubs))
+		require.True(t, strings.HasPrefix(unsubs[0], "1:"))
+		require.Error(t, wsc.Unsubscribe(bID))
+
+		// No blocks missed during the next reconnection.
+		srv.dropConns(15, false)
+		select {
+		case gap := <-gapCh:
+			require.Equal(t, ConnectionGap{From: 15, To: 14}, gap)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no gap notification")
+		}
+		subs, _ = srv.getLogs()
+		require.Equal(t, "2:transaction_executed", subs[len(subs)-1])
+		select {
+		case <-aerCh:
+			t.Fatal("receiver channel is closed")
+		default:
+		}
+
+		wsc.Close()
+		_, ok := <-aerCh
+		require.False(t, ok)
+	})
+	t.Run("reconnection failure", func(t *testing.T) {
+		srv := newReconnectTestServer(t, 10)
+		wsc, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), WSOptions{
+			Reconnect:         true,
+			ReconnectAttempts: 2,
+			ReconnectMinDelay: 10 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		t.Cleanup(wsc.Close)
+
+		bCh := make(chan *block.Block)
+		_, err = wsc.ReceiveBlocks(nil, bCh)
+		require.NoError(t, err)
+
+		srv.dropConns(10, true)
+		select {
+		case _, ok := <-bCh:
+			require.False(t, ok)
+		case <-time.After(5 * time.Second):
+			t.Fatal("receiver channel is not closed")
+		}
+		require.ErrorContains(t, wsc.GetError(), "failed to reconnect after 2 attempts")
+		_, err = wsc.GetBlockCount()
+		require.ErrorIs(t, err, ErrWSConnLost)
+	})
+}
@@ -0,0 +1,384 @@
+/*
+Package devnode implements block production for a single-node developer chain.
+
+The Service replaces consensus on such a chain: it creates a new block as soon
+as there are some transactions in the memory pool and allows to mint blocks,
+shift block timestamps and snapshot/revert chain state on demand.
+*/
+package devnode
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+type (
+	// Ledger is an interface to Blockchain sufficient for Service.
+	Ledger interface {
+		AddBlock(block *block.Block) error
+		ApplyPolicyToTxSet([]*transaction.Transaction) []*transaction.Transaction
+		BlockHeight() uint32
+		CurrentBlockHash() util.Uint256
+		FeePerByte() int64
+		GetBaseExecFee() int64
+		GetConfig() config.Blockchain
+		GetHeader(hash util.Uint256) (*block.Header, error)
+		GetMemPool() *mempool.Pool
+		GetNativeContractScriptHash(string) (util.Uint160, error)
+		GetNextBlockValidators() ([]*keys.PublicKey, error)
+		GetStateRoot(height uint32) (*state.MPTRoot, error)
+		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
+		GetValidators() ([]*keys.PublicKey, error)
+		ResetRunning(height uint32) error
+	}
+
+	// Service is a developer chain block producer.
+	Service struct {
+		chain Ledger
+		log   *zap.Logger
+		// acc is the standby validators multisignature account.
+		acc *wallet.Account
+		// funded is the list of wallet accounts to prefund.
+		funded []util.Uint160
+
+		// lock protects chain modifications made by the service and the
+		// fields below.
+		lock         sync.Mutex
+		timeOffset   time.Duration
+		snapshots    map[uint32]snapshot
+		nextSnapshot uint32
+
+		started *atomic.Bool
+		quit    chan struct{}
+		done    chan struct{}
+	}
+
+	// snapshot is the chain state saved by the Snapshot call.
+	snapshot struct {
+		height     uint32
+		timeOffset time.Duration
+	}
+)
+
+const (
+	// PrefundNEO is the amount of NEO transferred to every wallet account.
+	PrefundNEO = 1000
+	// PrefundGAS is the amount of GAS (in GAS fractions) transferred to every
+	// wallet account.
+	PrefundGAS = 10000_0000_0000
+
+	// pollInterval is the interval of memory pool checks.
+	pollInterval = 100 * time.Millisecond
+)
+
+// New creates a new Service for the given chain. The wallet must contain an
+// unlocked standby validators account (it's used to sign blocks), all of the
+// other wallet accounts are prefunded by the first block if the chain is at
+// genesis.
+func New(chain Ledger, w *wallet.Wallet, log *zap.Logger) (*Service, error) {
+	validators, err := chain.GetNextBlockValidators()
+	if err != nil {
+		return nil, fmt.Errorf("can't get validators: %w", err)
+	}
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(validators)
+	if err != nil {
+		return nil, fmt.Errorf("can't create validators script: %w", err)
+	}
+	acc := w.GetAccount(hash.Hash160(script))
+	if acc == nil {
+		return nil, errors.New("no validators account in the wallet")
+	}
+	if !acc.CanSign() {
+		return nil, errors.New("validators account is locked")
+	}
+	var funded []util.Uint160
+	for _, a := range w.Accounts {
+		if a != acc {
+			funded = append(funded, a.ScriptHash())
+		}
+	}
+	return &Service{
+		chain:     chain,
+		log:       log,
+		acc:       acc,
+		funded:    funded,
+		snapshots: make(map[uint32]snapshot),
+		started:   atomic.NewBool(false),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Name returns service name.
+func (s *Service) Name() string {
+	return "devnode"
+}
+
+// Start prefunds wallet accounts (if not yet done) and runs a routine that
+// creates blocks for mempooled transactions. It's a no-op if the service is
+// already started.
+func (s *Service) Start() {
+	if !s.started.CompareAndSwap(false, true) {
+		return
+	}
+	s.log.Info("starting developer chain block producer")
+	if s.chain.BlockHeight() == 0 && len(s.funded) != 0 {
+		if err := s.prefund(); err != nil {
+			s.log.Error("failed to prefund wallet accounts", zap.Error(err))
+		}
+	}
+	go s.run()
+}
+
+// Shutdown stops the service. It can only be called once, subsequent calls
+// to Shutdown on the same instance are no-op.
+func (s *Service) Shutdown() {
+	if !s.started.CompareAndSwap(true, false) {
+		return
+	}
+	s.log.Info("stopping developer chain block producer")
+	close(s.quit)
+	<-s.done
+}
+
+func (s *Service) run() {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if s.chain.GetMemPool().Count() == 0 {
+				continue
+			}
+			s.lock.Lock()
+			_, err := s.mint(nil)
+			s.lock.Unlock()
+			if err != nil {
+				s.log.Error("failed to create block", zap.Error(err))
+			}
+		case <-s.quit:
+			close(s.done)
+			return
+		}
+	}
+}
+
+// MintBlocks creates n new blocks with mempooled transactions (if any) and
+// returns their hashes.
+func (s *Service) MintBlocks(n int) ([]util.Uint256, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := make([]util.Uint256, 0, n)
+	for i := 0; i < n; i++ {
+		b, err := s.mint(nil)
+		if err != nil {
+			return res, err
+		}
+		res = append(res, b.Hash())
+	}
+	return res, nil
+}
+
+// AdvanceTime shifts timestamps of the subsequent blocks by d and returns the
+// timestamp (in milliseconds) the next block will have if created now.
+func (s *Service) AdvanceTime(d time.Duration) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.timeOffset += d
+	return s.nextTimestamp()
+}
+
+// Snapshot saves the current chain state and returns its identifier that can
+// be used to revert to this state later.
+func (s *Service) Snapshot() uint32 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := s.nextSnapshot
+	s.nextSnapshot++
+	s.snapshots[id] = snapshot{
+		height:     s.chain.BlockHeight(),
+		timeOffset: s.timeOffset,
+	}
+	return id
+}
+
+// Revert restores the chain state saved by Snapshot with the given identifier.
+// This snapshot and all of the ones made after it can't be used after revert.
+func (s *Service) Revert(id uint32) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snap, ok := s.snapshots[id]
+	if !ok {
+		return fmt.Errorf("unknown snapshot %d", id)
+	}
+	err := s.chain.ResetRunning(snap.height)
+	if err != nil {
+		return fmt.Errorf("failed to reset chain to %d: %w", snap.height, err)
+	}
+	s.timeOffset = snap.timeOffset
+	for i := range s.snapshots {
+		if i >= id {
+			delete(s.snapshots, i)
+		}
+	}
+	return nil
+}
+
+// nextTimestamp returns the timestamp for the next block, it's always bigger
+// than the one of the current block.
+func (s *Service) nextTimestamp() uint64 {
+	ts := uint64(time.Now().Add(s.timeOffset).UnixMilli())
+	prev, err := s.chain.GetHeader(s.chain.CurrentBlockHash())
+	if err == nil && ts <= prev.Timestamp {
+		ts = prev.Timestamp + 1
+	}
+	return ts
+}
+
+// mint creates, signs and adds a new block to the chain. If txes are not given,
+// mempooled transactions are used. It must be called with lock held.
+func (s *Service) mint(txes []*transaction.Transaction) (*block.Block, error) {
+	cfg := s.chain.GetConfig()
+	if txes == nil {
+		txes = s.chain.GetMemPool().GetVerifiedTransactions()
+		if len(txes) != 0 {
+			txes = s.chain.ApplyPolicyToTxSet(txes)
+		}
+	}
+	b := &block.Block{
+		Header: block.Header{
+			PrevHash:  s.chain.CurrentBlockHash(),
+			Timestamp: s.nextTimestamp(),
+			Nonce:     rand.Uint64(),
+			Index:     s.chain.BlockHeight() + 1,
+		},
+		Transactions: txes,
+	}
+	if cfg.StateRootInHeader {
+		sr, err := s.chain.GetStateRoot(b.Index - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state root: %w", err)
+		}
+		b.StateRootEnabled = true
+		b.PrevStateRoot = sr.Root
+	}
+	var (
+		validators keys.PublicKeys
+		err        error
+	)
+	if cfg.ShouldUpdateCommitteeAt(b.Index) {
+		validators, err = s.chain.GetValidators()
+	} else {
+		validators, err = s.chain.GetNextBlockValidators()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators: %w", err)
+	}
+	script, err := smartcontract.CreateDefaultMultiSigRedeemScript(validators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multisignature script: %w", err)
+	}
+	b.NextConsensus = hash.Hash160(script)
+	b.RebuildMerkleRoot()
+
+	sig := s.acc.SignHashable(cfg.Magic, b)
+	if sig == nil {
+		return nil, errors.New("failed to sign block")
+	}
+	b.Script = transaction.Witness{
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
+		VerificationScript: s.acc.Contract.Script,
+	}
+	err = s.chain.AddBlock(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add block %d: %w", b.Index, err)
+	}
+	s.log.Debug("new block created", zap.Uint32("index", b.Index), zap.Int("txes", len(b.Transactions)))
+	return b, nil
+}
+
+// prefund creates a block with PrefundNEO and PrefundGAS transfers from the
+// validators account to every other wallet account.
+func (s *Service) prefund() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	neoHash, err := s.chain.GetNativeContractScriptHash(nativenames.Neo)
+	if err != nil {
+		return err
+	}
+	gasHash, err := s.chain.GetNativeContractScriptHash(nativenames.Gas)
+	if err != nil {
+		return err
+	}
+	from := s.acc.ScriptHash()
+	w := io.NewBufBinWriter()
+	for _, to := range s.funded {
+		emit.AppCall(w.BinWriter, neoHash, "transfer", callflag.All, from, to, PrefundNEO, nil)
+		emit.Opcodes(w.BinWriter, opcode.ASSERT)
+		emit.AppCall(w.BinWriter, gasHash, "transfer", callflag.All, from, to, PrefundGAS, nil)
+		emit.Opcodes(w.BinWriter, opcode.ASSERT)
+	}
+	if w.Err != nil {
+		return w.Err
+	}
+
+	cfg := s.chain.GetConfig()
+	tx := transaction.New(w.Bytes(), 0)
+	tx.Nonce = rand.Uint32()
+	tx.ValidUntilBlock = s.chain.BlockHeight() + cfg.MaxValidUntilBlockIncrement
+	tx.Signers = []transaction.Signer{{Account: from, Scopes: transaction.CalledByEntry}}
+
+	ic, err := s.chain.GetTestVM(trigger.Application, tx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create test VM: %w", err)
+	}
+	ic.VM.LoadScriptWithFlags(tx.Script, callflag.All)
+	err = ic.VM.Run()
+	tx.SystemFee = ic.VM.GasConsumed()
+	ic.Finalize()
+	if err != nil {
+		return fmt.Errorf("transfer script failed: %w", err)
+	}
+
+	netFee, sizeDelta := fee.Calculate(s.chain.GetBaseExecFee(), s.acc.Contract.Script)
+	tx.NetworkFee = netFee + int64(io.GetVarSize(tx)+sizeDelta)*s.chain.FeePerByte()
+	err = s.acc.SignTx(cfg.Magic, tx)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	_, err = s.mint([]*transaction.Transaction{tx})
+	if err != nil {
+		return err
+	}
+	s.log.Info("wallet accounts are prefunded", zap.Int("accounts", len(s.funded)))
+	return nil
+}
@@ -0,0 +1,126 @@
+package devnode_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/services/devnode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWallet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	w, err := devnode.NewWallet(path, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(w.Accounts))
+	pub, err := devnode.Validator(w)
+	require.NoError(t, err)
+
+	w2, err := devnode.OpenWallet(path)
+	require.NoError(t, err)
+	require.Equal(t, len(w.Accounts), len(w2.Accounts))
+	for i := range w.Accounts {
+		require.Equal(t, w.Accounts[i].Address, w2.Accounts[i].Address)
+		require.True(t, w2.Accounts[i].CanSign())
+	}
+	pub2, err := devnode.Validator(w2)
+	require.NoError(t, err)
+	require.True(t, pub.Equal(pub2))
+}
+
+func TestService(t *testing.T) {
+	w, err := devnode.NewWallet(filepath.Join(t.TempDir(), "wallet.json"), 2)
+	require.NoError(t, err)
+	pub, err := devnode.Validator(w)
+	require.NoError(t, err)
+	bc, _ := chain.NewSingleWithCustomConfig(t, func(c *config.Blockchain) {
+		c.StandbyCommittee = []string{hex.EncodeToString(pub.Bytes())}
+		c.StateRootInHeader = true
+	})
+
+	s, err := devnode.New(bc, w, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, "devnode", s.Name())
+	s.Start()
+	t.Cleanup(s.Shutdown)
+
+	// Accounts are prefunded by the first block.
+	require.Equal(t, uint32(1), bc.BlockHeight())
+	for _, acc := range w.Accounts[1:] {
+		neo, _ := bc.GetGoverningTokenBalance(acc.ScriptHash())
+		require.Equal(t, big.NewInt(devnode.PrefundNEO), neo)
+		require.Equal(t, big.NewInt(devnode.PrefundGAS), bc.GetUtilityTokenBalance(acc.ScriptHash()))
+	}
+
+	t.Run("auto", func(t *testing.T) {
+		acc := w.Accounts[1]
+		h := bc.BlockHeight()
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.ValidUntilBlock = h + 10
+		tx.NetworkFee = 1_0000_0000
+		tx.Signers = []transaction.Signer{{Account: acc.ScriptHash()}}
+		require.NoError(t, acc.SignTx(bc.GetConfig().Magic, tx))
+		require.NoError(t, bc.PoolTx(tx))
+		require.Eventually(t, func() bool { return bc.BlockHeight() == h+1 }, time.Second, 10*time.Millisecond)
+		_, height, err := bc.GetTransaction(tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, h+1, height)
+	})
+
+	t.Run("mint", func(t *testing.T) {
+		h := bc.BlockHeight()
+		hashes, err := s.MintBlocks(3)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(hashes))
+		require.Equal(t, h+3, bc.BlockHeight())
+		require.Equal(t, hashes[2], bc.CurrentBlockHash())
+	})
+
+	t.Run("time", func(t *testing.T) {
+		ts := s.AdvanceTime(time.Hour)
+		require.GreaterOrEqual(t, ts, uint64(time.Now().Add(time.Hour).UnixMilli())-1000)
+		hashes, err := s.MintBlocks(1)
+		require.NoError(t, err)
+		b, err := bc.GetBlock(hashes[0])
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, b.Timestamp, ts)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		h := bc.BlockHeight()
+		first := s.Snapshot()
+		_, err := s.MintBlocks(2)
+		require.NoError(t, err)
+		second := s.Snapshot()
+		s.AdvanceTime(24 * time.Hour)
+		hashes, err := s.MintBlocks(2)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Revert(second))
+		require.Equal(t, h+2, bc.BlockHeight())
+		_, err = bc.GetBlock(hashes[0])
+		require.Error(t, err)
+		require.Error(t, s.Revert(second)) // Already used.
+
+		// Time offset is restored as well.
+		require.Less(t, s.AdvanceTime(0), uint64(time.Now().Add(12*time.Hour).UnixMilli()))
+
+		require.NoError(t, s.Revert(first))
+		require.Equal(t, h, bc.BlockHeight())
+
+		// The chain is still functional.
+		_, err = s.MintBlocks(1)
+		require.NoError(t, err)
+		require.Equal(t, h+1, bc.BlockHeight())
+
+		require.Error(t, s.Revert(100500))
+	})
+}
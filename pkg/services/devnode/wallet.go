@@ -0,0 +1,88 @@
+package devnode
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// WalletPassword is the password for all accounts of the developer chain wallet.
+const WalletPassword = "one"
+
+// NewWallet creates a developer chain wallet at the given path. It contains a
+// standby validators account (1-of-1 multisignature one with the label
+// "validator") and n single-signature accounts labeled "dev-1", "dev-2" and
+// so on. All accounts are encrypted with WalletPassword and are unlocked in
+// the returned wallet.
+func NewWallet(path string, n int) (*wallet.Wallet, error) {
+	w, err := wallet.NewWallet(path)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := wallet.NewAccount()
+	if err != nil {
+		return nil, err
+	}
+	err = acc.ConvertMultisig(1, keys.PublicKeys{acc.PublicKey()})
+	if err != nil {
+		return nil, err
+	}
+	acc.Label = "validator"
+	err = addAccount(w, acc)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= n; i++ {
+		acc, err := wallet.NewAccount()
+		if err != nil {
+			return nil, err
+		}
+		acc.Label = fmt.Sprintf("dev-%d", i)
+		err = addAccount(w, acc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = w.SavePretty()
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// OpenWallet opens a developer chain wallet created by NewWallet and unlocks
+// all of its accounts.
+func OpenWallet(path string) (*wallet.Wallet, error) {
+	w, err := wallet.NewWalletFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, acc := range w.Accounts {
+		err = acc.Decrypt(WalletPassword, w.Scrypt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt account %s: %w", acc.Address, err)
+		}
+	}
+	return w, nil
+}
+
+// Validator returns the public key of the developer chain validator stored in
+// the given wallet (the first one of its accounts).
+func Validator(w *wallet.Wallet) (*keys.PublicKey, error) {
+	if len(w.Accounts) == 0 || !w.Accounts[0].CanSign() {
+		return nil, fmt.Errorf("no unlocked validator account in %s", w.Path())
+	}
+	return w.Accounts[0].PublicKey(), nil
+}
+
+// addAccount encrypts acc with WalletPassword keeping it unlocked and adds it
+// to w.
+func addAccount(w *wallet.Wallet, acc *wallet.Account) error {
+	err := acc.Encrypt(WalletPassword, w.Scrypt)
+	if err != nil {
+		return err
+	}
+	w.AddAccount(acc)
+	return nil
+}
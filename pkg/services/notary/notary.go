@@ -23,6 +23,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -132,7 +133,7 @@ func (r request) isMainCompleted() bool {
 // NewNotary returns a new Notary module.
 func NewNotary(cfg Config, net netmode.Magic, mp *mempool.Pool, onTransaction func(tx *transaction.Transaction) error) (*Notary, error) {
 	w := cfg.MainCfg.UnlockWallet
-	wallet, err := wallet.NewWalletFromFile(w.Path)
+	wallet, err := extsigner.OpenWallet(w)
 	if err != nil {
 		return nil, err
 	}
@@ -412,8 +413,12 @@ func (n *Notary) PostPersist() {
 
 // finalize adds missing Notary witnesses to the transaction (main or fallback) and pushes it to the network.
 func (n *Notary) finalize(acc *wallet.Account, tx *transaction.Transaction, h util.Uint256) error {
+	sig, err := acc.SignHash(hash.NetSha256(uint32(n.Network), tx))
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
 	notaryWitness := transaction.Witness{
-		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, acc.SignHashable(n.Network, tx)...),
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
 		VerificationScript: []byte{},
 	}
 	for i, signer := range tx.Signers {
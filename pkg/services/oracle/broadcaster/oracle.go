@@ -7,9 +7,10 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
-	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 	"github.com/nspcc-dev/neo-go/pkg/services/helpers/rpcbroadcaster"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
 
@@ -40,10 +41,14 @@ func New(cfg config.OracleConfiguration, log *zap.Logger) *OracleBroadcaster {
 }
 
 // SendResponse implements interfaces.Broadcaster.
-func (r *OracleBroadcaster) SendResponse(priv *keys.PrivateKey, resp *transaction.OracleResponse, txSig []byte) {
-	pub := priv.PublicKey()
+func (r *OracleBroadcaster) SendResponse(signer wallet.Signer, resp *transaction.OracleResponse, txSig []byte) {
+	pub := signer.PublicKey()
 	data := GetMessage(pub.Bytes(), resp.ID, txSig)
-	msgSig := priv.Sign(data)
+	msgSig, err := signer.SignHash(hash.Sha256(data))
+	if err != nil {
+		r.Log.Error("can't sign oracle response", zap.Uint64("id", resp.ID), zap.Error(err))
+		return
+	}
 	params := []any{
 		base64.StdEncoding.EncodeToString(pub.Bytes()),
 		resp.ID,
@@ -2,14 +2,43 @@ package oracle
 
 import (
 	"bytes"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	json "github.com/nspcc-dev/go-ordered-json"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle/jsonpath"
+	"github.com/nspcc-dev/neo-go/pkg/services/oracle/xpath"
 )
 
+// filterFunc applies the filter expression to the response.
+type filterFunc func(value []byte, expr string) ([]byte, error)
+
+// Additional filter languages, filters using them are prefixed with the
+// language name followed by a colon (like "xpath:/a/b").
+var extraFilters = map[string]filterFunc{
+	"xpath": filterXPath,
+	"csv":   filterCSV,
+}
+
+// initFilters enables additional filter languages specified in the
+// configuration.
+func (o *Oracle) initFilters() error {
+	o.filters = make(map[string]filterFunc)
+	for _, name := range o.MainCfg.Filters {
+		f, ok := extraFilters[name]
+		if !ok {
+			return fmt.Errorf("unknown oracle filter %q", name)
+		}
+		o.filters[name] = f
+	}
+	return nil
+}
+
 func filter(value []byte, path string) ([]byte, error) {
 	if !utf8.Valid(value) {
 		return nil, errors.New("not an UTF-8")
@@ -31,9 +60,67 @@ func filter(value []byte, path string) ([]byte, error) {
 	return json.Marshal(result)
 }
 
-func filterRequest(result []byte, req *state.OracleRequest) ([]byte, error) {
-	if req.Filter != nil {
-		return filter(result, *req.Filter)
+// filterXPath selects XML nodes with XPath expression and returns their
+// string values as JSON array.
+func filterXPath(value []byte, path string) ([]byte, error) {
+	result, err := xpath.Get(path, value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+// filterCSV selects a CSV column and returns its values as JSON array. Column
+// is specified either by name (the first record is treated as a header then
+// and is not included into the result) or by zero-based index prefixed with
+// '#'.
+func filterCSV(value []byte, column string) ([]byte, error) {
+	records, err := csv.NewReader(bytes.NewReader(value)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var index = -1
+	if strings.HasPrefix(column, "#") {
+		index, err = strconv.Atoi(column[1:])
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid column index %q", column)
+		}
+		if len(records) != 0 && index >= len(records[0]) {
+			return nil, fmt.Errorf("column %d is out of range", index)
+		}
+	} else {
+		if len(records) == 0 {
+			return nil, errors.New("missing CSV header")
+		}
+		for i, name := range records[0] {
+			if name == column {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		records = records[1:]
+	}
+	var result = make([]string, 0, len(records))
+	for _, r := range records {
+		result = append(result, r[index])
+	}
+	return json.Marshal(result)
+}
+
+// filterRequest applies the request filter to the result. Filters are
+// JSONPath expressions unless prefixed with the name of some enabled
+// additional filter language.
+func (o *Oracle) filterRequest(result []byte, req *state.OracleRequest) ([]byte, error) {
+	if req.Filter == nil {
+		return result, nil
+	}
+	if lang, expr, ok := strings.Cut(*req.Filter, ":"); ok {
+		if f, ok := o.filters[lang]; ok {
+			return f(result, expr)
+		}
 	}
-	return result, nil
+	return filter(result, *req.Filter)
 }
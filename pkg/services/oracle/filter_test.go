@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/stretchr/testify/require"
 )
 
@@ -81,3 +83,59 @@ func TestFilterOOM(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestFilterCSV(t *testing.T) {
+	data := []byte("name,price\nAnvil,50\n\"Elbow, Grease\",99.95\n")
+
+	testCases := []struct {
+		result, column string
+	}{
+		{`["Anvil","Elbow, Grease"]`, "name"},
+		{`["50","99.95"]`, "price"},
+		{`["price","50","99.95"]`, "#1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			actual, err := filterCSV(data, tc.column)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, string(actual))
+		})
+	}
+
+	for _, col := range []string{"unknown", "#2", "#-1", "#x"} {
+		_, err := filterCSV(data, col)
+		require.Error(t, err, col)
+	}
+	_, err := filterCSV([]byte("a,b\n1\n"), "a")
+	require.Error(t, err)
+	_, err = filterCSV(nil, "a")
+	require.Error(t, err)
+}
+
+func TestFilterRequest(t *testing.T) {
+	o := &Oracle{Config: Config{MainCfg: config.OracleConfiguration{Filters: []string{"xpath"}}}}
+	require.NoError(t, o.initFilters())
+
+	check := func(t *testing.T, data, flt, expected string) {
+		req := &state.OracleRequest{Filter: &flt}
+		actual, err := o.filterRequest([]byte(data), req)
+		if expected == "" {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, expected, string(actual))
+	}
+	check(t, `<a><b>1</b></a>`, "xpath:/a/b", `["1"]`)
+	check(t, `{"a":1}`, "$.a", `[1]`)
+	check(t, `{"a":1}`, "$['a']", `[1]`)
+	// CSV is not enabled, so it's treated as a JSONPath.
+	check(t, "a\n1\n", "csv:a", "")
+
+	res, err := o.filterRequest([]byte("raw"), &state.OracleRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "raw", string(res))
+
+	o.MainCfg.Filters = []string{"unknown"}
+	require.Error(t, o.initFilters())
+}
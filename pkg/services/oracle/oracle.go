@@ -18,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"go.uber.org/zap"
 )
 
@@ -67,6 +68,11 @@ type (
 		removed map[uint64]bool
 
 		wallet *wallet.Wallet
+
+		// protocols contains handlers for supported URL schemes.
+		protocols map[string]protocol
+		// filters contains enabled additional filter languages.
+		filters map[string]filterFunc
 	}
 
 	// Config contains oracle module parameters.
@@ -78,6 +84,9 @@ type (
 		Chain           Ledger
 		ResponseHandler Broadcaster
 		OnTransaction   TxCallback
+		// Protocols contains custom handlers for URL schemes, they can
+		// also replace the built-in ones.
+		Protocols map[string]ProtocolHandler
 	}
 
 	// HTTPClient is an interface capable of doing oracle requests.
@@ -87,7 +96,7 @@ type (
 
 	// Broadcaster broadcasts oracle responses.
 	Broadcaster interface {
-		SendResponse(priv *keys.PrivateKey, resp *transaction.OracleResponse, txSig []byte)
+		SendResponse(signer wallet.Signer, resp *transaction.OracleResponse, txSig []byte)
 		Run()
 		Shutdown()
 	}
@@ -145,7 +154,7 @@ func NewOracle(cfg Config) (*Oracle, error) {
 
 	var err error
 	w := cfg.MainCfg.UnlockWallet
-	if o.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
+	if o.wallet, err = extsigner.OpenWallet(w); err != nil {
 		return nil, err
 	}
 
@@ -169,6 +178,12 @@ func NewOracle(cfg Config) (*Oracle, error) {
 	if o.Client == nil {
 		o.Client = getDefaultClient(o.MainCfg)
 	}
+	if err = o.initProtocols(cfg.Protocols); err != nil {
+		return nil, err
+	}
+	if err = o.initFilters(); err != nil {
+		return nil, err
+	}
 	return o, nil
 }
 
@@ -438,7 +438,7 @@ type saveToMapBroadcaster struct {
 	m   map[uint64]*responseWithSig
 }
 
-func (b *saveToMapBroadcaster) SendResponse(_ *keys.PrivateKey, resp *transaction.OracleResponse, txSig []byte) {
+func (b *saveToMapBroadcaster) SendResponse(_ wallet.Signer, resp *transaction.OracleResponse, txSig []byte) {
 	b.mtx.Lock()
 	defer b.mtx.Unlock()
 	b.m[resp.ID] = &responseWithSig{
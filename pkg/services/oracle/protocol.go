@@ -0,0 +1,276 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gio "io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/services/oracle/neofs"
+	"go.uber.org/zap"
+)
+
+type (
+	// ProtocolHandler fetches data for oracle requests with some URL scheme.
+	ProtocolHandler interface {
+		// Fetch returns the data for the request. If an error is returned, the
+		// code must describe it, otherwise the code is transaction.Success and
+		// the reader is closed by the caller after reading the response.
+		// Fetch must respect the context deadline.
+		Fetch(ctx context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error)
+	}
+
+	// ProtocolRequest is an oracle request passed to ProtocolHandler.
+	ProtocolRequest struct {
+		// ID is the oracle request ID.
+		ID uint64
+		// URL is the parsed request URL.
+		URL *url.URL
+		// Attempt is the number of previous attempts to process the request.
+		Attempt int
+		// Key is the oracle node key, it's nil if the key is kept by an
+		// external signer.
+		Key *keys.PrivateKey
+	}
+
+	// protocol is a registered ProtocolHandler with its settings.
+	protocol struct {
+		ProtocolHandler
+		timeout time.Duration
+	}
+
+	// httpProtocol is the handler for https requests.
+	httpProtocol struct {
+		client       HTTPClient
+		contentTypes []string
+	}
+
+	// neofsProtocol is the handler for NeoFS requests.
+	neofsProtocol struct {
+		nodes []string
+	}
+
+	// fileProtocol is the handler for local files.
+	fileProtocol struct {
+		root string
+	}
+
+	// ipfsProtocol is the handler for IPFS requests served via HTTP gateway.
+	ipfsProtocol struct {
+		httpProtocol
+		gateway *url.URL
+	}
+)
+
+// Built-in protocol schemes.
+const (
+	httpsScheme = "https"
+	fileScheme  = "file"
+	ipfsScheme  = "ipfs"
+)
+
+// Handler-specific configuration parameters.
+const (
+	fileRootParam    = "Root"
+	ipfsGatewayParam = "Gateway"
+)
+
+// initProtocols creates built-in protocol handlers according to the
+// configuration and adds custom handlers to them.
+func (o *Oracle) initProtocols(custom map[string]ProtocolHandler) error {
+	o.protocols = make(map[string]protocol)
+	add := func(scheme string, h ProtocolHandler, timeout time.Duration) {
+		cfg := o.MainCfg.Protocols[scheme]
+		if cfg.Disabled {
+			return
+		}
+		if cfg.Timeout != 0 {
+			timeout = cfg.Timeout
+		}
+		o.protocols[scheme] = protocol{ProtocolHandler: h, timeout: timeout}
+	}
+	add(httpsScheme, &httpProtocol{client: o.Client, contentTypes: o.MainCfg.AllowedContentTypes}, o.MainCfg.RequestTimeout)
+	if len(o.MainCfg.NeoFS.Nodes) != 0 {
+		add(neofs.URIScheme, &neofsProtocol{nodes: o.MainCfg.NeoFS.Nodes}, o.MainCfg.NeoFS.Timeout)
+	}
+	if cfg, ok := o.MainCfg.Protocols[fileScheme]; ok && !cfg.Disabled {
+		root := cfg.Parameters[fileRootParam]
+		if root == "" {
+			return fmt.Errorf("%s protocol: %s parameter is required", fileScheme, fileRootParam)
+		}
+		add(fileScheme, &fileProtocol{root: root}, o.MainCfg.RequestTimeout)
+	}
+	if cfg, ok := o.MainCfg.Protocols[ipfsScheme]; ok && !cfg.Disabled {
+		gw, err := url.Parse(cfg.Parameters[ipfsGatewayParam])
+		if err != nil || gw.Scheme == "" || gw.Host == "" {
+			return fmt.Errorf("%s protocol: invalid %s parameter", ipfsScheme, ipfsGatewayParam)
+		}
+		// Gateway is a trusted node-local service, so it can be private.
+		h := &ipfsProtocol{
+			httpProtocol: httpProtocol{client: &http.Client{}, contentTypes: o.MainCfg.AllowedContentTypes},
+			gateway:      gw,
+		}
+		add(ipfsScheme, h, o.MainCfg.RequestTimeout)
+	}
+	for scheme, h := range custom {
+		add(scheme, h, o.MainCfg.RequestTimeout)
+	}
+	return nil
+}
+
+// fetch retrieves data for the request using the handler registered for the
+// URL scheme.
+func (o *Oracle) fetch(req *ProtocolRequest) ([]byte, transaction.OracleResponseCode) {
+	p, ok := o.protocols[req.URL.Scheme]
+	if !ok {
+		o.Log.Warn("unknown oracle request scheme", zap.String("url", req.URL.String()))
+		return nil, transaction.ProtocolNotSupported
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	rc, code, err := p.Fetch(ctx, req)
+	if err != nil {
+		o.Log.Warn("oracle request failed", zap.String("url", req.URL.String()), zap.Error(err), zap.Stringer("code", code))
+		return nil, code
+	}
+	defer rc.Close() // intentionally skip the closing error, it doesn't affect the result.
+	return o.readResponse(rc, req.URL.String())
+}
+
+// Fetch implements the ProtocolHandler interface.
+func (p *httpProtocol) Fetch(ctx context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	return p.get(ctx, req.URL.String())
+}
+
+func (p *httpProtocol) get(ctx context.Context, u string) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, transaction.Error, fmt.Errorf("failed to create http request: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", "NeoOracleService/3.0")
+	httpReq.Header.Set("Content-Type", "application/json")
+	r, err := p.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, ErrRestrictedRedirect) {
+			return nil, transaction.Forbidden, err
+		}
+		return nil, transaction.Error, err
+	}
+	var code transaction.OracleResponseCode
+	switch r.StatusCode {
+	case http.StatusOK:
+		if checkMediaType(r.Header.Get("Content-Type"), p.contentTypes) {
+			return r.Body, transaction.Success, nil
+		}
+		code = transaction.ContentTypeNotSupported
+	case http.StatusForbidden:
+		code = transaction.Forbidden
+	case http.StatusNotFound:
+		code = transaction.NotFound
+	case http.StatusRequestTimeout:
+		code = transaction.Timeout
+	default:
+		code = transaction.Error
+	}
+	r.Body.Close()
+	return nil, code, fmt.Errorf("unexpected response: %s", r.Status)
+}
+
+// Fetch implements the ProtocolHandler interface.
+func (p *neofsProtocol) Fetch(ctx context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	var (
+		index = (int(req.ID) + req.Attempt) % len(p.nodes)
+		key   = req.Key
+	)
+	if key == nil {
+		// NeoFS requests just need to be signed by some key.
+		var err error
+		if key, err = keys.NewPrivateKey(); err != nil {
+			return nil, transaction.Error, err
+		}
+	}
+	rc, err := neofs.Get(ctx, key, req.URL, p.nodes[index])
+	if err != nil {
+		if rc != nil {
+			rc.Close() // intentionally skip the closing error, make it unified with Oracle `https` protocol.
+		}
+		return nil, transaction.Error, err
+	}
+	return rc, transaction.Success, nil
+}
+
+// Fetch implements the ProtocolHandler interface. URL path is relative to the
+// configured root directory, host must be empty or "localhost".
+func (p *fileProtocol) Fetch(_ context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	if req.URL.Host != "" && req.URL.Host != "localhost" {
+		return nil, transaction.Forbidden, errors.New("remote files are not supported")
+	}
+	root, err := filepath.EvalSymlinks(p.root)
+	if err != nil {
+		return nil, transaction.Error, fmt.Errorf("invalid root: %w", err)
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, transaction.Error, fmt.Errorf("invalid root: %w", err)
+	}
+	// Cleaning the rooted path removes all ".." elements, but symlinks can
+	// still point outside of the root, so the path is checked once more
+	// after resolving them.
+	name, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(path.Clean("/"+req.URL.Path))))
+	if err == nil {
+		name, err = filepath.Abs(name)
+	}
+	if err != nil {
+		return nil, fileErrorCode(err), err
+	}
+	if rel, err := filepath.Rel(root, name); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, transaction.Forbidden, errors.New("file is outside of the root directory")
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fileErrorCode(err), err
+	}
+	if fi, err := f.Stat(); err != nil || !fi.Mode().IsRegular() {
+		f.Close()
+		return nil, transaction.NotFound, errors.New("not a regular file")
+	}
+	return f, transaction.Success, nil
+}
+
+// fileErrorCode converts file access error into the response code.
+func fileErrorCode(err error) transaction.OracleResponseCode {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return transaction.NotFound
+	case errors.Is(err, fs.ErrPermission):
+		return transaction.Forbidden
+	default:
+		return transaction.Error
+	}
+}
+
+// Fetch implements the ProtocolHandler interface. It converts
+// "ipfs://<CID>/<path>" URL to the gateway one and performs HTTP request.
+func (p *ipfsProtocol) Fetch(ctx context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	if req.URL.Host == "" {
+		return nil, transaction.Error, errors.New("missing CID")
+	}
+	u := *p.gateway
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ipfs/" + req.URL.Host
+	if req.URL.Path != "" {
+		u.Path += path.Clean("/" + req.URL.Path)
+	}
+	u.RawPath = ""
+	u.RawQuery = req.URL.RawQuery
+	return p.get(ctx, u.String())
+}
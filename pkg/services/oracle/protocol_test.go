@@ -0,0 +1,198 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	gio "io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type (
+	// echoProtocol returns the URL path as the response.
+	echoProtocol struct {
+		deadline bool
+	}
+
+	// urlClient records the requested URL.
+	urlClient struct {
+		url string
+	}
+)
+
+func (p *echoProtocol) Fetch(ctx context.Context, req *ProtocolRequest) (gio.ReadCloser, transaction.OracleResponseCode, error) {
+	_, p.deadline = ctx.Deadline()
+	if req.URL.Path == "/fail" {
+		return nil, transaction.NotFound, errors.New("fail")
+	}
+	return gio.NopCloser(bytes.NewReader([]byte(req.URL.Path))), transaction.Success, nil
+}
+
+func (c *urlClient) Do(req *http.Request) (*http.Response, error) {
+	c.url = req.URL.String()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       gio.NopCloser(bytes.NewReader([]byte("ipfs"))),
+	}, nil
+}
+
+func newTestOracle(t *testing.T, cfg config.OracleConfiguration, protocols map[string]ProtocolHandler) (*Oracle, error) {
+	cfg.UnlockWallet = config.Wallet{Path: "./testdata/oracle1.json", Password: "one"}
+	return NewOracle(Config{
+		Log:       zaptest.NewLogger(t),
+		MainCfg:   cfg,
+		Protocols: protocols,
+	})
+}
+
+func fetchURL(t *testing.T, o *Oracle, s string) ([]byte, transaction.OracleResponseCode) {
+	u, err := url.ParseRequestURI(s)
+	require.NoError(t, err)
+	return o.fetch(&ProtocolRequest{URL: u})
+}
+
+func TestProtocols(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		o, err := newTestOracle(t, config.OracleConfiguration{}, nil)
+		require.NoError(t, err)
+		require.Contains(t, o.protocols, httpsScheme)
+		require.NotContains(t, o.protocols, "neofs") // No nodes configured.
+		require.NotContains(t, o.protocols, fileScheme)
+
+		_, code := fetchURL(t, o, "file:///etc/passwd")
+		require.Equal(t, transaction.ProtocolNotSupported, code)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		o, err := newTestOracle(t, config.OracleConfiguration{
+			Protocols: map[string]config.OracleProtocolConfiguration{httpsScheme: {Disabled: true}},
+		}, nil)
+		require.NoError(t, err)
+		_, code := fetchURL(t, o, "https://example.com")
+		require.Equal(t, transaction.ProtocolNotSupported, code)
+	})
+	t.Run("custom", func(t *testing.T) {
+		h := new(echoProtocol)
+		o, err := newTestOracle(t, config.OracleConfiguration{
+			Protocols: map[string]config.OracleProtocolConfiguration{"echo": {Timeout: time.Minute}},
+		}, map[string]ProtocolHandler{"echo": h})
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, o.protocols["echo"].timeout)
+
+		res, code := fetchURL(t, o, "echo:///some/path")
+		require.Equal(t, transaction.Success, code)
+		require.Equal(t, "/some/path", string(res))
+		require.True(t, h.deadline)
+
+		_, code = fetchURL(t, o, "echo:///fail")
+		require.Equal(t, transaction.NotFound, code)
+	})
+	t.Run("file", func(t *testing.T) {
+		_, err := newTestOracle(t, config.OracleConfiguration{
+			Protocols: map[string]config.OracleProtocolConfiguration{fileScheme: {}},
+		}, nil)
+		require.Error(t, err)
+
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "data.json"), []byte(`{"a":1}`), 0644))
+		require.NoError(t, os.Mkdir(filepath.Join(root, "dir"), 0755))
+		o, err := newTestOracle(t, config.OracleConfiguration{
+			Protocols: map[string]config.OracleProtocolConfiguration{fileScheme: {
+				Parameters: map[string]string{fileRootParam: root},
+			}},
+		}, nil)
+		require.NoError(t, err)
+
+		res, code := fetchURL(t, o, "file:///data.json")
+		require.Equal(t, transaction.Success, code)
+		require.Equal(t, `{"a":1}`, string(res))
+		res, code = fetchURL(t, o, "file://localhost/dir/../data.json")
+		require.Equal(t, transaction.Success, code)
+		require.Equal(t, `{"a":1}`, string(res))
+
+		_, code = fetchURL(t, o, "file:///../../../data.json")
+		require.Equal(t, transaction.Success, code) // Can't escape the root.
+		_, code = fetchURL(t, o, "file:///missing.json")
+		require.Equal(t, transaction.NotFound, code)
+		_, code = fetchURL(t, o, "file:///dir")
+		require.Equal(t, transaction.NotFound, code)
+		_, code = fetchURL(t, o, "file://remote.host/data.json")
+		require.Equal(t, transaction.Forbidden, code)
+
+		t.Run("symlinks", func(t *testing.T) {
+			outside := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.json"), []byte(`{"s":1}`), 0644))
+			if err := os.Symlink(filepath.Join(outside, "secret.json"), filepath.Join(root, "secret.json")); err != nil {
+				t.Skipf("symlinks are not supported: %s", err)
+			}
+			require.NoError(t, os.Symlink(outside, filepath.Join(root, "out")))
+			require.NoError(t, os.Symlink(filepath.Join(root, "data.json"), filepath.Join(root, "dir", "link.json")))
+			require.NoError(t, os.Symlink(filepath.Join(root, "missing.json"), filepath.Join(root, "dangling.json")))
+
+			_, code := fetchURL(t, o, "file:///secret.json")
+			require.Equal(t, transaction.Forbidden, code)
+			_, code = fetchURL(t, o, "file:///out/secret.json")
+			require.Equal(t, transaction.Forbidden, code)
+			_, code = fetchURL(t, o, "file:///dangling.json")
+			require.Equal(t, transaction.NotFound, code)
+			res, code := fetchURL(t, o, "file:///dir/link.json")
+			require.Equal(t, transaction.Success, code)
+			require.Equal(t, `{"a":1}`, string(res))
+
+			// Root itself can be a symlink.
+			rootLink := filepath.Join(t.TempDir(), "root")
+			require.NoError(t, os.Symlink(root, rootLink))
+			o, err := newTestOracle(t, config.OracleConfiguration{
+				Protocols: map[string]config.OracleProtocolConfiguration{fileScheme: {
+					Parameters: map[string]string{fileRootParam: rootLink},
+				}},
+			}, nil)
+			require.NoError(t, err)
+			res, code = fetchURL(t, o, "file:///data.json")
+			require.Equal(t, transaction.Success, code)
+			require.Equal(t, `{"a":1}`, string(res))
+			_, code = fetchURL(t, o, "file:///out/secret.json")
+			require.Equal(t, transaction.Forbidden, code)
+		})
+	})
+	t.Run("ipfs", func(t *testing.T) {
+		_, err := newTestOracle(t, config.OracleConfiguration{
+			Protocols: map[string]config.OracleProtocolConfiguration{ipfsScheme: {
+				Parameters: map[string]string{ipfsGatewayParam: "localhost"},
+			}},
+		}, nil)
+		require.Error(t, err)
+
+		o, err := newTestOracle(t, config.OracleConfiguration{
+			AllowedContentTypes: []string{"text/plain"},
+			Protocols: map[string]config.OracleProtocolConfiguration{ipfsScheme: {
+				Parameters: map[string]string{ipfsGatewayParam: "http://127.0.0.1:8080/"},
+			}},
+		}, nil)
+		require.NoError(t, err)
+		c := new(urlClient)
+		o.protocols[ipfsScheme].ProtocolHandler.(*ipfsProtocol).client = c
+
+		res, code := fetchURL(t, o, "ipfs://bafybeigdyr/dir/file.txt?x=1")
+		require.Equal(t, transaction.Success, code)
+		require.Equal(t, "ipfs", string(res))
+		require.Equal(t, "http://127.0.0.1:8080/ipfs/bafybeigdyr/dir/file.txt?x=1", c.url)
+
+		_, code = fetchURL(t, o, "ipfs://bafybeigdyr")
+		require.Equal(t, transaction.Success, code)
+		require.Equal(t, "http://127.0.0.1:8080/ipfs/bafybeigdyr", c.url)
+
+		_, code = fetchURL(t, o, "ipfs:///path")
+		require.Equal(t, transaction.Error, code)
+	})
+}
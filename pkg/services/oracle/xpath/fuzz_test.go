@@ -0,0 +1,26 @@
+//go:build go1.18
+// +build go1.18
+
+package xpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzGet(f *testing.F) {
+	for _, path := range []string{
+		"/store/book/title",
+		"//book[@id='2']/../*[last()]/@id",
+		"//title[text()='Neo']/../price",
+		"//note/text()[2]",
+		"/store/book[title='Go'][1]/@*",
+	} {
+		f.Add(path, testDoc)
+	}
+	f.Add("//a[1]", `<a><a>1</a><a>2<a>3</a></a></a>`)
+	f.Fuzz(func(t *testing.T, path string, doc string) {
+		require.NotPanics(t, func() { _, _ = Get(path, []byte(doc)) })
+	})
+}
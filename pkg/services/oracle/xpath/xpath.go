@@ -0,0 +1,520 @@
+/*
+Package xpath implements a subset of XPath 1.0 used by oracle response filters.
+
+Supported expressions are location paths consisting of steps separated by '/'
+(child axis) or '//' (descendant axis):
+  - element name (namespace prefix is ignored, names are matched against local
+    names) or '*' for any element;
+  - '@name' or '@*' selecting attributes (only as the last step);
+  - 'text()' selecting the text of an element (only as the last step);
+  - '.' and '..' for the current and parent element.
+
+Element and text() steps can have any number of predicates:
+  - [n] selects the n-th (1-based) node;
+  - [last()] selects the last node;
+  - [@name] selects elements having the attribute;
+  - [@name='value'] selects elements with the given attribute value;
+  - [name='value'] selects elements having a child element with the given
+    string value;
+  - [text()='value'] selects nodes with the given string value.
+
+The result is a list of string values of the selected nodes in the document
+order. String value of an element is the concatenation of all its descendant
+text nodes.
+*/
+package xpath
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// node is an XML document node.
+	node struct {
+		name     string
+		attrs    []xml.Attr
+		text     string
+		isText   bool
+		parent   *node
+		children []*node
+	}
+
+	// step is a single location path step.
+	step struct {
+		descendant bool
+		kind       stepKind
+		name       string
+		predicates []predicate
+	}
+
+	stepKind byte
+
+	// predicate is a node filtering condition.
+	predicate struct {
+		kind  predicateKind
+		name  string
+		value string
+		pos   int
+	}
+
+	predicateKind byte
+)
+
+const (
+	stepElement stepKind = iota
+	stepAttribute
+	stepText
+	stepSelf
+	stepParent
+)
+
+const (
+	predPosition predicateKind = iota
+	predLast
+	predHasAttr
+	predAttrEquals
+	predChildEquals
+	predTextEquals
+)
+
+const (
+	// maxSteps is the maximum number of steps in the path.
+	maxSteps = 32
+	// maxDepth is the maximum document nesting depth.
+	maxDepth = 128
+)
+
+// Get returns string values of the nodes selected by the path in the XML
+// document.
+func Get(path string, data []byte) ([]string, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	root, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	var (
+		nodes = []*node{root}
+		res   = []string{}
+	)
+	for i, s := range steps {
+		switch s.kind {
+		case stepAttribute:
+			if i != len(steps)-1 {
+				return nil, errors.New("attribute step must be the last one")
+			}
+			for _, n := range expand(nodes, s.descendant) {
+				for _, a := range n.attrs {
+					if s.name == "*" || a.Name.Local == s.name {
+						res = append(res, a.Value)
+					}
+				}
+			}
+			return res, nil
+		case stepText:
+			if i != len(steps)-1 {
+				return nil, errors.New("text() step must be the last one")
+			}
+		}
+		nodes = apply(nodes, s)
+	}
+	for _, n := range nodes {
+		if n.isText {
+			res = append(res, n.text)
+		} else if n.parent != nil {
+			res = append(res, n.value())
+		}
+	}
+	return res, nil
+}
+
+// parseDocument builds the node tree from the XML document.
+func parseDocument(data []byte) (*node, error) {
+	var (
+		d     = xml.NewDecoder(bytes.NewReader(data))
+		root  = &node{}
+		curr  = root
+		depth int
+	)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth++; depth > maxDepth {
+				return nil, errors.New("too deep")
+			}
+			n := &node{name: t.Name.Local, attrs: t.Attr, parent: curr}
+			curr.children = append(curr.children, n)
+			curr = n
+		case xml.EndElement:
+			depth--
+			curr = curr.parent
+		case xml.CharData:
+			if curr == root {
+				continue
+			}
+			if l := len(curr.children); l != 0 && curr.children[l-1].isText {
+				curr.children[l-1].text += string(t)
+				continue
+			}
+			curr.children = append(curr.children, &node{text: string(t), isText: true, parent: curr})
+		}
+	}
+	if len(root.children) == 0 {
+		return nil, errors.New("no root element")
+	}
+	return root, nil
+}
+
+// value returns the string value of the node.
+func (n *node) value() string {
+	if n.isText {
+		return n.text
+	}
+	var sb strings.Builder
+	n.writeText(&sb)
+	return sb.String()
+}
+
+func (n *node) writeText(sb *strings.Builder) {
+	for _, c := range n.children {
+		if c.isText {
+			sb.WriteString(c.text)
+		} else {
+			c.writeText(sb)
+		}
+	}
+}
+
+// expand returns the nodes themselves if descendant is false and all the
+// nodes of their subtrees (including themselves) otherwise. The result is in
+// the document order and has no duplicates.
+func expand(nodes []*node, descendant bool) []*node {
+	if !descendant {
+		return nodes
+	}
+	var (
+		res []*node
+		in  = make(map[*node]bool, len(nodes))
+	)
+	for _, n := range nodes {
+		in[n] = true
+	}
+	var walk func(n *node, inside bool)
+	walk = func(n *node, inside bool) {
+		inside = inside || in[n]
+		if inside && !n.isText {
+			res = append(res, n)
+		}
+		for _, c := range n.children {
+			walk(c, inside)
+		}
+	}
+	root := nodes[0]
+	for root.parent != nil {
+		root = root.parent
+	}
+	walk(root, false)
+	return res
+}
+
+// apply performs the step for every context node.
+func apply(nodes []*node, s step) []*node {
+	var (
+		res  []*node
+		seen = make(map[*node]bool)
+	)
+	for _, ctx := range expand(nodes, s.descendant) {
+		var candidates []*node
+		switch s.kind {
+		case stepSelf:
+			candidates = []*node{ctx}
+		case stepParent:
+			if ctx.parent != nil && ctx.parent.parent != nil {
+				candidates = []*node{ctx.parent}
+			}
+		case stepText:
+			for _, c := range ctx.children {
+				if c.isText {
+					candidates = append(candidates, c)
+				}
+			}
+		case stepElement:
+			for _, c := range ctx.children {
+				if !c.isText && (s.name == "*" || c.name == s.name) {
+					candidates = append(candidates, c)
+				}
+			}
+		}
+		for _, p := range s.predicates {
+			candidates = p.filter(candidates)
+		}
+		for _, c := range candidates {
+			if !seen[c] {
+				seen[c] = true
+				res = append(res, c)
+			}
+		}
+	}
+	// Children of nested context nodes can be interleaved.
+	sortByDocumentOrder(res)
+	return res
+}
+
+// sortByDocumentOrder restores the document order of the nodes.
+func sortByDocumentOrder(nodes []*node) {
+	if len(nodes) < 2 {
+		return
+	}
+	var (
+		order = make(map[*node]int, len(nodes))
+		i     int
+		walk  func(n *node)
+	)
+	walk = func(n *node) {
+		order[n] = i
+		i++
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	root := nodes[0]
+	for root.parent != nil {
+		root = root.parent
+	}
+	walk(root)
+	sort.Slice(nodes, func(i, j int) bool { return order[nodes[i]] < order[nodes[j]] })
+}
+
+// filter returns the nodes matching the predicate.
+func (p predicate) filter(nodes []*node) []*node {
+	switch p.kind {
+	case predPosition:
+		if p.pos <= len(nodes) {
+			return nodes[p.pos-1 : p.pos]
+		}
+		return nil
+	case predLast:
+		if len(nodes) != 0 {
+			return nodes[len(nodes)-1:]
+		}
+		return nil
+	}
+	var res []*node
+	for _, n := range nodes {
+		if p.match(n) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func (p predicate) match(n *node) bool {
+	switch p.kind {
+	case predHasAttr, predAttrEquals:
+		for _, a := range n.attrs {
+			if a.Name.Local == p.name && (p.kind == predHasAttr || a.Value == p.value) {
+				return true
+			}
+		}
+	case predChildEquals:
+		for _, c := range n.children {
+			if !c.isText && c.name == p.name && c.value() == p.value {
+				return true
+			}
+		}
+	case predTextEquals:
+		return n.value() == p.value
+	}
+	return false
+}
+
+// parsePath parses the location path.
+func parsePath(path string) ([]step, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("path must be absolute")
+	}
+	var steps []step
+	for len(path) != 0 {
+		if len(steps) == maxSteps {
+			return nil, errors.New("too many steps")
+		}
+		var s step
+		path = path[1:]
+		if strings.HasPrefix(path, "/") {
+			s.descendant = true
+			path = path[1:]
+		}
+		end := stepEnd(path)
+		if err := s.parse(path[:end]); err != nil {
+			return nil, err
+		}
+		steps = append(steps, s)
+		path = path[end:]
+	}
+	return steps, nil
+}
+
+// stepEnd returns the index of the next step separator.
+func stepEnd(path string) int {
+	var quote byte
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '/':
+			return i
+		}
+	}
+	return len(path)
+}
+
+func (s *step) parse(str string) error {
+	test, preds, _ := strings.Cut(str, "[")
+	switch {
+	case test == "":
+		return errors.New("empty step")
+	case test == ".":
+		s.kind = stepSelf
+	case test == "..":
+		s.kind = stepParent
+	case test == "text()":
+		s.kind = stepText
+	case strings.HasPrefix(test, "@"):
+		s.kind = stepAttribute
+		s.name = localName(test[1:])
+		if s.name != "*" && !isName(s.name) {
+			return fmt.Errorf("invalid attribute name %q", test[1:])
+		}
+	default:
+		s.kind = stepElement
+		s.name = localName(test)
+		if s.name != "*" && !isName(s.name) {
+			return fmt.Errorf("invalid element name %q", test)
+		}
+	}
+	if len(preds) == 0 && !strings.HasSuffix(str, "[") {
+		return nil
+	}
+	if s.kind != stepElement && s.kind != stepText {
+		return fmt.Errorf("predicates are not supported for %q", test)
+	}
+	preds = "[" + preds
+	for len(preds) != 0 {
+		if preds[0] != '[' {
+			return fmt.Errorf("invalid predicate %q", preds)
+		}
+		end := predicateEnd(preds)
+		if end < 0 {
+			return fmt.Errorf("unterminated predicate %q", preds)
+		}
+		p, err := parsePredicate(strings.TrimSpace(preds[1:end]))
+		if err != nil {
+			return err
+		}
+		s.predicates = append(s.predicates, p)
+		preds = preds[end+1:]
+	}
+	return nil
+}
+
+// predicateEnd returns the index of the closing bracket of the predicate.
+func predicateEnd(str string) int {
+	var quote byte
+	for i := 1; i < len(str); i++ {
+		switch c := str[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parsePredicate(str string) (predicate, error) {
+	if str == "last()" {
+		return predicate{kind: predLast}, nil
+	}
+	if n, err := strconv.Atoi(str); err == nil {
+		if n < 1 {
+			return predicate{}, fmt.Errorf("invalid position %d", n)
+		}
+		return predicate{kind: predPosition, pos: n}, nil
+	}
+	lhs, rhs, hasValue := strings.Cut(str, "=")
+	lhs = strings.TrimSpace(lhs)
+	var p predicate
+	if hasValue {
+		rhs = strings.TrimSpace(rhs)
+		if len(rhs) < 2 || (rhs[0] != '\'' && rhs[0] != '"') || rhs[len(rhs)-1] != rhs[0] {
+			return p, fmt.Errorf("invalid literal %q", rhs)
+		}
+		p.value = rhs[1 : len(rhs)-1]
+	}
+	switch {
+	case strings.HasPrefix(lhs, "@"):
+		p.kind = predHasAttr
+		if hasValue {
+			p.kind = predAttrEquals
+		}
+		p.name = localName(lhs[1:])
+	case lhs == "text()" && hasValue:
+		p.kind = predTextEquals
+	case hasValue:
+		p.kind = predChildEquals
+		p.name = localName(lhs)
+	default:
+		return p, fmt.Errorf("unsupported predicate %q", str)
+	}
+	if p.kind != predTextEquals && !isName(p.name) {
+		return p, fmt.Errorf("invalid name in predicate %q", str)
+	}
+	return p, nil
+}
+
+// localName strips the namespace prefix.
+func localName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func isName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c > 0x7f:
+		case i != 0 && (c == '-' || c == '.' || c >= '0' && c <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
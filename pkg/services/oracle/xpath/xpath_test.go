@@ -0,0 +1,245 @@
+package xpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `<?xml version="1.0"?>
+<store xmlns:p="urn:test">
+	<book id="1" lang="en">
+		<title>Go</title>
+		<price>10</price>
+	</book>
+	<book id="2">
+		<title>Neo</title>
+		<price>20</price>
+		<p:note>a<b>b</b>c</p:note>
+	</book>
+	<magazine id="3"><title>Weekly</title></magazine>
+</store>`
+
+func TestGet(t *testing.T) {
+	testCases := []struct {
+		path   string
+		result []string
+	}{
+		{"/store/book/title", []string{"Go", "Neo"}},
+		{"/store/book[1]/title", []string{"Go"}},
+		{"/store/book[last()]/price", []string{"20"}},
+		{"/store/book[3]/title", []string{}},
+		{"//title", []string{"Go", "Neo", "Weekly"}},
+		{"/store/*/title", []string{"Go", "Neo", "Weekly"}},
+		{"/store/*/@id", []string{"1", "2", "3"}},
+		{"//book/@*", []string{"1", "en", "2"}},
+		{"/store/book[@lang]/title", []string{"Go"}},
+		{"/store/book[@id='2']/price", []string{"20"}},
+		{`/store/book[@id="2"][1]/price`, []string{"20"}},
+		{"/store/book[title='Go']/@id", []string{"1"}},
+		{"//title[text()='Neo']/../price", []string{"20"}},
+		{"/store/book/p:note", []string{"abc"}},
+		{"//note/text()", []string{"a", "c"}},
+		{"//note/text()[2]", []string{"c"}},
+		{"//b/.", []string{"b"}},
+		{"/store/book[price='10']/..//magazine/title", []string{"Weekly"}},
+		{"/store/nothing", []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			res, err := Get(tc.path, []byte(testDoc))
+			require.NoError(t, err)
+			require.Equal(t, tc.result, res)
+		})
+	}
+}
+
+func TestGetInvalid(t *testing.T) {
+	paths := []string{
+		"",
+		"store",
+		"/",
+		"/store//",
+		"/store/book[",
+		"/store/book[0]",
+		"/store/book[@id=2]",
+		"/store/book[@id='2]",
+		"/store/book[count(a)]",
+		"/store/@id/book",
+		"/store/text()/a",
+		"/store/@id[1]",
+		"/store/1book",
+		"/" + strings.Repeat("a/", maxSteps) + "a",
+		"//",
+		"/store/",
+		"/store/@",
+		"/store/book[]",
+		"/store/book[-1]",
+		"/store/book[1]x",
+		"/store/book]",
+		"/store/book[@]",
+		"/store/book[@='1']",
+		"/store/book[='1']",
+		"/store/book[text()]",
+		"/store/book[title]",
+		"/store/book['1']",
+		`/store/book[@id="1']`,
+		"/store/book[@id='1'",
+		"/store/book[@1d='1']",
+		"/store/book[ti tle='Go']",
+		"/store/.[1]",
+		"/store/..[1]",
+		"/store/book/@id/..",
+		"/store/book/text()/..",
+		"/store/book/a:",
+	}
+	for _, p := range paths {
+		t.Run(p, func(t *testing.T) {
+			_, err := Get(p, []byte(testDoc))
+			require.Error(t, err)
+		})
+	}
+
+	docs := []string{
+		"",
+		"text",
+		"<a><b></a>",
+		"<a>",
+		"<a>&unknown;</a>",
+		"<!-- only a comment -->",
+		strings.Repeat("<a>", maxDepth+1) + strings.Repeat("</a>", maxDepth+1),
+	}
+	for _, d := range docs {
+		_, err := Get("/a", []byte(d))
+		require.Error(t, err)
+	}
+}
+
+func TestGetNested(t *testing.T) {
+	const doc = `<a><a>1</a><a>2<a>3</a></a></a>`
+
+	testCases := []struct {
+		path   string
+		result []string
+	}{
+		{"/a", []string{"123"}},
+		{"/a/a", []string{"1", "23"}},
+		{"//a", []string{"123", "1", "23", "3"}},
+		// Every node is returned once in the document order.
+		{"//a/a", []string{"1", "23", "3"}},
+		{"//a//a", []string{"1", "23", "3"}},
+		{"/a//a", []string{"1", "23", "3"}},
+		// Positional predicates apply to the children of every context node.
+		{"//a[1]", []string{"123", "1", "3"}},
+		{"//a[last()]", []string{"123", "23", "3"}},
+		{"//a[2]", []string{"23"}},
+		{"//a/..", []string{"123", "23"}},
+		{"//a/.", []string{"123", "1", "23", "3"}},
+		{"//text()", []string{"1", "2", "3"}},
+		{"/a/a/text()", []string{"1", "2"}},
+		{"//a[text()='2']", []string{}},
+		{"//a[text()='23']/a", []string{"3"}},
+		{"//a[a='3']", []string{"23"}},
+		{"//a[a='1'][a='23']", []string{"123"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			res, err := Get(tc.path, []byte(doc))
+			require.NoError(t, err)
+			require.Equal(t, tc.result, res)
+		})
+	}
+}
+
+func TestGetDocuments(t *testing.T) {
+	testCases := []struct {
+		doc    string
+		path   string
+		result []string
+	}{
+		{`<a/>`, "/a", []string{""}},
+		{`<a></a>`, "/a/text()", []string{}},
+		{`<a>x &amp; y<![CDATA[<z>]]></a>`, "/a", []string{"x & y<z>"}},
+		{`<a>x &amp; y<![CDATA[<z>]]></a>`, "/a/text()", []string{"x & y<z>"}},
+		{`<a>1<!-- comment -->2<?pi data?>3</a>`, "/a", []string{"123"}},
+		{`<a><b>1</b> <b>2</b></a>`, "/a", []string{"1 2"}},
+		{`<a><b>1</b> <b>2</b></a>`, "/a/text()", []string{" "}},
+		{`<?xml version="1.0"?><!DOCTYPE a><a>1</a>`, "/a", []string{"1"}},
+		{`<a b="1" c="2" b2="3"/>`, "/a/@*", []string{"1", "2", "3"}},
+		{`<a b="&lt;&quot;&gt;"/>`, "/a/@b", []string{`<">`}},
+		{`<a><b c="1"/><b/><b c="2"/></a>`, "/a/b[@c][2]/@c", []string{"2"}},
+		{`<a><b c="1"/><b/><b c="2"/></a>`, "/a/b[2][@c]", []string{}},
+		{`<a><b c="1"/><b/><b c="2"/></a>`, "//@c", []string{"1", "2"}},
+		{`<a><b c="x]/y">1</b><b c="it's">2</b></a>`, "/a/b[@c='x]/y']", []string{"1"}},
+		{`<a><b c="x]/y">1</b><b c="it's">2</b></a>`, `/a/b[@c="it's"]`, []string{"2"}},
+		{`<a><b c="1">1</b></a>`, "/a/b[ @c = '1' ]", []string{"1"}},
+		{`<a><b c=""/></a>`, "/a/b[@c='']/@c", []string{""}},
+		{`<a><b>1</b><c>1</c></a>`, "/a/*[text()='1']", []string{"1", "1"}},
+		{`<a><b><c>x</c></b></a>`, "/a[b='x']", []string{"x"}},
+		{`<données><élément>1</élément></données>`, "/données/élément", []string{"1"}},
+		{`<a-b.c1><_d>1</_d></a-b.c1>`, "/a-b.c1/_d", []string{"1"}},
+		{`<r xmlns="urn:x" xmlns:q="urn:q"><q:i q:k="v">1</q:i><i k="w">2</i></r>`, "/r/i", []string{"1", "2"}},
+		{`<r xmlns="urn:x" xmlns:q="urn:q"><q:i q:k="v">1</q:i><i k="w">2</i></r>`, "/r/q:i/@k", []string{"v", "w"}},
+		{`<r xmlns="urn:x" xmlns:q="urn:q"><q:i q:k="v">1</q:i><i k="w">2</i></r>`, "/r/x:i[@q:k='w']", []string{"2"}},
+		{`<a><b>1</b></a>`, "/.", []string{}},
+		{`<a><b>1</b></a>`, "/..", []string{}},
+		{`<a><b>1</b></a>`, "/a/..", []string{}},
+		{`<a><b>1</b></a>`, "/text()", []string{}},
+		{`<a><b>1</b></a>`, "/b", []string{}},
+		{`<a><b>1</b></a>`, "/*", []string{"1"}},
+		{`<a><b>1</b></a>`, "/a/b[1][1][last()]", []string{"1"}},
+		{`<a><b>1</b></a>`, "/a/b[2][1]", []string{}},
+		{`<a><b>1</b></a>`, "/a/b/text()[1]", []string{"1"}},
+		{`<a><b>1</b></a>`, "/a/b/text()[text()='1']", []string{"1"}},
+		{`<a><b>1</b></a>`, "/a/b/text()[@c]", []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc+tc.path, func(t *testing.T) {
+			res, err := Get(tc.path, []byte(tc.doc))
+			require.NoError(t, err)
+			require.Equal(t, tc.result, res)
+		})
+	}
+}
+
+func TestGetLimits(t *testing.T) {
+	doc := strings.Repeat("<a>", maxDepth) + "1" + strings.Repeat("</a>", maxDepth)
+	res, err := Get("/"+strings.Repeat("a/", maxSteps-1)+"a", []byte(doc))
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, res)
+
+	res, err = Get("//a[last()]/text()", []byte(doc))
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, res)
+
+	res, err = Get("//a", []byte(doc))
+	require.NoError(t, err)
+	require.Equal(t, maxDepth, len(res))
+}
+
+func TestParsePath(t *testing.T) {
+	steps, err := parsePath(`//p:a[2][@b]/c[@d='/]'][e="x"][text()='y'][last()]/text()`)
+	require.NoError(t, err)
+	require.Equal(t, []step{
+		{descendant: true, kind: stepElement, name: "a", predicates: []predicate{
+			{kind: predPosition, pos: 2},
+			{kind: predHasAttr, name: "b"},
+		}},
+		{kind: stepElement, name: "c", predicates: []predicate{
+			{kind: predAttrEquals, name: "d", value: "/]"},
+			{kind: predChildEquals, name: "e", value: "x"},
+			{kind: predTextEquals, value: "y"},
+			{kind: predLast},
+		}},
+		{kind: stepText},
+	}, steps)
+
+	steps, err = parsePath("/./../@*")
+	require.NoError(t, err)
+	require.Equal(t, []step{
+		{kind: stepSelf},
+		{kind: stepParent},
+		{kind: stepAttribute, name: "*"},
+	}, steps)
+}
@@ -2723,3 +2723,333 @@ func TestClient_GetStorageHistoric(t *testing.T) {
 	_, err = c.GetStorageByHashHistoric(earlyRoot.Root, h, key)
 	require.ErrorIs(t, neorpc.ErrUnknownStorageItem, err)
 }
+
+func TestClient_ExecutionIndex(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.Ledger.ExecutionIndex = true
+	})
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	neoHash := neo.Hash
+	acc := testchain.PrivateKeyByID(0).GetScriptHash()
+	var (
+		expectedNotifications []result.FoundNotification
+		expectedTxs           []result.FoundTransaction
+	)
+	addNotifications := func(index uint32, container util.Uint256, aer *state.AppExecResult) {
+		for j, ev := range aer.Events {
+			if aer.VMState == vmstate.Halt && ev.ScriptHash == neoHash && ev.Name == "Transfer" {
+				expectedNotifications = append(expectedNotifications, result.FoundNotification{
+					BlockIndex:  index,
+					Container:   container,
+					Trigger:     aer.Trigger,
+					NotifyIndex: uint32(j),
+					Event:       ev,
+				})
+			}
+		}
+	}
+	for i := uint32(0); i <= chain.BlockHeight(); i++ {
+		b, err := chain.GetBlock(chain.GetHeaderHash(i))
+		require.NoError(t, err)
+		baers, err := chain.GetAppExecResults(b.Hash(), trigger.All)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(baers))
+		addNotifications(i, b.Hash(), &baers[0])
+		for _, tx := range b.Transactions {
+			for j, s := range tx.Signers {
+				if s.Account == acc {
+					expectedTxs = append(expectedTxs, result.FoundTransaction{Hash: tx.Hash(), BlockIndex: i, Sender: j == 0})
+				}
+			}
+			aers, err := chain.GetAppExecResults(tx.Hash(), trigger.Application)
+			require.NoError(t, err)
+			addNotifications(i, tx.Hash(), &aers[0])
+		}
+		addNotifications(i, b.Hash(), &baers[1])
+	}
+	require.NotEqual(t, 0, len(expectedNotifications))
+	require.NotEqual(t, 0, len(expectedTxs))
+
+	t.Run("notifications", func(t *testing.T) {
+		res, err := c.FindNotifications(neoHash, "Transfer", 0, chain.BlockHeight(), nil, nil)
+		require.NoError(t, err)
+		require.False(t, res.Truncated)
+		require.Equal(t, expectedNotifications, res.Notifications)
+
+		limit, page := 1, 1
+		res, err = c.FindNotifications(neoHash, "Transfer", 0, chain.BlockHeight(), &limit, &page)
+		require.NoError(t, err)
+		require.True(t, res.Truncated)
+		require.Equal(t, expectedNotifications[1:2], res.Notifications)
+
+		last := expectedNotifications[len(expectedNotifications)-1].BlockIndex
+		res, err = c.FindNotifications(neoHash, "Transfer", last, last, nil, nil)
+		require.NoError(t, err)
+		for _, n := range res.Notifications {
+			require.Equal(t, last, n.BlockIndex)
+		}
+
+		res, err = c.FindNotifications(neoHash, "", 0, 0, nil, nil) // Genesis block has no transactions.
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(res.Notifications))
+		for _, n := range res.Notifications {
+			require.NotEqual(t, trigger.Application, n.Trigger)
+			require.Equal(t, chain.GetHeaderHash(0), n.Container)
+		}
+	})
+	t.Run("transactions", func(t *testing.T) {
+		res, err := c.FindTransactions(acc, 0, chain.BlockHeight(), nil, nil)
+		require.NoError(t, err)
+		require.False(t, res.Truncated)
+		require.Equal(t, address.Uint160ToString(acc), res.Address)
+		require.Equal(t, expectedTxs, res.Transactions)
+
+		limit := 2
+		res, err = c.FindTransactions(acc, 0, chain.BlockHeight(), &limit, nil)
+		require.NoError(t, err)
+		require.True(t, res.Truncated)
+		require.Equal(t, expectedTxs[:2], res.Transactions)
+	})
+	t.Run("bad parameters", func(t *testing.T) {
+		_, err := c.FindTransactions(acc, 5, 1, nil, nil)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+		limit := rpcSrv.config.MaxFindResultItems + 1
+		_, err = c.FindNotifications(neoHash, "", 0, 1, &limit, nil)
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+}
+
+func TestClient_ExecutionIndexUntraceable(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.Ledger.ExecutionIndex = true
+		c.ApplicationConfiguration.Ledger.RemoveUntraceableBlocks = true
+		c.ProtocolConfiguration.MaxTraceableBlocks = 10
+	})
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	limit := rpcSrv.config.MaxFindResultItems
+	all, err := c.FindNotifications(gas.Hash, "Transfer", 0, chain.BlockHeight(), &limit, nil)
+	require.NoError(t, err)
+	require.False(t, all.Truncated)
+	require.True(t, len(all.Notifications) > 2)
+	// Notifications of removed blocks and transactions are not returned.
+	var untraceable int
+	for i := uint32(1); i <= chain.BlockHeight(); i++ {
+		if aers, _ := chain.GetAppExecResults(chain.GetHeaderHash(i), trigger.All); len(aers) == 0 {
+			untraceable++
+		}
+	}
+	require.NotEqual(t, 0, untraceable)
+	for _, n := range all.Notifications {
+		aers, err := chain.GetAppExecResults(n.Container, trigger.All)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(aers))
+	}
+
+	// Pages are counted over returned notifications only.
+	limit = 1
+	for page := range all.Notifications {
+		res, err := c.FindNotifications(gas.Hash, "Transfer", 0, chain.BlockHeight(), &limit, &page)
+		require.NoError(t, err)
+		require.Equal(t, all.Notifications[page:page+1], res.Notifications)
+	}
+}
+
+func TestClient_ExecutionIndexDisabled(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	_, err = c.FindNotifications(neo.Hash, "", 0, 0, nil, nil)
+	require.ErrorContains(t, err, core.ErrExecutionIndexDisabled.Error())
+	_, err = c.FindTransactions(util.Uint160{}, 0, 0, nil, nil)
+	require.ErrorContains(t, err, core.ErrExecutionIndexDisabled.Error())
+}
+
+func TestClient_InvokeWithOverrides(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	rubles, err := util.Uint160DecodeStringLE(testContractHash)
+	require.NoError(t, err)
+	acc := testchain.PrivateKeyByID(0).GetScriptHash()
+	other := util.Uint160{1, 2, 3}
+	rubReader := nep17.NewReader(invoker.New(c, nil), rubles)
+	accBalance, err := rubReader.BalanceOf(acc)
+	require.NoError(t, err)
+
+	t.Run("storage", func(t *testing.T) {
+		inv := invoker.NewWithOverrides(&neorpc.StateOverrides{
+			Storage: []neorpc.StorageOverride{
+				{Contract: rubles, Key: other.BytesBE(), Value: bigint.ToBytes(big.NewInt(100500))},
+				{Contract: rubles, Key: acc.BytesBE()},
+			},
+		}, c, nil)
+		r := nep17.NewReader(inv, rubles)
+		b, err := r.BalanceOf(other)
+		require.NoError(t, err)
+		require.EqualValues(t, 100500, b.Int64())
+		b, err = r.BalanceOf(acc)
+		require.NoError(t, err)
+		require.EqualValues(t, 0, b.Sign())
+
+		// The chain itself is not changed.
+		b, err = rubReader.BalanceOf(other)
+		require.NoError(t, err)
+		require.EqualValues(t, 0, b.Sign())
+		b, err = rubReader.BalanceOf(acc)
+		require.NoError(t, err)
+		require.Equal(t, accBalance, b)
+	})
+	t.Run("balances", func(t *testing.T) {
+		neoSupply, err := neo.NewReader(invoker.New(c, nil)).TotalSupply()
+		require.NoError(t, err)
+		neoBalance, err := neo.NewReader(invoker.New(c, nil)).BalanceOf(acc)
+		require.NoError(t, err)
+
+		inv := invoker.NewWithOverrides(&neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{
+				{Token: gas.Hash, Account: other, Amount: "100500"},
+				{Token: neo.Hash, Account: acc, Amount: "1"},
+			},
+		}, c, nil)
+		b, err := gas.NewReader(inv).BalanceOf(other)
+		require.NoError(t, err)
+		require.EqualValues(t, 100500, b.Int64())
+		b, err = neo.NewReader(inv).BalanceOf(acc)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, b.Int64())
+		s, err := neo.NewReader(inv).TotalSupply()
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Sub(neoSupply, new(big.Int).Sub(neoBalance, big.NewInt(1))), s)
+
+		_, err = invoker.NewWithOverrides(&neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{Token: rubles, Account: other, Amount: "1"}},
+		}, c, nil).Call(gas.Hash, "symbol")
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+		_, err = invoker.NewWithOverrides(&neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{Token: gas.Hash, Account: other, Amount: "-1"}},
+		}, c, nil).Call(gas.Hash, "symbol")
+		require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+	})
+	t.Run("contracts", func(t *testing.T) {
+		nns, err := c.GetContractStateByID(basicchain.NNSContractID)
+		require.NoError(t, err)
+		nefBytes, err := nns.NEF.Bytes()
+		require.NoError(t, err)
+		newHash := util.Uint160{9, 9, 9}
+
+		inv := invoker.NewWithOverrides(&neorpc.StateOverrides{
+			Contracts: []neorpc.ContractOverride{
+				{Hash: rubles, NEF: nefBytes, Manifest: &nns.Manifest},
+				{Hash: newHash, NEF: nefBytes, Manifest: &nns.Manifest},
+			},
+		}, c, nil)
+		for _, h := range []util.Uint160{rubles, newHash} {
+			res, err := inv.Call(h, "symbol")
+			require.NoError(t, err)
+			require.Equal(t, vmstate.Halt.String(), res.State, res.FaultException)
+			require.Equal(t, []stackitem.Item{stackitem.Make("NNS")}, res.Stack)
+		}
+		sym, err := rubReader.Symbol()
+		require.NoError(t, err)
+		require.Equal(t, "RUB", sym)
+
+		for _, o := range []neorpc.ContractOverride{
+			{Hash: gas.Hash, NEF: nefBytes, Manifest: &nns.Manifest}, // Native.
+			{Hash: newHash, NEF: nefBytes},                           // New contract without manifest.
+			{Hash: rubles},                                           // Nothing to override.
+			{Hash: rubles, NEF: []byte{1, 2, 3}},                     // Bad NEF.
+		} {
+			_, err = invoker.NewWithOverrides(&neorpc.StateOverrides{
+				Contracts: []neorpc.ContractOverride{o},
+			}, c, nil).Call(rubles, "symbol")
+			require.ErrorIs(t, err, neorpc.ErrInvalidParams)
+		}
+	})
+	t.Run("script", func(t *testing.T) {
+		script, err := smartcontract.CreateCallScript(gas.Hash, "balanceOf", other)
+		require.NoError(t, err)
+		res, err := c.InvokeScriptWithOverrides(script, nil, &neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{Token: gas.Hash, Account: other, Amount: "42"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res.Stack)
+	})
+	t.Run("NEO transfer", func(t *testing.T) {
+		// No GAS is accrued for the overridden NEO balance.
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, neo.Hash, "transfer", callflag.All, other, acc, 1, nil)
+		emit.Opcodes(w.BinWriter, opcode.ASSERT)
+		emit.AppCall(w.BinWriter, gas.Hash, "balanceOf", callflag.ReadStates, other)
+		require.NoError(t, w.Err)
+		res, err := c.InvokeScriptWithOverrides(w.Bytes(), []transaction.Signer{{
+			Account: other,
+			Scopes:  transaction.CalledByEntry,
+		}}, &neorpc.StateOverrides{
+			Balances: []neorpc.BalanceOverride{{Token: neo.Hash, Account: other, Amount: "1000"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vmstate.Halt.String(), res.State, res.FaultException)
+		require.Equal(t, []stackitem.Item{stackitem.Make(0)}, res.Stack)
+	})
+}
+
+func TestClient_TraceTransaction(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	h, err := util.Uint256DecodeStringLE(deploymentTxHash)
+	require.NoError(t, err)
+	appLog, err := c.GetApplicationLog(h, nil)
+	require.NoError(t, err)
+
+	res, err := c.TraceTransaction(h, nil)
+	require.NoError(t, err)
+	require.Equal(t, appLog.Executions[0].VMState, res.VMState)
+	require.Equal(t, appLog.Executions[0].GasConsumed, res.GasConsumed)
+	require.Equal(t, appLog.Executions[0].Events, res.Notifications)
+	require.NotEmpty(t, res.StorageReads)
+	require.NotEmpty(t, res.StorageChanges)
+
+	res, err = c.TraceTransaction(h, &neorpc.TraceOptions{Detail: neorpc.TraceDetailFull, MaxSteps: 10})
+	require.NoError(t, err)
+	require.True(t, res.Trace.Truncated)
+	require.Equal(t, 10, len(res.Trace.Steps))
+	_, err = res.Trace.StateAt(10)
+	require.NoError(t, err)
+
+	_, err = c.TraceTransaction(util.Uint256{1, 2, 3}, nil)
+	require.ErrorIs(t, err, neorpc.ErrUnknownTransaction)
+}
@@ -0,0 +1,105 @@
+package rpcsrv
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// DevHandler is the interface developer chain block producer needs to provide
+// for the Server to handle developer mode methods.
+type DevHandler interface {
+	MintBlocks(n int) ([]util.Uint256, error)
+	AdvanceTime(d time.Duration) uint64
+	Snapshot() uint32
+	Revert(id uint32) error
+}
+
+// maxMintBlocks is the maximum number of blocks that can be created with a
+// single mintblocks call.
+const maxMintBlocks = 1000
+
+// SetDevHandler allows to update developer mode handler used by the Server,
+// developer mode methods are only available if it's set.
+func (s *Server) SetDevHandler(dev DevHandler) {
+	s.dev.Store(dev)
+}
+
+func (s *Server) getDevHandler() (DevHandler, *neorpc.Error) {
+	devPtr := s.dev.Load()
+	if devPtr == nil {
+		return nil, neorpc.ErrDevModeDisabled
+	}
+	return devPtr.(DevHandler), nil
+}
+
+// mintBlocks creates the given number of blocks (1 by default) and returns
+// their hashes.
+func (s *Server) mintBlocks(reqParams params.Params) (any, *neorpc.Error) {
+	dev, respErr := s.getDevHandler()
+	if respErr != nil {
+		return nil, respErr
+	}
+	var n = 1
+	if len(reqParams) > 0 {
+		var err error
+		n, err = reqParams[0].GetInt()
+		if err != nil {
+			return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("invalid block count: %s", err))
+		}
+		if n <= 0 || n > maxMintBlocks {
+			return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("block count should be in [1, %d] range", maxMintBlocks))
+		}
+	}
+	hashes, err := dev.MintBlocks(n)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to mint blocks: %s", err))
+	}
+	return hashes, nil
+}
+
+// advanceTime shifts timestamps of subsequent blocks by the given number of
+// seconds and returns the timestamp of the next block.
+func (s *Server) advanceTime(reqParams params.Params) (any, *neorpc.Error) {
+	dev, respErr := s.getDevHandler()
+	if respErr != nil {
+		return nil, respErr
+	}
+	secs, err := reqParams.Value(0).GetInt()
+	if err != nil {
+		return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("invalid number of seconds: %s", err))
+	}
+	if secs < 0 {
+		return nil, neorpc.NewInvalidParamsError("time can't be moved backwards")
+	}
+	return dev.AdvanceTime(time.Duration(secs) * time.Second), nil
+}
+
+// snapshot saves the current chain state and returns its identifier.
+func (s *Server) snapshot(_ params.Params) (any, *neorpc.Error) {
+	dev, respErr := s.getDevHandler()
+	if respErr != nil {
+		return nil, respErr
+	}
+	return dev.Snapshot(), nil
+}
+
+// revert restores the chain state saved by snapshot.
+func (s *Server) revert(reqParams params.Params) (any, *neorpc.Error) {
+	dev, respErr := s.getDevHandler()
+	if respErr != nil {
+		return nil, respErr
+	}
+	id, err := reqParams.Value(0).GetInt()
+	if err != nil || id < 0 || uint64(id) > uint64(^uint32(0)) {
+		return nil, neorpc.NewInvalidParamsError("invalid snapshot ID")
+	}
+	err = dev.Revert(uint32(id))
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+	}
+	return true, nil
+}
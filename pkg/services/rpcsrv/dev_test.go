@@ -0,0 +1,92 @@
+package rpcsrv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type devStub struct {
+	minted    int
+	offset    time.Duration
+	snapshots uint32
+	reverted  []uint32
+}
+
+func (d *devStub) MintBlocks(n int) ([]util.Uint256, error) {
+	res := make([]util.Uint256, n)
+	for i := range res {
+		d.minted++
+		res[i] = util.Uint256{byte(d.minted)}
+	}
+	return res, nil
+}
+
+func (d *devStub) AdvanceTime(dur time.Duration) uint64 {
+	d.offset += dur
+	return uint64(d.offset.Milliseconds())
+}
+
+func (d *devStub) Snapshot() uint32 {
+	d.snapshots++
+	return d.snapshots - 1
+}
+
+func (d *devStub) Revert(id uint32) error {
+	if id >= d.snapshots {
+		return errors.New("unknown snapshot")
+	}
+	d.reverted = append(d.reverted, id)
+	return nil
+}
+
+func TestDevHandler(t *testing.T) {
+	_, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	dev := new(devStub)
+	rpcSrv.SetDevHandler(dev)
+
+	call := func(t *testing.T, method string, params string, fail bool, errCode int64) json.RawMessage {
+		req := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "%s", "params": %s}`, method, params)
+		return checkErrGetResult(t, doRPCCallOverHTTP(req, httpSrv.URL, t), fail, errCode)
+	}
+
+	t.Run("mintblocks", func(t *testing.T) {
+		var hashes []util.Uint256
+		require.NoError(t, json.Unmarshal(call(t, "mintblocks", `[]`, false, 0), &hashes))
+		require.Equal(t, []util.Uint256{{1}}, hashes)
+		require.NoError(t, json.Unmarshal(call(t, "mintblocks", `[2]`, false, 0), &hashes))
+		require.Equal(t, []util.Uint256{{2}, {3}}, hashes)
+
+		call(t, "mintblocks", `[0]`, true, neorpc.InvalidParamsCode)
+		call(t, "mintblocks", `[100500]`, true, neorpc.InvalidParamsCode)
+		call(t, "mintblocks", `["one"]`, true, neorpc.InvalidParamsCode)
+	})
+	t.Run("advancetime", func(t *testing.T) {
+		var ts uint64
+		require.NoError(t, json.Unmarshal(call(t, "advancetime", `[60]`, false, 0), &ts))
+		require.Equal(t, uint64(60000), ts)
+
+		call(t, "advancetime", `[]`, true, neorpc.InvalidParamsCode)
+		call(t, "advancetime", `[-1]`, true, neorpc.InvalidParamsCode)
+	})
+	t.Run("snapshot and revert", func(t *testing.T) {
+		var id uint32
+		require.NoError(t, json.Unmarshal(call(t, "snapshot", `[]`, false, 0), &id))
+		require.Equal(t, uint32(0), id)
+
+		var ok bool
+		require.NoError(t, json.Unmarshal(call(t, "revert", `[0]`, false, 0), &ok))
+		require.True(t, ok)
+		require.Equal(t, []uint32{0}, dev.reverted)
+
+		call(t, "revert", `[]`, true, neorpc.InvalidParamsCode)
+		call(t, "revert", `[-1]`, true, neorpc.InvalidParamsCode)
+		call(t, "revert", `[1]`, true, neorpc.InvalidParamsCode)
+	})
+}
@@ -48,6 +48,10 @@ func getHTTPCodeForError(respErr *neorpc.Error) int {
 		httpCode = http.StatusMethodNotAllowed
 	case neorpc.InternalServerErrorCode:
 		httpCode = http.StatusInternalServerError
+	case neorpc.ErrUnauthorizedCode:
+		httpCode = http.StatusUnauthorized
+	case neorpc.ErrRateLimitExceededCode:
+		httpCode = http.StatusTooManyRequests
 	default:
 		httpCode = http.StatusUnprocessableEntity
 	}
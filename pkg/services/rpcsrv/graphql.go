@@ -0,0 +1,1091 @@
+package rpcsrv
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gio "io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/zap"
+)
+
+// GraphQL query limits, the complexity budget is proportional to
+// MaxFindResultItems which is the default (and maximum) size of paged lists.
+// It allows to fetch a full page of items with a full page of subitems each,
+// but not to go further without explicit limits.
+const (
+	// graphQLMaxDepth is the maximum nesting level of GraphQL queries.
+	graphQLMaxDepth = 10
+	// graphQLComplexityFactor is the maximum query complexity per
+	// MaxFindResultItems.
+	graphQLComplexityFactor = 1000
+	// graphQLMaxAliases is the maximum number of aliased fields in the
+	// query.
+	graphQLMaxAliases = 32
+)
+
+type (
+	// gqlRequest is a GraphQL request as defined by the GraphQL over HTTP
+	// specification.
+	gqlRequest struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName,omitempty"`
+		Variables     map[string]any `json:"variables,omitempty"`
+	}
+
+	// gqlResponse is a GraphQL response, Data is absent if the request
+	// failed before the execution.
+	gqlResponse struct {
+		Data   any                        `json:"data,omitempty"`
+		Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+	}
+
+	// gqlLimits estimates the query cost: every field costs 1, the cost of
+	// subfields of list fields is multiplied by the maximum list size.
+	// Fragments are accounted for every time they're spread.
+	gqlLimits struct {
+		maxComplexity int
+		defListSize   int
+		fragments     map[string]*ast.FragmentDefinition
+		vars          map[string]any
+		// fields is the number of fields checked so far, every field
+		// costs at least 1, so it can't exceed maxComplexity.
+		fields  int
+		aliases int
+	}
+
+	// gqlTransaction is a transaction along with the height of the block it's
+	// included in (math.MaxUint32 for mempooled transactions).
+	gqlTransaction struct {
+		tx     *transaction.Transaction
+		height uint32
+	}
+
+	// gqlTransfers are NEP-11 or NEP-17 transfers of some account.
+	gqlTransfers struct {
+		received []gqlTransfer
+		sent     []gqlTransfer
+	}
+
+	// gqlTransfer is a NEP-11 or NEP-17 transfer, ID is empty for NEP-17
+	// ones.
+	gqlTransfer struct {
+		result.NEP11Transfer
+	}
+)
+
+// gqlListSizes are the maximum sizes of list fields that are not limited by
+// MaxFindResultItems.
+var gqlListSizes = map[string]int{
+	"Transaction.signers": transaction.MaxAttributes,
+	// OnPersist and PostPersist for blocks, Application for transactions.
+	"ApplicationLog.executions": 2,
+	"Transfers.received":        maxTransfersLimit,
+	"Transfers.sent":            maxTransfersLimit,
+}
+
+var (
+	// gqlLong is a 64-bit integer, it's serialized as a JSON number.
+	gqlLong = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Long",
+		Description: "The `Long` scalar type represents 64-bit integers.",
+		Serialize: func(v any) any {
+			switch v := v.(type) {
+			case uint32:
+				return int64(v)
+			case int64, uint64:
+				return v
+			}
+			return nil
+		},
+		ParseValue: func(v any) any {
+			switch v := v.(type) {
+			case int:
+				return int64(v)
+			case float64:
+				if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+					return int64(v)
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(v ast.Value) any {
+			if v, ok := v.(*ast.IntValue); ok {
+				if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+					return i
+				}
+			}
+			return nil
+		},
+	})
+
+	// gqlJSON is an arbitrary JSON value, it's output-only.
+	gqlJSON = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "JSON",
+		Description: "The `JSON` scalar type represents arbitrary JSON values.",
+		Serialize:   func(v any) any { return v },
+	})
+)
+
+// handleGraphQLRequest serves GraphQL queries received via GET or POST HTTP
+// requests.
+func (s *Server) handleGraphQLRequest(w http.ResponseWriter, httpRequest *http.Request) {
+	var req gqlRequest
+
+	switch httpRequest.Method {
+	case "GET":
+		q := httpRequest.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.NewDecoder(strings.NewReader(v)).Decode(&req.Variables); err != nil {
+				s.writeGraphQLResponse(w, http.StatusBadRequest, graphQLError(fmt.Errorf("invalid variables: %w", err)))
+				return
+			}
+		}
+	case "POST":
+		ct, _, _ := mime.ParseMediaType(httpRequest.Header.Get("Content-Type"))
+		if ct == "application/graphql" {
+			b, err := gio.ReadAll(httpRequest.Body)
+			if err != nil {
+				s.writeGraphQLResponse(w, http.StatusBadRequest, graphQLError(err))
+				return
+			}
+			req.Query = string(b)
+		} else if err := json.NewDecoder(httpRequest.Body).Decode(&req); err != nil {
+			s.writeGraphQLResponse(w, http.StatusBadRequest, graphQLError(fmt.Errorf("invalid request: %w", err)))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		s.writeGraphQLResponse(w, http.StatusMethodNotAllowed, graphQLError(fmt.Errorf("invalid method '%s', please retry with 'GET' or 'POST'", httpRequest.Method)))
+		return
+	}
+	if req.Query == "" {
+		s.writeGraphQLResponse(w, http.StatusBadRequest, graphQLError(errors.New("query is missing")))
+		return
+	}
+	s.writeGraphQLResponse(w, http.StatusOK, s.execGraphQL(httpRequest.Context(), req))
+}
+
+// execGraphQL parses, validates and executes the request. Errors are always
+// returned as a part of the response.
+func (s *Server) execGraphQL(ctx context.Context, req gqlRequest) *gqlResponse {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return graphQLError(err)
+	}
+	if res := graphql.ValidateDocument(s.graphQL, doc, nil); !res.IsValid {
+		return &gqlResponse{Errors: res.Errors}
+	}
+	if err := s.checkGraphQLLimits(doc, req.OperationName, req.Variables); err != nil {
+		return graphQLError(err)
+	}
+	res := graphql.Execute(graphql.ExecuteParams{
+		Schema:        *s.graphQL,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	return &gqlResponse{Data: res.Data, Errors: res.Errors}
+}
+
+func (s *Server) writeGraphQLResponse(w http.ResponseWriter, code int, resp *gqlResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if s.config.EnableCORSWorkaround {
+		setCORSOriginHeaders(w.Header())
+	}
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log.Error("Error encountered while encoding GraphQL response", zap.Error(err))
+	}
+}
+
+func graphQLError(err error) *gqlResponse {
+	return &gqlResponse{Errors: gqlerrors.FormatErrors(err)}
+}
+
+// checkGraphQLLimits checks the (already validated) query against depth,
+// alias and complexity limits before any resolver is invoked.
+func (s *Server) checkGraphQLLimits(doc *ast.Document, opName string, vars map[string]any) error {
+	var (
+		op *ast.OperationDefinition
+		l  = &gqlLimits{
+			maxComplexity: graphQLComplexityFactor * s.config.MaxFindResultItems,
+			defListSize:   s.config.MaxFindResultItems,
+			fragments:     make(map[string]*ast.FragmentDefinition),
+			vars:          vars,
+		}
+	)
+	for _, d := range doc.Definitions {
+		switch d := d.(type) {
+		case *ast.OperationDefinition:
+			if opName == "" || d.Name != nil && d.Name.Value == opName {
+				op = d
+			}
+		case *ast.FragmentDefinition:
+			l.fragments[d.Name.Value] = d
+		}
+	}
+	if op == nil {
+		return nil // Unknown operation, execution will fail anyway.
+	}
+	if op.Operation != ast.OperationTypeQuery {
+		return fmt.Errorf("%s operations are not supported", op.Operation)
+	}
+	cost, err := l.selections(s.graphQL.QueryType(), op.SelectionSet, 1)
+	if err != nil {
+		return err
+	}
+	return l.checkComplexity(cost)
+}
+
+// selections returns the estimated cost of the selection set.
+func (l *gqlLimits) selections(t *graphql.Object, set *ast.SelectionSet, depth int) (int, error) {
+	if depth > graphQLMaxDepth {
+		return 0, fmt.Errorf("query is nested too deep (max depth is %d)", graphQLMaxDepth)
+	}
+	var total int
+	for _, sel := range set.Selections {
+		var (
+			cost int
+			err  error
+		)
+		switch sel := sel.(type) {
+		case *ast.Field:
+			cost, err = l.field(t, sel, depth)
+		case *ast.FragmentSpread:
+			// Fragment cycles are rejected by validation.
+			cost, err = l.selections(t, l.fragments[sel.Name.Value].SelectionSet, depth)
+		case *ast.InlineFragment:
+			cost, err = l.selections(t, sel.SelectionSet, depth)
+		}
+		if err != nil {
+			return 0, err
+		}
+		total = addGQLCost(total, cost)
+	}
+	return total, nil
+}
+
+// field returns the estimated cost of the field: 1 for the field itself plus
+// the cost of its subfields multiplied by the list size for list fields.
+func (l *gqlLimits) field(t *graphql.Object, f *ast.Field, depth int) (int, error) {
+	l.fields++
+	if err := l.checkComplexity(l.fields); err != nil {
+		return 0, err
+	}
+	if f.Alias != nil {
+		l.aliases++
+		if l.aliases > graphQLMaxAliases {
+			return 0, fmt.Errorf("too many aliases (max is %d)", graphQLMaxAliases)
+		}
+	}
+	var def *graphql.FieldDefinition
+	switch f.Name.Value {
+	case "__schema":
+		def = graphql.SchemaMetaFieldDef
+	case "__type":
+		def = graphql.TypeMetaFieldDef
+	case "__typename":
+		def = graphql.TypeNameMetaFieldDef
+	default:
+		def = t.Fields()[f.Name.Value]
+	}
+	var (
+		ft   graphql.Type = def.Type
+		size              = 1
+	)
+	for {
+		if nn, ok := ft.(*graphql.NonNull); ok {
+			ft = nn.OfType
+			continue
+		}
+		lt, ok := ft.(*graphql.List)
+		if !ok {
+			break
+		}
+		size = mulGQLCost(size, l.listSize(t.Name()+"."+f.Name.Value, f.Arguments))
+		ft = lt.OfType
+	}
+	obj, ok := ft.(*graphql.Object)
+	if !ok || f.SelectionSet == nil {
+		return 1, nil
+	}
+	cost, err := l.selections(obj, f.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+	return addGQLCost(1, mulGQLCost(size, cost)), nil
+}
+
+// listSize returns the estimated number of list field elements, it's the
+// value of the limit argument if it's provided and is lower than the
+// maximum.
+func (l *gqlLimits) listSize(field string, args []*ast.Argument) int {
+	size, ok := gqlListSizes[field]
+	if !ok {
+		size = l.defListSize
+	}
+	for _, a := range args {
+		if a.Name.Value != "limit" {
+			continue
+		}
+		var limit any
+		if v, ok := a.Value.(*ast.Variable); ok {
+			limit = graphql.Int.ParseValue(l.vars[v.Name.Value])
+		} else {
+			limit = graphql.Int.ParseLiteral(a.Value)
+		}
+		if n, ok := limit.(int); ok && n > 0 && n < size {
+			size = n
+		}
+	}
+	return size
+}
+
+func (l *gqlLimits) checkComplexity(cost int) error {
+	if cost > l.maxComplexity {
+		return fmt.Errorf("query is too complex (max complexity is %d)", l.maxComplexity)
+	}
+	return nil
+}
+
+// addGQLCost returns a+b saturating at math.MaxInt.
+func addGQLCost(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulGQLCost returns a*b saturating at math.MaxInt, both values are positive.
+func mulGQLCost(a, b int) int {
+	if b != 0 && a > math.MaxInt/b {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// newGraphQLSchema creates the GraphQL schema exposing chain data.
+func (s *Server) newGraphQLSchema() (*graphql.Schema, error) {
+	var (
+		blockT, transactionT, signerT, appLogT, executionT, notificationT,
+		contractT, accountT, nep17BalanceT, nep11BalanceT, nep11TokenT,
+		transfersT, transferT *graphql.Object
+
+		pageArgs = graphql.FieldConfigArgument{
+			"offset": {Type: graphql.Int, DefaultValue: 0},
+			"limit":  {Type: graphql.Int, DefaultValue: s.config.MaxFindResultItems},
+		}
+		transferArgs = graphql.FieldConfigArgument{
+			"start": {Type: gqlLong},
+			"end":   {Type: gqlLong},
+			"limit": {Type: graphql.Int, DefaultValue: maxTransfersLimit},
+			"page":  {Type: graphql.Int, DefaultValue: 0},
+		}
+		hashArg = graphql.FieldConfigArgument{
+			"hash": {Type: graphql.NewNonNull(graphql.String)},
+		}
+	)
+
+	blockT = gqlObject("Block", func() graphql.Fields {
+		return graphql.Fields{
+			"hash":    leaf(graphql.String, func(src any) any { return uint256String(src.(*block.Block).Hash()) }),
+			"size":    leaf(graphql.Int, func(src any) any { return io.GetVarSize(src.(*block.Block)) }),
+			"version": leaf(graphql.Int, func(src any) any { return src.(*block.Block).Version }),
+			"merkleRoot": leaf(graphql.String, func(src any) any {
+				return uint256String(src.(*block.Block).MerkleRoot)
+			}),
+			"time":  leaf(gqlLong, func(src any) any { return src.(*block.Block).Timestamp }),
+			"nonce": leaf(graphql.String, func(src any) any { return fmt.Sprintf("%016X", src.(*block.Block).Nonce) }),
+			"index": leaf(graphql.Int, func(src any) any { return src.(*block.Block).Index }),
+			"primary": leaf(graphql.Int, func(src any) any {
+				return src.(*block.Block).PrimaryIndex
+			}),
+			"nextConsensus": leaf(graphql.String, func(src any) any {
+				return address.Uint160ToString(src.(*block.Block).NextConsensus)
+			}),
+			"confirmations": leaf(graphql.Int, func(src any) any {
+				return s.chain.BlockHeight() - src.(*block.Block).Index + 1
+			}),
+			"previousBlock": {
+				Type: blockT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					b := p.Source.(*block.Block)
+					if b.Index == 0 {
+						return nil, nil
+					}
+					return s.gqlBlockByHash(b.PrevHash)
+				},
+			},
+			"nextBlock": {
+				Type: blockT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.gqlBlockByIndex(p.Source.(*block.Block).Index + 1)
+				},
+			},
+			"transactionCount": leaf(graphql.Int, func(src any) any { return len(src.(*block.Block).Transactions) }),
+			"transactions": {
+				Type: graphql.NewList(transactionT),
+				Args: pageArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					b := p.Source.(*block.Block)
+					lo, hi, err := s.gqlPage(p.Args, len(b.Transactions))
+					if err != nil {
+						return nil, err
+					}
+					res := make([]gqlTransaction, 0, hi-lo)
+					for _, tx := range b.Transactions[lo:hi] {
+						res = append(res, gqlTransaction{tx: tx, height: b.Index})
+					}
+					return res, nil
+				},
+			},
+			"applicationLog": {
+				Type: appLogT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.gqlApplicationLog(p.Source.(*block.Block).Hash())
+				},
+			},
+		}
+	})
+
+	transactionT = gqlObject("Transaction", func() graphql.Fields {
+		return graphql.Fields{
+			"hash":    leaf(graphql.String, func(src any) any { return uint256String(src.(gqlTransaction).tx.Hash()) }),
+			"size":    leaf(graphql.Int, func(src any) any { return src.(gqlTransaction).tx.Size() }),
+			"version": leaf(graphql.Int, func(src any) any { return src.(gqlTransaction).tx.Version }),
+			"nonce":   leaf(gqlLong, func(src any) any { return src.(gqlTransaction).tx.Nonce }),
+			"sender": leaf(graphql.String, func(src any) any {
+				return address.Uint160ToString(src.(gqlTransaction).tx.Sender())
+			}),
+			"sysFee": leaf(graphql.String, func(src any) any {
+				return strconv.FormatInt(src.(gqlTransaction).tx.SystemFee, 10)
+			}),
+			"netFee": leaf(graphql.String, func(src any) any {
+				return strconv.FormatInt(src.(gqlTransaction).tx.NetworkFee, 10)
+			}),
+			"validUntilBlock": leaf(graphql.Int, func(src any) any { return src.(gqlTransaction).tx.ValidUntilBlock }),
+			"script": leaf(graphql.String, func(src any) any {
+				return base64.StdEncoding.EncodeToString(src.(gqlTransaction).tx.Script)
+			}),
+			"signers": leaf(graphql.NewList(signerT), func(src any) any { return src.(gqlTransaction).tx.Signers }),
+			"blockIndex": leaf(graphql.Int, func(src any) any {
+				t := src.(gqlTransaction)
+				if t.height == math.MaxUint32 {
+					return nil
+				}
+				return t.height
+			}),
+			"block": {
+				Type: blockT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					t := p.Source.(gqlTransaction)
+					if t.height == math.MaxUint32 {
+						return nil, nil
+					}
+					return s.gqlBlockByIndex(t.height)
+				},
+			},
+			"applicationLog": {
+				Type: appLogT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					t := p.Source.(gqlTransaction)
+					if t.height == math.MaxUint32 {
+						return nil, nil
+					}
+					return s.gqlApplicationLog(t.tx.Hash())
+				},
+			},
+		}
+	})
+
+	signerT = gqlObject("Signer", func() graphql.Fields {
+		return graphql.Fields{
+			"account": leaf(graphql.String, func(src any) any {
+				return address.Uint160ToString(src.(transaction.Signer).Account)
+			}),
+			"scopes": leaf(graphql.String, func(src any) any { return src.(transaction.Signer).Scopes.String() }),
+		}
+	})
+
+	appLogT = gqlObject("ApplicationLog", func() graphql.Fields {
+		return graphql.Fields{
+			"container": leaf(graphql.String, func(src any) any { return uint256String(src.(result.ApplicationLog).Container) }),
+			"executions": {
+				Type: graphql.NewList(executionT),
+				Args: graphql.FieldConfigArgument{"trigger": {Type: graphql.String}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					l := p.Source.(result.ApplicationLog)
+					ts, ok := p.Args["trigger"].(string)
+					if !ok {
+						return l.Executions, nil
+					}
+					trig, err := trigger.FromString(ts)
+					if err != nil {
+						return nil, err
+					}
+					res := make([]state.Execution, 0, len(l.Executions))
+					for _, e := range l.Executions {
+						if e.Trigger&trig != 0 {
+							res = append(res, e)
+						}
+					}
+					return res, nil
+				},
+			},
+		}
+	})
+
+	executionT = gqlObject("Execution", func() graphql.Fields {
+		return graphql.Fields{
+			"trigger":   leaf(graphql.String, func(src any) any { return src.(state.Execution).Trigger.String() }),
+			"vmState":   leaf(graphql.String, func(src any) any { return src.(state.Execution).VMState.String() }),
+			"exception": leaf(graphql.String, func(src any) any { return nilIfEmpty(src.(state.Execution).FaultException) }),
+			"gasConsumed": leaf(graphql.String, func(src any) any {
+				return strconv.FormatInt(src.(state.Execution).GasConsumed, 10)
+			}),
+			"stack": {
+				Type: gqlJSON,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					stack := p.Source.(state.Execution).Stack
+					res := make([]json.RawMessage, len(stack))
+					for i := range stack {
+						var err error
+						res[i], err = stackitem.ToJSONWithTypes(stack[i])
+						if err != nil {
+							return nil, fmt.Errorf("item %d: %w", i, err)
+						}
+					}
+					return res, nil
+				},
+			},
+			"notifications": {
+				Type: graphql.NewList(notificationT),
+				Args: withArgs(pageArgs, graphql.FieldConfigArgument{
+					"contract":  {Type: graphql.String},
+					"eventName": {Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var (
+						evs      = p.Source.(state.Execution).Events
+						contract *util.Uint160
+					)
+					if str, ok := p.Args["contract"].(string); ok {
+						h, err := s.gqlContractHash(str)
+						if err != nil {
+							return nil, err
+						}
+						contract = &h
+					}
+					name, _ := p.Args["eventName"].(string)
+					filtered := make([]state.NotificationEvent, 0, len(evs))
+					for _, e := range evs {
+						if (contract == nil || e.ScriptHash.Equals(*contract)) && (name == "" || e.Name == name) {
+							filtered = append(filtered, e)
+						}
+					}
+					lo, hi, err := s.gqlPage(p.Args, len(filtered))
+					if err != nil {
+						return nil, err
+					}
+					return filtered[lo:hi], nil
+				},
+			},
+		}
+	})
+
+	notificationT = gqlObject("Notification", func() graphql.Fields {
+		return graphql.Fields{
+			"scriptHash": leaf(graphql.String, func(src any) any { return uint160String(src.(state.NotificationEvent).ScriptHash) }),
+			"eventName":  leaf(graphql.String, func(src any) any { return src.(state.NotificationEvent).Name }),
+			"state": {
+				Type: gqlJSON,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					item := p.Source.(state.NotificationEvent).Item
+					if item == nil {
+						return nil, nil
+					}
+					b, err := stackitem.ToJSONWithTypes(item)
+					if err != nil {
+						return nil, err
+					}
+					return json.RawMessage(b), nil
+				},
+			},
+			"contract": {
+				Type: contractT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.chain.GetContractState(p.Source.(state.NotificationEvent).ScriptHash), nil
+				},
+			},
+		}
+	})
+
+	contractT = gqlObject("Contract", func() graphql.Fields {
+		return graphql.Fields{
+			"id":            leaf(graphql.Int, func(src any) any { return src.(*state.Contract).ID }),
+			"hash":          leaf(graphql.String, func(src any) any { return uint160String(src.(*state.Contract).Hash) }),
+			"updateCounter": leaf(graphql.Int, func(src any) any { return src.(*state.Contract).UpdateCounter }),
+			"name":          leaf(graphql.String, func(src any) any { return src.(*state.Contract).Manifest.Name }),
+			"checksum":      leaf(gqlLong, func(src any) any { return src.(*state.Contract).NEF.Checksum }),
+			"supportedStandards": leaf(graphql.NewList(graphql.String), func(src any) any {
+				return src.(*state.Contract).Manifest.SupportedStandards
+			}),
+			"manifest": leaf(gqlJSON, func(src any) any { return &src.(*state.Contract).Manifest }),
+		}
+	})
+
+	accountT = gqlObject("Account", func() graphql.Fields {
+		return graphql.Fields{
+			"address":    leaf(graphql.String, func(src any) any { return address.Uint160ToString(src.(util.Uint160)) }),
+			"scriptHash": leaf(graphql.String, func(src any) any { return uint160String(src.(util.Uint160)) }),
+			"nep17Balances": {
+				Type: graphql.NewList(nep17BalanceT),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					res, respErr := s.getNEP17Balances(gqlParams(address.Uint160ToString(p.Source.(util.Uint160))))
+					if respErr != nil {
+						return nil, respErr
+					}
+					return res.(*result.NEP17Balances).Balances, nil
+				},
+			},
+			"nep11Balances": {
+				Type: graphql.NewList(nep11BalanceT),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					res, respErr := s.getNEP11Balances(gqlParams(address.Uint160ToString(p.Source.(util.Uint160))))
+					if respErr != nil {
+						return nil, respErr
+					}
+					return res.(*result.NEP11Balances).Balances, nil
+				},
+			},
+			"nep17Transfers": {
+				Type: transfersT,
+				Args: transferArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.gqlTransfers(p.Source.(util.Uint160), p.Args, false)
+				},
+			},
+			"nep11Transfers": {
+				Type: transfersT,
+				Args: transferArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.gqlTransfers(p.Source.(util.Uint160), p.Args, true)
+				},
+			},
+		}
+	})
+
+	nep17BalanceT = gqlObject("NEP17Balance", func() graphql.Fields {
+		return graphql.Fields{
+			"asset":    leaf(graphql.String, func(src any) any { return uint160String(src.(result.NEP17Balance).Asset) }),
+			"name":     leaf(graphql.String, func(src any) any { return src.(result.NEP17Balance).Name }),
+			"symbol":   leaf(graphql.String, func(src any) any { return src.(result.NEP17Balance).Symbol }),
+			"decimals": leaf(graphql.Int, func(src any) any { return src.(result.NEP17Balance).Decimals }),
+			"amount":   leaf(graphql.String, func(src any) any { return src.(result.NEP17Balance).Amount }),
+			"lastUpdatedBlock": leaf(graphql.Int, func(src any) any {
+				return src.(result.NEP17Balance).LastUpdated
+			}),
+			"token": {
+				Type: contractT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.chain.GetContractState(p.Source.(result.NEP17Balance).Asset), nil
+				},
+			},
+		}
+	})
+
+	nep11BalanceT = gqlObject("NEP11Balance", func() graphql.Fields {
+		return graphql.Fields{
+			"asset":    leaf(graphql.String, func(src any) any { return uint160String(src.(result.NEP11AssetBalance).Asset) }),
+			"name":     leaf(graphql.String, func(src any) any { return src.(result.NEP11AssetBalance).Name }),
+			"symbol":   leaf(graphql.String, func(src any) any { return src.(result.NEP11AssetBalance).Symbol }),
+			"decimals": leaf(graphql.Int, func(src any) any { return src.(result.NEP11AssetBalance).Decimals }),
+			"tokens": leaf(graphql.NewList(nep11TokenT), func(src any) any {
+				return src.(result.NEP11AssetBalance).Tokens
+			}),
+			"token": {
+				Type: contractT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.chain.GetContractState(p.Source.(result.NEP11AssetBalance).Asset), nil
+				},
+			},
+		}
+	})
+
+	nep11TokenT = gqlObject("NEP11Token", func() graphql.Fields {
+		return graphql.Fields{
+			"tokenId": leaf(graphql.String, func(src any) any { return src.(result.NEP11TokenBalance).ID }),
+			"amount":  leaf(graphql.String, func(src any) any { return src.(result.NEP11TokenBalance).Amount }),
+			"lastUpdatedBlock": leaf(graphql.Int, func(src any) any {
+				return src.(result.NEP11TokenBalance).LastUpdated
+			}),
+		}
+	})
+
+	transfersT = gqlObject("Transfers", func() graphql.Fields {
+		return graphql.Fields{
+			"received": leaf(graphql.NewList(transferT), func(src any) any { return src.(*gqlTransfers).received }),
+			"sent":     leaf(graphql.NewList(transferT), func(src any) any { return src.(*gqlTransfers).sent }),
+		}
+	})
+
+	transferT = gqlObject("Transfer", func() graphql.Fields {
+		return graphql.Fields{
+			"timestamp": leaf(gqlLong, func(src any) any { return src.(gqlTransfer).Timestamp }),
+			"asset":     leaf(graphql.String, func(src any) any { return uint160String(src.(gqlTransfer).Asset) }),
+			"address":   leaf(graphql.String, func(src any) any { return nilIfEmpty(src.(gqlTransfer).Address) }),
+			"amount":    leaf(graphql.String, func(src any) any { return src.(gqlTransfer).Amount }),
+			"tokenId":   leaf(graphql.String, func(src any) any { return nilIfEmpty(src.(gqlTransfer).ID) }),
+			"blockIndex": leaf(graphql.Int, func(src any) any {
+				return src.(gqlTransfer).Index
+			}),
+			"transferNotifyIndex": leaf(graphql.Int, func(src any) any {
+				return src.(gqlTransfer).NotifyIndex
+			}),
+			"txHash": leaf(graphql.String, func(src any) any {
+				h := src.(gqlTransfer).TxHash
+				if h.Equals(util.Uint256{}) {
+					return nil
+				}
+				return uint256String(h)
+			}),
+			"token": {
+				Type: contractT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.chain.GetContractState(p.Source.(gqlTransfer).Asset), nil
+				},
+			},
+			"block": {
+				Type: blockT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.gqlBlockByIndex(p.Source.(gqlTransfer).Index)
+				},
+			},
+			"transaction": {
+				Type: transactionT,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					h := p.Source.(gqlTransfer).TxHash
+					if h.Equals(util.Uint256{}) {
+						return nil, nil
+					}
+					return s.gqlTransaction(h), nil
+				},
+			},
+		}
+	})
+
+	query := gqlObject("Query", func() graphql.Fields {
+		return graphql.Fields{
+			"height": leaf(graphql.Int, func(any) any { return s.chain.BlockHeight() }),
+			"block": {
+				Type: blockT,
+				Args: graphql.FieldConfigArgument{
+					"index": {Type: graphql.Int},
+					"hash":  {Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if _, ok := p.Args["hash"].(string); ok {
+						h, err := gqlHashArg(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						return s.gqlBlockByHash(h)
+					}
+					i, ok := p.Args["index"].(int)
+					if !ok {
+						i = int(s.chain.BlockHeight())
+					}
+					if i < 0 {
+						return nil, errors.New("negative block index")
+					}
+					return s.gqlBlockByIndex(uint32(i))
+				},
+			},
+			"blocks": {
+				Type: graphql.NewList(blockT),
+				Args: graphql.FieldConfigArgument{
+					"from":  {Type: graphql.Int, DefaultValue: 0},
+					"limit": pageArgs["limit"],
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					from, _ := p.Args["from"].(int)
+					if from < 0 {
+						return nil, errors.New("negative block index")
+					}
+					height := int(s.chain.BlockHeight())
+					if from > height {
+						return []*block.Block{}, nil
+					}
+					_, n, err := s.gqlPage(p.Args, height-from+1)
+					if err != nil {
+						return nil, err
+					}
+					res := make([]*block.Block, 0, n)
+					for i := from; i < from+n; i++ {
+						b, err := s.gqlBlockByIndex(uint32(i))
+						if err != nil {
+							return nil, err
+						}
+						res = append(res, b)
+					}
+					return res, nil
+				},
+			},
+			"transaction": {
+				Type: transactionT,
+				Args: hashArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					h, err := gqlHashArg(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return s.gqlTransaction(h), nil
+				},
+			},
+			"applicationLog": {
+				Type: appLogT,
+				Args: hashArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					h, err := gqlHashArg(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return s.gqlApplicationLog(h)
+				},
+			},
+			"contract": {
+				Type: contractT,
+				Args: hashArg,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					h, err := s.gqlContractHash(p.Args["hash"].(string))
+					if err != nil {
+						return nil, err
+					}
+					return s.chain.GetContractState(h), nil
+				},
+			},
+			"account": {
+				Type: accountT,
+				Args: graphql.FieldConfigArgument{
+					"address": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					u, err := gqlParams(p.Args["address"]).Value(0).GetUint160FromAddressOrHex()
+					if err != nil {
+						return nil, fmt.Errorf("invalid address: %w", err)
+					}
+					return u, nil
+				},
+			},
+		}
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// gqlObject creates an object type with lazily defined fields (so that types
+// can refer to each other), resolvers are not invoked after the request
+// context is done.
+func gqlObject(name string, fields func() graphql.Fields) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fs := fields()
+			for _, f := range fs {
+				resolve := f.Resolve
+				f.Resolve = func(p graphql.ResolveParams) (any, error) {
+					if err := p.Context.Err(); err != nil {
+						return nil, err
+					}
+					return resolve(p)
+				}
+			}
+			return fs
+		}),
+	})
+}
+
+// withArgs returns a union of argument sets.
+func withArgs(sets ...graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	res := make(graphql.FieldConfigArgument)
+	for _, set := range sets {
+		for name, arg := range set {
+			res[name] = arg
+		}
+	}
+	return res
+}
+
+// leaf returns a field that can't fail.
+func leaf(t graphql.Output, f func(src any) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return f(p.Source), nil
+		},
+	}
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func uint160String(u util.Uint160) string {
+	return "0x" + u.StringLE()
+}
+
+func uint256String(u util.Uint256) string {
+	return "0x" + u.StringLE()
+}
+
+// gqlParams converts values into JSON-RPC parameters to reuse JSON-RPC
+// handlers.
+func gqlParams(vals ...any) params.Params {
+	ps := make(params.Params, len(vals))
+	for i := range vals {
+		b, _ := json.Marshal(vals[i])
+		ps[i] = params.Param{RawMessage: b}
+	}
+	return ps
+}
+
+func gqlHashArg(args map[string]any) (util.Uint256, error) {
+	h, err := gqlParams(args["hash"]).Value(0).GetUint256()
+	if err != nil {
+		return util.Uint256{}, fmt.Errorf("invalid hash: %w", err)
+	}
+	return h, nil
+}
+
+// gqlContractHash returns the hash of the contract specified by the hash,
+// address, native contract name or ID.
+func (s *Server) gqlContractHash(str string) (util.Uint160, error) {
+	h, respErr := s.contractScriptHashFromParam(&gqlParams(str)[0])
+	if respErr != nil {
+		return util.Uint160{}, respErr
+	}
+	return h, nil
+}
+
+// gqlPage returns the range of list items to be returned using "offset" and
+// "limit" arguments. The limit can't exceed MaxFindResultItems.
+func (s *Server) gqlPage(args map[string]any, n int) (int, int, error) {
+	offset, _ := args["offset"].(int)
+	limit, _ := args["limit"].(int)
+	if offset < 0 {
+		return 0, 0, errors.New("negative offset")
+	}
+	if limit <= 0 || limit > s.config.MaxFindResultItems {
+		return 0, 0, fmt.Errorf("limit should be in [1, %d] range", s.config.MaxFindResultItems)
+	}
+	if offset > n {
+		offset = n
+	}
+	if limit > n-offset {
+		limit = n - offset
+	}
+	return offset, offset + limit, nil
+}
+
+func (s *Server) gqlBlockByIndex(i uint32) (*block.Block, error) {
+	if i > s.chain.BlockHeight() {
+		return nil, nil
+	}
+	return s.gqlBlockByHash(s.chain.GetHeaderHash(i))
+}
+
+func (s *Server) gqlBlockByHash(h util.Uint256) (*block.Block, error) {
+	b, err := s.chain.GetBlock(h)
+	if err != nil {
+		return nil, nil
+	}
+	return b, nil
+}
+
+func (s *Server) gqlTransaction(h util.Uint256) any {
+	tx, height, err := s.chain.GetTransaction(h)
+	if err != nil {
+		return nil
+	}
+	return gqlTransaction{tx: tx, height: height}
+}
+
+func (s *Server) gqlApplicationLog(h util.Uint256) (any, error) {
+	aers, err := s.chain.GetAppExecResults(h, trigger.All)
+	if err != nil || len(aers) == 0 {
+		return nil, nil
+	}
+	return result.NewApplicationLog(h, aers, trigger.All), nil
+}
+
+// gqlTransfers returns transfers of the account using getnep*transfers
+// defaults and limits.
+func (s *Server) gqlTransfers(acc util.Uint160, args map[string]any, isNEP11 bool) (any, error) {
+	now := time.Now()
+	start, ok := args["start"].(int64)
+	if !ok {
+		start = now.Add(-time.Hour * 24 * 7).UnixMilli()
+	}
+	end, ok := args["end"].(int64)
+	if !ok {
+		end = now.UnixMilli()
+	}
+	res, respErr := s.getTokenTransfers(gqlParams(address.Uint160ToString(acc), start, end, args["limit"], args["page"]), isNEP11)
+	if respErr != nil {
+		return nil, respErr
+	}
+	trs := res.(*tokenTransfers)
+	return &gqlTransfers{
+		received: toGQLTransfers(trs.Received),
+		sent:     toGQLTransfers(trs.Sent),
+	}, nil
+}
+
+func toGQLTransfers(trs []any) []gqlTransfer {
+	res := make([]gqlTransfer, 0, len(trs))
+	for _, t := range trs {
+		switch t := t.(type) {
+		case *result.NEP17Transfer:
+			res = append(res, gqlTransfer{nep17TransferToNEP11(t, "")})
+		case result.NEP11Transfer:
+			res = append(res, gqlTransfer{t})
+		}
+	}
+	return res
+}
@@ -0,0 +1,334 @@
+package rpcsrv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gio "io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/nspcc-dev/neo-go/internal/testchain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLTestResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+		Path    []any  `json:"path"`
+	} `json:"errors"`
+}
+
+func doGraphQLQuery(t *testing.T, u string, query string, vars map[string]any) graphQLTestResponse {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	require.NoError(t, err)
+	cl := http.Client{Timeout: time.Second}
+	resp, err := cl.Post(u+"/graphql", "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var res graphQLTestResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	return res
+}
+
+func TestGraphQL(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.RPC.GraphQLEnabled = true
+	})
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	t.Run("blocks and transactions", func(t *testing.T) {
+		resp := doGraphQLQuery(t, httpSrv.URL, `query($i: Int!) {
+			height
+			block(index: $i) {
+				index hash transactionCount
+				previousBlock { index }
+				transactions(limit: 1) {
+					hash blockIndex
+					block { index }
+					applicationLog { executions { trigger vmState notifications { eventName contract { name } } } }
+				}
+			}
+		}`, map[string]any{"i": 1})
+		require.Nil(t, resp.Errors)
+
+		var data struct {
+			Height uint32 `json:"height"`
+			Block  struct {
+				Index            uint32 `json:"index"`
+				Hash             string `json:"hash"`
+				TransactionCount int    `json:"transactionCount"`
+				PreviousBlock    struct {
+					Index uint32 `json:"index"`
+				} `json:"previousBlock"`
+				Transactions []struct {
+					Hash       string `json:"hash"`
+					BlockIndex uint32 `json:"blockIndex"`
+					Block      struct {
+						Index uint32 `json:"index"`
+					} `json:"block"`
+					ApplicationLog struct {
+						Executions []struct {
+							Trigger       string `json:"trigger"`
+							VMState       string `json:"vmState"`
+							Notifications []struct {
+								EventName string `json:"eventName"`
+								Contract  struct {
+									Name string `json:"name"`
+								} `json:"contract"`
+							} `json:"notifications"`
+						} `json:"executions"`
+					} `json:"applicationLog"`
+				} `json:"transactions"`
+			} `json:"block"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+		require.Equal(t, chain.BlockHeight(), data.Height)
+		require.Equal(t, uint32(1), data.Block.Index)
+		require.Equal(t, "0x"+chain.GetHeaderHash(1).StringLE(), data.Block.Hash)
+		require.Equal(t, uint32(0), data.Block.PreviousBlock.Index)
+
+		b, err := chain.GetBlock(chain.GetHeaderHash(1))
+		require.NoError(t, err)
+		require.Equal(t, len(b.Transactions), data.Block.TransactionCount)
+		require.Equal(t, 1, len(data.Block.Transactions))
+		tx := data.Block.Transactions[0]
+		require.Equal(t, "0x"+b.Transactions[0].Hash().StringLE(), tx.Hash)
+		require.Equal(t, uint32(1), tx.BlockIndex)
+		require.Equal(t, uint32(1), tx.Block.Index)
+		require.Equal(t, 1, len(tx.ApplicationLog.Executions))
+		exec := tx.ApplicationLog.Executions[0]
+		require.Equal(t, "Application", exec.Trigger)
+		require.Equal(t, "HALT", exec.VMState)
+		require.NotEqual(t, 0, len(exec.Notifications))
+		require.Equal(t, "Transfer", exec.Notifications[0].EventName)
+		require.Equal(t, nativenames.Neo, exec.Notifications[0].Contract.Name)
+	})
+
+	t.Run("contract", func(t *testing.T) {
+		resp := doGraphQLQuery(t, httpSrv.URL, `{
+			neo: contract(hash: "NeoToken") { id name supportedStandards }
+			missing: contract(hash: "0x0000000000000000000000000000000000000000") { id }
+		}`, nil)
+		require.Nil(t, resp.Errors)
+		require.JSONEq(t, `{"neo":{"id":-5,"name":"NeoToken","supportedStandards":["NEP-17"]},"missing":null}`, string(resp.Data))
+	})
+
+	t.Run("account", func(t *testing.T) {
+		acc := testchain.PrivateKeyByID(0).Address()
+		resp := doGraphQLQuery(t, httpSrv.URL, `query($a: String!) {
+			account(address: $a) {
+				address
+				nep17Balances { asset symbol amount token { name } }
+				nep17Transfers(start: 0, limit: 3) { sent { asset amount } received { asset amount } }
+				nep11Balances { symbol tokens { tokenId } }
+			}
+		}`, map[string]any{"a": acc})
+		require.Nil(t, resp.Errors)
+
+		var data struct {
+			Account struct {
+				Address       string `json:"address"`
+				NEP17Balances []struct {
+					Asset  string `json:"asset"`
+					Symbol string `json:"symbol"`
+					Amount string `json:"amount"`
+					Token  struct {
+						Name string `json:"name"`
+					} `json:"token"`
+				} `json:"nep17Balances"`
+				NEP17Transfers struct {
+					Sent     []json.RawMessage `json:"sent"`
+					Received []json.RawMessage `json:"received"`
+				} `json:"nep17Transfers"`
+				NEP11Balances []struct {
+					Symbol string `json:"symbol"`
+					Tokens []struct {
+						TokenID string `json:"tokenId"`
+					} `json:"tokens"`
+				} `json:"nep11Balances"`
+			} `json:"account"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Data, &data))
+		require.Equal(t, acc, data.Account.Address)
+
+		res, respErr := rpcSrv.getNEP17Balances(gqlParams(acc))
+		require.Nil(t, respErr)
+		expected := make(map[string]result.NEP17Balance)
+		for _, bal := range res.(*result.NEP17Balances).Balances {
+			expected["0x"+bal.Asset.StringLE()] = bal
+		}
+		require.Equal(t, len(expected), len(data.Account.NEP17Balances))
+		for _, bal := range data.Account.NEP17Balances {
+			exp, ok := expected[bal.Asset]
+			require.True(t, ok)
+			require.Equal(t, exp.Symbol, bal.Symbol)
+			require.Equal(t, exp.Amount, bal.Amount)
+			require.Equal(t, exp.Name, bal.Token.Name)
+		}
+		require.Equal(t, 3, len(data.Account.NEP17Transfers.Sent)+len(data.Account.NEP17Transfers.Received))
+		var nns bool
+		for _, bal := range data.Account.NEP11Balances {
+			if bal.Symbol == "NNS" {
+				nns = true
+				require.Equal(t, nnsToken1ID, bal.Tokens[0].TokenID)
+			}
+		}
+		require.True(t, nns)
+	})
+
+	t.Run("pagination limits", func(t *testing.T) {
+		resp := doGraphQLQuery(t, httpSrv.URL, `query($n: Int) { blocks(from: 1, limit: $n) { index } }`,
+			map[string]any{"n": rpcSrv.config.MaxFindResultItems + 1})
+		require.Equal(t, 1, len(resp.Errors))
+		require.Equal(t, []any{"blocks"}, resp.Errors[0].Path)
+		require.JSONEq(t, `{"blocks":null}`, string(resp.Data))
+
+		resp = doGraphQLQuery(t, httpSrv.URL, `{ blocks(from: 2, limit: 3) { index } }`, nil)
+		require.Nil(t, resp.Errors)
+		require.JSONEq(t, `{"blocks":[{"index":2},{"index":3},{"index":4}]}`, string(resp.Data))
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		resp := doGraphQLQuery(t, httpSrv.URL, `{ block { unknown } }`, nil)
+		require.Nil(t, resp.Data)
+		require.Equal(t, 1, len(resp.Errors))
+
+		resp = doGraphQLQuery(t, httpSrv.URL, `{ transaction(hash: "bad") { hash } }`, nil)
+		require.Equal(t, 1, len(resp.Errors))
+		require.JSONEq(t, `{"transaction":null}`, string(resp.Data))
+
+		resp = doGraphQLQuery(t, httpSrv.URL, `{ blocks { transactions { applicationLog {
+			executions { notifications { contract { hash } } } } } } }`, nil)
+		require.Nil(t, resp.Data)
+		require.Equal(t, 1, len(resp.Errors))
+		require.Contains(t, resp.Errors[0].Message, "too complex")
+
+		resp = doGraphQLQuery(t, httpSrv.URL, `{ blocks(limit: 2) { transactions(limit: 2) { applicationLog {
+			executions { notifications { contract { hash } } } } } } }`, nil)
+		require.Nil(t, resp.Errors)
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		resp, err := http.Get(httpSrv.URL + "/graphql?query=" + url.QueryEscape(`{ block(index: 0) { index } }`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := gio.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"data":{"block":{"index":0}}}`, string(body))
+	})
+}
+
+func newTestGraphQLServer(t *testing.T) *Server {
+	s := &Server{config: config.RPC{MaxFindResultItems: 100}}
+	var err error
+	s.graphQL, err = s.newGraphQLSchema()
+	require.NoError(t, err)
+	return s
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	s := newTestGraphQLServer(t)
+
+	var aliases strings.Builder
+	for i := 0; i <= graphQLMaxAliases; i++ {
+		fmt.Fprintf(&aliases, "h%d: height ", i)
+	}
+	testCases := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		err   string
+	}{
+		// 1 + 100*(1 + 100*1).
+		{name: "page of pages", query: `{ blocks { transactions { hash } } }`},
+		// 1 + 100*(1 + 100*(1 + 16*1)).
+		{name: "nested lists", query: `{ blocks { transactions { signers { account } } } }`, err: "too complex"},
+		// 1 + 5*(1 + 100*(1 + 16*1)).
+		{name: "limited", query: `{ blocks(limit: 5) { transactions { signers { account } } } }`},
+		{name: "variable limit", query: `query($n: Int) { blocks(limit: $n) { transactions { signers { account } } } }`,
+			vars: map[string]any{"n": float64(5)}},
+		{name: "no variable limit", query: `query($n: Int) { blocks(limit: $n) { transactions { signers { account } } } }`,
+			err: "too complex"},
+		// 1 + 100*(10*(1 + 100*1)).
+		{name: "fragments", query: `{ blocks { ` + strings.Repeat("...T ", 10) + `} } fragment T on Block { transactions { hash } }`,
+			err: "too complex"},
+		{name: "aliases", query: `{ ` + aliases.String() + `}`, err: "too many aliases"},
+		{name: "depth", query: `{ block { ` + strings.Repeat("previousBlock { ", graphQLMaxDepth) + `index` +
+			strings.Repeat(" }", graphQLMaxDepth) + ` } }`, err: "nested too deep"},
+		// 1 + 100*(1 + 1 + 100*1).
+		{name: "introspection", query: `{ __schema { types { name fields { name } } } }`},
+		{name: "deep introspection", query: `{ __schema { types { fields { type { fields { name } } } } } }`,
+			err: "too complex"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(tc.query)})})
+			require.NoError(t, err)
+			require.True(t, graphql.ValidateDocument(s.graphQL, doc, nil).IsValid)
+			err = s.checkGraphQLLimits(doc, "", tc.vars)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestGraphQLRequestErrors(t *testing.T) {
+	s := newTestGraphQLServer(t)
+
+	testCases := map[string]gqlRequest{
+		"syntax":            {Query: `{ height`},
+		"unknown field":     {Query: `{ block { unknown } }`},
+		"unknown argument":  {Query: `{ block(foo: 1) { index } }`},
+		"argument type":     {Query: `{ block(index: "1") { index } }`},
+		"missing argument":  {Query: `{ transaction { hash } }`},
+		"missing selection": {Query: `{ block }`},
+		"mutation":          {Query: `mutation { height }`},
+		"too deep":          {Query: `{ block { ` + strings.Repeat("nextBlock { ", graphQLMaxDepth) + `index` + strings.Repeat(" }", graphQLMaxDepth+1) + ` }`},
+	}
+	for name, req := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp := s.execGraphQL(context.Background(), req)
+			require.Nil(t, resp.Data)
+			require.Equal(t, 1, len(resp.Errors), resp.Errors)
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		resp := s.execGraphQL(ctx, gqlRequest{Query: `{ height }`})
+		require.Equal(t, 1, len(resp.Errors))
+		require.Equal(t, context.Canceled.Error(), resp.Errors[0].Message)
+	})
+}
+
+func TestGraphQLDisabled(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	// It's handled as a regular JSON-RPC request.
+	body := doRPCCallOverHTTP(`{"query": "{ height }"}`, httpSrv.URL+"/graphql", t)
+	_ = checkErrGetResult(t, body, true, neorpc.InvalidParamsCode)
+}
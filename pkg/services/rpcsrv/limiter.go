@@ -0,0 +1,217 @@
+package rpcsrv
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"go.uber.org/zap"
+)
+
+type (
+	// limiter authenticates RPC clients and enforces their request quotas.
+	limiter struct {
+		required bool
+		keys     map[[sha256.Size]byte]*rpcClient
+		ipQuota  config.RPCQuota
+		costs    map[string]int
+
+		lock      sync.Mutex
+		ips       map[string]*rpcClient
+		lastSweep time.Time
+	}
+
+	// rpcClient is an RPC client identified either by API key or by IP
+	// address.
+	rpcClient struct {
+		name   string
+		quota  config.RPCQuota
+		tokens float64
+		last   time.Time
+	}
+)
+
+const (
+	// apiKeyHeader is an alternative to the bearer Authorization header.
+	apiKeyHeader = "X-Api-Key"
+
+	// ipSweepInterval is the interval between idle per-IP client cleanups.
+	ipSweepInterval = time.Minute
+)
+
+// defaultMethodCosts contains request costs for methods that are more
+// expensive to handle than others (cost 1 is used for the rest).
+var defaultMethodCosts = map[string]int{
+	"findnotifications":            5,
+	"findstates":                   5,
+	"findstorage":                  5,
+	"findstoragehistoric":          5,
+	"findtransactions":             5,
+	"getnep11transfers":            5,
+	"getnep17transfers":            5,
+	"graphql":                      5,
+	"invokecontractverify":         10,
+	"invokecontractverifyhistoric": 10,
+	"invokefunction":               10,
+	"invokefunctionhistoric":       10,
+	"invokescript":                 10,
+	"invokescripthistoric":         10,
+	"tracetransaction":             20,
+	"traverseiterator":             5,
+}
+
+// newLimiter creates a limiter from the configuration, nil is returned if
+// there is nothing to limit.
+func newLimiter(cfg config.RPCAuth, log *zap.Logger) *limiter {
+	if !cfg.Required && len(cfg.Keys) == 0 && cfg.PerIPQuota.Rate <= 0 {
+		return nil
+	}
+	l := &limiter{
+		required: cfg.Required,
+		keys:     make(map[[sha256.Size]byte]*rpcClient, len(cfg.Keys)),
+		ipQuota:  cfg.PerIPQuota,
+		costs:    make(map[string]int, len(defaultMethodCosts)+len(cfg.MethodCosts)),
+		ips:      make(map[string]*rpcClient),
+	}
+	for i, k := range cfg.Keys {
+		if k.Key == "" {
+			log.Warn("RPC API key is empty, skipping", zap.Int("index", i), zap.String("name", k.Name))
+			continue
+		}
+		h := sha256.Sum256([]byte(k.Key))
+		if _, ok := l.keys[h]; ok {
+			log.Warn("duplicate RPC API key, skipping", zap.Int("index", i), zap.String("name", k.Name))
+			continue
+		}
+		name := k.Name
+		if name == "" {
+			name = fmt.Sprintf("key #%d", i)
+		}
+		l.keys[h] = newRPCClient(name, k.Quota)
+	}
+	for m, c := range defaultMethodCosts {
+		l.costs[m] = c
+	}
+	for m, c := range cfg.MethodCosts {
+		if c < 0 {
+			log.Warn("negative RPC method cost, using 0", zap.String("method", m), zap.Int("cost", c))
+			c = 0
+		}
+		l.costs[m] = c
+	}
+	return l
+}
+
+func newRPCClient(name string, q config.RPCQuota) *rpcClient {
+	if q.Burst <= 0 {
+		q.Burst = q.Rate
+	}
+	return &rpcClient{
+		name:   name,
+		quota:  q,
+		tokens: float64(q.Burst),
+	}
+}
+
+// authenticate returns the client making the request. The client is nil if
+// the limiter is not configured.
+func (l *limiter) authenticate(r *http.Request) (*rpcClient, *neorpc.Error) {
+	if l == nil {
+		return nil, nil
+	}
+	key := r.Header.Get(apiKeyHeader)
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, ok := strings.Cut(auth, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrUnauthorized, "unsupported authorization scheme")
+		}
+		key = strings.TrimSpace(token)
+	}
+	if key != "" {
+		c, ok := l.keys[sha256.Sum256([]byte(key))]
+		if !ok {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrUnauthorized, "invalid API key")
+		}
+		return c, nil
+	}
+	if l.required {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnauthorized, "API key is required")
+	}
+	if l.ipQuota.Rate <= 0 {
+		return nil, nil
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	c, ok := l.ips[ip]
+	if !ok {
+		c = newRPCClient(ip, l.ipQuota)
+		l.ips[ip] = c
+	}
+	return c, nil
+}
+
+// charge takes the method cost from the client quota, an error is returned
+// if the quota is exceeded. Nil client is never limited.
+func (l *limiter) charge(c *rpcClient, method string) *neorpc.Error {
+	if c == nil || c.quota.Rate <= 0 {
+		return nil
+	}
+	cost, ok := l.costs[method]
+	if !ok {
+		cost = 1
+	}
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !c.take(cost, now) {
+		return neorpc.WrapErrorWithData(neorpc.ErrRateLimitExceeded,
+			fmt.Sprintf("%s quota exceeded (%d per second), %s costs %d", c.name, c.quota.Rate, method, cost))
+	}
+	if now.Sub(l.lastSweep) >= ipSweepInterval {
+		l.sweep(now)
+	}
+	return nil
+}
+
+// take refills the client's bucket and takes cost units from it. Requests
+// costing more than the burst are allowed when the bucket is full.
+func (c *rpcClient) take(cost int, now time.Time) bool {
+	c.refill(now)
+	if c.tokens < float64(cost) && c.tokens < float64(c.quota.Burst) {
+		return false
+	}
+	c.tokens -= float64(cost)
+	return true
+}
+
+func (c *rpcClient) refill(now time.Time) {
+	if !c.last.IsZero() {
+		c.tokens += now.Sub(c.last).Seconds() * float64(c.quota.Rate)
+		if c.tokens > float64(c.quota.Burst) {
+			c.tokens = float64(c.quota.Burst)
+		}
+	}
+	c.last = now
+}
+
+// sweep drops per-IP clients with full quota, they're indistinguishable from
+// new ones. It must be called with the lock held.
+func (l *limiter) sweep(now time.Time) {
+	for ip, c := range l.ips {
+		c.refill(now)
+		if c.tokens >= float64(c.quota.Burst) {
+			delete(l.ips, ip)
+		}
+	}
+	l.lastSweep = now
+}
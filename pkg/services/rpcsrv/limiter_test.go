@@ -0,0 +1,178 @@
+package rpcsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newLimiterTestRequest(remote string, headers ...string) *http.Request {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.RemoteAddr = remote
+	for i := 0; i < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	return r
+}
+
+func TestLimiterAuthenticate(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	require.Nil(t, newLimiter(config.RPCAuth{}, log))
+
+	var l *limiter
+	c, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5"))
+	require.Nil(t, err)
+	require.Nil(t, c)
+	require.Nil(t, l.charge(c, "invokescript"))
+
+	cfg := config.RPCAuth{
+		Keys: []config.RPCKey{
+			{Name: "alice", Key: "secret1"},
+			{Key: "secret2", Quota: config.RPCQuota{Rate: 1}},
+			{Name: "empty"},
+			{Name: "duplicate", Key: "secret1"},
+		},
+		PerIPQuota: config.RPCQuota{Rate: 10},
+	}
+	l = newLimiter(cfg, log)
+	require.Equal(t, 2, len(l.keys))
+
+	t.Run("bearer", func(t *testing.T) {
+		c, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5", "Authorization", "Bearer secret1"))
+		require.Nil(t, err)
+		require.Equal(t, "alice", c.name)
+	})
+	t.Run("header", func(t *testing.T) {
+		c, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5", "X-API-Key", "secret2"))
+		require.Nil(t, err)
+		require.Equal(t, "key #1", c.name)
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5", "X-API-Key", "secret3"))
+		require.NotNil(t, err)
+		require.Equal(t, int64(neorpc.ErrUnauthorizedCode), err.Code)
+	})
+	t.Run("invalid scheme", func(t *testing.T) {
+		_, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5", "Authorization", "Basic c2VjcmV0MQ=="))
+		require.NotNil(t, err)
+		require.Equal(t, int64(neorpc.ErrUnauthorizedCode), err.Code)
+	})
+	t.Run("per IP", func(t *testing.T) {
+		c1, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5"))
+		require.Nil(t, err)
+		require.Equal(t, "1.2.3.4", c1.name)
+		c2, err := l.authenticate(newLimiterTestRequest("1.2.3.4:6"))
+		require.Nil(t, err)
+		require.True(t, c1 == c2)
+		c3, err := l.authenticate(newLimiterTestRequest("[::1]:5"))
+		require.Nil(t, err)
+		require.Equal(t, "::1", c3.name)
+	})
+	t.Run("required", func(t *testing.T) {
+		cfg := cfg
+		cfg.Required = true
+		l := newLimiter(cfg, log)
+		_, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5"))
+		require.NotNil(t, err)
+		require.Equal(t, int64(neorpc.ErrUnauthorizedCode), err.Code)
+		c, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5", "X-API-Key", "secret1"))
+		require.Nil(t, err)
+		require.Equal(t, "alice", c.name)
+	})
+}
+
+func TestLimiterCharge(t *testing.T) {
+	l := newLimiter(config.RPCAuth{
+		PerIPQuota:  config.RPCQuota{Rate: 10, Burst: 20},
+		MethodCosts: map[string]int{"getblockcount": 0, "getversion": 3},
+	}, zaptest.NewLogger(t))
+	c, err := l.authenticate(newLimiterTestRequest("1.2.3.4:5"))
+	require.Nil(t, err)
+
+	require.Nil(t, l.charge(c, "invokefunction"))
+	require.Nil(t, l.charge(c, "getversion"))
+	require.Nil(t, l.charge(c, "getblockcount"))
+	require.True(t, c.tokens < 8)
+
+	err = l.charge(c, "invokefunction")
+	require.NotNil(t, err)
+	require.Equal(t, int64(neorpc.ErrRateLimitExceededCode), err.Code)
+	require.Nil(t, l.charge(c, "getblockcount")) // Free.
+
+	// Refill.
+	c.last = c.last.Add(-time.Second)
+	require.Nil(t, l.charge(c, "invokefunction"))
+
+	t.Run("cost over burst", func(t *testing.T) {
+		c := newRPCClient("test", config.RPCQuota{Rate: 1})
+		require.True(t, c.take(10, time.Now()))
+		require.False(t, c.take(1, time.Now()))
+	})
+	t.Run("sweep", func(t *testing.T) {
+		_, err := l.authenticate(newLimiterTestRequest("5.6.7.8:9"))
+		require.Nil(t, err)
+		require.Equal(t, 2, len(l.ips))
+		l.lastSweep = time.Time{}
+		require.Nil(t, l.charge(c, "getblockcount"))
+		require.Equal(t, 1, len(l.ips)) // Only the one with spent quota is left.
+		require.NotNil(t, l.ips["1.2.3.4"])
+	})
+}
+
+func TestGetHTTPCodeForLimitErrors(t *testing.T) {
+	require.Equal(t, http.StatusUnauthorized, getHTTPCodeForError(neorpc.ErrUnauthorized))
+	require.Equal(t, http.StatusTooManyRequests, getHTTPCodeForError(neorpc.ErrRateLimitExceeded))
+}
+
+func TestRPCAuth(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.RPC.Auth = config.RPCAuth{
+			Required: true,
+			Keys: []config.RPCKey{
+				{Name: "limited", Key: "secret", Quota: config.RPCQuota{Rate: 1, Burst: 2}},
+			},
+		}
+	})
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	doRequest := func(t *testing.T, key string, body string) (int, *neorpc.Response) {
+		req, err := http.NewRequest("POST", httpSrv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var res neorpc.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		return resp.StatusCode, &res
+	}
+	const getBlockCount = `{"jsonrpc": "2.0", "id": 1, "method": "getblockcount", "params": []}`
+
+	code, resp := doRequest(t, "", getBlockCount)
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, int64(neorpc.ErrUnauthorizedCode), resp.Error.Code)
+
+	code, resp = doRequest(t, "wrong", getBlockCount)
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, int64(neorpc.ErrUnauthorizedCode), resp.Error.Code)
+
+	code, resp = doRequest(t, "secret", getBlockCount)
+	require.Equal(t, http.StatusOK, code)
+	require.Nil(t, resp.Error)
+
+	// Invocation costs more than the rest of the quota.
+	code, resp = doRequest(t, "secret", `{"jsonrpc": "2.0", "id": 1, "method": "invokescript", "params": ["EQ=="]}`)
+	require.Equal(t, http.StatusTooManyRequests, code)
+	require.Equal(t, int64(neorpc.ErrRateLimitExceededCode), resp.Error.Code)
+}
@@ -0,0 +1,81 @@
+package rpcsrv
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// balanceSetter is implemented by NEO and GAS native contracts.
+type balanceSetter interface {
+	SetBalance(ic *interop.Context, h util.Uint160, amount *big.Int) error
+}
+
+// applyStateOverrides changes the state of the given invocation context
+// according to overrides. Test invocation context uses its own private DAO,
+// so these changes never reach the chain.
+func applyStateOverrides(ic *interop.Context, o *neorpc.StateOverrides) error {
+	var mgmt *native.Management
+	for _, c := range ic.Natives {
+		if m, ok := c.(*native.Management); ok {
+			mgmt = m
+			break
+		}
+	}
+	if mgmt == nil {
+		return errors.New("no Management contract")
+	}
+	for i, c := range o.Contracts {
+		var neff *nef.File
+		if c.NEF != nil {
+			nf, err := nef.FileFromBytes(c.NEF)
+			if err != nil {
+				return fmt.Errorf("contract %d: invalid NEF file: %w", i, err)
+			}
+			neff = &nf
+		}
+		if neff == nil && c.Manifest == nil {
+			return fmt.Errorf("contract %d: both NEF and manifest are missing", i)
+		}
+		if _, err := mgmt.Override(ic, c.Hash, neff, c.Manifest); err != nil {
+			return fmt.Errorf("contract %d: %w", i, err)
+		}
+	}
+	for i, s := range o.Storage {
+		cs, err := native.GetContract(ic.DAO, s.Contract)
+		if err != nil {
+			return fmt.Errorf("storage item %d: unknown contract %s", i, s.Contract.StringLE())
+		}
+		if s.Value == nil {
+			ic.DAO.DeleteStorageItem(cs.ID, s.Key)
+		} else {
+			ic.DAO.PutStorageItem(cs.ID, s.Key, s.Value)
+		}
+	}
+	for i, b := range o.Balances {
+		var token balanceSetter
+		for _, c := range ic.Natives {
+			if c.Metadata().Hash.Equals(b.Token) {
+				token, _ = c.(balanceSetter)
+				break
+			}
+		}
+		if token == nil {
+			return fmt.Errorf("balance %d: %s is not a native token", i, b.Token.StringLE())
+		}
+		amount, ok := new(big.Int).SetString(b.Amount, 10)
+		if !ok {
+			return fmt.Errorf("balance %d: invalid amount %q", i, b.Amount)
+		}
+		if err := token.SetBalance(ic, b.Account, amount); err != nil {
+			return fmt.Errorf("balance %d: %w", i, err)
+		}
+	}
+	return nil
+}
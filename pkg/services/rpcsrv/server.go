@@ -20,11 +20,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/limits"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/iterator"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
@@ -55,7 +57,12 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/profile"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -70,6 +77,8 @@ type (
 		FeePerByte() int64
 		ForEachNEP11Transfer(acc util.Uint160, newestTimestamp uint64, f func(*state.NEP11Transfer) (bool, error)) error
 		ForEachNEP17Transfer(acc util.Uint160, newestTimestamp uint64, f func(*state.NEP17Transfer) (bool, error)) error
+		ForEachNotification(contract util.Uint160, name string, start, end uint32, f func(*state.NotificationIndexEntry) (bool, error)) error
+		ForEachTransaction(acc util.Uint160, start, end uint32, f func(*state.TransactionIndexEntry) (bool, error)) error
 		GetAppExecResults(util.Uint256, trigger.Type) ([]state.AppExecResult, error)
 		GetBaseExecFee() int64
 		GetBlock(hash util.Uint256) (*block.Block, error)
@@ -96,15 +105,18 @@ type (
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
 		GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
+		GetTransactionReplayVM(h util.Uint256) (*interop.Context, error)
 		HeaderHeight() uint32
 		InitVerificationContext(ic *interop.Context, hash util.Uint160, witness *transaction.Witness) error
 		SubscribeForBlocks(ch chan *block.Block)
 		SubscribeForExecutions(ch chan *state.AppExecResult)
 		SubscribeForNotifications(ch chan *state.ContainedNotificationEvent)
+		SubscribeForStorageChanges(ch chan *state.StorageChangeEvent)
 		SubscribeForTransactions(ch chan *transaction.Transaction)
 		UnsubscribeFromBlocks(ch chan *block.Block)
 		UnsubscribeFromExecutions(ch chan *state.AppExecResult)
 		UnsubscribeFromNotifications(ch chan *state.ContainedNotificationEvent)
+		UnsubscribeFromStorageChanges(ch chan *state.StorageChangeEvent)
 		UnsubscribeFromTransactions(ch chan *transaction.Transaction)
 		VerifyTx(*transaction.Transaction) error
 		VerifyWitness(util.Uint160, hash.Hashable, *transaction.Witness, int64) (int64, error)
@@ -136,7 +148,10 @@ type (
 		network          netmode.Magic
 		stateRootEnabled bool
 		coreServer       *network.Server
+		graphQL          *graphql.Schema
+		limiter          *limiter
 		oracle           *atomic.Value
+		dev              *atomic.Value
 		log              *zap.Logger
 		shutdown         chan struct{}
 		started          *atomic.Bool
@@ -154,12 +169,14 @@ type (
 		notificationSubs  int
 		transactionSubs   int
 		notaryRequestSubs int
+		storageSubs       int
 
 		blockCh           chan *block.Block
 		executionCh       chan *state.AppExecResult
 		notificationCh    chan *state.ContainedNotificationEvent
 		transactionCh     chan *transaction.Transaction
 		notaryRequestCh   chan mempoolevent.Event
+		storageCh         chan *state.StorageChangeEvent
 		subEventsToExitCh chan struct{}
 	}
 
@@ -201,13 +218,19 @@ const (
 
 	// defaultSessionPoolSize is the number of concurrently running iterator sessions.
 	defaultSessionPoolSize = 20
+
+	// tracerName is the name of the tracer used for RPC request spans.
+	tracerName = "github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
 )
 
 var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
+	"advancetime":                  (*Server).advanceTime,
 	"calculatenetworkfee":          (*Server).calculateNetworkFee,
+	"findnotifications":            (*Server).findNotifications,
 	"findstates":                   (*Server).findStates,
 	"findstorage":                  (*Server).findStorage,
 	"findstoragehistoric":          (*Server).findStorageHistoric,
+	"findtransactions":             (*Server).findTransactions,
 	"getapplicationlog":            (*Server).getApplicationLog,
 	"getbestblockhash":             (*Server).getBestBlockHash,
 	"getblock":                     (*Server).getBlock,
@@ -245,11 +268,15 @@ var rpcHandlers = map[string]func(*Server, params.Params) (any, *neorpc.Error){
 	"invokescripthistoric":         (*Server).invokescripthistoric,
 	"invokecontractverify":         (*Server).invokeContractVerify,
 	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
+	"mintblocks":                   (*Server).mintBlocks,
+	"revert":                       (*Server).revert,
 	"sendrawtransaction":           (*Server).sendrawtransaction,
+	"snapshot":                     (*Server).snapshot,
 	"submitblock":                  (*Server).submitBlock,
 	"submitnotaryrequest":          (*Server).submitNotaryRequest,
 	"submitoracleresponse":         (*Server).submitOracleResponse,
 	"terminatesession":             (*Server).terminateSession,
+	"tracetransaction":             (*Server).traceTransaction,
 	"traverseiterator":             (*Server).traverseIterator,
 	"validateaddress":              (*Server).validateAddress,
 	"verifyproof":                  (*Server).verifyProof,
@@ -310,6 +337,10 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		conf.MaxNEP11Tokens = config.DefaultMaxNEP11Tokens
 		log.Info("MaxNEP11Tokens is not set or wrong, setting default value", zap.Int("MaxNEP11Tokens", config.DefaultMaxNEP11Tokens))
 	}
+	if conf.MaxTraceSteps <= 0 {
+		conf.MaxTraceSteps = config.DefaultMaxTraceSteps
+		log.Info("MaxTraceSteps is not set or wrong, setting default value", zap.Int("MaxTraceSteps", config.DefaultMaxTraceSteps))
+	}
 	if conf.MaxWebSocketClients == 0 {
 		conf.MaxWebSocketClients = defaultMaxWebSocketClients
 		log.Info("MaxWebSocketClients is not set or wrong, setting default value", zap.Int("MaxWebSocketClients", defaultMaxWebSocketClients))
@@ -333,8 +364,10 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		network:          protoCfg.Magic,
 		stateRootEnabled: protoCfg.StateRootInHeader,
 		coreServer:       coreServer,
+		limiter:          newLimiter(conf.Auth, log),
 		log:              log,
 		oracle:           oracleWrapped,
+		dev:              new(atomic.Value),
 		shutdown:         make(chan struct{}),
 		started:          atomic.NewBool(false),
 		errChan:          errChan,
@@ -348,6 +381,7 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		notificationCh:    make(chan *state.ContainedNotificationEvent),
 		transactionCh:     make(chan *transaction.Transaction),
 		notaryRequestCh:   make(chan mempoolevent.Event),
+		storageCh:         make(chan *state.StorageChangeEvent),
 		subEventsToExitCh: make(chan struct{}),
 	}
 }
@@ -370,6 +404,14 @@ func (s *Server) Start() {
 		return
 	}
 
+	if s.config.GraphQLEnabled {
+		var err error
+		s.graphQL, err = s.newGraphQLSchema()
+		if err != nil {
+			s.errChan <- fmt.Errorf("failed to create GraphQL schema: %w", err)
+			return
+		}
+	}
 	go s.handleSubEvents()
 
 	for _, srv := range s.http {
@@ -474,6 +516,19 @@ func (s *Server) SetOracleHandler(orc OracleHandler) {
 func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Request) {
 	req := params.NewRequest()
 
+	if httpRequest.Method == "OPTIONS" && s.config.EnableCORSWorkaround { // Preflight CORS.
+		setCORSOriginHeaders(w.Header())
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST") // GET for websockets.
+		w.Header().Set("Access-Control-Max-Age", "21600")           // 6 hours.
+		return
+	}
+
+	client, authErr := s.limiter.authenticate(httpRequest)
+	if authErr != nil {
+		s.writeHTTPErrorResponse(params.NewIn(), w, authErr)
+		return
+	}
+
 	if httpRequest.URL.Path == "/ws" && httpRequest.Method == "GET" {
 		// Technically there is a race between this check and
 		// s.subscribers modification 20 lines below, but it's tiny
@@ -502,14 +557,16 @@ func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Requ
 		s.subscribers[subscr] = true
 		s.subsLock.Unlock()
 		go s.handleWsWrites(ws, resChan, subChan)
-		s.handleWsReads(ws, resChan, subscr)
+		s.handleWsReads(ws, resChan, subscr, client)
 		return
 	}
 
-	if httpRequest.Method == "OPTIONS" && s.config.EnableCORSWorkaround { // Preflight CORS.
-		setCORSOriginHeaders(w.Header())
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST") // GET for websockets.
-		w.Header().Set("Access-Control-Max-Age", "21600")           // 6 hours.
+	if httpRequest.URL.Path == "/graphql" && s.graphQL != nil {
+		if err := s.limiter.charge(client, "graphql"); err != nil {
+			s.writeGraphQLResponse(w, getHTTPCodeForError(err), graphQLError(err))
+			return
+		}
+		s.handleGraphQLRequest(w, httpRequest)
 		return
 	}
 
@@ -528,7 +585,7 @@ func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Requ
 		return
 	}
 
-	resp := s.handleRequest(req, nil)
+	resp := s.handleRequest(req, nil, client)
 	s.writeHTTPServerResponse(req, w, resp)
 }
 
@@ -545,15 +602,15 @@ func (s *Server) RegisterLocal(ctx context.Context, events chan<- neorpc.Notific
 	}
 }
 
-func (s *Server) handleRequest(req *params.Request, sub *subscriber) abstractResult {
+func (s *Server) handleRequest(req *params.Request, sub *subscriber, client *rpcClient) abstractResult {
 	if req.In != nil {
 		req.In.Method = escapeForLog(req.In.Method) // No valid method name will be changed by it.
-		return s.handleIn(req.In, sub)
+		return s.handleIn(req.In, sub, client)
 	}
 	resp := make(abstractBatch, len(req.Batch))
 	for i, in := range req.Batch {
 		in.Method = escapeForLog(in.Method) // No valid method name will be changed by it.
-		resp[i] = s.handleIn(&in, sub)
+		resp[i] = s.handleIn(&in, sub, client)
 	}
 	return resp
 }
@@ -582,6 +639,8 @@ func (s *Server) handleInternal(req *neorpc.Request, sub *subscriber) (*neorpc.R
 
 	start := time.Now()
 	defer func() { addReqTimeMetric(req.Method, time.Since(start)) }()
+	span := startRequestSpan(req.Method)
+	defer func() { endRequestSpan(span, rpcRes.Error) }()
 
 	rpcRes.Error = neorpc.NewMethodNotFoundError(fmt.Sprintf("method %q not supported", req.Method))
 	handler, ok := rpcHandlers[req.Method]
@@ -603,12 +662,15 @@ func (s *Server) handleInternal(req *neorpc.Request, sub *subscriber) (*neorpc.R
 	return rpcRes, nil
 }
 
-func (s *Server) handleIn(req *params.In, sub *subscriber) abstract {
+func (s *Server) handleIn(req *params.In, sub *subscriber, client *rpcClient) abstract {
 	var res any
 	var resErr *neorpc.Error
 	if req.JSONRPC != neorpc.JSONRPCVersion {
 		return s.packResponse(req, nil, neorpc.NewInvalidParamsError(fmt.Sprintf("problem parsing JSON: invalid version, expected 2.0 got '%s'", req.JSONRPC)))
 	}
+	if resErr = s.limiter.charge(client, req.Method); resErr != nil {
+		return s.packResponse(req, nil, resErr)
+	}
 
 	reqParams := params.Params(req.RawParams)
 
@@ -618,6 +680,8 @@ func (s *Server) handleIn(req *params.In, sub *subscriber) abstract {
 
 	start := time.Now()
 	defer func() { addReqTimeMetric(req.Method, time.Since(start)) }()
+	span := startRequestSpan(req.Method)
+	defer func() { endRequestSpan(span, resErr) }()
 
 	resErr = neorpc.NewMethodNotFoundError(fmt.Sprintf("method %q not supported", req.Method))
 	handler, ok := rpcHandlers[req.Method]
@@ -632,6 +696,23 @@ func (s *Server) handleIn(req *params.In, sub *subscriber) abstract {
 	return s.packResponse(req, res, resErr)
 }
 
+// startRequestSpan starts the tracing span for the RPC method call.
+func startRequestSpan(method string) trace.Span {
+	_, span := otel.Tracer(tracerName).Start(context.Background(), method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("rpc.method", method)))
+	return span
+}
+
+// endRequestSpan records an RPC error (if any) in the span and ends it.
+func endRequestSpan(span trace.Span, err *neorpc.Error) {
+	if err != nil {
+		span.SetAttributes(attribute.Int64("rpc.error_code", err.Code))
+		span.SetStatus(codes.Error, err.Message)
+	}
+	span.End()
+}
+
 func (s *Server) handleLocalNotifications(ctx context.Context, events chan<- neorpc.Notification, subChan <-chan intEvent, subscr *subscriber) {
 eventloop:
 	for {
@@ -709,7 +790,7 @@ drainloop:
 	}
 }
 
-func (s *Server) handleWsReads(ws *websocket.Conn, resChan chan<- abstractResult, subscr *subscriber) {
+func (s *Server) handleWsReads(ws *websocket.Conn, resChan chan<- abstractResult, subscr *subscriber, client *rpcClient) {
 	ws.SetReadLimit(s.wsReadLimit)
 	err := ws.SetReadDeadline(time.Now().Add(wsPongLimit))
 	ws.SetPongHandler(func(string) error { return ws.SetReadDeadline(time.Now().Add(wsPongLimit)) })
@@ -720,7 +801,7 @@ requestloop:
 		if err != nil {
 			break
 		}
-		res := s.handleRequest(req, subscr)
+		res := s.handleRequest(req, subscr, client)
 		res.RunForErrors(func(jsonErr *neorpc.Error) {
 			s.logRequestError(req, jsonErr)
 		})
@@ -1218,7 +1299,7 @@ func (s *Server) invokeReadOnlyMulti(bw *io.BufBinWriter, h util.Uint160, method
 	}
 	ic.VM.GasLimit = core.HeaderVerificationGasLimit
 	ic.VM.LoadScriptWithFlags(script, callflag.All)
-	err = ic.VM.Run()
+	err = ic.RunContext(context.Background())
 	if err != nil {
 		ic.Finalize()
 		return nil, nil, fmt.Errorf("failed to run %d methods of %s: %w", len(methods), h.StringLE(), err)
@@ -1440,6 +1521,186 @@ func (s *Server) getHash(contractID int32, cache map[int32]util.Uint160) (util.U
 	return h, nil
 }
 
+// getHeightsAndLimit returns block height range, limit and page parameters
+// starting from the given index. The range defaults to the whole chain and
+// the limit can't exceed MaxFindResultItems.
+func (s *Server) getHeightsAndLimit(ps params.Params, index int) (uint32, uint32, int, int, error) {
+	var (
+		start, end  = uint32(0), s.chain.BlockHeight()
+		limit, page = s.config.MaxFindResultItems, 0
+	)
+	pStart, pEnd, pLimit, pPage := ps.Value(index), ps.Value(index+1), ps.Value(index+2), ps.Value(index+3)
+	if pStart != nil {
+		val, err := pStart.GetInt()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if err := checkUint32(val); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		start = uint32(val)
+	}
+	if pEnd != nil {
+		val, err := pEnd.GetInt()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if err := checkUint32(val); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		end = uint32(val)
+	}
+	if start > end {
+		return 0, 0, 0, 0, errors.New("start height is greater than end height")
+	}
+	if pLimit != nil {
+		l, err := pLimit.GetInt()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if l <= 0 {
+			return 0, 0, 0, 0, errors.New("can't use negative or zero limit")
+		}
+		if l > s.config.MaxFindResultItems {
+			return 0, 0, 0, 0, errors.New("too big limit requested")
+		}
+		limit = l
+	}
+	if pPage != nil {
+		p, err := pPage.GetInt()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if p < 0 {
+			return 0, 0, 0, 0, errors.New("can't use negative page")
+		}
+		page = p
+	}
+	return start, end, limit, page, nil
+}
+
+// findNotifications returns notifications of the given contract (optionally
+// filtered by event name) using the execution index.
+func (s *Server) findNotifications(ps params.Params) (any, *neorpc.Error) {
+	if !s.chain.GetConfig().Ledger.ExecutionIndex {
+		return nil, neorpc.NewMethodNotFoundError(fmt.Sprintf("'findnotifications' is not supported: %s", core.ErrExecutionIndexDisabled))
+	}
+	h, respErr := s.contractScriptHashFromParam(ps.Value(0))
+	if respErr != nil {
+		return nil, respErr
+	}
+	var name string
+	if p := ps.Value(1); p != nil && !p.IsNull() {
+		var err error
+		name, err = p.GetString()
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid event name: %s", err))
+		}
+	}
+	start, end, limit, page, err := s.getHeightsAndLimit(ps, 2)
+	if err != nil {
+		return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("malformed heights/limit: %s", err))
+	}
+
+	var (
+		res  = &result.FindNotifications{Notifications: []result.FoundNotification{}}
+		skip = page * limit
+		aers = make(map[util.Uint256][]state.AppExecResult)
+	)
+	err = s.chain.ForEachNotification(h, name, start, end, func(e *state.NotificationIndexEntry) (bool, error) {
+		if len(res.Notifications) == limit {
+			res.Truncated = true
+			return false, nil
+		}
+		// Only notifications that are returned are counted for paging, so
+		// entries of untraceable containers are to be resolved before
+		// skipping.
+		execs, ok := aers[e.Container]
+		if !ok {
+			var err error
+			execs, err = s.chain.GetAppExecResults(e.Container, trigger.All)
+			if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+				return false, err
+			}
+			aers[e.Container] = execs
+		}
+		if len(execs) == 0 {
+			// Removed untraceable transaction or block (only its header
+			// is kept then).
+			return true, nil
+		}
+		for i := range execs {
+			if execs[i].Trigger != e.Trigger {
+				continue
+			}
+			if int(e.Index) >= len(execs[i].Events) {
+				return false, fmt.Errorf("no notification #%d in %s execution of %s", e.Index, e.Trigger, e.Container.StringLE())
+			}
+			if skip > 0 {
+				skip--
+				return true, nil
+			}
+			res.Notifications = append(res.Notifications, result.FoundNotification{
+				BlockIndex:  e.BlockIndex,
+				Container:   e.Container,
+				Trigger:     e.Trigger,
+				NotifyIndex: e.Index,
+				Event:       execs[i].Events[e.Index],
+			})
+			return true, nil
+		}
+		return false, fmt.Errorf("no %s execution for %s", e.Trigger, e.Container.StringLE())
+	})
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("invalid notification index: %s", err))
+	}
+	return res, nil
+}
+
+// findTransactions returns transactions signed by the given account using the
+// execution index.
+func (s *Server) findTransactions(ps params.Params) (any, *neorpc.Error) {
+	if !s.chain.GetConfig().Ledger.ExecutionIndex {
+		return nil, neorpc.NewMethodNotFoundError(fmt.Sprintf("'findtransactions' is not supported: %s", core.ErrExecutionIndexDisabled))
+	}
+	u, err := ps.Value(0).GetUint160FromAddressOrHex()
+	if err != nil {
+		return nil, neorpc.ErrInvalidParams
+	}
+	start, end, limit, page, err := s.getHeightsAndLimit(ps, 1)
+	if err != nil {
+		return nil, neorpc.NewInvalidParamsError(fmt.Sprintf("malformed heights/limit: %s", err))
+	}
+
+	var (
+		res = &result.FindTransactions{
+			Address:      address.Uint160ToString(u),
+			Transactions: []result.FoundTransaction{},
+		}
+		skip = page * limit
+	)
+	err = s.chain.ForEachTransaction(u, start, end, func(e *state.TransactionIndexEntry) (bool, error) {
+		if len(res.Transactions) == limit {
+			res.Truncated = true
+			return false, nil
+		}
+		if skip > 0 {
+			skip--
+			return true, nil
+		}
+		res.Transactions = append(res.Transactions, result.FoundTransaction{
+			Hash:       e.Hash,
+			BlockIndex: e.BlockIndex,
+			Sender:     e.Sender,
+		})
+		return true, nil
+	})
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("invalid transaction index: %s", err))
+	}
+	return res, nil
+}
+
 func (s *Server) contractIDFromParam(param *params.Param, root ...util.Uint256) (int32, *neorpc.Error) {
 	var result int32
 	if param == nil {
@@ -1593,7 +1854,7 @@ func (s *Server) getState(ps params.Params) (any, *neorpc.Error) {
 	res, err := s.chain.GetStateModule().GetState(root, sKey)
 	if err != nil {
 		if errors.Is(err, mpt.ErrNotFound) {
-			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid key: %s", err.Error()))
+			return nil, neorpc.ErrUnknownStorageItem
 		}
 		return nil, neorpc.NewInternalServerError(fmt.Sprintf("Failed to get historical item state: %s", err.Error()))
 	}
@@ -2098,11 +2359,11 @@ func (s *Server) getCommittee(_ params.Params) (any, *neorpc.Error) {
 
 // invokeFunction implements the `invokeFunction` RPC call.
 func (s *Server) invokeFunction(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, overrides)
 }
 
 // invokeFunctionHistoric implements the `invokeFunctionHistoric` RPC call.
@@ -2114,24 +2375,24 @@ func (s *Server) invokeFunctionHistoric(reqParams params.Params) (any, *neorpc.E
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeFunctionParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeFunctionParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.StateOverrides, *neorpc.Error) {
 	if len(reqParams) < 2 {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	scriptHash, responseErr := s.contractScriptHashFromParam(reqParams.Value(0))
 	if responseErr != nil {
-		return nil, false, responseErr
+		return nil, false, nil, responseErr
 	}
 	method, err := reqParams[1].GetString()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 	var invparams *params.Param
 	if len(reqParams) > 2 {
@@ -2141,7 +2402,7 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 3 {
 		signers, _, err := reqParams[3].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
 		}
 		tx.Signers = signers
 	}
@@ -2149,27 +2410,31 @@ func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.
 	if len(reqParams) > 4 {
 		verbose, err = reqParams[4].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.ErrInvalidParams
+			return nil, false, nil, neorpc.ErrInvalidParams
 		}
 	}
+	overrides, respErr := getStateOverrides(reqParams.Value(5))
+	if respErr != nil {
+		return nil, false, nil, respErr
+	}
 	if len(tx.Signers) == 0 {
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	script, err := params.CreateFunctionInvocationScript(scriptHash, method, invparams)
 	if err != nil {
-		return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
+		return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't create invocation script: %s", err))
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
 }
 
 // invokescript implements the `invokescript` RPC call.
 func (s *Server) invokescript(reqParams params.Params) (any, *neorpc.Error) {
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams)
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams)
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, overrides)
 }
 
 // invokescripthistoric implements the `invokescripthistoric` RPC call.
@@ -2181,24 +2446,24 @@ func (s *Server) invokescripthistoric(reqParams params.Params) (any, *neorpc.Err
 	if len(reqParams) < 2 {
 		return nil, neorpc.ErrInvalidParams
 	}
-	tx, verbose, respErr := s.getInvokeScriptParams(reqParams[1:])
+	tx, verbose, overrides, respErr := s.getInvokeScriptParams(reqParams[1:])
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, overrides)
 }
 
-func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
+func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.StateOverrides, *neorpc.Error) {
 	script, err := reqParams.Value(0).GetBytesBase64()
 	if err != nil {
-		return nil, false, neorpc.ErrInvalidParams
+		return nil, false, nil, neorpc.ErrInvalidParams
 	}
 
 	tx := &transaction.Transaction{}
 	if len(reqParams) > 1 {
 		signers, witnesses, err := reqParams[1].GetSignersWithWitnesses()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 		tx.Signers = signers
 		tx.Scripts = witnesses
@@ -2207,14 +2472,31 @@ func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Tr
 	if len(reqParams) > 2 {
 		verbose, err = reqParams[2].GetBoolean()
 		if err != nil {
-			return nil, false, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
+			return nil, false, nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
 		}
 	}
+	overrides, respErr := getStateOverrides(reqParams.Value(3))
+	if respErr != nil {
+		return nil, false, nil, respErr
+	}
 	if len(tx.Signers) == 0 {
 		tx.Signers = []transaction.Signer{{Account: util.Uint160{}, Scopes: transaction.None}}
 	}
 	tx.Script = script
-	return tx, verbose, nil
+	return tx, verbose, overrides, nil
+}
+
+// getStateOverrides parses optional state overrides parameter of invoke* calls.
+func getStateOverrides(p *params.Param) (*neorpc.StateOverrides, *neorpc.Error) {
+	if p == nil || p.IsNull() {
+		return nil, nil
+	}
+	var o = new(neorpc.StateOverrides)
+	err := json.Unmarshal(p.RawMessage, o)
+	if err != nil {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid state overrides: %s", err))
+	}
+	return o, nil
 }
 
 // invokeContractVerify implements the `invokecontractverify` RPC call.
@@ -2223,7 +2505,7 @@ func (s *Server) invokeContractVerify(reqParams params.Params) (any, *neorpc.Err
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false, nil)
 }
 
 // invokeContractVerifyHistoric implements the `invokecontractverifyhistoric` RPC call.
@@ -2239,7 +2521,7 @@ func (s *Server) invokeContractVerifyHistoric(reqParams params.Params) (any, *ne
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false, nil)
 }
 
 func (s *Server) getInvokeContractVerifyParams(reqParams params.Params) (util.Uint160, *transaction.Transaction, []byte, *neorpc.Error) {
@@ -2309,7 +2591,7 @@ func (s *Server) getHistoricParams(reqParams params.Params) (uint32, *neorpc.Err
 	return height + 1, nil
 }
 
-func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool) (*interop.Context, *neorpc.Error) {
+func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool, overrides *neorpc.StateOverrides) (*interop.Context, *neorpc.Error) {
 	var (
 		err error
 		ic  *interop.Context
@@ -2325,8 +2607,21 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create historic VM: %s", err))
 		}
 	}
+	if overrides != nil {
+		err = applyStateOverrides(ic, overrides)
+		if err != nil {
+			ic.Finalize()
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("can't apply state overrides: %s", err))
+		}
+		// Keep overrides out of the changes made by the invocation itself.
+		ic.DAO = ic.DAO.GetPrivate()
+	}
 	if verbose {
 		ic.VM.EnableInvocationTree()
+		if s.config.GasProfilerEnabled {
+			ic.Profiler = vm.NewProfiler()
+			ic.VM.SetProfiler(ic.Profiler)
+		}
 	}
 	ic.VM.GasLimit = int64(s.config.MaxGasInvoke)
 	if t == trigger.Verification {
@@ -2358,13 +2653,14 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 // result. The script is either a simple script in case of `application` trigger,
 // witness invocation script in case of `verification` trigger (it pushes `verify`
 // arguments on stack before verification). In case of contract verification
-// contractScriptHash should be specified.
-func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool) (*result.Invoke, *neorpc.Error) {
-	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, nextH, verbose)
+// contractScriptHash should be specified. Optional overrides are applied to the
+// state before running the script.
+func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool, overrides *neorpc.StateOverrides) (*result.Invoke, *neorpc.Error) {
+	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, nextH, verbose, overrides)
 	if respErr != nil {
 		return nil, respErr
 	}
-	err := ic.VM.Run()
+	err := ic.RunContext(context.Background())
 	var faultException string
 	if err != nil {
 		faultException = err.Error()
@@ -2379,7 +2675,7 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 		if s.config.SessionBackedByMPT && nextH == nil {
 			ic.Finalize()
 			// Rerun with MPT-backed storage.
-			return s.runScriptInVM(t, script, contractScriptHash, tx, &ic.Block.Index, verbose)
+			return s.runScriptInVM(t, script, contractScriptHash, tx, &ic.Block.Index, verbose, overrides)
 		}
 		id = uuid.New()
 		sessionID := id.String()
@@ -2417,6 +2713,9 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 			Invocations: tree.Calls,
 			Changes:     storage.BatchToOperations(ic.DAO.GetBatch()),
 		}
+		if ic.Profiler != nil {
+			diag.GasProfile = s.gasProfile(ic.DAO, ic.Profiler.Samples())
+		}
 	}
 	notifications := ic.Notifications
 	if notifications == nil {
@@ -2436,6 +2735,50 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 	return res, nil
 }
 
+// gasProfile builds a flat gas profile from the given samples, contract
+// methods are resolved using contract manifests from the invocation DAO (so
+// that contracts deployed or updated by the invocation itself are handled
+// properly) falling back to the chain state for contracts destroyed by it.
+func (s *Server) gasProfile(d *dao.Simple, samples []vm.ProfileSample) *result.GasProfile {
+	var (
+		resolvers profile.Resolvers
+		seen      = make(map[util.Uint160]bool)
+	)
+	for _, smp := range samples {
+		for _, f := range smp.Stack {
+			if seen[f.ScriptHash] {
+				continue
+			}
+			seen[f.ScriptHash] = true
+			cs, err := native.GetContract(d, f.ScriptHash)
+			if err != nil {
+				cs = s.chain.GetContractState(f.ScriptHash)
+			}
+			if cs != nil {
+				resolvers = append(resolvers, profile.NewManifestResolver(f.ScriptHash, &cs.Manifest))
+			}
+		}
+	}
+	rep := profile.NewReport(samples, resolvers)
+	return &result.GasProfile{
+		Opcodes:  toGasProfileEntries(rep.Opcodes),
+		Syscalls: toGasProfileEntries(rep.Syscalls),
+		Methods:  toGasProfileEntries(rep.Functions),
+	}
+}
+
+func toGasProfileEntries(entries []profile.Entry) []result.GasProfileEntry {
+	res := make([]result.GasProfileEntry, len(entries))
+	for i, e := range entries {
+		res[i] = result.GasProfileEntry{
+			Name:         e.Name,
+			Instructions: e.Instructions,
+			Gas:          e.Gas,
+		}
+	}
+	return res
+}
+
 // postProcessExecStack changes iterator interop items according to the server configuration.
 // It does modifications in-place, but it returns a session if any iterator was registered.
 func (s *Server) postProcessExecStack(stack []stackitem.Item) *session {
@@ -2722,6 +3065,10 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (any, *neor
 			} else if err == nil {
 				err = errors.New("invalid state")
 			}
+		case neorpc.StorageChangeEventID:
+			flt := new(neorpc.StorageFilter)
+			err = jd.Decode(flt)
+			filter = *flt
 		}
 		if err != nil {
 			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, err.Error())
@@ -2785,6 +3132,11 @@ func (s *Server) subscribeToChannel(event neorpc.EventID) {
 			s.coreServer.SubscribeForNotaryRequests(s.notaryRequestCh)
 		}
 		s.notaryRequestSubs++
+	case neorpc.StorageChangeEventID:
+		if s.storageSubs == 0 {
+			s.chain.SubscribeForStorageChanges(s.storageCh)
+		}
+		s.storageSubs++
 	}
 }
 
@@ -2840,6 +3192,11 @@ func (s *Server) unsubscribeFromChannel(event neorpc.EventID) {
 		if s.notaryRequestSubs == 0 {
 			s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 		}
+	case neorpc.StorageChangeEventID:
+		s.storageSubs--
+		if s.storageSubs == 0 {
+			s.chain.UnsubscribeFromStorageChanges(s.storageCh)
+		}
 	}
 }
 
@@ -2889,6 +3246,9 @@ chloop:
 				Type:          e.Type,
 				NotaryRequest: e.Data.(*payload.P2PNotaryRequest),
 			}
+		case change := <-s.storageCh:
+			resp.Event = neorpc.StorageChangeEventID
+			resp.Payload[0] = change
 		}
 		s.subsLock.RLock()
 	subloop:
@@ -2941,6 +3301,7 @@ chloop:
 	s.chain.UnsubscribeFromTransactions(s.transactionCh)
 	s.chain.UnsubscribeFromNotifications(s.notificationCh)
 	s.chain.UnsubscribeFromExecutions(s.executionCh)
+	s.chain.UnsubscribeFromStorageChanges(s.storageCh)
 	if s.chain.P2PSigExtensionsEnabled() {
 		s.coreServer.UnsubscribeFromNotaryRequests(s.notaryRequestCh)
 	}
@@ -2953,6 +3314,7 @@ drainloop:
 		case <-s.notificationCh:
 		case <-s.transactionCh:
 		case <-s.notaryRequestCh:
+		case <-s.storageCh:
 		default:
 			break drainloop
 		}
@@ -2964,6 +3326,7 @@ drainloop:
 	close(s.notificationCh)
 	close(s.executionCh)
 	close(s.notaryRequestCh)
+	close(s.storageCh)
 	// notify Shutdown routine
 	close(s.subEventsToExitCh)
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dboper"
@@ -41,6 +42,9 @@ import (
 	rpc2 "github.com/nspcc-dev/neo-go/pkg/services/oracle/broadcaster"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -144,6 +148,14 @@ var rpcFunctionsWithUnsupportedStatesTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnsupportedStateCode,
 		},
 	},
+	"tracetransaction": {
+		{
+			name:    "unsupported state",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.ErrUnsupportedStateCode,
+		},
+	},
 }
 
 var rpcTestCases = map[string][]rpcTestCase{
@@ -2110,6 +2122,110 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrUnknownContractCode,
 		},
 	},
+	"tracetransaction": {
+		{
+			name:   "positive",
+			params: `["` + deploymentTxHash + `"]`,
+			result: func(e *executor) any { return &result.TransactionTrace{} },
+			check: func(t *testing.T, e *executor, acc any) {
+				res, ok := acc.(*result.TransactionTrace)
+				require.True(t, ok)
+				h, err := util.Uint256DecodeStringLE(deploymentTxHash)
+				require.NoError(t, err)
+				aers, err := e.chain.GetAppExecResults(h, trigger.Application)
+				require.NoError(t, err)
+				require.Equal(t, h, res.TxHash)
+				require.Equal(t, aers[0].VMState, res.VMState)
+				require.Equal(t, aers[0].GasConsumed, res.GasConsumed)
+				require.Equal(t, len(aers[0].Events), len(res.Notifications))
+				require.Equal(t, 1, len(res.Invocations))
+				require.NotEmpty(t, res.Invocations[0].Calls)
+				require.NotEmpty(t, res.StorageReads)
+				require.NotEmpty(t, res.StorageChanges)
+				for _, acc := range append(res.StorageReads, res.StorageChanges...) {
+					require.NotEqual(t, util.Uint160{}, acc.Contract)
+				}
+				require.NotNil(t, res.Trace)
+				require.NotEmpty(t, res.Trace.Steps)
+				require.False(t, res.Trace.Truncated)
+				require.Equal(t, res.GasConsumed, res.Trace.Steps[len(res.Trace.Steps)-1].GasConsumed)
+				for _, step := range res.Trace.Steps {
+					require.Nil(t, step.Push)
+				}
+			},
+		},
+		{
+			name:   "positive, full detail",
+			params: `["` + deploymentTxHash + `", {"detail": "full"}]`,
+			result: func(e *executor) any { return &result.TransactionTrace{} },
+			check: func(t *testing.T, e *executor, acc any) {
+				res, ok := acc.(*result.TransactionTrace)
+				require.True(t, ok)
+				require.NotNil(t, res.Trace)
+				var pushed int
+				for _, step := range res.Trace.Steps {
+					pushed += len(step.Push)
+				}
+				require.NotZero(t, pushed)
+				_, err := res.Trace.StateAt(len(res.Trace.Steps))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "positive, no instructions",
+			params: `["` + deploymentTxHash + `", {"detail": "none"}]`,
+			result: func(e *executor) any { return &result.TransactionTrace{} },
+			check: func(t *testing.T, e *executor, acc any) {
+				res, ok := acc.(*result.TransactionTrace)
+				require.True(t, ok)
+				require.Nil(t, res.Trace)
+				require.Equal(t, vmstate.Halt, res.VMState)
+				require.NotEmpty(t, res.StorageChanges)
+			},
+		},
+		{
+			name:   "positive, steps limit",
+			params: `["` + deploymentTxHash + `", {"maxsteps": 3}]`,
+			result: func(e *executor) any { return &result.TransactionTrace{} },
+			check: func(t *testing.T, e *executor, acc any) {
+				res, ok := acc.(*result.TransactionTrace)
+				require.True(t, ok)
+				require.Equal(t, 3, len(res.Trace.Steps))
+				require.True(t, res.Trace.Truncated)
+				require.Equal(t, vmstate.Halt, res.VMState)
+			},
+		},
+		{
+			name:    "no params",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid hash",
+			params:  `["notahex"]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "unknown transaction",
+			params:  `["` + util.Uint256{}.StringLE() + `"]`,
+			fail:    true,
+			errCode: neorpc.ErrUnknownTransactionCode,
+		},
+		{
+			name:    "invalid detail",
+			params:  `["` + deploymentTxHash + `", {"detail": "everything"}]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+		{
+			name:    "invalid options",
+			params:  `["` + deploymentTxHash + `", 42]`,
+			fail:    true,
+			errCode: neorpc.InvalidParamsCode,
+		},
+	},
 	"sendrawtransaction": {
 		{
 			name:   "positive",
@@ -2181,6 +2297,38 @@ var rpcTestCases = map[string][]rpcTestCase{
 			errCode: neorpc.ErrOracleDisabledCode,
 		},
 	},
+	"mintblocks": {
+		{
+			name:    "dev mode disabled",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.ErrDevModeDisabledCode,
+		},
+	},
+	"advancetime": {
+		{
+			name:    "dev mode disabled",
+			params:  `[10]`,
+			fail:    true,
+			errCode: neorpc.ErrDevModeDisabledCode,
+		},
+	},
+	"snapshot": {
+		{
+			name:    "dev mode disabled",
+			params:  `[]`,
+			fail:    true,
+			errCode: neorpc.ErrDevModeDisabledCode,
+		},
+	},
+	"revert": {
+		{
+			name:    "dev mode disabled",
+			params:  `[0]`,
+			fail:    true,
+			errCode: neorpc.ErrDevModeDisabledCode,
+		},
+	},
 	"submitnotaryrequest": {
 		{
 			name:    "no params",
@@ -2624,13 +2772,13 @@ func testRPCProtocol(t *testing.T, doRPCCall func(string, string, *testing.T) []
 			params := fmt.Sprintf(`"%s", "%s", "%s"`, root.Root.StringLE(), testContractHash, base64.StdEncoding.EncodeToString([]byte("testkey")))
 			testGetState(t, params, base64.StdEncoding.EncodeToString([]byte("testvalue")))
 		})
-		t.Run("negative: invalid key", func(t *testing.T) {
+		t.Run("negative: unknown key", func(t *testing.T) {
 			root, err := e.chain.GetStateModule().GetStateRoot(4)
 			require.NoError(t, err)
 			// `testkey`-`testvalue` pair was put to the contract storage at block #3
 			params := fmt.Sprintf(`"%s", "%s", "%s"`, root.Root.StringLE(), testContractHash, base64.StdEncoding.EncodeToString([]byte("invalidkey")))
 			body := doRPCCall(fmt.Sprintf(rpc, params), httpSrv.URL, t)
-			checkErrGetResult(t, body, true, neorpc.InvalidParamsCode)
+			checkErrGetResult(t, body, true, neorpc.ErrUnknownStorageItemCode)
 		})
 		t.Run("good: fresh state", func(t *testing.T) {
 			root, err := e.chain.GetStateModule().GetStateRoot(16)
@@ -3962,7 +4110,7 @@ func BenchmarkHandleIn(b *testing.B) {
 				b.FailNow()
 			}
 
-			res := rpcServer.handleIn(in, nil)
+			res := rpcServer.handleIn(in, nil, nil)
 			if res.Error != nil {
 				b.FailNow()
 			}
@@ -4017,3 +4165,89 @@ func TestErrorResponseContentType(t *testing.T) {
 	contentType := resp.Header.Get("Content-Type")
 	require.Equal(t, expectedContentType, contentType)
 }
+
+func TestInvokeGasProfile(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithCustomConfig(t, func(c *config.Config) {
+		c.ApplicationConfiguration.RPC.GasProfilerEnabled = true
+	})
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	call := func(t *testing.T, verbose bool) *result.Invoke {
+		req := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "invokefunction", "params": ["%s", "resolve", [{"type":"String", "value":"neo.com"},{"type":"Integer","value":1}], [], %t]}`, nnsContractHash, verbose)
+		raw := checkErrGetResult(t, doRPCCallOverHTTP(req, httpSrv.URL, t), false, 0)
+		res := new(result.Invoke)
+		require.NoError(t, json.Unmarshal(raw, res))
+		require.Equal(t, "HALT", res.State)
+		return res
+	}
+	t.Run("no diagnostics", func(t *testing.T) {
+		res := call(t, false)
+		require.Nil(t, res.Diagnostics)
+	})
+	t.Run("diagnostics", func(t *testing.T) {
+		res := call(t, true)
+		require.NotNil(t, res.Diagnostics)
+		prof := res.Diagnostics.GasProfile
+		require.NotNil(t, prof)
+
+		var total int64
+		for _, e := range prof.Opcodes {
+			total += e.Gas
+		}
+		require.Equal(t, res.GasConsumed, total)
+
+		var nnsResolve, native bool
+		for _, e := range prof.Methods {
+			nnsResolve = nnsResolve || e.Name == "NameService.resolve"
+			native = native || strings.HasPrefix(e.Name, nativenames.StdLib+".") ||
+				strings.HasPrefix(e.Name, nativenames.CryptoLib+".")
+		}
+		require.True(t, nnsResolve)
+		require.True(t, native)
+
+		var syscalls []string
+		for _, e := range prof.Syscalls {
+			syscalls = append(syscalls, e.Name)
+		}
+		require.Contains(t, syscalls, interopnames.SystemContractCall)
+	})
+	t.Run("deployed by invocation", func(t *testing.T) {
+		nefFile, err := nef.NewFile([]byte{byte(opcode.INITSLOT), 0, 2, byte(opcode.RET)})
+		require.NoError(t, err)
+		nefBytes, err := nefFile.Bytes()
+		require.NoError(t, err)
+		m := manifest.NewManifest("Profiled")
+		m.ABI.Methods = []manifest.Method{{
+			Name: manifest.MethodDeploy,
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("data", smartcontract.AnyType),
+				manifest.NewParameter("isUpdate", smartcontract.BoolType),
+			},
+			ReturnType: smartcontract.VoidType,
+		}}
+		mBytes, err := json.Marshal(m)
+		require.NoError(t, err)
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, state.CreateNativeContractHash(nativenames.Management), "deploy", callflag.All, nefBytes, mBytes)
+		require.NoError(t, w.Err)
+
+		req := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "invokescript", "params": ["%s", [{"account":"%s","scopes":"CalledByEntry"}], true]}`,
+			base64.StdEncoding.EncodeToString(w.Bytes()), testchain.PrivateKeyByID(0).GetScriptHash().StringLE())
+		raw := checkErrGetResult(t, doRPCCallOverHTTP(req, httpSrv.URL, t), false, 0)
+		res := new(result.Invoke)
+		require.NoError(t, json.Unmarshal(raw, res))
+		require.Equal(t, "HALT", res.State, res.FaultException)
+		require.NotNil(t, res.Diagnostics)
+		require.NotNil(t, res.Diagnostics.GasProfile)
+
+		var deployed bool
+		for _, e := range res.Diagnostics.GasProfile.Methods {
+			deployed = deployed || e.Name == "Profiled."+manifest.MethodDeploy
+		}
+		require.True(t, deployed, res.Diagnostics.GasProfile.Methods)
+	})
+}
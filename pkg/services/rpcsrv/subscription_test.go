@@ -1,6 +1,7 @@
 package rpcsrv
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -18,7 +19,11 @@ import (
 	"go.uber.org/atomic"
 )
 
-const testOverflow = false
+const (
+	testOverflow = false
+	// gasHashLE is the GAS contract hash in LE form.
+	gasHashLE = "cf76e28bd0062c4a478ee35561011319f3cfa4d2"
+)
 
 func wsReader(t *testing.T, ws *websocket.Conn, msgCh chan<- []byte, isFinished *atomic.Bool) {
 	for {
@@ -259,6 +264,27 @@ func TestFilteredSubscriptions(t *testing.T) {
 				require.Equal(t, "HALT", st)
 			},
 		},
+		"storage change matching contract": {
+			params: `["storage_changed", {"contract":"` + testContractHash + `"}]`,
+			check: func(t *testing.T, resp *neorpc.Notification) {
+				rmap := resp.Payload[0].(map[string]any)
+				require.Equal(t, neorpc.StorageChangeEventID, resp.Event)
+				c := rmap["contract"].(string)
+				require.Equal(t, "0x"+testContractHash, c)
+			},
+		},
+		"storage change matching contract and prefix": {
+			params: `["storage_changed", {"contract":"` + gasHashLE + `", "prefix":"FA=="}]`,
+			check: func(t *testing.T, resp *neorpc.Notification) {
+				rmap := resp.Payload[0].(map[string]any)
+				require.Equal(t, neorpc.StorageChangeEventID, resp.Event)
+				c := rmap["contract"].(string)
+				require.Equal(t, "0x"+gasHashLE, c)
+				key, err := base64.StdEncoding.DecodeString(rmap["key"].(string))
+				require.NoError(t, err)
+				require.Equal(t, byte(20), key[0])
+			},
+		},
 		"tx non-matching": {
 			params: `["transaction_added", {"sender":"00112233445566778899aabbccddeeff00112233"}]`,
 			check: func(t *testing.T, _ *neorpc.Notification) {
@@ -271,6 +297,12 @@ func TestFilteredSubscriptions(t *testing.T) {
 				t.Fatal("unexpected match for contract 00112233445566778899aabbccddeeff00112233")
 			},
 		},
+		"storage change non-matching": {
+			params: `["storage_changed", {"contract":"00112233445566778899aabbccddeeff00112233"}]`,
+			check: func(t *testing.T, _ *neorpc.Notification) {
+				t.Fatal("unexpected match for contract 00112233445566778899aabbccddeeff00112233")
+			},
+		},
 		"execution non-matching": {
 			// We have single FAULTed transaction in chain, this, use the wrong hash for this test instead of FAULT state.
 			params: `["transaction_executed", {"container":"0x` + util.Uint256{}.StringLE() + `"}]`,
@@ -494,6 +526,8 @@ func TestBadSubUnsub(t *testing.T) {
 		"notification filter 2":  `{"jsonrpc": "2.0", "method": "subscribe", "params": ["notification_from_execution", "name"], "id": 1}`,
 		"execution filter 1":     `{"jsonrpc": "2.0", "method": "subscribe", "params": ["transaction_executed", "FAULT"], "id": 1}`,
 		"execution filter 2":     `{"jsonrpc": "2.0", "method": "subscribe", "params": ["transaction_executed", {"state": "STOP"}], "id": 1}`,
+		"storage filter 1":       `{"jsonrpc": "2.0", "method": "subscribe", "params": ["storage_changed", {"prefix": 1}], "id": 1}`,
+		"storage filter 2":       `{"jsonrpc": "2.0", "method": "subscribe", "params": ["storage_changed", {"name": "test"}], "id": 1}`,
 	}
 	var unsubCases = map[string]string{
 		"no params":         `{"jsonrpc": "2.0", "method": "unsubscribe", "params": [], "id": 1}`,
@@ -0,0 +1,199 @@
+package rpcsrv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv/params"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+)
+
+// readRecorder is a storage.Store wrapper remembering contract storage items
+// read from the lower store.
+type readRecorder struct {
+	storage.Store
+
+	lock  sync.Mutex
+	seen  map[string]bool
+	reads []readRecord
+}
+
+// readRecord is a single contract storage item read, key includes contract
+// ID, value is nil for missing items.
+type readRecord struct {
+	key   []byte
+	value []byte
+}
+
+func newReadRecorder(s storage.Store) *readRecorder {
+	return &readRecorder{
+		Store: s,
+		seen:  make(map[string]bool),
+	}
+}
+
+// Get implements storage.Store interface.
+func (r *readRecorder) Get(key []byte) ([]byte, error) {
+	val, err := r.Store.Get(key)
+	r.record(key, val)
+	return val, err
+}
+
+// Seek implements storage.Store interface.
+func (r *readRecorder) Seek(rng storage.SeekRange, f func(k, v []byte) bool) {
+	r.Store.Seek(rng, func(k, v []byte) bool {
+		r.record(k, v)
+		return f(k, v)
+	})
+}
+
+// record saves the first read of contract storage item.
+func (r *readRecorder) record(key []byte, value []byte) {
+	if len(key) < 5 || key[0] != byte(storage.STStorage) {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.seen[string(key)] {
+		return
+	}
+	r.seen[string(key)] = true
+	var rec = readRecord{key: make([]byte, len(key)-1)}
+	copy(rec.key, key[1:])
+	if value != nil {
+		rec.value = make([]byte, len(value))
+		copy(rec.value, value)
+	}
+	r.reads = append(r.reads, rec)
+}
+
+// Reads returns all recorded reads.
+func (r *readRecorder) Reads() []readRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	res := make([]readRecord, len(r.reads))
+	copy(res, r.reads)
+	return res
+}
+
+// traceTransaction implements the `tracetransaction` RPC call.
+func (s *Server) traceTransaction(reqParams params.Params) (any, *neorpc.Error) {
+	if s.chain.GetConfig().Ledger.KeepOnlyLatestState {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrUnsupportedState, fmt.Sprintf("'tracetransaction' is not supported: %s", errKeepOnlyLatestState))
+	}
+	h, err := reqParams.Value(0).GetUint256()
+	if err != nil {
+		return nil, neorpc.ErrInvalidParams
+	}
+	var opts neorpc.TraceOptions
+	if p := reqParams.Value(1); p != nil && !p.IsNull() {
+		err = json.Unmarshal(p.RawMessage, &opts)
+		if err != nil {
+			return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("invalid trace options: %s", err))
+		}
+	}
+	switch opts.Detail {
+	case "":
+		opts.Detail = neorpc.TraceDetailOpcodes
+	case neorpc.TraceDetailNone, neorpc.TraceDetailOpcodes, neorpc.TraceDetailFull:
+	default:
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, fmt.Sprintf("unknown trace detail level %q", opts.Detail))
+	}
+	if opts.MaxSteps < 0 {
+		return nil, neorpc.WrapErrorWithData(neorpc.ErrInvalidParams, "negative steps limit")
+	}
+	if opts.MaxSteps == 0 || opts.MaxSteps > s.config.MaxTraceSteps {
+		opts.MaxSteps = s.config.MaxTraceSteps
+	}
+	_, height, err := s.chain.GetTransaction(h)
+	if err != nil || height == math.MaxUint32 {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+
+	ic, err := s.chain.GetTransactionReplayVM(h)
+	if err != nil {
+		return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to prepare transaction replay: %s", err))
+	}
+	// Everything read by the transaction itself goes through the recorder.
+	var (
+		base = ic.DAO
+		rec  = newReadRecorder(base.Store)
+	)
+	ic.DAO = base.GetPrivate()
+	ic.DAO.Store = storage.NewPrivateMemCachedStore(rec)
+
+	ic.VM.EnableInvocationTree()
+	if opts.Detail != neorpc.TraceDetailNone {
+		ic.Tracer = vm.NewTracer()
+		ic.Tracer.SetStateless(opts.Detail != neorpc.TraceDetailFull)
+		ic.Tracer.SetLimit(opts.MaxSteps)
+		ic.VM.SetTracer(ic.Tracer)
+	}
+	ic.VM.LoadScriptWithFlags(ic.Tx.Script, callflag.All)
+	ic.VM.GasLimit = ic.Tx.SystemFee
+	err = ic.RunContext(context.Background())
+	reads := rec.Reads()
+	ic.Finalize()
+
+	res := &result.TransactionTrace{
+		TxHash:        h,
+		BlockIndex:    height,
+		VMState:       ic.VM.State(),
+		GasConsumed:   ic.VM.GasConsumed(),
+		Invocations:   ic.VM.GetInvocationTree().Calls,
+		StorageReads:  make([]result.StorageAccess, 0, len(reads)),
+		Notifications: ic.Notifications,
+	}
+	if err != nil {
+		res.FaultException = err.Error()
+	}
+	if ic.Tracer != nil {
+		res.Trace = ic.Tracer.Trace()
+	}
+	if res.Notifications == nil {
+		res.Notifications = make([]state.NotificationEvent, 0)
+	}
+	// Contract hashes are resolved using the resulting state first, since
+	// the contract may be deployed by the transaction itself, and the state
+	// before the transaction then (for destroyed contracts).
+	var resolve = func(key []byte) (util.Uint160, []byte) {
+		id := int32(binary.LittleEndian.Uint32(key))
+		for _, d := range []*dao.Simple{ic.DAO, base} {
+			if h, err := native.GetContractScriptHash(d, id); err == nil {
+				return h, key[4:]
+			}
+		}
+		return util.Uint160{}, key[4:]
+	}
+	for _, r := range reads {
+		acc := result.StorageAccess{State: "Read", Value: r.value}
+		if r.value == nil {
+			acc.State = "NotFound"
+		}
+		acc.Contract, acc.Key = resolve(r.key)
+		res.StorageReads = append(res.StorageReads, acc)
+	}
+	ops := storage.BatchToOperations(ic.DAO.GetBatch())
+	res.StorageChanges = make([]result.StorageAccess, 0, len(ops))
+	for _, op := range ops {
+		if len(op.Key) < 4 {
+			continue
+		}
+		acc := result.StorageAccess{State: op.State, Value: op.Value}
+		acc.Contract, acc.Key = resolve(op.Key)
+		res.StorageChanges = append(res.StorageChanges, acc)
+	}
+	return res, nil
+}
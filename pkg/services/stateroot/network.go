@@ -7,6 +7,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -103,7 +104,11 @@ func (s *service) sendValidatedRoot(r *state.MPTRoot, acc *wallet.Account) {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig := acc.SignHashable(s.Network, ep)
+	sig, err := acc.SignHash(hash.NetSha256(uint32(s.Network), ep))
+	if err != nil {
+		s.log.Error("can't sign validated state root", zap.Uint32("height", r.Index), zap.Error(err))
+		return
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	ep.Witness.InvocationScript = buf.Bytes()
@@ -14,6 +14,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/extsigner"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -102,7 +103,7 @@ func New(cfg config.StateRoot, sm *stateroot.Module, log *zap.Logger, bc Ledger,
 		}
 		var err error
 		w := cfg.UnlockWallet
-		if s.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
+		if s.wallet, err = extsigner.OpenWallet(w); err != nil {
 			return nil, err
 		}
 
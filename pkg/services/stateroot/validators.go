@@ -1,10 +1,12 @@
 package stateroot
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -89,7 +91,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 		return nil
 	}
 
-	sig := acc.SignHashable(s.Network, r)
+	sig, err := acc.SignHash(hash.NetSha256(uint32(s.Network), r))
+	if err != nil {
+		return fmt.Errorf("can't sign state root: %w", err)
+	}
 	incRoot := s.getIncompleteRoot(r.Index, myIndex)
 	incRoot.Lock()
 	defer incRoot.Unlock()
@@ -119,7 +124,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig = acc.SignHashable(s.Network, e)
+	sig, err = acc.SignHash(hash.NetSha256(uint32(s.Network), e))
+	if err != nil {
+		return fmt.Errorf("can't sign vote: %w", err)
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	e.Witness.InvocationScript = buf.Bytes()
@@ -0,0 +1,226 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exporter sends spans to the OTLP/HTTP collector using JSON encoding of
+// the OTLP protocol (see opentelemetry-proto trace/v1/trace.proto). Only the
+// subset of the protocol needed for node spans is implemented: all spans
+// share the resource of the provider, links are not used and attribute values
+// other than strings, integers and booleans are sent as strings.
+type exporter struct {
+	url    string
+	client *http.Client
+}
+
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpStatus struct {
+		Message string `json:"message,omitempty"`
+		Code    int    `json:"code,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	otlpValue struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		BoolValue   *bool   `json:"boolValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"`
+	}
+)
+
+// OTLP status codes, they differ from codes.Code values.
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// maxResponseSize is the maximum size of the collector response read for
+// error reporting.
+const maxResponseSize = 4096
+
+func newExporter(url string, timeout time.Duration) *exporter {
+	return &exporter{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// ExportSpans implements the sdktrace.SpanExporter interface.
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(toOTLP(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		return fmt.Errorf("failed to export spans: %s: %s", resp.Status, msg)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body) // Allow connection reuse.
+	return nil
+}
+
+// Shutdown implements the sdktrace.SpanExporter interface.
+func (e *exporter) Shutdown(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// toOTLP groups spans by instrumentation scope, the resource is taken from
+// the first span since the provider has a single one.
+func toOTLP(spans []sdktrace.ReadOnlySpan) *otlpRequest {
+	var (
+		rs     otlpResourceSpans
+		scopes = make(map[instrumentation.Scope]int)
+	)
+	if res := spans[0].Resource(); res != nil {
+		rs.Resource.Attributes = toKeyValues(res.Attributes())
+	}
+	for _, s := range spans {
+		scope := s.InstrumentationScope()
+		i, ok := scopes[scope]
+		if !ok {
+			i = len(rs.ScopeSpans)
+			scopes[scope] = i
+			rs.ScopeSpans = append(rs.ScopeSpans, otlpScopeSpans{
+				Scope: otlpScope{Name: scope.Name, Version: scope.Version},
+			})
+		}
+		rs.ScopeSpans[i].Spans = append(rs.ScopeSpans[i].Spans, toSpan(s))
+	}
+	return &otlpRequest{ResourceSpans: []otlpResourceSpans{rs}}
+}
+
+func toSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	span := otlpSpan{
+		TraceID:           sc.TraceID().String(),
+		SpanID:            sc.SpanID().String(),
+		Name:              s.Name(),
+		Kind:              int(s.SpanKind()), // OTLP enum matches trace.SpanKind.
+		StartTimeUnixNano: unixNano(s.StartTime()),
+		EndTimeUnixNano:   unixNano(s.EndTime()),
+		Attributes:        toKeyValues(s.Attributes()),
+	}
+	if p := s.Parent(); p.IsValid() {
+		span.ParentSpanID = p.SpanID().String()
+	}
+	for _, ev := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: unixNano(ev.Time),
+			Name:         ev.Name,
+			Attributes:   toKeyValues(ev.Attributes),
+		})
+	}
+	switch st := s.Status(); st.Code {
+	case codes.Ok:
+		span.Status.Code = otlpStatusOk
+	case codes.Error:
+		span.Status.Code = otlpStatusError
+		span.Status.Message = st.Description
+	}
+	return span
+}
+
+// unixNano encodes the time as a decimal string since 64-bit integers are
+// encoded this way in OTLP JSON.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func toKeyValues(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	res := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		res = append(res, otlpKeyValue{Key: string(kv.Key), Value: toValue(kv.Value)})
+	}
+	return res
+}
+
+func toValue(v attribute.Value) otlpValue {
+	var res otlpValue
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		res.BoolValue = &b
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		res.IntValue = &i
+	default:
+		s := v.Emit()
+		res.StringValue = &s
+	}
+	return res
+}
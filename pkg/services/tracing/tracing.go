@@ -0,0 +1,124 @@
+/*
+Package tracing implements the service that collects OpenTelemetry spans
+recorded by the node and exports them to the OTLP collector.
+
+Node components get their tracers from the global OpenTelemetry provider, so
+they record nothing until the service is started.
+*/
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Default configuration values.
+const (
+	DefaultEndpoint     = "http://localhost:4318"
+	DefaultServiceName  = "neo-go"
+	DefaultBatchTimeout = 5 * time.Second
+	DefaultTimeout      = 10 * time.Second
+)
+
+// Service manages the global tracer provider.
+type Service struct {
+	config   config.Tracing
+	log      *zap.Logger
+	exporter *exporter
+	provider *sdktrace.TracerProvider
+	started  *atomic.Bool
+}
+
+// New validates the configuration and returns a new service instance.
+func New(cfg config.Tracing, log *zap.Logger) (*Service, error) {
+	s := &Service{
+		config:  cfg,
+		log:     log.With(zap.String("service", "Tracing")),
+		started: atomic.NewBool(false),
+	}
+	if !cfg.Enabled {
+		return s, nil
+	}
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		return nil, fmt.Errorf("invalid SamplingRatio: %v", cfg.SamplingRatio)
+	}
+	if cfg.Endpoint == "" {
+		s.config.Endpoint = DefaultEndpoint
+	}
+	u, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Endpoint: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("invalid Endpoint: http(s) URL expected")
+	}
+	if s.config.ServiceName == "" {
+		s.config.ServiceName = DefaultServiceName
+	}
+	if s.config.SamplingRatio == 0 {
+		s.config.SamplingRatio = 1
+	}
+	if s.config.BatchTimeout <= 0 {
+		s.config.BatchTimeout = DefaultBatchTimeout
+	}
+	if s.config.Timeout <= 0 {
+		s.config.Timeout = DefaultTimeout
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/traces"
+	s.exporter = newExporter(u.String(), s.config.Timeout)
+	return s, nil
+}
+
+// Start sets the tracer provider exporting spans to the collector as the
+// global one. It does nothing if the service is disabled.
+func (s *Service) Start() {
+	if !s.config.Enabled {
+		s.log.Info("service hasn't started since it's disabled")
+		return
+	}
+	if !s.started.CompareAndSwap(false, true) {
+		s.log.Info("service already started")
+		return
+	}
+	s.log.Info("starting service", zap.String("endpoint", s.exporter.url))
+	s.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(s.exporter,
+			sdktrace.WithBatchTimeout(s.config.BatchTimeout),
+			sdktrace.WithExportTimeout(s.config.Timeout)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(s.config.ServiceName),
+			semconv.ServiceVersionKey.String(config.Version))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(s.config.SamplingRatio))),
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		s.log.Warn("tracing error", zap.Error(err))
+	}))
+	otel.SetTracerProvider(s.provider)
+}
+
+// ShutDown exports the remaining spans and disables tracing.
+func (s *Service) ShutDown() {
+	if !s.started.CompareAndSwap(true, false) {
+		return
+	}
+	s.log.Info("shutting down service", zap.String("endpoint", s.exporter.url))
+	otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+	if err := s.provider.Shutdown(ctx); err != nil {
+		s.log.Error("can't shut service down", zap.Error(err))
+	}
+}
@@ -0,0 +1,291 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNew(t *testing.T) {
+	s, err := New(config.Tracing{SamplingRatio: 2, Endpoint: "bad"}, zaptest.NewLogger(t))
+	require.NoError(t, err) // Disabled service isn't validated.
+	s.Start()
+	s.ShutDown()
+
+	for _, cfg := range []config.Tracing{
+		{Enabled: true, SamplingRatio: -0.5},
+		{Enabled: true, SamplingRatio: 1.5},
+		{Enabled: true, Endpoint: "localhost:4318"},
+		{Enabled: true, Endpoint: "grpc://localhost:4317"},
+		{Enabled: true, Endpoint: "http://"},
+	} {
+		_, err := New(cfg, zaptest.NewLogger(t))
+		require.Error(t, err, cfg)
+	}
+
+	s, err = New(config.Tracing{Enabled: true}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, DefaultEndpoint+"/v1/traces", s.exporter.url)
+	require.Equal(t, DefaultServiceName, s.config.ServiceName)
+	require.Equal(t, float64(1), s.config.SamplingRatio)
+	require.Equal(t, DefaultBatchTimeout, s.config.BatchTimeout)
+	require.Equal(t, DefaultTimeout, s.config.Timeout)
+
+	s, err = New(config.Tracing{Enabled: true, Endpoint: "https://collector:4318/otlp/"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, "https://collector:4318/otlp/v1/traces", s.exporter.url)
+}
+
+func TestService(t *testing.T) {
+	var (
+		lock sync.Mutex
+		reqs []otlpRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req otlpRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		lock.Lock()
+		reqs = append(reqs, req)
+		lock.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := New(config.Tracing{
+		Enabled:      true,
+		Endpoint:     srv.URL,
+		ServiceName:  "test-node",
+		BatchTimeout: time.Hour,
+	}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	s.Start()
+	s.Start() // Second start is no-op.
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	parent.SetAttributes(
+		attribute.Int64("index", 1<<62),
+		attribute.Bool("ok", true),
+		attribute.Float64("ratio", 0.5),
+		attribute.StringSlice("list", []string{"a", "b"}))
+	_, child := otel.Tracer("test").Start(ctx, "child")
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	s.ShutDown() // Flushes pending spans.
+	s.ShutDown()
+	_, noop := otel.Tracer("test").Start(context.Background(), "noop")
+	require.False(t, noop.IsRecording())
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, 1, len(reqs))
+	require.Equal(t, 1, len(reqs[0].ResourceSpans))
+	rs := reqs[0].ResourceSpans[0]
+	var svc string
+	for _, kv := range rs.Resource.Attributes {
+		if kv.Key == "service.name" {
+			svc = *kv.Value.StringValue
+		}
+	}
+	require.Equal(t, "test-node", svc)
+	require.Equal(t, 1, len(rs.ScopeSpans))
+	require.Equal(t, "test", rs.ScopeSpans[0].Scope.Name)
+
+	spans := rs.ScopeSpans[0].Spans
+	require.Equal(t, 2, len(spans))
+	c, p := spans[0], spans[1]
+	require.Equal(t, "child", c.Name)
+	require.Equal(t, "parent", p.Name)
+	require.Equal(t, p.TraceID, c.TraceID)
+	require.Equal(t, p.SpanID, c.ParentSpanID)
+	require.Empty(t, p.ParentSpanID)
+	require.Equal(t, 32, len(p.TraceID))
+	require.Equal(t, 16, len(p.SpanID))
+
+	require.Equal(t, otlpStatus{Code: otlpStatusError, Message: "boom"}, c.Status)
+	require.Equal(t, 1, len(c.Events))
+	require.Equal(t, "exception", c.Events[0].Name)
+	require.Equal(t, otlpStatus{}, p.Status)
+
+	attrs := make(map[string]otlpValue)
+	for _, kv := range p.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	require.Equal(t, "4611686018427387904", *attrs["index"].IntValue)
+	require.True(t, *attrs["ok"].BoolValue)
+	// Other types are sent as strings.
+	require.Equal(t, "0.5", *attrs["ratio"].StringValue)
+	require.Equal(t, "[a b]", *attrs["list"].StringValue)
+}
+
+func TestToOTLP(t *testing.T) {
+	var (
+		res   = resource.NewSchemaless(attribute.String("service.name", "node"))
+		start = time.Unix(1, 2)
+		end   = time.Unix(3, 4)
+		tid   = trace.TraceID{1, 2, 3}
+		sc    = func(id byte) trace.SpanContext {
+			return trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: trace.SpanID{id}})
+		}
+	)
+	spans := tracetest.SpanStubs{{
+		Name:                   "a",
+		SpanContext:            sc(1),
+		SpanKind:               trace.SpanKindServer,
+		StartTime:              start,
+		EndTime:                end,
+		Status:                 sdktrace.Status{Code: codes.Ok},
+		Resource:               res,
+		InstrumentationLibrary: instrumentation.Library{Name: "one", Version: "1.0"},
+	}, {
+		Name:        "b",
+		SpanContext: sc(2),
+		Parent:      sc(1),
+		SpanKind:    trace.SpanKindConsumer,
+		StartTime:   start,
+		EndTime:     end,
+		Attributes: []attribute.KeyValue{
+			attribute.String("s", "str"),
+			attribute.Int("i", -5),
+			attribute.Bool("b", false),
+			attribute.Float64("f", 1.5),
+			attribute.Int64Slice("is", []int64{1, 2}),
+		},
+		Events: []sdktrace.Event{{
+			Name:       "exception",
+			Time:       end,
+			Attributes: []attribute.KeyValue{attribute.String("exception.message", "boom")},
+		}},
+		Status:                 sdktrace.Status{Code: codes.Error, Description: "boom"},
+		Resource:               res,
+		InstrumentationLibrary: instrumentation.Library{Name: "two"},
+	}, {
+		Name:                   "c",
+		SpanContext:            sc(3),
+		StartTime:              start,
+		EndTime:                end,
+		Status:                 sdktrace.Status{Code: codes.Unset, Description: "ignored"},
+		Resource:               res,
+		InstrumentationLibrary: instrumentation.Library{Name: "one", Version: "1.0"},
+	}}.Snapshots()
+
+	strPtr := func(s string) *string { return &s }
+	boolPtr := func(b bool) *bool { return &b }
+	expected := &otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{{Key: "service.name", Value: otlpValue{StringValue: strPtr("node")}}}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "one", Version: "1.0"},
+			Spans: []otlpSpan{{
+				TraceID:           tid.String(),
+				SpanID:            trace.SpanID{1}.String(),
+				Name:              "a",
+				Kind:              2,
+				StartTimeUnixNano: "1000000002",
+				EndTimeUnixNano:   "3000000004",
+				Status:            otlpStatus{Code: otlpStatusOk},
+			}, {
+				TraceID:           tid.String(),
+				SpanID:            trace.SpanID{3}.String(),
+				Name:              "c",
+				StartTimeUnixNano: "1000000002",
+				EndTimeUnixNano:   "3000000004",
+			}},
+		}, {
+			Scope: otlpScope{Name: "two"},
+			Spans: []otlpSpan{{
+				TraceID:           tid.String(),
+				SpanID:            trace.SpanID{2}.String(),
+				ParentSpanID:      trace.SpanID{1}.String(),
+				Name:              "b",
+				Kind:              5,
+				StartTimeUnixNano: "1000000002",
+				EndTimeUnixNano:   "3000000004",
+				Attributes: []otlpKeyValue{
+					{Key: "s", Value: otlpValue{StringValue: strPtr("str")}},
+					{Key: "i", Value: otlpValue{IntValue: strPtr("-5")}},
+					{Key: "b", Value: otlpValue{BoolValue: boolPtr(false)}},
+					{Key: "f", Value: otlpValue{StringValue: strPtr("1.5")}},
+					{Key: "is", Value: otlpValue{StringValue: strPtr("[1 2]")}},
+				},
+				Events: []otlpEvent{{
+					TimeUnixNano: "3000000004",
+					Name:         "exception",
+					Attributes:   []otlpKeyValue{{Key: "exception.message", Value: otlpValue{StringValue: strPtr("boom")}}},
+				}},
+				Status: otlpStatus{Code: otlpStatusError, Message: "boom"},
+			}},
+		}},
+	}}}
+	require.Equal(t, expected, toOTLP(spans))
+
+	// Values are encoded the way OTLP JSON expects them.
+	data, err := json.Marshal(toOTLP(spans[:1]))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"resourceSpans":[{
+		"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"node"}}]},
+		"scopeSpans":[{"scope":{"name":"one","version":"1.0"},"spans":[{
+			"traceId":"`+tid.String()+`","spanId":"`+trace.SpanID{1}.String()+`","name":"a","kind":2,
+			"startTimeUnixNano":"1000000002","endTimeUnixNano":"3000000004","status":{"code":1}}]}]}]}`, string(data))
+}
+
+func TestExporter(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	t.Cleanup(srv.Close)
+
+	e := newExporter(srv.URL, time.Second)
+	require.NoError(t, e.ExportSpans(context.Background(), nil))
+	require.Equal(t, 0, calls)
+	spans := tracetest.SpanStubs{{Name: "span"}}.Snapshots()
+	require.NoError(t, e.ExportSpans(context.Background(), spans))
+	require.Equal(t, 1, calls)
+	require.NoError(t, e.Shutdown(context.Background()))
+
+	srv.Close()
+	require.Error(t, e.ExportSpans(context.Background(), spans))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, newExporter(srv.URL, time.Second).ExportSpans(ctx, spans), context.Canceled)
+}
+
+func TestExporterError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no way", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := New(config.Tracing{Enabled: true, Endpoint: srv.URL}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	s.Start()
+	t.Cleanup(s.ShutDown)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	require.ErrorContains(t, s.provider.ForceFlush(context.Background()), "no way")
+}
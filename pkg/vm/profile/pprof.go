@@ -0,0 +1,246 @@
+package profile
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the pprof profile.proto messages.
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileMapping           = 3
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profilePeriodType        = 11
+	profilePeriod            = 12
+	profileDefaultSampleType = 14
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+	sampleLabel      = 3
+
+	labelKey = 1
+	labelStr = 2
+
+	mappingID           = 1
+	mappingFilename     = 5
+	mappingHasFunctions = 7
+
+	locationID        = 1
+	locationMappingID = 2
+	locationAddress   = 3
+	locationLine      = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID       = 1
+	functionName     = 2
+	functionFilename = 4
+)
+
+// pprofBuilder accumulates profile data that is then encoded as a pprof
+// Profile message.
+type pprofBuilder struct {
+	resolver Resolver
+
+	strings   []string
+	stringIDs map[string]int64
+
+	mappings   []byte
+	mappingIDs map[util.Uint160]uint64
+
+	locations   []byte
+	locationIDs map[vm.ProfileFrame]uint64
+
+	functions   []byte
+	functionIDs map[Location]uint64
+}
+
+// WritePprof writes the samples as a gzipped pprof profile (suitable for
+// `go tool pprof`) using the given resolver (which can be nil) to get function
+// names and source code locations. Every script is represented as a separate
+// mapping and every instruction offset as a location with the offset used as
+// an address. Profile has two sample values, the number of instructions
+// executed and the amount of GAS (in datoshi) spent, the latter is the
+// default. Samples are labeled with opcode and syscall names.
+func WritePprof(w io.Writer, samples []vm.ProfileSample, r Resolver) error {
+	var (
+		b = &pprofBuilder{
+			resolver:    r,
+			stringIDs:   make(map[string]int64),
+			mappingIDs:  make(map[util.Uint160]uint64),
+			locationIDs: make(map[vm.ProfileFrame]uint64),
+			functionIDs: make(map[Location]uint64),
+		}
+		buf []byte
+	)
+	b.str("") // String table must start with an empty string.
+
+	buf = protowire.AppendTag(buf, profileSampleType, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, b.valueType("instructions", "count"))
+	buf = protowire.AppendTag(buf, profileSampleType, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, b.valueType("gas", "datoshi"))
+	for _, s := range samples {
+		buf = protowire.AppendTag(buf, profileSample, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, b.sample(s))
+	}
+	buf = append(buf, b.mappings...)
+	buf = append(buf, b.locations...)
+	buf = append(buf, b.functions...)
+	buf = protowire.AppendTag(buf, profilePeriodType, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, b.valueType("instructions", "count"))
+	buf = protowire.AppendTag(buf, profilePeriod, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, 1)
+	buf = protowire.AppendTag(buf, profileDefaultSampleType, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str("gas")))
+	// String table is the last one since all other fields can add strings.
+	for _, s := range b.strings {
+		buf = protowire.AppendTag(buf, profileStringTable, protowire.BytesType)
+		buf = protowire.AppendString(buf, s)
+	}
+
+	gw := gzip.NewWriter(w)
+	if _, err := gw.Write(buf); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// str returns the string table index of s adding it if needed.
+func (b *pprofBuilder) str(s string) int64 {
+	id, ok := b.stringIDs[s]
+	if !ok {
+		id = int64(len(b.strings))
+		b.strings = append(b.strings, s)
+		b.stringIDs[s] = id
+	}
+	return id
+}
+
+func (b *pprofBuilder) valueType(typ, unit string) []byte {
+	var buf []byte
+	buf = protowire.AppendTag(buf, valueTypeType, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(typ)))
+	buf = protowire.AppendTag(buf, valueTypeUnit, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(unit)))
+	return buf
+}
+
+func (b *pprofBuilder) sample(s vm.ProfileSample) []byte {
+	var ids, vals, buf []byte
+	for _, f := range s.Stack {
+		ids = protowire.AppendVarint(ids, b.location(f))
+	}
+	vals = protowire.AppendVarint(vals, uint64(s.Instructions))
+	vals = protowire.AppendVarint(vals, uint64(s.Gas))
+
+	buf = protowire.AppendTag(buf, sampleLocationID, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, ids)
+	buf = protowire.AppendTag(buf, sampleValue, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, vals)
+	buf = protowire.AppendTag(buf, sampleLabel, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, b.label("opcode", s.Opcode.String()))
+	if s.Opcode == opcode.SYSCALL {
+		buf = protowire.AppendTag(buf, sampleLabel, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, b.label("syscall", SyscallName(s.Syscall)))
+	}
+	return buf
+}
+
+func (b *pprofBuilder) label(key, value string) []byte {
+	var buf []byte
+	buf = protowire.AppendTag(buf, labelKey, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(key)))
+	buf = protowire.AppendTag(buf, labelStr, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(value)))
+	return buf
+}
+
+// mapping returns the ID of the mapping for the given script adding it if
+// needed.
+func (b *pprofBuilder) mapping(h util.Uint160) uint64 {
+	id, ok := b.mappingIDs[h]
+	if ok {
+		return id
+	}
+	id = uint64(len(b.mappingIDs) + 1)
+	b.mappingIDs[h] = id
+
+	var buf []byte
+	buf = protowire.AppendTag(buf, mappingID, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, id)
+	buf = protowire.AppendTag(buf, mappingFilename, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(unknownName(h))))
+	buf = protowire.AppendTag(buf, mappingHasFunctions, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, protowire.EncodeBool(true))
+
+	b.mappings = protowire.AppendTag(b.mappings, profileMapping, protowire.BytesType)
+	b.mappings = protowire.AppendBytes(b.mappings, buf)
+	return id
+}
+
+// location returns the ID of the location for the given frame adding it if
+// needed.
+func (b *pprofBuilder) location(f vm.ProfileFrame) uint64 {
+	id, ok := b.locationIDs[f]
+	if ok {
+		return id
+	}
+	id = uint64(len(b.locationIDs) + 1)
+	b.locationIDs[f] = id
+
+	loc := resolve(b.resolver, f)
+	var line, buf []byte
+	line = protowire.AppendTag(line, lineFunctionID, protowire.VarintType)
+	line = protowire.AppendVarint(line, b.function(loc))
+	line = protowire.AppendTag(line, lineLine, protowire.VarintType)
+	line = protowire.AppendVarint(line, uint64(loc.Line))
+
+	buf = protowire.AppendTag(buf, locationID, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, id)
+	buf = protowire.AppendTag(buf, locationMappingID, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, b.mapping(f.ScriptHash))
+	buf = protowire.AppendTag(buf, locationAddress, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(f.Offset))
+	buf = protowire.AppendTag(buf, locationLine, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, line)
+
+	b.locations = protowire.AppendTag(b.locations, profileLocation, protowire.BytesType)
+	b.locations = protowire.AppendBytes(b.locations, buf)
+	return id
+}
+
+// function returns the ID of the function for the given location adding it
+// if needed. Functions are identified by name and file only.
+func (b *pprofBuilder) function(loc Location) uint64 {
+	key := Location{Function: loc.Function, File: loc.File}
+	id, ok := b.functionIDs[key]
+	if ok {
+		return id
+	}
+	id = uint64(len(b.functionIDs) + 1)
+	b.functionIDs[key] = id
+
+	var buf []byte
+	buf = protowire.AppendTag(buf, functionID, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, id)
+	buf = protowire.AppendTag(buf, functionName, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(loc.Function)))
+	buf = protowire.AppendTag(buf, functionFilename, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(b.str(loc.File)))
+
+	b.functions = protowire.AppendTag(b.functions, profileFunction, protowire.BytesType)
+	b.functions = protowire.AppendBytes(b.functions, buf)
+	return id
+}
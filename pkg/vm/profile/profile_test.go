@@ -0,0 +1,172 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testManifest() *manifest.Manifest {
+	m := manifest.DefaultManifest("Foo")
+	m.ABI.Methods = []manifest.Method{
+		{Name: "second", Offset: 10},
+		{Name: "first", Offset: 0},
+	}
+	return m
+}
+
+func TestManifestResolver(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	r := NewManifestResolver(h, testManifest())
+
+	_, ok := r.Resolve(util.Uint160{}, 0)
+	require.False(t, ok)
+	for offset, name := range map[int]string{0: "Foo.first", 9: "Foo.first", 10: "Foo.second", 100: "Foo.second"} {
+		loc, ok := r.Resolve(h, offset)
+		require.True(t, ok)
+		require.Equal(t, Location{Function: name}, loc)
+	}
+}
+
+func TestDebugInfoResolver(t *testing.T) {
+	src := `package foo
+func Main() int {
+	return helper(1)
+}
+func helper(a int) int {
+	return a + 1
+}`
+	_, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	h := util.Uint160{1, 2, 3}
+	r := NewDebugInfoResolver(h, di)
+	_, ok := r.Resolve(util.Uint160{}, 0)
+	require.False(t, ok)
+
+	var found int
+	for _, m := range di.Methods {
+		for _, sp := range m.SeqPoints {
+			loc, ok := r.Resolve(h, sp.Opcode)
+			require.True(t, ok)
+			require.Equal(t, "foo."+m.ID, loc.Function)
+			require.Equal(t, di.Documents[sp.Document], loc.File)
+			require.Equal(t, sp.StartLine, loc.Line)
+			found++
+		}
+	}
+	require.True(t, found >= 2)
+}
+
+func testSamples(h util.Uint160) []vm.ProfileSample {
+	return []vm.ProfileSample{
+		{
+			Stack:        []vm.ProfileFrame{{ScriptHash: h, Offset: 12}, {ScriptHash: h, Offset: 3}},
+			Opcode:       opcode.SYSCALL,
+			Syscall:      interopnames.ToID([]byte(interopnames.SystemRuntimeLog)),
+			Instructions: 2,
+			Gas:          500,
+		},
+		{
+			Stack:        []vm.ProfileFrame{{ScriptHash: h, Offset: 3}},
+			Opcode:       opcode.CALL,
+			Instructions: 1,
+			Gas:          10,
+		},
+		{
+			Stack:        []vm.ProfileFrame{{ScriptHash: util.Uint160{}, Offset: 5}},
+			Opcode:       opcode.PUSH1,
+			Instructions: 4,
+			Gas:          4,
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	rep := NewReport(testSamples(h), NewManifestResolver(h, testManifest()))
+	require.Equal(t, &Report{
+		Opcodes: []Entry{
+			{Name: "SYSCALL", Instructions: 2, Gas: 500},
+			{Name: "CALL", Instructions: 1, Gas: 10},
+			{Name: "PUSH1", Instructions: 4, Gas: 4},
+		},
+		Syscalls: []Entry{
+			{Name: interopnames.SystemRuntimeLog, Instructions: 2, Gas: 500},
+		},
+		Functions: []Entry{
+			{Name: "Foo.second", Instructions: 2, Gas: 500},
+			{Name: "Foo.first", Instructions: 1, Gas: 10},
+			{Name: "0x" + util.Uint160{}.StringLE(), Instructions: 4, Gas: 4},
+		},
+		Instructions: 7,
+		Gas:          514,
+	}, rep)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, rep.Print(buf, 1))
+	out := buf.String()
+	require.Contains(t, out, "Total: 7 instructions, 0.00000514 GAS")
+	require.Contains(t, out, "Foo.second")
+	require.NotContains(t, out, "Foo.first")
+}
+
+func TestWritePprof(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, WritePprof(buf, testSamples(h), NewManifestResolver(h, testManifest())))
+
+	gr, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(gr)
+	require.NoError(t, err)
+
+	var (
+		counts  = make(map[protowire.Number]int)
+		strs    []string
+		defType uint64
+	)
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		require.True(t, n > 0)
+		raw = raw[n:]
+		counts[num]++
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(raw)
+			require.True(t, n > 0)
+			if num == profileStringTable {
+				strs = append(strs, string(v))
+			}
+			raw = raw[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(raw)
+			require.True(t, n > 0)
+			if num == profileDefaultSampleType {
+				defType = v
+			}
+			raw = raw[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+	}
+	require.Equal(t, 2, counts[profileSampleType])
+	require.Equal(t, 3, counts[profileSample])
+	require.Equal(t, 2, counts[profileMapping])
+	require.Equal(t, 3, counts[profileLocation])
+	require.Equal(t, 3, counts[profileFunction])
+	require.Equal(t, "", strs[0])
+	require.Equal(t, "gas", strs[defType])
+	require.Subset(t, strs, []string{"Foo.first", "Foo.second", "opcode", "SYSCALL", "syscall", interopnames.SystemRuntimeLog})
+}
@@ -0,0 +1,154 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Entry is a single report line.
+type Entry struct {
+	// Name is the name of the opcode, syscall or function.
+	Name string
+	// Instructions is the number of instructions executed.
+	Instructions int64
+	// Gas is the amount of GAS spent.
+	Gas int64
+}
+
+// Report is a flat gas profile, every entry contains its own (self) cost only.
+// All entry lists are sorted by GAS spent in descending order.
+type Report struct {
+	// Opcodes contains the data per opcode.
+	Opcodes []Entry
+	// Syscalls contains the data per syscall (SYSCALL instructions only).
+	Syscalls []Entry
+	// Functions contains the data per function executing the instruction.
+	// Native contract methods are included here if they can be resolved.
+	Functions []Entry
+	// Instructions is the total number of instructions executed.
+	Instructions int64
+	// Gas is the total amount of GAS spent.
+	Gas int64
+}
+
+// NewReport builds a Report from the given samples using the given resolver
+// (which can be nil) to get function names. Unresolved functions are named
+// after their script hash.
+func NewReport(samples []vm.ProfileSample, r Resolver) *Report {
+	var (
+		rep       = new(Report)
+		opcodes   = make(map[string]*Entry)
+		syscalls  = make(map[string]*Entry)
+		functions = make(map[string]*Entry)
+	)
+	for _, s := range samples {
+		rep.Instructions += s.Instructions
+		rep.Gas += s.Gas
+		addEntry(opcodes, s.Opcode.String(), s)
+		if s.Opcode == opcode.SYSCALL {
+			addEntry(syscalls, SyscallName(s.Syscall), s)
+		}
+		if len(s.Stack) != 0 {
+			addEntry(functions, resolve(r, s.Stack[0]).Function, s)
+		}
+	}
+	rep.Opcodes = sortEntries(opcodes)
+	rep.Syscalls = sortEntries(syscalls)
+	rep.Functions = sortEntries(functions)
+	return rep
+}
+
+// SyscallName returns the name of the syscall with the given ID or its
+// hexadecimal representation if it's unknown.
+func SyscallName(id uint32) string {
+	name, err := interopnames.FromID(id)
+	if err != nil {
+		return fmt.Sprintf("0x%08x", id)
+	}
+	return name
+}
+
+// Print writes the report as a set of human-readable tables, at most n entries
+// are printed for every table (all of them if n is not positive).
+func (rep *Report) Print(w io.Writer, n int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Total: %d instructions, %s GAS\n", rep.Instructions, gasString(rep.Gas))
+	for _, t := range []struct {
+		title   string
+		entries []Entry
+	}{
+		{"FUNCTION", rep.Functions},
+		{"SYSCALL", rep.Syscalls},
+		{"OPCODE", rep.Opcodes},
+	} {
+		if len(t.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s\tINSTRUCTIONS\tGAS\t%%\n", t.title)
+		for i, e := range t.entries {
+			if n > 0 && i >= n {
+				break
+			}
+			var pct float64
+			if rep.Gas != 0 {
+				pct = float64(e.Gas) * 100 / float64(rep.Gas)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%.2f\n", e.Name, e.Instructions, gasString(e.Gas), pct)
+		}
+	}
+	return tw.Flush()
+}
+
+func gasString(gas int64) string {
+	return fixedn.Fixed8(gas).String()
+}
+
+func addEntry(m map[string]*Entry, name string, s vm.ProfileSample) {
+	e, ok := m[name]
+	if !ok {
+		e = &Entry{Name: name}
+		m[name] = e
+	}
+	e.Instructions += s.Instructions
+	e.Gas += s.Gas
+}
+
+func sortEntries(m map[string]*Entry) []Entry {
+	res := make([]Entry, 0, len(m))
+	for _, e := range m {
+		res = append(res, *e)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Gas != res[j].Gas {
+			return res[i].Gas > res[j].Gas
+		}
+		if res[i].Instructions != res[j].Instructions {
+			return res[i].Instructions > res[j].Instructions
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// resolve returns the location for the given frame, unresolved frames are
+// named after their script hash.
+func resolve(r Resolver, f vm.ProfileFrame) Location {
+	if r != nil {
+		if loc, ok := r.Resolve(f.ScriptHash, f.Offset); ok {
+			return loc
+		}
+	}
+	return Location{Function: unknownName(f.ScriptHash)}
+}
+
+func unknownName(h util.Uint160) string {
+	return "0x" + h.StringLE()
+}
@@ -0,0 +1,153 @@
+/*
+Package profile contains tools for gas profiler data analysis. It resolves
+script offsets collected by vm.Profiler into contract methods and source code
+locations, builds aggregated reports and writes pprof-compatible profiles.
+*/
+package profile
+
+import (
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Location is a resolved script location.
+type Location struct {
+	// Function is the name of the function (or contract method).
+	Function string
+	// File is the source code file name, it can be empty if unknown.
+	File string
+	// Line is the source code line number, it's 0 if unknown.
+	Line int
+}
+
+// Resolver resolves script offsets into locations.
+type Resolver interface {
+	// Resolve returns the location for the given script offset and a flag
+	// specifying whether the location is known.
+	Resolve(h util.Uint160, offset int) (Location, bool)
+}
+
+// ResolverFunc is an adapter allowing to use ordinary functions as Resolver.
+type ResolverFunc func(h util.Uint160, offset int) (Location, bool)
+
+// Resolvers is a list of resolvers tried one by one until the location is
+// resolved.
+type Resolvers []Resolver
+
+type (
+	// manifestResolver resolves offsets into contract methods using the
+	// contract manifest.
+	manifestResolver struct {
+		hash    util.Uint160
+		name    string
+		methods []manifest.Method
+	}
+
+	// debugInfoResolver resolves offsets into Go functions and source code
+	// lines using the contract debug info.
+	debugInfoResolver struct {
+		hash util.Uint160
+		di   *compiler.DebugInfo
+	}
+)
+
+// Resolve implements the Resolver interface.
+func (f ResolverFunc) Resolve(h util.Uint160, offset int) (Location, bool) {
+	return f(h, offset)
+}
+
+// Resolve implements the Resolver interface.
+func (rs Resolvers) Resolve(h util.Uint160, offset int) (Location, bool) {
+	for _, r := range rs {
+		if loc, ok := r.Resolve(h, offset); ok {
+			return loc, true
+		}
+	}
+	return Location{}, false
+}
+
+// NewManifestResolver returns a Resolver for the contract with the given hash
+// using its manifest. Every method is assumed to span up to the next method
+// offset, so unexported functions are attributed to the preceding method. It
+// works well for native contracts and is a reasonable fallback for contracts
+// without debug info. Resolved function names have the "Contract.method" form.
+func NewManifestResolver(h util.Uint160, m *manifest.Manifest) Resolver {
+	methods := make([]manifest.Method, len(m.ABI.Methods))
+	copy(methods, m.ABI.Methods)
+	sort.SliceStable(methods, func(i, j int) bool {
+		return methods[i].Offset < methods[j].Offset
+	})
+	return &manifestResolver{
+		hash:    h,
+		name:    m.Name,
+		methods: methods,
+	}
+}
+
+// Resolve implements the Resolver interface.
+func (r *manifestResolver) Resolve(h util.Uint160, offset int) (Location, bool) {
+	if !h.Equals(r.hash) {
+		return Location{}, false
+	}
+	i := sort.Search(len(r.methods), func(i int) bool {
+		return r.methods[i].Offset > offset
+	})
+	if i == 0 {
+		return Location{}, false
+	}
+	return Location{Function: r.name + "." + r.methods[i-1].Name}, true
+}
+
+// NewDebugInfoResolver returns a Resolver for the contract with the given hash
+// using its debug info produced by the compiler. Resolved function names have
+// the "package.Function" form, file and line are taken from the closest
+// sequence point preceding the offset.
+func NewDebugInfoResolver(h util.Uint160, di *compiler.DebugInfo) Resolver {
+	return &debugInfoResolver{
+		hash: h,
+		di:   di,
+	}
+}
+
+// Resolve implements the Resolver interface.
+func (r *debugInfoResolver) Resolve(h util.Uint160, offset int) (Location, bool) {
+	if !h.Equals(r.hash) {
+		return Location{}, false
+	}
+	// Functions that are not emitted (like inlined ones) have the whole
+	// script as their range, so the narrowest matching one is used.
+	var m *compiler.MethodDebugInfo
+	for i := range r.di.Methods {
+		rng := r.di.Methods[i].Range
+		if offset < int(rng.Start) || offset > int(rng.End) {
+			continue
+		}
+		if m == nil || rng.End-rng.Start < m.Range.End-m.Range.Start {
+			m = &r.di.Methods[i]
+		}
+	}
+	if m == nil {
+		return Location{}, false
+	}
+	loc := Location{Function: m.ID}
+	if m.Name.Namespace != "" {
+		loc.Function = m.Name.Namespace + "." + m.ID
+	}
+	var best = -1
+	for i, sp := range m.SeqPoints {
+		if sp.Opcode <= offset && (best < 0 || sp.Opcode >= m.SeqPoints[best].Opcode) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		sp := m.SeqPoints[best]
+		if sp.Document >= 0 && sp.Document < len(r.di.Documents) {
+			loc.File = r.di.Documents[sp.Document]
+		}
+		loc.Line = sp.StartLine
+	}
+	return loc, true
+}
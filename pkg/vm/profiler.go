@@ -0,0 +1,155 @@
+package vm
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Profiler collects the number of executed instructions and the amount of GAS
+// spent by them. Data is aggregated by the invocation stack, opcode and syscall
+// ID, so that it can be attributed to contract methods (or source code
+// functions) later. A single Profiler can be used by multiple VMs (and is safe
+// for concurrent use), data from all of them is accumulated.
+type Profiler struct {
+	lock    sync.Mutex
+	samples map[string]*ProfileSample
+}
+
+// ProfileFrame is a single invocation stack frame.
+type ProfileFrame struct {
+	// ScriptHash is the hash of the script being executed.
+	ScriptHash util.Uint160
+	// Offset is the offset of the current instruction in the script.
+	Offset int
+}
+
+// ProfileSample is an aggregated profiling data for some particular
+// instruction executed with some particular invocation stack.
+type ProfileSample struct {
+	// Stack is the invocation stack, the first frame is the one executing
+	// the instruction.
+	Stack []ProfileFrame
+	// Opcode is the instruction opcode.
+	Opcode opcode.Opcode
+	// Syscall is the interop ID for SYSCALL instructions, it's 0 for other
+	// opcodes.
+	Syscall uint32
+	// Instructions is the number of times the instruction was executed.
+	Instructions int64
+	// Gas is the amount of GAS spent by the instruction itself (including
+	// syscall and native method prices), GAS spent by nested executions
+	// (like contract calls made by native contracts) is not included.
+	Gas int64
+}
+
+// NewProfiler returns a new empty Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		samples: make(map[string]*ProfileSample),
+	}
+}
+
+// SetProfiler registers the given Profiler in v, nil can be used to disable
+// profiling.
+func (v *VM) SetProfiler(p *Profiler) {
+	v.profNested = 0
+	if p == nil {
+		v.setHook(hookProfiler, nil)
+		return
+	}
+	v.setHook(hookProfiler, func(_ *Context, op opcode.Opcode, parameter []byte) func(error) {
+		return v.profile(p, op, parameter)
+	})
+}
+
+// Samples returns a copy of all collected samples sorted by GAS spent (in
+// descending order).
+func (p *Profiler) Samples() []ProfileSample {
+	p.lock.Lock()
+	res := make([]ProfileSample, 0, len(p.samples))
+	for _, s := range p.samples {
+		cp := *s
+		cp.Stack = append([]ProfileFrame(nil), s.Stack...)
+		res = append(res, cp)
+	}
+	p.lock.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Gas != res[j].Gas {
+			return res[i].Gas > res[j].Gas
+		}
+		return res[i].Instructions > res[j].Instructions
+	})
+	return res
+}
+
+// Reset drops all collected data.
+func (p *Profiler) Reset() {
+	p.lock.Lock()
+	p.samples = make(map[string]*ProfileSample)
+	p.lock.Unlock()
+}
+
+// captureStack returns the current invocation stack (leaf first) and a key
+// identifying it.
+func captureStack(istack []*Context) ([]ProfileFrame, []byte) {
+	var (
+		stack = make([]ProfileFrame, len(istack))
+		key   = make([]byte, 0, len(istack)*(util.Uint160Size+4)+5)
+	)
+	for i := range istack {
+		ctx := istack[len(istack)-1-i]
+		stack[i] = ProfileFrame{ScriptHash: ctx.ScriptHash(), Offset: ctx.ip}
+		key = append(key, stack[i].ScriptHash.BytesBE()...)
+		key = appendUint32(key, uint32(ctx.ip))
+	}
+	return stack, key
+}
+
+// appendUint32 appends little-endian u to b.
+func appendUint32(b []byte, u uint32) []byte {
+	return append(b, byte(u), byte(u>>8), byte(u>>16), byte(u>>24))
+}
+
+// add accounts a single instruction execution.
+func (p *Profiler) add(stack []ProfileFrame, key []byte, op opcode.Opcode, syscall uint32, gas int64) {
+	key = append(key, byte(op))
+	key = appendUint32(key, syscall)
+	p.lock.Lock()
+	s, ok := p.samples[string(key)]
+	if !ok {
+		s = &ProfileSample{
+			Stack:   stack,
+			Opcode:  op,
+			Syscall: syscall,
+		}
+		p.samples[string(key)] = s
+	}
+	s.Instructions++
+	s.Gas += gas
+	p.lock.Unlock()
+}
+
+// profile starts profiling of the instruction being executed, the function
+// returned must be called after the execution.
+func (v *VM) profile(p *Profiler, op opcode.Opcode, parameter []byte) func(error) {
+	var (
+		stack, key = captureStack(v.istack)
+		syscall    uint32
+		startGas   = v.gasConsumed
+		outer      = v.profNested
+	)
+	if op == opcode.SYSCALL && len(parameter) == 4 {
+		syscall = GetInteropID(parameter)
+	}
+	// Instructions executed from within this one (native contracts can
+	// call other contracts) account their GAS into profNested.
+	v.profNested = 0
+	return func(error) {
+		total := v.gasConsumed - startGas
+		p.add(stack, key, op, syscall, total-v.profNested)
+		v.profNested = outer + total
+	}
+}
@@ -0,0 +1,655 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Tracer records the execution trace of a VM: every instruction executed with
+// the changes it made to the evaluation stack and slots of the current
+// context. Compound items (arrays, structs, maps and buffers) are recorded in
+// the state they have after the instruction, changes made to them in-place
+// (while they stay on the stack or in a slot) are not tracked. Unlike
+// Profiler, Tracer is not safe for concurrent use and should be used with a
+// single VM.
+type Tracer struct {
+	trace  Trace
+	hashes map[util.Uint160]bool
+	// level is the number of instructions being executed now (more than one
+	// when instructions are executed from within other ones, like native
+	// contracts calling other contracts).
+	level int
+	// limit is the maximum number of steps to record (0 for no limit).
+	limit int
+	// stateless disables stack and slot changes recording.
+	stateless bool
+}
+
+// Trace is an execution trace recorded by Tracer, it can be serialized to
+// JSON and replayed later (see VM.SetReplay).
+type Trace struct {
+	// Scripts contains all scripts executed.
+	Scripts []TraceScript `json:"scripts"`
+	// Start is the state of the current context before the first step.
+	Start TraceState `json:"start"`
+	// Steps contains all executed instructions in the order of execution
+	// start.
+	Steps []TraceStep `json:"steps"`
+	// Truncated is set if not all steps were recorded because of the limit
+	// set with SetLimit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TraceScript is a script executed during the traced execution.
+type TraceScript struct {
+	Hash   util.Uint160 `json:"hash"`
+	Script []byte       `json:"script"`
+}
+
+// TraceState is the state of the evaluation stack and slots of the current
+// context. Items are serialized to JSON with types (see
+// stackitem.ToJSONWithTypes), the top of the stack is the last item.
+type TraceState struct {
+	EStack    []json.RawMessage `json:"estack"`
+	Static    []json.RawMessage `json:"static"`
+	Local     []json.RawMessage `json:"local"`
+	Arguments []json.RawMessage `json:"arguments"`
+}
+
+// TraceStep is a single executed instruction.
+type TraceStep struct {
+	// ScriptHash is the hash of the script being executed.
+	ScriptHash util.Uint160
+	// Offset is the offset of the instruction in the script.
+	Offset int
+	// Opcode is the instruction opcode.
+	Opcode opcode.Opcode
+	// Depth is the invocation stack depth.
+	Depth int
+	// Level is the nesting level of the step, it's 0 for the instructions
+	// executed by the VM directly and greater than 0 for the ones executed
+	// from within another instruction (they follow it in the trace).
+	Level int
+	// GasConsumed is the amount of GAS consumed by the VM after the
+	// instruction.
+	GasConsumed int64
+	// Error is set if the instruction has failed.
+	Error string
+	// Before is the state before the instruction, it's only set for steps
+	// that can't be derived from the previous ones (the first nested step).
+	Before *TraceState
+	// Pop is the number of items popped from the evaluation stack.
+	Pop int
+	// Push contains items pushed to the evaluation stack.
+	Push []json.RawMessage
+	// NewStack is set if the evaluation stack was changed along with the
+	// context, Pop and Push refer to the old stack then and EStack contains
+	// the whole new one.
+	NewStack bool
+	EStack   []json.RawMessage
+	// Static, Local and Arguments contain changes made to the slots.
+	Static    *TraceSlotDelta
+	Local     *TraceSlotDelta
+	Arguments *TraceSlotDelta
+	// Loaded contains contexts loaded by the instruction.
+	Loaded []TraceContext
+}
+
+// TraceSlotDelta is a change made to the slot by some instruction.
+type TraceSlotDelta struct {
+	// Replaced is set if the slot was replaced (initialized or changed along
+	// with the context), Items contains the whole new slot then.
+	Replaced bool `json:"replaced,omitempty"`
+	// Size is the slot size after the instruction.
+	Size int `json:"size"`
+	// Items contains changed slot items by their index.
+	Items map[int]json.RawMessage `json:"items,omitempty"`
+}
+
+// TraceContext is a context loaded by some instruction.
+type TraceContext struct {
+	ScriptHash util.Uint160 `json:"scripthash"`
+	// Offset is the offset of the next instruction to execute.
+	Offset   int `json:"offset"`
+	RetCount int `json:"retcount"`
+	// Shared is set for contexts sharing the script context with the
+	// previous one (created by CALL).
+	Shared bool `json:"shared,omitempty"`
+}
+
+// traceStepAux is an auxiliary struct for TraceStep JSON marshalling.
+type traceStepAux struct {
+	ScriptHash  util.Uint160      `json:"scripthash"`
+	Offset      int               `json:"offset"`
+	Opcode      string            `json:"opcode"`
+	Depth       int               `json:"depth"`
+	Level       int               `json:"level,omitempty"`
+	GasConsumed int64             `json:"gasconsumed"`
+	Error       string            `json:"error,omitempty"`
+	Before      *TraceState       `json:"before,omitempty"`
+	Pop         int               `json:"pop,omitempty"`
+	Push        []json.RawMessage `json:"push,omitempty"`
+	NewStack    bool              `json:"newstack,omitempty"`
+	EStack      []json.RawMessage `json:"estack,omitempty"`
+	Static      *TraceSlotDelta   `json:"static,omitempty"`
+	Local       *TraceSlotDelta   `json:"local,omitempty"`
+	Arguments   *TraceSlotDelta   `json:"arguments,omitempty"`
+	Loaded      []TraceContext    `json:"loaded,omitempty"`
+}
+
+// traceSnapshot is the state captured before the instruction execution.
+type traceSnapshot struct {
+	index  int
+	ctx    *Context
+	depth  int
+	estack *Stack
+	items  []stackitem.Item
+	// Slots and copies of their items.
+	static, staticItems slot
+	local, localItems   slot
+	args, argsItems     slot
+}
+
+// nullJSON is the JSON representation of uninitialized slot items.
+var nullJSON = json.RawMessage(`{"type":"Any"}`)
+
+// NewTracer returns a new empty Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{
+		hashes: make(map[util.Uint160]bool),
+	}
+}
+
+// SetTracer registers the given Tracer in v, nil can be used to disable
+// tracing.
+func (v *VM) SetTracer(t *Tracer) {
+	if t == nil {
+		v.setHook(hookTracer, nil)
+		return
+	}
+	v.setHook(hookTracer, func(ctx *Context, op opcode.Opcode, _ []byte) func(error) {
+		return v.traceStep(t, ctx, op)
+	})
+}
+
+// SetLimit limits the number of steps recorded, the trace is marked as
+// truncated if more instructions are executed. 0 means no limit.
+func (t *Tracer) SetLimit(n int) {
+	t.limit = n
+}
+
+// SetStateless disables recording of the evaluation stack and slot changes,
+// only instructions with GAS consumed and contexts loaded are recorded then.
+// Such traces are much smaller, but can't be replayed.
+func (t *Tracer) SetStateless(stateless bool) {
+	t.stateless = stateless
+}
+
+// Trace returns the trace recorded so far, it must not be modified and it's
+// only valid until the next instruction is executed by the traced VM.
+func (t *Tracer) Trace() *Trace {
+	return &t.trace
+}
+
+// Len returns the number of steps recorded.
+func (t *Tracer) Len() int {
+	return len(t.trace.Steps)
+}
+
+// Reset drops all collected data.
+func (t *Tracer) Reset() {
+	t.trace = Trace{}
+	t.hashes = make(map[util.Uint160]bool)
+	t.level = 0
+}
+
+// addScript records the script of the given context if it's not yet known.
+func (t *Tracer) addScript(ctx *Context) util.Uint160 {
+	h := ctx.ScriptHash()
+	if !t.hashes[h] {
+		t.hashes[h] = true
+		t.trace.Scripts = append(t.trace.Scripts, TraceScript{Hash: h, Script: ctx.sc.prog})
+	}
+	return h
+}
+
+// traceStep starts recording of the instruction being executed, the function
+// returned must be called after the execution.
+func (v *VM) traceStep(t *Tracer, ctx *Context, op opcode.Opcode) func(error) {
+	steps := t.trace.Steps
+	if t.limit > 0 && len(steps) >= t.limit {
+		t.trace.Truncated = true
+		return func(error) {}
+	}
+	if len(steps) == 0 && t.level == 0 && !t.stateless {
+		t.trace.Start = *captureTraceState(v)
+	}
+	step := TraceStep{
+		ScriptHash: t.addScript(ctx),
+		Offset:     ctx.ip,
+		Opcode:     op,
+		Depth:      len(v.istack),
+		Level:      t.level,
+	}
+	snap := &traceSnapshot{
+		index: len(steps),
+		ctx:   ctx,
+		depth: len(v.istack),
+	}
+	if !t.stateless {
+		if t.level > 0 && len(steps) > 0 && steps[len(steps)-1].Level < t.level {
+			step.Before = captureTraceState(v)
+		}
+		snap.estack = v.estack
+		snap.items = stackItems(v.estack)
+		snap.static, snap.staticItems = ctx.sc.static, copySlot(ctx.sc.static)
+		snap.local, snap.localItems = ctx.local, copySlot(ctx.local)
+		snap.args, snap.argsItems = ctx.arguments, copySlot(ctx.arguments)
+	}
+	t.trace.Steps = append(steps, step)
+	t.level++
+	return func(err error) {
+		t.level--
+		t.finishStep(v, snap, err)
+	}
+}
+
+// finishStep fills the step started with the given snapshot with the changes
+// made by the instruction.
+func (t *Tracer) finishStep(v *VM, snap *traceSnapshot, err error) {
+	step := &t.trace.Steps[snap.index]
+	step.GasConsumed = v.gasConsumed
+	if err != nil {
+		var e *errorAtInstruct
+		if errors.As(err, &e) {
+			step.Error = fmt.Sprint(e.err)
+		} else {
+			step.Error = err.Error()
+		}
+	}
+	if !t.stateless {
+		t.recordChanges(v, snap, step)
+	}
+	for i := snap.depth; i < len(v.istack); i++ {
+		c := v.istack[i]
+		step.Loaded = append(step.Loaded, TraceContext{
+			ScriptHash: t.addScript(c),
+			Offset:     c.nextip,
+			RetCount:   c.retCount,
+			Shared:     i > 0 && v.istack[i-1].sc == c.sc,
+		})
+	}
+}
+
+// recordChanges fills the step with the changes made by the instruction to
+// the evaluation stack and slots.
+func (t *Tracer) recordChanges(v *VM, snap *traceSnapshot, step *TraceStep) {
+	var common int
+	cur := snap.estack.elems
+	for common < len(snap.items) && common < len(cur) && snap.items[common] == cur[common].value {
+		common++
+	}
+	step.Pop = len(snap.items) - common
+	if len(cur) > common {
+		step.Push = encodeTraceItems(stackElemItems(cur[common:]))
+	}
+	if v.estack != snap.estack {
+		step.NewStack = true
+		if v.estack.Len() != 0 {
+			step.EStack = encodeTraceItems(stackItems(v.estack))
+		}
+	}
+
+	var (
+		ctx                 = v.Context()
+		static, local, args slot
+		sameCtx, sameScript bool
+	)
+	if ctx != nil {
+		static, local, args = ctx.sc.static, ctx.local, ctx.arguments
+		sameCtx = ctx == snap.ctx
+		sameScript = ctx.sc == snap.ctx.sc
+	}
+	step.Static = slotDelta(snap.static, snap.staticItems, static, !sameScript)
+	step.Local = slotDelta(snap.local, snap.localItems, local, !sameCtx)
+	step.Arguments = slotDelta(snap.args, snap.argsItems, args, !sameCtx)
+}
+
+// slotDelta returns the changes made to the slot (nil if there are none), old
+// is the slot before the instruction and items is the copy of its items.
+func slotDelta(old, items, cur slot, replaced bool) *TraceSlotDelta {
+	if replaced || len(old) != len(cur) || len(old) != 0 && &old[0] != &cur[0] {
+		if len(old) == 0 && len(cur) == 0 {
+			return nil
+		}
+		d := &TraceSlotDelta{Replaced: true, Size: len(cur)}
+		if len(cur) != 0 {
+			d.Items = make(map[int]json.RawMessage, len(cur))
+			for i := range cur {
+				d.Items[i] = encodeTraceItem(cur[i])
+			}
+		}
+		return d
+	}
+	var d *TraceSlotDelta
+	for i := range cur {
+		if items[i] != cur[i] {
+			if d == nil {
+				d = &TraceSlotDelta{Size: len(cur), Items: make(map[int]json.RawMessage)}
+			}
+			d.Items[i] = encodeTraceItem(cur[i])
+		}
+	}
+	return d
+}
+
+// copySlot returns a copy of the slot.
+func copySlot(s slot) slot {
+	if s == nil {
+		return nil
+	}
+	return append(make(slot, 0, len(s)), s...)
+}
+
+// stackItems returns items of the stack (the top one is the last).
+func stackItems(s *Stack) []stackitem.Item {
+	return stackElemItems(s.elems)
+}
+
+func stackElemItems(elems []Element) []stackitem.Item {
+	res := make([]stackitem.Item, len(elems))
+	for i := range elems {
+		res[i] = elems[i].value
+	}
+	return res
+}
+
+// captureTraceState returns the current state of the VM.
+func captureTraceState(v *VM) *TraceState {
+	st := &TraceState{
+		EStack: encodeTraceItems(stackItems(v.estack)),
+	}
+	if ctx := v.Context(); ctx != nil {
+		st.Static = encodeTraceItems(ctx.sc.static)
+		st.Local = encodeTraceItems(ctx.local)
+		st.Arguments = encodeTraceItems(ctx.arguments)
+	} else {
+		st.Static, st.Local, st.Arguments = []json.RawMessage{}, []json.RawMessage{}, []json.RawMessage{}
+	}
+	return st
+}
+
+func encodeTraceItems(items []stackitem.Item) []json.RawMessage {
+	res := make([]json.RawMessage, len(items))
+	for i := range items {
+		res[i] = encodeTraceItem(items[i])
+	}
+	return res
+}
+
+// encodeTraceItem serializes the item to JSON, items that can't be serialized
+// (too big or recursive) are represented as JSON null.
+func encodeTraceItem(item stackitem.Item) json.RawMessage {
+	if item == nil {
+		return nullJSON
+	}
+	data, err := stackitem.ToJSONWithTypes(item)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s TraceStep) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&traceStepAux{
+		ScriptHash:  s.ScriptHash,
+		Offset:      s.Offset,
+		Opcode:      s.Opcode.String(),
+		Depth:       s.Depth,
+		Level:       s.Level,
+		GasConsumed: s.GasConsumed,
+		Error:       s.Error,
+		Before:      s.Before,
+		Pop:         s.Pop,
+		Push:        s.Push,
+		NewStack:    s.NewStack,
+		EStack:      s.EStack,
+		Static:      s.Static,
+		Local:       s.Local,
+		Arguments:   s.Arguments,
+		Loaded:      s.Loaded,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *TraceStep) UnmarshalJSON(data []byte) error {
+	aux := new(traceStepAux)
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	op, err := opcode.FromString(aux.Opcode)
+	if err != nil {
+		return err
+	}
+	*s = TraceStep{
+		ScriptHash:  aux.ScriptHash,
+		Offset:      aux.Offset,
+		Opcode:      op,
+		Depth:       aux.Depth,
+		Level:       aux.Level,
+		GasConsumed: aux.GasConsumed,
+		Error:       aux.Error,
+		Before:      aux.Before,
+		Pop:         aux.Pop,
+		Push:        aux.Push,
+		NewStack:    aux.NewStack,
+		EStack:      aux.EStack,
+		Static:      aux.Static,
+		Local:       aux.Local,
+		Arguments:   aux.Arguments,
+		Loaded:      aux.Loaded,
+	}
+	return nil
+}
+
+// StateAt returns the state of the current context before the n-th step (or
+// after the last one if n is equal to the number of steps).
+func (t *Trace) StateAt(n int) (*TraceState, error) {
+	if n < 0 || n > len(t.Steps) {
+		return nil, fmt.Errorf("step %d is out of range", n)
+	}
+	// after contains the state after the last step of every nesting level.
+	var after = []*TraceState{t.Start.copy()}
+	for i := 0; ; i++ {
+		var (
+			st    *TraceState
+			level int
+		)
+		if i < len(t.Steps) {
+			level = t.Steps[i].Level
+		}
+		switch {
+		case level < len(after):
+			st = after[level]
+		case level == len(after) && t.Steps[i].Before != nil:
+			st = t.Steps[i].Before.copy()
+		default:
+			return nil, fmt.Errorf("step %d: missing state", i)
+		}
+		if i == n {
+			return st.copy(), nil
+		}
+		after = append(after[:level], st)
+		if err := st.apply(&t.Steps[i]); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+}
+
+// apply changes the state according to the step.
+func (s *TraceState) apply(step *TraceStep) error {
+	if step.Pop > len(s.EStack) {
+		return errors.New("stack underflow")
+	}
+	if step.NewStack {
+		s.EStack = append([]json.RawMessage{}, step.EStack...)
+	} else {
+		s.EStack = append(s.EStack[:len(s.EStack)-step.Pop], step.Push...)
+	}
+	var err error
+	if s.Static, err = step.Static.apply(s.Static); err != nil {
+		return fmt.Errorf("static slot: %w", err)
+	}
+	if s.Local, err = step.Local.apply(s.Local); err != nil {
+		return fmt.Errorf("local slot: %w", err)
+	}
+	if s.Arguments, err = step.Arguments.apply(s.Arguments); err != nil {
+		return fmt.Errorf("arguments slot: %w", err)
+	}
+	return nil
+}
+
+// apply returns the slot changed according to d.
+func (d *TraceSlotDelta) apply(s []json.RawMessage) ([]json.RawMessage, error) {
+	if d == nil {
+		return s, nil
+	}
+	if d.Replaced {
+		s = make([]json.RawMessage, d.Size)
+		for i := range s {
+			s[i] = nullJSON
+		}
+	} else if len(s) != d.Size {
+		return nil, errors.New("size mismatch")
+	}
+	for i, item := range d.Items {
+		if i < 0 || i >= len(s) {
+			return nil, fmt.Errorf("index %d is out of range", i)
+		}
+		s[i] = item
+	}
+	return s, nil
+}
+
+func (s *TraceState) copy() *TraceState {
+	return &TraceState{
+		EStack:    append([]json.RawMessage{}, s.EStack...),
+		Static:    append([]json.RawMessage{}, s.Static...),
+		Local:     append([]json.RawMessage{}, s.Local...),
+		Arguments: append([]json.RawMessage{}, s.Arguments...),
+	}
+}
+
+// replayer applies recorded trace to the VM.
+type replayer struct {
+	trace   *Trace
+	pos     int
+	scripts map[util.Uint160][]byte
+}
+
+// SetReplay makes v follow the given trace: every instruction executed is
+// checked to match the recorded one (the VM faults otherwise) and instead of
+// executing SYSCALL and CALLT instructions their recorded effects are applied
+// (including loading new contexts and GAS consumption). This allows to
+// replay an execution offline without the state it originally depended on.
+// Instructions executed from within SYSCALLs are skipped. The VM should have
+// the same script (and parameters) loaded as the traced one had. nil can be
+// used to disable replaying.
+func (v *VM) SetReplay(t *Trace) {
+	if t == nil {
+		v.replay = nil
+		return
+	}
+	r := &replayer{
+		trace:   t,
+		scripts: make(map[util.Uint160][]byte, len(t.Scripts)),
+	}
+	for _, s := range t.Scripts {
+		r.scripts[s.Hash] = s.Script
+	}
+	v.replay = r
+}
+
+// ReplayPosition returns the index of the next trace step to be replayed and
+// the number of steps in the trace (0, 0 if replay is not enabled).
+func (v *VM) ReplayPosition() (int, int) {
+	if v.replay == nil {
+		return 0, 0
+	}
+	return v.replay.pos, len(v.replay.trace.Steps)
+}
+
+// apply checks the instruction to be executed against the trace and applies
+// its recorded effects if needed, true is returned in this case. It panics
+// on mismatch.
+func (r *replayer) apply(v *VM, ctx *Context, op opcode.Opcode) bool {
+	steps := r.trace.Steps
+	if r.pos >= len(steps) {
+		panic("replay: trace is exhausted")
+	}
+	step := &steps[r.pos]
+	if h := ctx.ScriptHash(); !h.Equals(step.ScriptHash) || ctx.ip != step.Offset || op != step.Opcode || len(v.istack) != step.Depth {
+		panic(fmt.Sprintf("replay: step %d mismatch: expected %s at %d of %s (depth %d), got %s at %d of %s (depth %d)",
+			r.pos, step.Opcode, step.Offset, step.ScriptHash.StringLE(), step.Depth, op, ctx.ip, h.StringLE(), len(v.istack)))
+	}
+	r.pos++
+	if op != opcode.SYSCALL && op != opcode.CALLT {
+		return false
+	}
+	for r.pos < len(steps) && steps[r.pos].Level > step.Level {
+		r.pos++
+	}
+	if step.Error != "" {
+		panic(step.Error)
+	}
+	if step.Pop > v.estack.Len() {
+		panic("replay: stack underflow")
+	}
+	for i := 0; i < step.Pop; i++ {
+		v.estack.Pop()
+	}
+	pushTraceItems(v.estack, step.Push)
+	for _, c := range step.Loaded {
+		if c.Shared {
+			v.call(v.Context(), c.Offset)
+			continue
+		}
+		script, ok := r.scripts[c.ScriptHash]
+		if !ok {
+			panic(fmt.Sprintf("replay: missing script %s", c.ScriptHash.StringLE()))
+		}
+		v.loadScriptWithCallingHash(script, nil, v.GetCurrentScriptHash(), c.ScriptHash, callflag.All, c.RetCount, c.Offset, nil)
+	}
+	if step.NewStack {
+		if len(step.Loaded) == 0 {
+			panic("replay: unsupported stack change")
+		}
+		if v.estack.Len() != 0 {
+			panic("replay: new stack is not empty")
+		}
+		pushTraceItems(v.estack, step.EStack)
+	}
+	v.gasConsumed = step.GasConsumed
+	if v.GasLimit >= 0 && v.gasConsumed > v.GasLimit {
+		panic("gas limit is exceeded")
+	}
+	return true
+}
+
+// pushTraceItems pushes items deserialized from JSON to the stack.
+func pushTraceItems(s *Stack, items []json.RawMessage) {
+	for i := range items {
+		item, err := stackitem.FromJSONWithTypes(items[i])
+		if err != nil {
+			panic(fmt.Sprintf("replay: invalid item: %s", err))
+		}
+		s.PushItem(item)
+	}
+}
@@ -0,0 +1,267 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// tracedCallee doubles its argument.
+	tracedCallee = []byte{byte(opcode.PUSH2), byte(opcode.MUL), byte(opcode.RET)}
+	// tracedNative increments its argument.
+	tracedNative = []byte{byte(opcode.INC), byte(opcode.RET)}
+)
+
+// tracedSyscallHandler implements syscalls pushing a value, calling a
+// contract (like System.Contract.Call), calling a contract from within the
+// syscall (like native contracts do) and failing.
+func tracedSyscallHandler(v *VM, id uint32) error {
+	switch id {
+	case interopnames.ToID([]byte("push")):
+		v.AddGas(100)
+		v.Estack().PushVal(10)
+	case interopnames.ToID([]byte("call")):
+		arg := v.Estack().Pop().Item()
+		v.LoadScriptWithHash(tracedCallee, hash.Hash160(tracedCallee), callflag.All)
+		v.Estack().PushItem(arg)
+	case interopnames.ToID([]byte("native")):
+		arg := v.Estack().Pop().Item()
+		depth := len(v.istack)
+		v.LoadScriptWithHash(tracedNative, hash.Hash160(tracedNative), callflag.All)
+		v.Estack().PushItem(arg)
+		for len(v.istack) > depth && !v.HasStopped() {
+			if err := v.Step(); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func getTracedScript(t *testing.T) []byte {
+	w := io.NewBufBinWriter()
+	emit.Instruction(w.BinWriter, opcode.INITSSLOT, []byte{1})
+	emit.Instruction(w.BinWriter, opcode.INITSLOT, []byte{1, 0})
+	emit.Opcodes(w.BinWriter, opcode.PUSH5, opcode.STLOC0)
+	emit.Syscall(w.BinWriter, "push")
+	emit.Opcodes(w.BinWriter, opcode.LDLOC0, opcode.ADD)
+	emit.Syscall(w.BinWriter, "call")
+	emit.Syscall(w.BinWriter, "native")
+	emit.Instruction(w.BinWriter, opcode.CALL, []byte{5})
+	emit.Opcodes(w.BinWriter, opcode.STSFLD0, opcode.LDSFLD0, opcode.RET)
+	emit.Opcodes(w.BinWriter, opcode.DUP, opcode.ADD, opcode.RET)
+	require.NoError(t, w.Err)
+	return w.Bytes()
+}
+
+func newTracedVM(script []byte) *VM {
+	v := newTestVM()
+	v.SetPriceGetter(func(opcode.Opcode, []byte) int64 { return 1 })
+	v.LoadScript(script)
+	return v
+}
+
+func TestTracer(t *testing.T) {
+	script := getTracedScript(t)
+	v := newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	tr := NewTracer()
+	v.SetTracer(tr)
+	var states []*TraceState
+	v.SetOnExecHook(func(util.Uint160, int, opcode.Opcode) {
+		states = append(states, captureTraceState(v))
+	})
+	runVM(t, v)
+	require.Equal(t, big.NewInt(62), v.estack.Pop().BigInt())
+
+	trace := tr.Trace()
+	require.Equal(t, len(states), tr.Len())
+	require.Equal(t, 3, len(trace.Scripts))
+	require.Equal(t, TraceScript{Hash: hash.Hash160(script), Script: script}, trace.Scripts[0])
+	require.Equal(t, v.GasConsumed(), trace.Steps[len(trace.Steps)-1].GasConsumed)
+	for i := range states {
+		st, err := trace.StateAt(i)
+		require.NoError(t, err)
+		require.Equal(t, states[i], st, i)
+	}
+	st, err := trace.StateAt(tr.Len())
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{json.RawMessage(`{"type":"Integer","value":"62"}`)}, st.EStack)
+	_, err = trace.StateAt(tr.Len() + 1)
+	require.Error(t, err)
+
+	var nested int
+	for _, step := range trace.Steps {
+		if step.Level > 0 {
+			nested++
+			require.Equal(t, hash.Hash160(tracedNative), step.ScriptHash)
+		}
+		if step.Opcode == opcode.SYSCALL && step.Offset == 13 {
+			require.Equal(t, 1, step.Pop)
+			require.True(t, step.NewStack)
+			require.Equal(t, []TraceContext{{ScriptHash: hash.Hash160(tracedCallee), RetCount: 1}}, step.Loaded)
+		}
+	}
+	require.Equal(t, 2, nested)
+
+	data, err := json.Marshal(trace)
+	require.NoError(t, err)
+	actual := new(Trace)
+	require.NoError(t, json.Unmarshal(data, actual))
+	require.Equal(t, trace, actual)
+
+	tr.Reset()
+	require.Equal(t, 0, tr.Len())
+}
+
+func TestVM_SetReplay(t *testing.T) {
+	script := getTracedScript(t)
+	v := newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	tr := NewTracer()
+	v.SetTracer(tr)
+	runVM(t, v)
+	trace := tr.Trace()
+
+	t.Run("good", func(t *testing.T) {
+		// No syscall handler, all syscalls are replayed.
+		r := newTracedVM(script)
+		r.SetReplay(trace)
+		rt := NewTracer()
+		r.SetTracer(rt)
+		runVM(t, r)
+		require.Equal(t, big.NewInt(62), r.estack.Pop().BigInt())
+		require.Equal(t, v.GasConsumed(), r.GasConsumed())
+		pos, total := r.ReplayPosition()
+		require.Equal(t, len(trace.Steps), pos)
+		require.Equal(t, pos, total)
+
+		var expected []TraceStep
+		for _, step := range trace.Steps {
+			if step.Level == 0 {
+				expected = append(expected, step)
+			}
+		}
+		require.Equal(t, expected, rt.Trace().Steps)
+	})
+	t.Run("changed result", func(t *testing.T) {
+		data, err := json.Marshal(trace)
+		require.NoError(t, err)
+		changed := new(Trace)
+		require.NoError(t, json.Unmarshal(data, changed))
+		// Arguments of the called contract come from the trace, so only the
+		// last syscall (native) result affects the execution.
+		for i := len(changed.Steps) - 1; i >= 0; i-- {
+			if changed.Steps[i].Opcode == opcode.SYSCALL && changed.Steps[i].Level == 0 {
+				changed.Steps[i].Push = []json.RawMessage{json.RawMessage(`{"type":"Integer","value":"40"}`)}
+				break
+			}
+		}
+		r := newTracedVM(script)
+		r.SetReplay(changed)
+		runVM(t, r)
+		require.Equal(t, big.NewInt(80), r.estack.Pop().BigInt())
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		r := newTracedVM(append([]byte{byte(opcode.NOP)}, script...))
+		r.SetReplay(trace)
+		err := r.Run()
+		require.Error(t, err)
+		require.ErrorContains(t, err, "replay: step 0 mismatch")
+	})
+	t.Run("exhausted", func(t *testing.T) {
+		r := newTracedVM(script)
+		r.SetReplay(&Trace{Steps: trace.Steps[:3]})
+		err := r.Run()
+		require.ErrorContains(t, err, "replay: trace is exhausted")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		r := newTracedVM(script)
+		r.SetReplay(trace)
+		r.SetReplay(nil)
+		checkVMFailed(t, r)
+		pos, total := r.ReplayPosition()
+		require.Equal(t, 0, pos)
+		require.Equal(t, 0, total)
+	})
+}
+
+func TestTracerFault(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1)
+	emit.Syscall(w.BinWriter, "fail")
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	script := w.Bytes()
+
+	v := newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	tr := NewTracer()
+	v.SetTracer(tr)
+	errOrig := v.Run()
+	require.Error(t, errOrig)
+	steps := tr.Trace().Steps
+	require.Equal(t, 2, len(steps))
+	require.Contains(t, steps[1].Error, "failed: boom")
+
+	r := newTracedVM(script)
+	r.SetReplay(tr.Trace())
+	err := r.Run()
+	require.Error(t, err)
+	require.Equal(t, errOrig.Error(), err.Error())
+	require.Equal(t, []stackitem.Item{stackitem.Make(1)}, stackItems(r.estack))
+}
+
+func TestTracerLimitAndStateless(t *testing.T) {
+	script := getTracedScript(t)
+	full := NewTracer()
+	v := newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	v.SetTracer(full)
+	runVM(t, v)
+
+	tr := NewTracer()
+	tr.SetStateless(true)
+	v = newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	v.SetTracer(tr)
+	runVM(t, v)
+	require.Equal(t, full.Len(), tr.Len())
+	require.Equal(t, full.Trace().Scripts, tr.Trace().Scripts)
+	require.False(t, tr.Trace().Truncated)
+	for i, step := range tr.Trace().Steps {
+		fs := full.Trace().Steps[i]
+		require.Equal(t, TraceStep{
+			ScriptHash:  fs.ScriptHash,
+			Offset:      fs.Offset,
+			Opcode:      fs.Opcode,
+			Depth:       fs.Depth,
+			Level:       fs.Level,
+			GasConsumed: fs.GasConsumed,
+			Loaded:      fs.Loaded,
+		}, step, i)
+	}
+
+	tr = NewTracer()
+	tr.SetLimit(5)
+	v = newTracedVM(script)
+	v.SyscallHandler = tracedSyscallHandler
+	v.SetTracer(tr)
+	runVM(t, v)
+	require.Equal(t, 5, tr.Len())
+	require.True(t, tr.Trace().Truncated)
+	require.Equal(t, full.Trace().Steps[:5], tr.Trace().Steps)
+}
@@ -89,8 +89,38 @@ type VM struct {
 
 	// invTree is a top-level invocation tree (if enabled).
 	invTree *invocations.Tree
+
+	// hooks are instruction execution hooks (OnExecHook, profiler and
+	// tracer), hooked is set if any of them is set.
+	hooks  [hookCount]execHook
+	hooked bool
+	// profNested is the amount of GAS spent by nested instructions, used
+	// for profiling only.
+	profNested int64
+
+	// replay is a trace being replayed (if enabled).
+	replay *replayer
 }
 
+// OnExecHook is a function called before each instruction is executed. It
+// accepts the hash of the script being executed, the offset of the
+// instruction in it and its opcode.
+type OnExecHook func(scriptHash util.Uint160, offset int, op opcode.Opcode)
+
+// execHook is an internal instruction execution hook, it's called before the
+// instruction is executed and returns a function to be called after that
+// with the execution result (or nil if it's not needed).
+type execHook func(ctx *Context, op opcode.Opcode, parameter []byte) func(error)
+
+// Execution hook kinds, hooks are invoked in this order before the
+// instruction and in the reverse one after it.
+const (
+	hookTracer = iota
+	hookProfiler
+	hookOnExec
+	hookCount
+)
+
 var (
 	bigMinusOne = big.NewInt(-1)
 	bigZero     = big.NewInt(0)
@@ -121,6 +151,48 @@ func (v *VM) SetPriceGetter(f func(opcode.Opcode, []byte) int64) {
 	v.getPrice = f
 }
 
+// SetOnExecHook registers the given OnExecHook in v, it's called before each
+// instruction execution. nil can be used to remove the hook.
+func (v *VM) SetOnExecHook(h OnExecHook) {
+	if h == nil {
+		v.setHook(hookOnExec, nil)
+		return
+	}
+	v.setHook(hookOnExec, func(ctx *Context, op opcode.Opcode, _ []byte) func(error) {
+		h(ctx.ScriptHash(), ctx.ip, op)
+		return nil
+	})
+}
+
+// setHook sets (or removes if h is nil) the execution hook of the given kind.
+func (v *VM) setHook(kind int, h execHook) {
+	v.hooks[kind] = h
+	v.hooked = false
+	for i := range v.hooks {
+		if v.hooks[i] != nil {
+			v.hooked = true
+		}
+	}
+}
+
+// runHooks invokes execution hooks before the instruction, the function
+// returned invokes their finalizers after it.
+func (v *VM) runHooks(ctx *Context, op opcode.Opcode, parameter []byte) func(error) {
+	var after [hookCount]func(error)
+	for i, h := range v.hooks {
+		if h != nil {
+			after[i] = h(ctx, op, parameter)
+		}
+	}
+	return func(err error) {
+		for i := len(after) - 1; i >= 0; i-- {
+			if after[i] != nil {
+				after[i](err)
+			}
+		}
+	}
+}
+
 // Reset allows to reuse existing VM for subsequent executions making them somewhat
 // more efficient. It reuses invocation and evaluation stacks as well as VM structure
 // itself.
@@ -137,6 +209,10 @@ func (v *VM) Reset(t trigger.Type) {
 	v.LoadToken = nil
 	v.trigger = t
 	v.invTree = nil
+	v.hooks = [hookCount]execHook{}
+	v.hooked = false
+	v.profNested = 0
+	v.replay = nil
 }
 
 // GasConsumed returns the amount of GAS consumed during execution.
@@ -576,6 +652,10 @@ func GetInteropID(parameter []byte) uint32 {
 
 // execute performs an instruction cycle in the VM. Acting on the instruction (opcode).
 func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err error) {
+	if v.hooked {
+		finish := v.runHooks(ctx, op, parameter)
+		defer func() { finish(err) }()
+	}
 	// Instead of polluting the whole VM logic with error handling, we will recover
 	// each panic at a central point, putting the VM in a fault state and setting error.
 	defer func() {
@@ -595,6 +675,10 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		}
 	}
 
+	if v.replay != nil && v.replay.apply(v, ctx, op) {
+		return
+	}
+
 	if op <= opcode.PUSHINT256 {
 		v.estack.PushItem(stackitem.NewBigInteger(bigint.FromBytes(parameter)))
 		return
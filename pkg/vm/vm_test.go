@@ -14,10 +14,12 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -97,6 +99,72 @@ func TestVM_SetPriceGetter(t *testing.T) {
 	})
 }
 
+func TestVM_SetOnExecHook(t *testing.T) {
+	v := newTestVM()
+	prog := []byte{
+		byte(opcode.PUSH4), byte(opcode.PUSHDATA1), 0x01, 0x01,
+		byte(opcode.DROP), byte(opcode.RET),
+	}
+
+	var (
+		offsets []int
+		ops     []opcode.Opcode
+	)
+	v.SetOnExecHook(func(h util.Uint160, offset int, op opcode.Opcode) {
+		require.Equal(t, hash.Hash160(prog), h)
+		offsets = append(offsets, offset)
+		ops = append(ops, op)
+	})
+	v.Load(prog)
+	runVM(t, v)
+	require.Equal(t, []int{0, 1, 4, 5}, offsets)
+	require.Equal(t, []opcode.Opcode{opcode.PUSH4, opcode.PUSHDATA1, opcode.DROP, opcode.RET}, ops)
+
+	v.Reset(trigger.Application)
+	offsets = offsets[:0]
+	v.Load(prog)
+	runVM(t, v)
+	require.Equal(t, 0, len(offsets))
+}
+
+func TestVM_SetProfiler(t *testing.T) {
+	v := newTestVM()
+	prog := []byte{
+		byte(opcode.CALL), 3, byte(opcode.RET),
+		byte(opcode.SYSCALL), 0x01, 0x02, 0x03, 0x04, byte(opcode.RET),
+	}
+	h := hash.Hash160(prog)
+	v.SetPriceGetter(func(opcode.Opcode, []byte) int64 { return 1 })
+	v.SyscallHandler = func(v *VM, id uint32) error {
+		require.Equal(t, uint32(0x04030201), id)
+		v.AddGas(100)
+		return nil
+	}
+	p := NewProfiler()
+	v.SetProfiler(p)
+	v.Load(prog)
+	runVM(t, v)
+
+	expected := []ProfileSample{
+		{
+			Stack:        []ProfileFrame{{h, 3}, {h, 0}},
+			Opcode:       opcode.SYSCALL,
+			Syscall:      0x04030201,
+			Instructions: 1,
+			Gas:          101,
+		},
+		{Stack: []ProfileFrame{{h, 0}}, Opcode: opcode.CALL, Instructions: 1, Gas: 1},
+		{Stack: []ProfileFrame{{h, 2}}, Opcode: opcode.RET, Instructions: 1, Gas: 1},
+		{Stack: []ProfileFrame{{h, 8}, {h, 0}}, Opcode: opcode.RET, Instructions: 1, Gas: 1},
+	}
+	actual := p.Samples()
+	require.Equal(t, expected[0], actual[0])
+	require.ElementsMatch(t, expected[1:], actual[1:])
+
+	p.Reset()
+	require.Equal(t, 0, len(p.Samples()))
+}
+
 func TestAddGas(t *testing.T) {
 	v := newTestVM()
 	v.GasLimit = 10
@@ -3,6 +3,7 @@ package wallet
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -20,6 +21,9 @@ type Account struct {
 	// NEO private key.
 	privateKey *keys.PrivateKey
 
+	// External signer used instead of the private key.
+	signer Signer
+
 	// Script hash corresponding to the Address.
 	scriptHash util.Uint160
 
@@ -111,10 +115,10 @@ func (a *Account) SignTx(net netmode.Magic, t *transaction.Transaction) error {
 	if len(a.Contract.Parameters) == 0 {
 		return nil
 	}
-	if a.privateKey == nil {
-		return errors.New("account key is not available (need to decrypt?)")
+	sign, err := a.signHashable(net, t)
+	if err != nil {
+		return err
 	}
-	sign := a.privateKey.SignHashable(uint32(net), t)
 
 	invoc := append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sign...)
 	if len(a.Contract.Parameters) == 1 {
@@ -127,18 +131,47 @@ func (a *Account) SignTx(net netmode.Magic, t *transaction.Transaction) error {
 }
 
 // SignHashable signs the given Hashable item and returns the signature. If this
-// account can't sign (CanSign() returns false) nil is returned.
+// account can't sign (CanSign() returns false) or its external signer fails, nil
+// is returned.
 func (a *Account) SignHashable(net netmode.Magic, item hash.Hashable) []byte {
 	if !a.CanSign() {
 		return nil
 	}
-	return a.privateKey.SignHashable(uint32(net), item)
+	sig, _ := a.signHashable(net, item)
+	return sig
+}
+
+func (a *Account) signHashable(net netmode.Magic, item hash.Hashable) ([]byte, error) {
+	return a.SignHash(hash.NetSha256(uint32(net), item))
+}
+
+// SignHash signs the given digest with the account's private key or external
+// signer.
+func (a *Account) SignHash(digest util.Uint256) ([]byte, error) {
+	if a.Locked {
+		return nil, errors.New("account is locked")
+	}
+	if a.signer == nil {
+		if a.privateKey == nil {
+			return nil, errors.New("account key is not available (need to decrypt?)")
+		}
+		return a.privateKey.SignHash(digest), nil
+	}
+	sig, err := a.signer.SignHash(digest)
+	if err != nil {
+		return nil, fmt.Errorf("external signer failed: %w", err)
+	}
+	if len(sig) != keys.SignatureLen {
+		return nil, fmt.Errorf("external signer returned invalid signature length %d", len(sig))
+	}
+	return sig, nil
 }
 
 // CanSign returns true when account is not locked and has a decrypted private
-// key inside, so it's ready to create real signatures.
+// key inside or an external signer attached, so it's ready to create real
+// signatures.
 func (a *Account) CanSign() bool {
-	return !a.Locked && a.privateKey != nil
+	return !a.Locked && (a.privateKey != nil || a.signer != nil)
 }
 
 // GetVerificationScript returns account's verification script.
@@ -153,9 +186,14 @@ func (a *Account) GetVerificationScript() []byte {
 // if anything goes wrong. After the decryption Account can be used to sign
 // things unless it's locked. Don't decrypt the key unless you want to sign
 // something and don't forget to call Close after use for maximum safety.
+// Accounts using an external signer don't need to be decrypted, so it's a no-op
+// for them.
 func (a *Account) Decrypt(passphrase string, scrypt keys.ScryptParams) error {
 	var err error
 
+	if a.signer != nil {
+		return nil
+	}
 	if a.EncryptedWIF == "" {
 		return errors.New("no encrypted wif in the account")
 	}
@@ -179,6 +217,7 @@ func (a *Account) Encrypt(passphrase string, scrypt keys.ScryptParams) error {
 }
 
 // PrivateKey returns private key corresponding to the account if it's unlocked.
+// It's always nil for accounts using an external signer.
 // Please be very careful when using it, do not copy its contents and do not
 // keep a pointer to it unless you absolutely need to. Most of the time you can
 // use other methods (PublicKey, ScriptHash, SignHashable) depending on your
@@ -193,6 +232,9 @@ func (a *Account) PublicKey() *keys.PublicKey {
 	if !a.CanSign() {
 		return nil
 	}
+	if a.signer != nil {
+		return a.signer.PublicKey()
+	}
 	return a.privateKey.PublicKey()
 }
 
@@ -208,8 +250,15 @@ func (a *Account) ScriptHash() util.Uint160 {
 
 // Close cleans up the private key used by Account and disassociates it from
 // Account. The Account can no longer sign anything after this call, but Decrypt
-// can make it usable again.
+// can make it usable again. External signer is closed (if it implements
+// io.Closer) and detached, Decrypt can't restore it.
 func (a *Account) Close() {
+	if a.signer != nil {
+		if c, ok := a.signer.(io.Closer); ok {
+			_ = c.Close()
+		}
+		a.signer = nil
+	}
 	if a.privateKey == nil {
 		return
 	}
@@ -244,11 +293,11 @@ func (a *Account) ConvertMultisig(m int, pubs []*keys.PublicKey) error {
 	if a.Locked {
 		return errors.New("account is locked")
 	}
-	if a.privateKey == nil {
+	if !a.CanSign() {
 		return errors.New("account key is not available (need to decrypt?)")
 	}
 	var found bool
-	accKey := a.privateKey.PublicKey()
+	accKey := a.PublicKey()
 	for i := range pubs {
 		if accKey.Equal(pubs[i]) {
 			found = true
@@ -292,6 +341,23 @@ func NewAccountFromPrivateKey(p *keys.PrivateKey) *Account {
 	return a
 }
 
+// NewAccountFromSigner creates a standard signature account using the given
+// external signer. Such account doesn't have a private key or EncryptedWIF
+// inside, every signature is created by the signer.
+func NewAccountFromSigner(s Signer) *Account {
+	pubKey := s.PublicKey()
+
+	return &Account{
+		signer:     s,
+		scriptHash: pubKey.GetScriptHash(),
+		Address:    pubKey.Address(),
+		Contract: &Contract{
+			Script:     pubKey.GetVerificationScript(),
+			Parameters: getContractParams(1),
+		},
+	}
+}
+
 func getContractParams(n int) []ContractParam {
 	params := make([]ContractParam, n)
 	for i := range params {
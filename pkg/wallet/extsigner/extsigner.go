@@ -0,0 +1,85 @@
+/*
+Package extsigner implements wallet.Signer using keys stored outside of the
+node: in PKCS#11-compatible devices (HSMs, SoftHSM for testing) or in a remote
+signing service (see NewHandler for the protocol description).
+
+PKCS#11 signer requires cgo, it's not available in builds with CGO_ENABLED=0.
+*/
+package extsigner
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// Signer is a wallet.Signer holding some resources that need to be released
+// with Close after use. Close is safe to call multiple times.
+type Signer interface {
+	wallet.Signer
+	io.Closer
+}
+
+// New creates a signer using the given configuration.
+func New(cfg config.ExternalSigner) (Signer, error) {
+	switch {
+	case cfg.PKCS11 != nil && cfg.Remote != nil:
+		return nil, errors.New("only one of PKCS11 and Remote signers can be specified")
+	case cfg.PKCS11 != nil:
+		s, err := NewPKCS11(*cfg.PKCS11)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case cfg.Remote != nil:
+		s, err := NewRemote(*cfg.Remote)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, errors.New("no external signer specified")
+	}
+}
+
+// OpenWallet opens the wallet using the given configuration. If it has an
+// external signer specified, the signer is attached to all wallet accounts
+// having its key (at least one is required). If there is no wallet file path,
+// an in-memory wallet with a single standard account for the signer's key is
+// returned. Closing the wallet closes the signer.
+func OpenWallet(cfg config.Wallet) (*wallet.Wallet, error) {
+	if cfg.ExternalSigner == nil {
+		return wallet.NewWalletFromFile(cfg.Path)
+	}
+	s, err := New(*cfg.ExternalSigner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create external signer: %w", err)
+	}
+	if len(cfg.Path) == 0 {
+		return &wallet.Wallet{
+			Accounts: []*wallet.Account{wallet.NewAccountFromSigner(s)},
+			Scrypt:   keys.NEP2ScryptParams(),
+		}, nil
+	}
+	w, err := wallet.NewWalletFromFile(cfg.Path)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	var found bool
+	for _, acc := range w.Accounts {
+		if acc.SetSigner(s) == nil {
+			found = true
+		}
+	}
+	if !found {
+		_ = s.Close()
+		return nil, fmt.Errorf("no account for external signer key %s in the wallet", hex.EncodeToString(s.PublicKey().Bytes()))
+	}
+	return w, nil
+}
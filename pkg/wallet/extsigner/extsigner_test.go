@@ -0,0 +1,94 @@
+package extsigner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(config.ExternalSigner{})
+	require.Error(t, err)
+
+	_, err = New(config.ExternalSigner{PKCS11: &config.PKCS11Signer{}, Remote: &config.RemoteSigner{}})
+	require.Error(t, err)
+
+	_, err = New(config.ExternalSigner{PKCS11: &config.PKCS11Signer{KeyLabel: "key"}})
+	require.Error(t, err)
+
+	_, err = New(config.ExternalSigner{Remote: &config.RemoteSigner{URL: "http://localhost"}})
+	require.Error(t, err)
+
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	s, err := New(config.ExternalSigner{Remote: &config.RemoteSigner{URL: "http://localhost", PublicKey: pubHex(key), Token: testToken}})
+	require.NoError(t, err)
+	require.IsType(t, (*Remote)(nil), s)
+}
+
+func TestOpenWallet(t *testing.T) {
+	key, srv := newRemoteServer(t)
+	signerCfg := &config.ExternalSigner{Remote: &config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(key), Token: testToken}}
+
+	t.Run("no signer", func(t *testing.T) {
+		_, err := OpenWallet(config.Wallet{Path: filepath.Join(t.TempDir(), "none.json")})
+		require.Error(t, err)
+	})
+	t.Run("in-memory", func(t *testing.T) {
+		w, err := OpenWallet(config.Wallet{ExternalSigner: signerCfg})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(w.Accounts))
+		acc := w.GetAccount(key.GetScriptHash())
+		require.NotNil(t, acc)
+		require.True(t, acc.CanSign())
+		require.NoError(t, acc.Decrypt("", w.Scrypt))
+		require.NotNil(t, acc.SignHashable(0, transaction.New([]byte{1}, 0)))
+		w.Close()
+		require.False(t, acc.CanSign())
+	})
+
+	path := filepath.Join(t.TempDir(), "wallet.json")
+	w, err := wallet.NewWallet(path)
+	require.NoError(t, err)
+	other, err := wallet.NewAccount()
+	require.NoError(t, err)
+	multi := wallet.NewAccountFromPrivateKey(key)
+	require.NoError(t, multi.ConvertMultisig(1, keys.PublicKeys{key.PublicKey(), other.PublicKey()}))
+	for _, acc := range []*wallet.Account{other, multi, wallet.NewAccountFromPrivateKey(key)} {
+		require.NoError(t, acc.Encrypt("pass", keys.NEP2ScryptParams()))
+		w.AddAccount(acc)
+	}
+	require.NoError(t, w.Save())
+	w.Close()
+
+	t.Run("bad signer", func(t *testing.T) {
+		_, err := OpenWallet(config.Wallet{Path: path, ExternalSigner: &config.ExternalSigner{}})
+		require.Error(t, err)
+	})
+	t.Run("bad path", func(t *testing.T) {
+		_, err := OpenWallet(config.Wallet{Path: path + ".bad", ExternalSigner: signerCfg})
+		require.Error(t, err)
+	})
+	t.Run("file", func(t *testing.T) {
+		w, err := OpenWallet(config.Wallet{Path: path, ExternalSigner: signerCfg})
+		require.NoError(t, err)
+		require.Equal(t, 3, len(w.Accounts))
+		require.False(t, w.Accounts[0].CanSign())
+		require.True(t, w.Accounts[1].CanSign())
+		require.True(t, w.Accounts[2].CanSign())
+		require.Nil(t, w.Accounts[2].PrivateKey())
+	})
+	t.Run("no matching account", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		_, err = OpenWallet(config.Wallet{Path: path, ExternalSigner: &config.ExternalSigner{
+			Remote: &config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(other), Token: testToken},
+		}})
+		require.Error(t, err)
+	})
+}
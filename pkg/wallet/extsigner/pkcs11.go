@@ -0,0 +1,268 @@
+//go:build cgo
+
+package extsigner
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// PKCS11 is a signer using the secp256r1 key stored in the PKCS#11-compatible
+// device.
+type PKCS11 struct {
+	lock    sync.Mutex
+	mod     *module
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+	pub     *keys.PublicKey
+}
+
+// module is a loaded PKCS#11 library. Cryptoki can be initialized only once
+// per process, so modules are shared between signers.
+type module struct {
+	path string
+	ctx  *pkcs11.Ctx
+	refs int
+}
+
+var (
+	modulesLock sync.Mutex
+	modules     = make(map[string]*module)
+)
+
+// secp256r1OID is the DER-encoded secp256r1 curve OID used as CKA_EC_PARAMS.
+var secp256r1OID = []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}
+
+// NewPKCS11 opens a session to the token specified by the configuration and
+// finds the key pair to sign with.
+func NewPKCS11(cfg config.PKCS11Signer) (*PKCS11, error) {
+	if len(cfg.Module) == 0 {
+		return nil, errors.New("no PKCS#11 module specified")
+	}
+	if len(cfg.KeyLabel) == 0 && len(cfg.KeyID) == 0 {
+		return nil, errors.New("KeyLabel or KeyID must be specified")
+	}
+	var template []*pkcs11.Attribute
+	if len(cfg.KeyLabel) != 0 {
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel))
+	}
+	if len(cfg.KeyID) != 0 {
+		id, err := hex.DecodeString(cfg.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KeyID: %w", err)
+		}
+		template = append(template, pkcs11.NewAttribute(pkcs11.CKA_ID, id))
+	}
+
+	mod, err := openModule(cfg.Module)
+	if err != nil {
+		return nil, err
+	}
+	s := &PKCS11{mod: mod}
+	err = s.init(cfg, template)
+	if err != nil {
+		mod.release()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *PKCS11) init(cfg config.PKCS11Signer, template []*pkcs11.Attribute) error {
+	ctx := s.mod.ctx
+	slot, err := findSlot(ctx, cfg.TokenLabel)
+	if err != nil {
+		return err
+	}
+	s.session, err = ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	if len(cfg.PIN) != 0 {
+		err = ctx.Login(s.session, pkcs11.CKU_USER, cfg.PIN)
+		if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			_ = ctx.CloseSession(s.session)
+			return fmt.Errorf("failed to login: %w", err)
+		}
+	}
+	err = s.findKeys(template)
+	if err != nil {
+		_ = ctx.CloseSession(s.session)
+		return err
+	}
+	return nil
+}
+
+func (s *PKCS11) findKeys(template []*pkcs11.Attribute) error {
+	var err error
+
+	s.key, err = s.findObject(append(template,
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC)))
+	if err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	pubObj, err := s.findObject(append(template,
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC)))
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	attrs, err := s.mod.ctx.GetAttributeValue(s.session, pubObj, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get public key: %w", err)
+	}
+	var params, point []byte
+	for _, a := range attrs {
+		switch a.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			params = a.Value
+		case pkcs11.CKA_EC_POINT:
+			point = a.Value
+		}
+	}
+	if !bytes.Equal(params, secp256r1OID) {
+		return errors.New("not a secp256r1 key")
+	}
+	// CKA_EC_POINT is a DER-encoded OCTET STRING, but some devices return
+	// the raw point.
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+	s.pub, err = keys.NewPublicKeyFromBytes(point, elliptic.P256())
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return nil
+}
+
+func (s *PKCS11) findObject(template []*pkcs11.Attribute) (pkcs11.ObjectHandle, error) {
+	ctx := s.mod.ctx
+	err := ctx.FindObjectsInit(s.session, template)
+	if err != nil {
+		return 0, fmt.Errorf("search failed: %w", err)
+	}
+	objs, _, err := ctx.FindObjects(s.session, 2)
+	_ = ctx.FindObjectsFinal(s.session)
+	if err != nil {
+		return 0, fmt.Errorf("search failed: %w", err)
+	}
+	switch len(objs) {
+	case 0:
+		return 0, errors.New("not found")
+	case 1:
+		return objs[0], nil
+	default:
+		return 0, errors.New("more than one object found")
+	}
+}
+
+func findSlot(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slots: %w", err)
+	}
+	if len(label) == 0 {
+		if len(slots) != 1 {
+			return 0, fmt.Errorf("TokenLabel must be specified for %d tokens", len(slots))
+		}
+		return slots[0], nil
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info: %w", err)
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("token %q not found", label)
+}
+
+func openModule(path string) (*module, error) {
+	modulesLock.Lock()
+	defer modulesLock.Unlock()
+
+	m, ok := modules[path]
+	if !ok {
+		ctx := pkcs11.New(path)
+		if ctx == nil {
+			return nil, fmt.Errorf("failed to load PKCS#11 module %s", path)
+		}
+		err := ctx.Initialize()
+		if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+			ctx.Destroy()
+			return nil, fmt.Errorf("failed to initialize PKCS#11 module: %w", err)
+		}
+		m = &module{path: path, ctx: ctx}
+		modules[path] = m
+	}
+	m.refs++
+	return m, nil
+}
+
+func (m *module) release() {
+	modulesLock.Lock()
+	defer modulesLock.Unlock()
+
+	m.refs--
+	if m.refs == 0 {
+		delete(modules, m.path)
+		_ = m.ctx.Finalize()
+		m.ctx.Destroy()
+	}
+}
+
+// PublicKey implements the wallet.Signer interface.
+func (s *PKCS11) PublicKey() *keys.PublicKey {
+	return s.pub
+}
+
+// SignHash implements the wallet.Signer interface.
+func (s *PKCS11) SignHash(digest util.Uint256) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.mod == nil {
+		return nil, errors.New("signer is closed")
+	}
+	ctx := s.mod.ctx
+	err := ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	sig, err := ctx.Sign(s.session, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return sig, nil
+}
+
+// Close implements the io.Closer interface. It closes the session, but doesn't
+// log out since other signers can use the same token.
+func (s *PKCS11) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.mod == nil {
+		return nil
+	}
+	err := s.mod.ctx.CloseSession(s.session)
+	s.mod.release()
+	s.mod = nil
+	return err
+}
@@ -0,0 +1,35 @@
+//go:build !cgo
+
+package extsigner
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// PKCS11 is a signer using the key stored in the PKCS#11-compatible device. It
+// requires cgo, so it can't be created in this build.
+type PKCS11 struct{}
+
+// NewPKCS11 always returns an error since PKCS#11 support requires cgo.
+func NewPKCS11(config.PKCS11Signer) (*PKCS11, error) {
+	return nil, errors.New("PKCS#11 signer is not supported in builds without cgo")
+}
+
+// PublicKey implements the wallet.Signer interface.
+func (s *PKCS11) PublicKey() *keys.PublicKey {
+	return nil
+}
+
+// SignHash implements the wallet.Signer interface.
+func (s *PKCS11) SignHash(util.Uint256) ([]byte, error) {
+	return nil, errors.New("PKCS#11 signer is not supported")
+}
+
+// Close implements the io.Closer interface.
+func (s *PKCS11) Close() error {
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build cgo
+
+package extsigner
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPKCS11 needs an initialized token, it can be created with SoftHSM:
+//
+//	softhsm2-util --init-token --free --label neo-go --pin 1234 --so-pin 1234
+//	NEOGO_PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so NEOGO_PKCS11_TOKEN=neo-go NEOGO_PKCS11_PIN=1234 go test ./pkg/wallet/extsigner
+func TestPKCS11(t *testing.T) {
+	cfg := config.PKCS11Signer{
+		Module:     os.Getenv("NEOGO_PKCS11_MODULE"),
+		TokenLabel: os.Getenv("NEOGO_PKCS11_TOKEN"),
+		PIN:        os.Getenv("NEOGO_PKCS11_PIN"),
+		KeyLabel:   "neo-go-test",
+		KeyID:      "0a0b0c",
+	}
+	if cfg.Module == "" {
+		t.Skip("NEOGO_PKCS11_MODULE is not set")
+	}
+
+	// Session keys are visible in all sessions of the process and destroyed
+	// after the session that created them is closed.
+	mod, err := openModule(cfg.Module)
+	require.NoError(t, err)
+	t.Cleanup(mod.release)
+	slot, err := findSlot(mod.ctx, cfg.TokenLabel)
+	require.NoError(t, err)
+	session, err := mod.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mod.ctx.CloseSession(session) })
+	err = mod.ctx.Login(session, pkcs11.CKU_USER, cfg.PIN)
+	if err != nil {
+		require.ErrorIs(t, err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN))
+	}
+	id, _ := hex.DecodeString(cfg.KeyID)
+	_, _, err = mod.ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256r1OID),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.KeyLabel),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		})
+	require.NoError(t, err)
+
+	t.Run("bad config", func(t *testing.T) {
+		for _, bad := range []config.PKCS11Signer{
+			{Module: cfg.Module, TokenLabel: cfg.TokenLabel},
+			{Module: cfg.Module, TokenLabel: cfg.TokenLabel, KeyID: "zz"},
+			{Module: cfg.Module, TokenLabel: "unknown token", KeyLabel: cfg.KeyLabel},
+			{Module: cfg.Module, TokenLabel: cfg.TokenLabel, KeyLabel: "unknown key"},
+			{Module: "/nonexistent/module.so", KeyLabel: cfg.KeyLabel},
+		} {
+			_, err := NewPKCS11(bad)
+			require.Error(t, err, bad)
+		}
+	})
+
+	for name, c := range map[string]config.PKCS11Signer{
+		"label": {Module: cfg.Module, TokenLabel: cfg.TokenLabel, PIN: cfg.PIN, KeyLabel: cfg.KeyLabel},
+		"id":    {Module: cfg.Module, TokenLabel: cfg.TokenLabel, PIN: cfg.PIN, KeyID: cfg.KeyID},
+	} {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			s, err := New(config.ExternalSigner{PKCS11: &c})
+			require.NoError(t, err)
+			pub := s.PublicKey()
+			require.NotNil(t, pub)
+
+			digest := hash.Sha256([]byte("data"))
+			sig, err := s.SignHash(digest)
+			require.NoError(t, err)
+			require.True(t, pub.Verify(sig, digest[:]))
+
+			require.NoError(t, s.Close())
+			require.NoError(t, s.Close())
+			_, err = s.SignHash(digest)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package extsigner
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// DefaultRemoteTimeout is the default remote signing request timeout.
+const DefaultRemoteTimeout = 10 * time.Second
+
+// maxMessageSize limits the size of remote signing protocol messages.
+const maxMessageSize = 4096
+
+// Remote is a signer using the remote signing service. Signatures it returns
+// are always checked against the configured public key.
+type Remote struct {
+	url    string
+	auth   string
+	pub    *keys.PublicKey
+	client *http.Client
+}
+
+// SignRequest is a remote signing request, it's sent as a JSON-encoded body
+// of the HTTP POST request to the signing service URL with the shared secret
+// passed in the "Authorization: Bearer <token>" header.
+type SignRequest struct {
+	// PublicKey is the hex-encoded compressed public key of the key to sign
+	// with.
+	PublicKey string `json:"publicKey"`
+	// Hash is the hex-encoded digest to sign.
+	Hash string `json:"hash"`
+}
+
+// SignResponse is a successful remote signing response, it's returned by the
+// signing service as a JSON-encoded body with 200 OK status. Any other status
+// means an error with a text explanation in the body.
+type SignResponse struct {
+	// Signature is the hex-encoded 64-byte signature.
+	Signature string `json:"signature"`
+}
+
+// NewRemote creates a remote signer using the given configuration.
+func NewRemote(cfg config.RemoteSigner) (*Remote, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("invalid URL: http(s) URL expected")
+	}
+	pub, err := keys.NewPublicKeyFromString(cfg.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PublicKey: %w", err)
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("no Token")
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+	tlsCfg, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil && u.Scheme != "https" {
+		return nil, errors.New("TLS settings require https URL")
+	}
+	var client = &http.Client{Timeout: timeout}
+	if tlsCfg != nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsCfg
+		client.Transport = tr
+	}
+	return &Remote{
+		url:    u.String(),
+		auth:   "Bearer " + cfg.Token,
+		pub:    pub,
+		client: client,
+	}, nil
+}
+
+// newTLSConfig creates TLS client configuration from the given signer
+// configuration, nil is returned if no TLS settings are specified.
+func newTLSConfig(cfg config.RemoteSigner) (*tls.Config, error) {
+	if cfg.CAFile == "" && cfg.CertFile == "" && cfg.KeyFile == "" {
+		return nil, nil
+	}
+	var tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		data, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CAFile: %w", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificates in CAFile")
+		}
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// PublicKey implements the wallet.Signer interface.
+func (r *Remote) PublicKey() *keys.PublicKey {
+	return r.pub
+}
+
+// SignHash implements the wallet.Signer interface.
+func (r *Remote) SignHash(digest util.Uint256) ([]byte, error) {
+	body, err := json.Marshal(SignRequest{
+		PublicKey: hex.EncodeToString(r.pub.Bytes()),
+		Hash:      hex.EncodeToString(digest[:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", r.auth)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("signing request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signing request failed: %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var res SignResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("invalid signing response: %w", err)
+	}
+	sig, err := hex.DecodeString(res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if !r.pub.Verify(sig, digest[:]) {
+		return nil, errors.New("invalid signature")
+	}
+	return sig, nil
+}
+
+// Close implements the io.Closer interface.
+func (r *Remote) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
+
+// NewHandler returns an HTTP handler implementing the server side of the remote
+// signing protocol for the given signers (decrypted wallet accounts can also be
+// used). It accepts POST requests with SignRequest body authenticated with
+// the given token and replies with SignResponse for known keys. Requests
+// without a valid token are rejected with 401 status (all of them if the token
+// is empty). The handler should be served over TLS unless the network is
+// trusted, the token is sent in plain text otherwise.
+func NewHandler(token string, signers ...wallet.Signer) http.Handler {
+	var (
+		auth  = []byte("Bearer " + token)
+		byKey = make(map[string]wallet.Signer, len(signers))
+	)
+	for _, s := range signers {
+		byKey[hex.EncodeToString(s.PublicKey().Bytes())] = s
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), auth) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST method expected", http.StatusMethodNotAllowed)
+			return
+		}
+		var req SignRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxMessageSize)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+		s, ok := byKey[req.PublicKey]
+		if !ok {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+		h, err := hex.DecodeString(req.Hash)
+		if err != nil || len(h) != util.Uint256Size {
+			http.Error(w, "invalid hash", http.StatusBadRequest)
+			return
+		}
+		var digest util.Uint256
+		copy(digest[:], h)
+		sig, err := s.SignHash(digest)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to sign: %s", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SignResponse{Signature: hex.EncodeToString(sig)})
+	})
+}
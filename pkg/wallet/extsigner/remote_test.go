@@ -0,0 +1,180 @@
+package extsigner
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+func newRemoteServer(t *testing.T) (*keys.PrivateKey, *httptest.Server) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewHandler(testToken, wallet.NewAccountFromPrivateKey(key)))
+	t.Cleanup(srv.Close)
+	return key, srv
+}
+
+func pubHex(key *keys.PrivateKey) string {
+	return hex.EncodeToString(key.PublicKey().Bytes())
+}
+
+func TestNewRemote(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	pub := pubHex(key)
+
+	for _, cfg := range []config.RemoteSigner{
+		{PublicKey: pub, Token: testToken},
+		{URL: "localhost:8080", PublicKey: pub, Token: testToken},
+		{URL: "ftp://localhost", PublicKey: pub, Token: testToken},
+		{URL: "http://localhost", Token: testToken},
+		{URL: "http://localhost", PublicKey: "0102", Token: testToken},
+		{URL: "http://localhost", PublicKey: pub},
+		{URL: "http://localhost", PublicKey: pub, Token: testToken, CAFile: "/nonexistent"},
+		{URL: "https://localhost", PublicKey: pub, Token: testToken, CAFile: "/nonexistent"},
+		{URL: "https://localhost", PublicKey: pub, Token: testToken, CertFile: "/nonexistent"},
+	} {
+		_, err := NewRemote(cfg)
+		require.Error(t, err, cfg)
+	}
+
+	r, err := NewRemote(config.RemoteSigner{URL: "http://localhost", PublicKey: pub, Token: testToken})
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey(), r.PublicKey())
+	require.Equal(t, DefaultRemoteTimeout, r.client.Timeout)
+	require.NoError(t, r.Close())
+
+	r, err = NewRemote(config.RemoteSigner{URL: "http://localhost", PublicKey: pub, Token: testToken, Timeout: time.Second})
+	require.NoError(t, err)
+	require.Equal(t, time.Second, r.client.Timeout)
+}
+
+func TestRemote_SignHash(t *testing.T) {
+	key, srv := newRemoteServer(t)
+	digest := hash.Sha256([]byte("data"))
+
+	r, err := NewRemote(config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(key), Token: testToken})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	sig, err := r.SignHash(digest)
+	require.NoError(t, err)
+	require.True(t, key.PublicKey().Verify(sig, digest[:]))
+
+	t.Run("unknown key", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		r, err := NewRemote(config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(other), Token: testToken})
+		require.NoError(t, err)
+		_, err = r.SignHash(digest)
+		require.ErrorContains(t, err, "unknown key")
+	})
+	t.Run("wrong token", func(t *testing.T) {
+		r, err := NewRemote(config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(key), Token: "wrong"})
+		require.NoError(t, err)
+		_, err = r.SignHash(digest)
+		require.ErrorContains(t, err, "401")
+	})
+	t.Run("wrong signature", func(t *testing.T) {
+		other, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		// Signs with the other key, but pretends it's the right one.
+		bad := httptest.NewServer(NewHandler(testToken, &fakeKeySigner{Account: wallet.NewAccountFromPrivateKey(other), pub: key.PublicKey()}))
+		t.Cleanup(bad.Close)
+		r, err := NewRemote(config.RemoteSigner{URL: bad.URL, PublicKey: pubHex(key), Token: testToken})
+		require.NoError(t, err)
+		_, err = r.SignHash(digest)
+		require.ErrorContains(t, err, "invalid signature")
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		down := httptest.NewServer(nil)
+		down.Close()
+		r, err := NewRemote(config.RemoteSigner{URL: down.URL, PublicKey: pubHex(key), Token: testToken})
+		require.NoError(t, err)
+		_, err = r.SignHash(digest)
+		require.Error(t, err)
+	})
+}
+
+func TestRemote_SignHashTLS(t *testing.T) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	srv := httptest.NewTLSServer(NewHandler(testToken, wallet.NewAccountFromPrivateKey(key)))
+	t.Cleanup(srv.Close)
+	digest := hash.Sha256([]byte("data"))
+
+	// Self-signed server certificate is not trusted by default.
+	r, err := NewRemote(config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(key), Token: testToken})
+	require.NoError(t, err)
+	_, err = r.SignHash(digest)
+	require.Error(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0644))
+	r, err = NewRemote(config.RemoteSigner{URL: srv.URL, PublicKey: pubHex(key), Token: testToken, CAFile: caFile})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+	sig, err := r.SignHash(digest)
+	require.NoError(t, err)
+	require.True(t, key.PublicKey().Verify(sig, digest[:]))
+}
+
+type fakeKeySigner struct {
+	*wallet.Account
+	pub *keys.PublicKey
+}
+
+func (s *fakeKeySigner) PublicKey() *keys.PublicKey {
+	return s.pub
+}
+
+func TestHandler(t *testing.T) {
+	key, srv := newRemoteServer(t)
+
+	do := func(t *testing.T, url, method, auth, body string) int {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	valid := `{"publicKey":"` + pubHex(key) + `","hash":"` + hash.Sha256(nil).StringLE() + `"}`
+
+	require.Equal(t, http.StatusMethodNotAllowed, do(t, srv.URL, http.MethodGet, "Bearer "+testToken, ""))
+	for _, auth := range []string{"", testToken, "Bearer", "Bearer wrong", "Basic " + testToken} {
+		require.Equal(t, http.StatusUnauthorized, do(t, srv.URL, http.MethodPost, auth, valid), auth)
+	}
+
+	noToken := httptest.NewServer(NewHandler("", wallet.NewAccountFromPrivateKey(key)))
+	t.Cleanup(noToken.Close)
+	for _, auth := range []string{"", "Bearer", "Bearer "} {
+		require.Equal(t, http.StatusUnauthorized, do(t, noToken.URL, http.MethodPost, auth, valid), auth)
+	}
+
+	for body, code := range map[string]int{
+		`not a json`: http.StatusBadRequest,
+		`{"publicKey":"` + pubHex(key) + `","hash":"0102"}`:                 http.StatusBadRequest,
+		`{"publicKey":"` + pubHex(key) + `","hash":"zz"}`:                   http.StatusBadRequest,
+		`{"publicKey":"0102","hash":"` + hash.Sha256(nil).StringLE() + `"}`: http.StatusNotFound,
+		valid: http.StatusOK,
+	} {
+		require.Equal(t, code, do(t, srv.URL, http.MethodPost, "Bearer "+testToken, body), body)
+	}
+}
@@ -0,0 +1,289 @@
+package wallet
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// NeoCoinType is the SLIP-0044 coin type registered for Neo, it's used
+	// as a second level of BIP-44 derivation path.
+	NeoCoinType = 888
+	// HardenedKeyStart is the index of the first hardened child key.
+	HardenedKeyStart uint32 = 0x80000000
+)
+
+// masterKeyHMACKey is the HMAC key used to generate the master key from seed
+// for secp256r1 curve as specified in SLIP-0010.
+var masterKeyHMACKey = []byte("Nist256p1 seed")
+
+// ExtendedKey is a BIP-32 extended private key. Neo uses secp256r1 curve, so
+// derivation follows SLIP-0010 which generalizes BIP-32 for other curves.
+type ExtendedKey struct {
+	key       []byte
+	chainCode []byte
+	depth     uint8
+	index     uint32
+}
+
+// NewMnemonic generates a new random BIP-39 mnemonic of the given number of
+// words (12, 15, 18, 21 or 24) using the English word list.
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("invalid number of words: %d", words)
+	}
+	entropy, err := bip39.NewEntropy(words / 3 * 32)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// IsMnemonicValid checks whether the given mnemonic is a valid BIP-39 mnemonic
+// (including its checksum).
+func IsMnemonicValid(mnemonic string) bool {
+	return bip39.IsMnemonicValid(NormalizeMnemonic(mnemonic))
+}
+
+// NormalizeMnemonic trims extra whitespace from the mnemonic and converts it to
+// lower case.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// NewSeedFromMnemonic checks the given BIP-39 mnemonic and returns the seed
+// for it protected with the given passphrase (which can be empty).
+func NewSeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	return bip39.NewSeedWithErrorChecking(NormalizeMnemonic(mnemonic), passphrase)
+}
+
+// NewMasterKey creates a BIP-32 master key from the given seed (16-64 bytes).
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("invalid seed length")
+	}
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, masterKeyHMACKey)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		k := new(big.Int).SetBytes(i[:32])
+		if k.Sign() != 0 && k.Cmp(elliptic.P256().Params().N) < 0 {
+			return &ExtendedKey{
+				key:       i[:32],
+				chainCode: i[32:],
+			}, nil
+		}
+		data = i
+	}
+}
+
+// Child derives a child key with the given index, indices starting from
+// HardenedKeyStart denote hardened keys.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, errors.New("derivation depth exceeded")
+	}
+	var (
+		n    = elliptic.P256().Params().N
+		data []byte
+	)
+	if index >= HardenedKeyStart {
+		data = append([]byte{0}, k.key...)
+	} else {
+		priv, err := keys.NewPrivateKeyFromBytes(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = priv.PublicKey().Bytes()
+	}
+	data = appendIndex(data, index)
+	for {
+		mac := hmac.New(sha512.New, k.chainCode)
+		mac.Write(data)
+		i := mac.Sum(nil)
+		il := new(big.Int).SetBytes(i[:32])
+		if il.Cmp(n) < 0 {
+			il.Add(il, new(big.Int).SetBytes(k.key))
+			il.Mod(il, n)
+			if il.Sign() != 0 {
+				return &ExtendedKey{
+					key:       il.FillBytes(make([]byte, 32)),
+					chainCode: i[32:],
+					depth:     k.depth + 1,
+					index:     index,
+				}, nil
+			}
+		}
+		// Invalid key, retry as specified in SLIP-0010.
+		data = appendIndex(append([]byte{1}, i[32:]...), index)
+	}
+}
+
+func appendIndex(data []byte, index uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], index)
+	return append(data, b[:]...)
+}
+
+// Derive derives a key using the given path relative to the current key.
+func (k *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	var (
+		res = k
+		err error
+	)
+	for _, index := range path {
+		res, err = res.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Depth returns the key depth (0 for the master key).
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Index returns the index of the key in its parent's children.
+func (k *ExtendedKey) Index() uint32 {
+	return k.index
+}
+
+// ChainCode returns the key chain code.
+func (k *ExtendedKey) ChainCode() []byte {
+	return k.chainCode
+}
+
+// PrivateKey returns the secp256r1 private key.
+func (k *ExtendedKey) PrivateKey() (*keys.PrivateKey, error) {
+	return keys.NewPrivateKeyFromBytes(k.key)
+}
+
+// ParseDerivationPath parses a BIP-32 derivation path like "m/44'/888'/0'/0/0"
+// into a list of indices. Hardened indices can be marked with "'", "h" or "H".
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, errors.New("derivation path should start with 'm'")
+	}
+	res := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		var offset uint32
+		if strings.HasSuffix(p, "'") || strings.HasSuffix(p, "h") || strings.HasSuffix(p, "H") {
+			p = p[:len(p)-1]
+			offset = HardenedKeyStart
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil || uint32(i) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid derivation path element %q", p)
+		}
+		res = append(res, uint32(i)+offset)
+	}
+	return res, nil
+}
+
+// DerivationPath returns the BIP-44 external chain derivation path of the
+// Neo account with the given index, like "m/44'/888'/0'/0/index".
+func DerivationPath(account uint32, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0/%d", NeoCoinType, account, index)
+}
+
+// NewAccountFromSeed creates a new Account using the key derived from the
+// given BIP-39 seed via the given BIP-32 derivation path.
+func NewAccountFromSeed(seed []byte, path string) (*Account, error) {
+	p, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	k, err := m.Derive(p)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := k.PrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewAccountFromPrivateKey(priv), nil
+}
+
+// HDInfo contains the data required to derive new accounts for a wallet
+// created from a BIP-39 mnemonic. It doesn't contain any secrets, the mnemonic
+// (and passphrase) has to be provided for every derivation.
+type HDInfo struct {
+	// Account is the BIP-44 account index (the third path level).
+	Account uint32 `json:"account"`
+	// Next is the index of the next key to derive on the external chain.
+	Next uint32 `json:"next"`
+	// Address is the address of the first key derived, it's used to check
+	// that the same mnemonic and passphrase are used for derivation.
+	Address string `json:"address"`
+}
+
+// DeriveAccounts derives count sequential accounts from the given BIP-39 seed
+// using BIP-44 paths for the wallet HD account, encrypts them with the given
+// passphrase and adds them to the wallet (it's not saved). If the wallet has
+// no HD info yet, it's initialized with the given account index and derivation
+// starts from index 0, otherwise the seed is checked to match the one used
+// previously. Accounts are labeled with the given name (if any) suffixed by
+// their index.
+func (w *Wallet) DeriveAccounts(seed []byte, account uint32, count int, name, passphrase string) ([]*Account, error) {
+	if count <= 0 {
+		return nil, errors.New("number of accounts should be positive")
+	}
+	hd := w.Extra.HD
+	if hd == nil {
+		hd = &HDInfo{Account: account}
+	}
+	if hd.Address != "" {
+		first, err := NewAccountFromSeed(seed, DerivationPath(hd.Account, 0))
+		if err != nil {
+			return nil, err
+		}
+		if first.Address != hd.Address {
+			return nil, errors.New("mnemonic or passphrase doesn't match the wallet")
+		}
+	}
+	if uint64(hd.Next)+uint64(count) > uint64(HardenedKeyStart) {
+		return nil, errors.New("derivation index overflow")
+	}
+	res := make([]*Account, 0, count)
+	for i := 0; i < count; i++ {
+		index := hd.Next + uint32(i)
+		acc, err := NewAccountFromSeed(seed, DerivationPath(hd.Account, index))
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			acc.Label = fmt.Sprintf("%s %d", name, index)
+		}
+		if err := acc.Encrypt(passphrase, w.Scrypt); err != nil {
+			return nil, err
+		}
+		if index == 0 {
+			hd.Address = acc.Address
+		}
+		res = append(res, acc)
+	}
+	for _, acc := range res {
+		w.AddAccount(acc)
+	}
+	hd.Next += uint32(count)
+	w.Extra.HD = hd
+	return res, nil
+}
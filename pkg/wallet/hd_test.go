@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMnemonic(t *testing.T) {
+	for _, n := range []int{12, 15, 18, 21, 24} {
+		m, err := NewMnemonic(n)
+		require.NoError(t, err)
+		require.Equal(t, n, len(strings.Fields(m)))
+		require.True(t, IsMnemonicValid(m))
+	}
+	for _, n := range []int{0, 11, 13, 27} {
+		_, err := NewMnemonic(n)
+		require.Error(t, err)
+	}
+}
+
+func TestNewSeedFromMnemonic(t *testing.T) {
+	// BIP-39 test vector.
+	const mnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	seed, err := NewSeedFromMnemonic(" Legal winner thank year wave sausage  worth useful legal winner thank yellow\n", "TREZOR")
+	require.NoError(t, err)
+	require.Equal(t, "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607", hex.EncodeToString(seed))
+	require.True(t, IsMnemonicValid(mnemonic))
+
+	_, err = NewSeedFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank thank", "")
+	require.Error(t, err) // Bad checksum.
+	_, err = NewSeedFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank neo", "")
+	require.Error(t, err) // Unknown word.
+}
+
+func TestExtendedKeyDerive(t *testing.T) {
+	// SLIP-0010 test vectors for nist256p1 curve.
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	testCases := []struct {
+		path      string
+		chainCode string
+		key       string
+	}{
+		{"m", "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2"},
+		{"m/0'", "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c"},
+		{"m/0'/1", "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c", "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129"},
+		{"m/0H/1/2h", "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318", "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7"},
+		// Derivation retry.
+		{"m/28578'", "e94c8ebe30c2250a14713212f6449b20f3329105ea15b652ca5bdfc68f6c65c2", "06f0db126f023755d0b8d86d4591718a5210dd8d024e3e14b6159d63f53aa669"},
+		{"m/28578'/33941", "9e87fe95031f14736774cd82f25fd885065cb7c358c1edf813c72af535e83071", "092154eed4af83e078ff9b84322015aefe5769e31270f62c3f66c33888335f3a"},
+	}
+	m, err := NewMasterKey(seed)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := ParseDerivationPath(tc.path)
+			require.NoError(t, err)
+			k, err := m.Derive(p)
+			require.NoError(t, err)
+			require.Equal(t, tc.chainCode, hex.EncodeToString(k.ChainCode()))
+			priv, err := k.PrivateKey()
+			require.NoError(t, err)
+			require.Equal(t, tc.key, hex.EncodeToString(priv.Bytes()))
+			require.Equal(t, uint8(len(p)), k.Depth())
+		})
+	}
+
+	_, err = NewMasterKey(seed[:15])
+	require.Error(t, err)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	p, err := ParseDerivationPath(DerivationPath(1, 5))
+	require.NoError(t, err)
+	require.Equal(t, []uint32{44 + HardenedKeyStart, NeoCoinType + HardenedKeyStart, 1 + HardenedKeyStart, 0, 5}, p)
+
+	for _, bad := range []string{"", "44'/888'", "m/", "m/x", "m/-1", "m/2147483648", "m/1''"} {
+		_, err := ParseDerivationPath(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestNewAccountFromSeed(t *testing.T) {
+	seed, err := NewSeedFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow", "")
+	require.NoError(t, err)
+	a1, err := NewAccountFromSeed(seed, DerivationPath(0, 0))
+	require.NoError(t, err)
+	a2, err := NewAccountFromSeed(seed, DerivationPath(0, 0))
+	require.NoError(t, err)
+	a3, err := NewAccountFromSeed(seed, DerivationPath(0, 1))
+	require.NoError(t, err)
+	require.Equal(t, a1.Address, a2.Address)
+	require.NotEqual(t, a1.Address, a3.Address)
+
+	_, err = NewAccountFromSeed(seed, "44'")
+	require.Error(t, err)
+}
+
+func TestWalletDeriveAccounts(t *testing.T) {
+	seed, err := NewSeedFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow", "")
+	require.NoError(t, err)
+	w := checkWalletConstructor(t)
+	w.Scrypt = keys.ScryptParams{N: 2, R: 1, P: 1}
+
+	_, err = w.DeriveAccounts(seed, 0, 0, "", "pass")
+	require.Error(t, err)
+
+	accs, err := w.DeriveAccounts(seed, 0, 2, "hd", "pass")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(accs))
+	require.Equal(t, accs, w.Accounts)
+	require.Equal(t, "hd 1", accs[1].Label)
+	require.Equal(t, &HDInfo{Next: 2, Address: accs[0].Address}, w.Extra.HD)
+	require.NoError(t, accs[0].Decrypt("pass", w.Scrypt))
+	exp, err := NewAccountFromSeed(seed, DerivationPath(0, 0))
+	require.NoError(t, err)
+	require.Equal(t, exp.Address, accs[0].Address)
+
+	accs, err = w.DeriveAccounts(seed, 5, 1, "", "pass")
+	require.NoError(t, err)
+	exp, err = NewAccountFromSeed(seed, DerivationPath(0, 2))
+	require.NoError(t, err)
+	require.Equal(t, exp.Address, accs[0].Address)
+	require.Equal(t, uint32(3), w.Extra.HD.Next)
+	require.Equal(t, 3, len(w.Accounts))
+
+	other, err := NewSeedFromMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow", "passphrase")
+	require.NoError(t, err)
+	_, err = w.DeriveAccounts(other, 0, 1, "", "pass")
+	require.Error(t, err)
+	require.Equal(t, 3, len(w.Accounts))
+}
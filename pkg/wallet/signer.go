@@ -0,0 +1,52 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+)
+
+// Signer is a holder of the private key that can be used by Account instead of
+// the decrypted key itself. It allows to keep the key outside of the process,
+// like in HSM or in some remote signing service (see extsigner package for
+// implementations).
+type Signer interface {
+	// PublicKey returns the public key corresponding to the signing key.
+	PublicKey() *keys.PublicKey
+	// SignHash signs the given digest (the same way keys.PrivateKey.SignHash
+	// does) and returns a 64-byte signature.
+	SignHash(digest util.Uint256) ([]byte, error)
+}
+
+// SetSigner attaches the external signer to the account. It can be used for
+// standard signature and multisignature accounts having the signer's key
+// inside. The account can sign without decryption after this call.
+func (a *Account) SetSigner(s Signer) error {
+	if a.Contract == nil {
+		return errors.New("account has no contract")
+	}
+	var (
+		found bool
+		pub   = s.PublicKey().Bytes()
+	)
+	if key, ok := vm.ParseSignatureContract(a.Contract.Script); ok {
+		found = bytes.Equal(key, pub)
+	} else if _, pubs, ok := vm.ParseMultiSigContract(a.Contract.Script); ok {
+		for i := range pubs {
+			if bytes.Equal(pubs[i], pub) {
+				found = true
+				break
+			}
+		}
+	} else {
+		return errors.New("account contract is not a standard one")
+	}
+	if !found {
+		return errors.New("signer key doesn't match account contract")
+	}
+	a.signer = s
+	return nil
+}
@@ -0,0 +1,105 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigner struct {
+	key    *keys.PrivateKey
+	err    error
+	sig    []byte
+	closed bool
+}
+
+func (s *testSigner) PublicKey() *keys.PublicKey {
+	return s.key.PublicKey()
+}
+
+func (s *testSigner) SignHash(digest util.Uint256) ([]byte, error) {
+	if s.err != nil || s.sig != nil {
+		return s.sig, s.err
+	}
+	return s.key.SignHash(digest), nil
+}
+
+func (s *testSigner) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	return &testSigner{key: key}
+}
+
+func TestNewAccountFromSigner(t *testing.T) {
+	s := newTestSigner(t)
+	acc := NewAccountFromSigner(s)
+	require.Equal(t, s.key.Address(), acc.Address)
+	require.Equal(t, s.key.GetScriptHash(), acc.ScriptHash())
+	require.Equal(t, s.key.PublicKey().GetVerificationScript(), acc.Contract.Script)
+	require.True(t, acc.CanSign())
+	require.Equal(t, s.key.PublicKey(), acc.PublicKey())
+	require.Nil(t, acc.PrivateKey())
+	require.NoError(t, acc.Decrypt("pass", keys.NEP2ScryptParams()))
+	require.True(t, acc.CanSign())
+
+	tx := transaction.New([]byte{1, 2, 3}, 0)
+	tx.Signers = []transaction.Signer{{Account: acc.ScriptHash()}}
+	require.NoError(t, acc.SignTx(netmode.UnitTestNet, tx))
+	require.True(t, s.key.PublicKey().VerifyHashable(tx.Scripts[0].InvocationScript[2:], uint32(netmode.UnitTestNet), tx))
+	require.True(t, s.key.PublicKey().VerifyHashable(acc.SignHashable(netmode.UnitTestNet, tx), uint32(netmode.UnitTestNet), tx))
+
+	s.sig = []byte{1, 2, 3}
+	require.Error(t, acc.SignTx(netmode.UnitTestNet, tx))
+	require.Nil(t, acc.SignHashable(netmode.UnitTestNet, tx))
+
+	s.err = errors.New("bad signer")
+	require.ErrorIs(t, acc.SignTx(netmode.UnitTestNet, tx), s.err)
+	require.Nil(t, acc.SignHashable(netmode.UnitTestNet, tx))
+
+	acc.Locked = true
+	require.False(t, acc.CanSign())
+	_, err := acc.SignHash(util.Uint256{})
+	require.Error(t, err)
+	acc.Locked = false
+
+	acc.Close()
+	require.True(t, s.closed)
+	require.False(t, acc.CanSign())
+}
+
+func TestAccount_SetSigner(t *testing.T) {
+	s := newTestSigner(t)
+
+	require.Error(t, new(Account).SetSigner(s))
+
+	other, err := NewAccount()
+	require.NoError(t, err)
+	require.Error(t, other.SetSigner(s))
+
+	acc := NewAccountFromPrivateKey(s.key)
+	acc.Close()
+	require.NoError(t, acc.SetSigner(s))
+	require.True(t, acc.CanSign())
+
+	multi := NewAccountFromPrivateKey(s.key)
+	require.NoError(t, multi.ConvertMultisig(1, keys.PublicKeys{other.PublicKey(), s.key.PublicKey()}))
+	multi.Close()
+	require.NoError(t, multi.SetSigner(s))
+	require.True(t, multi.CanSign())
+
+	require.NoError(t, other.ConvertMultisig(1, keys.PublicKeys{other.PublicKey()}))
+	require.Error(t, other.SetSigner(s))
+
+	other.Contract.Script = []byte{1, 2, 3}
+	require.Error(t, other.SetSigner(s))
+}
@@ -36,10 +36,13 @@ type Wallet struct {
 	path string
 }
 
-// Extra stores imported token contracts.
+// Extra stores imported token contracts and HD wallet data.
 type Extra struct {
 	// Tokens is a list of imported token contracts.
 	Tokens []*Token
+	// HD contains hierarchical deterministic key derivation data for
+	// wallets created from a BIP-39 mnemonic, it's nil for other wallets.
+	HD *HDInfo `json:"HD,omitempty"`
 }
 
 // NewWallet creates a new NEO wallet at the given location.